@@ -0,0 +1,29 @@
+package configs
+
+import (
+	"os"
+	"strings"
+)
+
+// URL หน้าเว็บ (frontend) ที่ผู้เยี่ยมชมเปิดดู เช่น หน้าโปรไฟล์สุนัข
+func PublicSiteURL() string {
+	return envOr("PUBLIC_SITE_URL", "http://localhost:5173")
+}
+
+// URL ของ API นี้ที่เข้าถึงได้จากภายนอก (ใช้ประกอบลิงก์แชร์/QR)
+func PublicAPIURL() string {
+	return envOr("PUBLIC_API_URL", "http://localhost:8000")
+}
+
+// ฟอนต์ TTF ภาษาไทยของการ์ดหน้าคอก/เอกสาร PDF (เช่น Sarabun, OFL)
+// ไม่มีไฟล์ = ใช้ฟอนต์มาตรฐานของ PDF แทน (แสดงได้เฉพาะอักษรละติน)
+func CardFontPath() string {
+	return envOr("CARD_FONT_PATH", "static/fonts/card.ttf")
+}
+
+func envOr(key, def string) string {
+	if v := strings.TrimSpace(os.Getenv(key)); v != "" {
+		return strings.TrimRight(v, "/")
+	}
+	return def
+}
@@ -32,6 +32,9 @@ type UpdateStatusRequest struct {
 	Status string `json:"status" binding:"required,oneof=approved rejected"`
 }
 
+// errDogDeceased: ไม่รับคำขอ/ไม่อนุมัติการรับเลี้ยงสุนัขที่เสียชีวิตแล้ว
+var errDogDeceased = errors.New("dog is deceased")
+
 // CreateAdoption จัดการการสร้างหรืออัปเดตคำขอรับเลี้ยงใหม่
 func CreateAdoption(c *gin.Context) {
 	var req CreateAdoptionRequest
@@ -56,6 +59,9 @@ func CreateAdoption(c *gin.Context) {
 			// คืนค่า error แบบมาตรฐาน
 			return errors.New("dog has already been adopted")
 		}
+		if dog.Status == entity.DogStatusDeceased {
+			return errDogDeceased
+		}
 		// --- จบส่วนที่แก้ไข ---
 
 		// --- ส่วนที่แก้ไข: ตรวจสอบและอัปเดต/สร้างคำขอ ---
@@ -127,6 +133,10 @@ func CreateAdoption(c *gin.Context) {
 			return
 		}
 		// --- จบส่วนที่แก้ไข ---
+		if errors.Is(err, errDogDeceased) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process adoption request: " + err.Error()})
 		return
@@ -184,6 +194,10 @@ func UpdateAdoptionStatus(c *gin.Context) {
 			c.JSON(http.StatusConflict, gin.H{"error": errorMessage})
 			return
 		}
+		if dog.Status == entity.DogStatusDeceased {
+			c.JSON(http.StatusConflict, gin.H{"error": errDogDeceased.Error()})
+			return
+		}
 	}
 	// --- จบส่วนที่แก้ไข ---
 
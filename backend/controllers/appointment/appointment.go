@@ -0,0 +1,531 @@
+package appointment
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"example.com/project-sa/configs"
+	health_records "example.com/project-sa/controllers/health_record"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/middlewares"
+	"example.com/project-sa/utils/timeutil"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+/* ========== Vet appointments ========== */
+
+const (
+	defaultDurationMinutes = 30
+	maxCalendarDays        = 31
+)
+
+type appointmentRequest struct {
+	DogID           uint   `json:"dog_id" binding:"required"`
+	VetID           uint   `json:"vet_id" binding:"required"`
+	Reason          string `json:"reason" binding:"required"`
+	StartAt         string `json:"start_at" binding:"required"` // RFC3339 หรือ "YYYY-MM-DD HH:MM" (เวลาไทย)
+	DurationMinutes int    `json:"duration_minutes" binding:"omitempty,min=5,max=480"`
+	Notes           string `json:"notes"`
+}
+
+type rescheduleRequest struct {
+	VetID           *uint   `json:"vet_id"`
+	Reason          *string `json:"reason"`
+	StartAt         *string `json:"start_at"`
+	DurationMinutes *int    `json:"duration_minutes" binding:"omitempty,min=5,max=480"`
+	Notes           *string `json:"notes"`
+}
+
+type closeRequest struct {
+	Reason string `json:"reason"`
+}
+
+// ผลการตรวจ (ค่าเริ่มต้นของ MedicalRecord ที่จะสร้าง)
+type completeRequest struct {
+	Weight         float64     `json:"weight"`
+	Temperature    float64     `json:"temperature"`
+	Symptoms       string      `json:"symptoms"`
+	Diagnosis      string      `json:"diagnosis"`
+	Treatment      string      `json:"treatment"`
+	Medication     string      `json:"medication"`
+	Notes          string      `json:"notes"`
+	VaccineRecords []vaccineIn `json:"vaccine_records" binding:"dive"`
+}
+
+// วัคซีนที่ฉีดในนัด (รับเฉพาะฟิลด์ที่กรอกได้ ไม่ผูก entity ตรง ๆ)
+type vaccineIn struct {
+	VaccineID   uint   `json:"vaccine_id" binding:"required"`
+	DoseNumber  int    `json:"dose_number"`
+	LotNumber   string `json:"lot_number"`
+	NextDueDate string `json:"next_due_date"` // "YYYY-MM-DD" หรือ RFC3339 (ว่าง = คำนวณจากตารางวัคซีน)
+}
+
+// conflictError นัดที่ชนกับช่วงเวลาที่ขอ
+type conflictError struct {
+	conflicts []entity.VetAppointment
+}
+
+func (e *conflictError) Error() string {
+	return "vet or dog is already booked in this slot"
+}
+
+func parseSlot(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.UTC(), nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02T15:04"} {
+		if t, err := time.ParseInLocation(layout, s, timeutil.TZBangkok()); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, errors.New("invalid start_at (RFC3339 or YYYY-MM-DD HH:MM)")
+}
+
+func preloadAppointment(db *gorm.DB) *gorm.DB {
+	return db.Preload("Dog").Preload("Vet").Preload("BookedBy")
+}
+
+// checkConflicts หานัดที่ยังไม่ปิดของสัตวแพทย์หรือสุนัขตัวเดียวกันที่เวลาซ้อนกัน
+func checkConflicts(tx *gorm.DB, a *entity.VetAppointment) error {
+	var rows []entity.VetAppointment
+	q := tx.Preload("Dog").Preload("Vet").
+		Where("status = ?", entity.AppointmentScheduled).
+		Where("start_at < ? AND end_at > ?", a.EndAt, a.StartAt).
+		Where("vet_id = ? OR dog_id = ?", a.VetID, a.DogID)
+	if a.ID != 0 {
+		q = q.Where("id <> ?", a.ID)
+	}
+	if err := q.Order("start_at ASC").Find(&rows).Error; err != nil {
+		return err
+	}
+	if len(rows) > 0 {
+		return &conflictError{conflicts: rows}
+	}
+	return nil
+}
+
+func loadVet(tx *gorm.DB, id uint) error {
+	var vet entity.Staff
+	if err := tx.First(&vet, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("invalid vet_id")
+		}
+		return err
+	}
+	return nil
+}
+
+func respondError(c *gin.Context, status int, prefix string, err error) {
+	var ce *conflictError
+	if errors.As(err, &ce) {
+		c.JSON(http.StatusConflict, gin.H{"error": prefix + err.Error(), "conflicts": ce.conflicts})
+		return
+	}
+	var fields health_records.FieldErrors
+	if errors.As(err, &fields) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": prefix + "invalid health record", "fields": fields})
+		return
+	}
+	if status == 0 && entity.IsStockError(err) {
+		status = http.StatusConflict
+	}
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+	c.JSON(status, gin.H{"error": prefix + err.Error()})
+}
+
+// POST /appointments
+func CreateAppointment(c *gin.Context) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req appointmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	start, err := parseSlot(req.StartAt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if start.Before(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "start_at cannot be in the past"})
+		return
+	}
+	duration := req.DurationMinutes
+	if duration == 0 {
+		duration = defaultDurationMinutes
+	}
+
+	a := entity.VetAppointment{
+		DogID:      req.DogID,
+		VetID:      req.VetID,
+		Reason:     strings.TrimSpace(req.Reason),
+		StartAt:    start,
+		EndAt:      start.Add(time.Duration(duration) * time.Minute),
+		Status:     entity.AppointmentScheduled,
+		Notes:      req.Notes,
+		BookedByID: staffID,
+	}
+	var status int
+	err = configs.DB().Transaction(func(tx *gorm.DB) error {
+		var dog entity.Dog
+		if err := tx.First(&dog, req.DogID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				status = http.StatusBadRequest
+				return errors.New("invalid dog_id")
+			}
+			return err
+		}
+		if dog.IsAdopted || (dog.Status != entity.DogStatusShelter && dog.Status != entity.DogStatusFoster) {
+			status = http.StatusConflict
+			return errors.New("dog is not in our care (status: " + dog.Status + ")")
+		}
+		if err := loadVet(tx, req.VetID); err != nil {
+			status = http.StatusBadRequest
+			return err
+		}
+		if err := checkConflicts(tx, &a); err != nil {
+			return err
+		}
+		return tx.Create(&a).Error
+	})
+	if err != nil {
+		respondError(c, status, "create failed: ", err)
+		return
+	}
+	if err := preloadAppointment(configs.DB()).First(&a, a.ID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": a})
+}
+
+// PUT /appointments/:id — เลื่อนนัด/เปลี่ยนสัตวแพทย์ (เฉพาะนัดที่ยังไม่ปิด)
+func UpdateAppointment(c *gin.Context) {
+	if middlewares.StaffID(c) == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req rescheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	var a entity.VetAppointment
+	var status int
+	err := configs.DB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&a, c.Param("id")).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				status = http.StatusNotFound
+				return errors.New("appointment not found")
+			}
+			return err
+		}
+		if a.Status != entity.AppointmentScheduled {
+			status = http.StatusConflict
+			return errors.New("appointment is " + a.Status)
+		}
+		duration := a.EndAt.Sub(a.StartAt)
+		if req.DurationMinutes != nil {
+			duration = time.Duration(*req.DurationMinutes) * time.Minute
+		}
+		if req.StartAt != nil {
+			start, err := parseSlot(*req.StartAt)
+			if err != nil {
+				status = http.StatusBadRequest
+				return err
+			}
+			if start.Before(time.Now()) {
+				status = http.StatusBadRequest
+				return errors.New("start_at cannot be in the past")
+			}
+			a.StartAt = start
+		}
+		a.EndAt = a.StartAt.Add(duration)
+		if req.VetID != nil {
+			if err := loadVet(tx, *req.VetID); err != nil {
+				status = http.StatusBadRequest
+				return err
+			}
+			a.VetID = *req.VetID
+		}
+		if req.Reason != nil {
+			a.Reason = strings.TrimSpace(*req.Reason)
+		}
+		if req.Notes != nil {
+			a.Notes = *req.Notes
+		}
+		if err := checkConflicts(tx, &a); err != nil {
+			return err
+		}
+		return tx.Model(&a).Updates(map[string]any{
+			"vet_id":   a.VetID,
+			"reason":   a.Reason,
+			"start_at": a.StartAt,
+			"end_at":   a.EndAt,
+			"notes":    a.Notes,
+		}).Error
+	})
+	if err != nil {
+		respondError(c, status, "update failed: ", err)
+		return
+	}
+	if err := preloadAppointment(configs.DB()).First(&a, a.ID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": a})
+}
+
+// POST /appointments/:id/cancel
+func CancelAppointment(c *gin.Context) {
+	closeAppointment(c, entity.AppointmentCancelled)
+}
+
+// POST /appointments/:id/no-show
+func MarkNoShow(c *gin.Context) {
+	closeAppointment(c, entity.AppointmentNoShow)
+}
+
+func closeAppointment(c *gin.Context, to string) {
+	if middlewares.StaffID(c) == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req closeRequest
+	_ = c.ShouldBindJSON(&req)
+	if to == entity.AppointmentCancelled && strings.TrimSpace(req.Reason) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "reason is required"})
+		return
+	}
+	var a entity.VetAppointment
+	if err := configs.DB().First(&a, c.Param("id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "appointment not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	if a.Status != entity.AppointmentScheduled {
+		c.JSON(http.StatusConflict, gin.H{"error": "appointment is " + a.Status})
+		return
+	}
+	if to == entity.AppointmentNoShow && a.StartAt.After(time.Now()) {
+		c.JSON(http.StatusConflict, gin.H{"error": "appointment has not started yet"})
+		return
+	}
+	if err := configs.DB().Model(&a).Updates(map[string]any{
+		"status":        to,
+		"cancel_reason": req.Reason,
+		"closed_at":     time.Now(),
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed: " + err.Error()})
+		return
+	}
+	if err := preloadAppointment(configs.DB()).First(&a, a.ID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": a})
+}
+
+// POST /appointments/:id/complete — ปิดนัดและสร้างประวัติสุขภาพ (ผ่าน flow เดียวกับ /health-records)
+func CompleteAppointment(c *gin.Context) {
+	if middlewares.StaffID(c) == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req completeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	vaccines := make([]entity.VaccineRecord, len(req.VaccineRecords))
+	for i, v := range req.VaccineRecords {
+		vaccines[i] = entity.VaccineRecord{VaccineID: v.VaccineID, DoseNumber: v.DoseNumber, LotNumber: v.LotNumber}
+		if v.NextDueDate == "" {
+			continue
+		}
+		due, err := time.Parse(time.RFC3339, v.NextDueDate)
+		if err != nil {
+			due, err = time.Parse("2006-01-02", v.NextDueDate)
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid vaccine_records[%d].next_due_date (YYYY-MM-DD or RFC3339)", i)})
+			return
+		}
+		vaccines[i].NextDueDate = due
+	}
+	var a entity.VetAppointment
+	var status int
+	err := configs.DB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&a, c.Param("id")).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				status = http.StatusNotFound
+				return errors.New("appointment not found")
+			}
+			return err
+		}
+		if a.Status != entity.AppointmentScheduled {
+			status = http.StatusConflict
+			return errors.New("appointment is " + a.Status)
+		}
+		if timeutil.TodayYMD() < a.StartAt.In(timeutil.TZBangkok()).Format("2006-01-02") {
+			status = http.StatusConflict
+			return errors.New("appointment is not until " + a.StartAt.In(timeutil.TZBangkok()).Format("2006-01-02"))
+		}
+
+		symptoms := req.Symptoms
+		if strings.TrimSpace(symptoms) == "" {
+			symptoms = a.Reason
+		}
+		notes := "Vet appointment #" + strconv.FormatUint(uint64(a.ID), 10)
+		if req.Notes != "" {
+			notes += ": " + req.Notes
+		}
+		record := entity.MedicalRecord{
+			DateRecord:    time.Now(),
+			Weight:        req.Weight,
+			Temperature:   req.Temperature,
+			Symptoms:      symptoms,
+			Diagnosis:     req.Diagnosis,
+			TreatmentPlan: req.Treatment,
+			Medication:    req.Medication,
+			Vaccination:   entity.VaccinationNo,
+			Notes:         notes,
+			DogID:         a.DogID,
+			StaffID:       a.VetID,
+		}
+		if len(vaccines) > 0 {
+			record.Vaccination = entity.VaccinationYes
+		}
+		if err := health_records.CreateRecord(tx, &record, vaccines); err != nil {
+			return err
+		}
+		return tx.Model(&a).Updates(map[string]any{
+			"status":            entity.AppointmentCompleted,
+			"closed_at":         time.Now(),
+			"medical_record_id": record.ID,
+		}).Error
+	})
+	if err != nil {
+		respondError(c, status, "complete failed: ", err)
+		return
+	}
+	if err := preloadAppointment(configs.DB()).Preload("MedicalRecord").Preload("MedicalRecord.VaccineRecords").
+		First(&a, a.ID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": a})
+}
+
+// GET /appointments/:id
+func GetAppointment(c *gin.Context) {
+	var a entity.VetAppointment
+	if err := preloadAppointment(configs.DB()).Preload("MedicalRecord").First(&a, c.Param("id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "appointment not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": a})
+}
+
+// GET /appointments?dog_id=&vet_id=&status=
+func GetAppointments(c *gin.Context) {
+	db := preloadAppointment(configs.DB())
+	for _, f := range []string{"dog_id", "vet_id", "status"} {
+		if v := c.Query(f); v != "" {
+			db = db.Where(f+" = ?", v)
+		}
+	}
+	var rows []entity.VetAppointment
+	if err := db.Order("start_at ASC, id ASC").Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": rows})
+}
+
+type calendarVet struct {
+	VetID        uint                    `json:"vet_id"`
+	VetName      string                  `json:"vet_name"`
+	Appointments []entity.VetAppointment `json:"appointments"`
+}
+
+type calendarDay struct {
+	Date string        `json:"date"`
+	Vets []calendarVet `json:"vets"`
+}
+
+// GET /appointments/calendar?date=YYYY-MM-DD&days=7&vet_id= — แยกตามวัน (เวลาไทย) และสัตวแพทย์
+// ไม่รวมนัดที่ยกเลิก
+func GetCalendar(c *gin.Context) {
+	dateStr := c.DefaultQuery("date", timeutil.TodayYMD())
+	from, err := time.ParseInLocation("2006-01-02", dateStr, timeutil.TZBangkok())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date (YYYY-MM-DD)"})
+		return
+	}
+	days := 1
+	if v := c.Query("days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxCalendarDays {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid days (1-31)"})
+			return
+		}
+		days = n
+	}
+	to := from.AddDate(0, 0, days)
+
+	db := configs.DB().Preload("Dog").Preload("Vet").
+		Where("start_at >= ? AND start_at < ?", from.UTC(), to.UTC()).
+		Where("status <> ?", entity.AppointmentCancelled)
+	if v := c.Query("vet_id"); v != "" {
+		db = db.Where("vet_id = ?", v)
+	}
+	var rows []entity.VetAppointment
+	if err := db.Order("start_at ASC, id ASC").Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+
+	out := make([]calendarDay, days)
+	for i := range out {
+		out[i] = calendarDay{Date: from.AddDate(0, 0, i).Format("2006-01-02"), Vets: []calendarVet{}}
+	}
+	for _, a := range rows {
+		i := int(a.StartAt.In(timeutil.TZBangkok()).Sub(from).Hours() / 24)
+		if i < 0 || i >= days {
+			continue
+		}
+		day := &out[i]
+		j := sort.Search(len(day.Vets), func(k int) bool { return day.Vets[k].VetID >= a.VetID })
+		if j == len(day.Vets) || day.Vets[j].VetID != a.VetID {
+			name := ""
+			if a.Vet != nil {
+				name = strings.TrimSpace(a.Vet.FirstName + " " + a.Vet.LastName)
+			}
+			day.Vets = append(day.Vets, calendarVet{})
+			copy(day.Vets[j+1:], day.Vets[j:])
+			day.Vets[j] = calendarVet{VetID: a.VetID, VetName: name, Appointments: []entity.VetAppointment{}}
+		}
+		day.Vets[j].Appointments = append(day.Vets[j].Appointments, a)
+	}
+	c.JSON(http.StatusOK, gin.H{"data": out})
+}
@@ -0,0 +1,62 @@
+package appointment
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/migrations"
+	"example.com/project-sa/seeds"
+	"example.com/project-sa/utils/testutil"
+)
+
+func TestMain(m *testing.M) { testutil.Main(m, migrations.AutoMigrate, seeds.SeedAll) }
+
+// วัคซีนตอนปิดนัดรับเฉพาะฟิลด์ที่กรอกได้: ID/med_id ที่ส่งมาต้องไม่ถูกใช้
+func TestCompleteAppointmentVaccines(t *testing.T) {
+	r := testutil.Router(1)
+	r.POST("/appointments", CreateAppointment)
+	r.POST("/appointments/:id/complete", CompleteAppointment)
+
+	book := func(dogID uint) uint {
+		a := testutil.MustDo(t, r, http.MethodPost, "/appointments", map[string]any{
+			"dog_id": dogID, "vet_id": 1, "reason": "vaccination",
+			"start_at": time.Now().Add(time.Minute).Format(time.RFC3339),
+		})
+		return testutil.ID(a)
+	}
+
+	id := book(2)
+	path := fmt.Sprintf("/appointments/%d/complete", id)
+	if code, out := testutil.Do(t, r, http.MethodPost, path, map[string]any{
+		"vaccine_records": []map[string]any{{"dose_number": 1}},
+	}); code != http.StatusBadRequest {
+		t.Errorf("complete without vaccine_id = %d %v, want 400", code, out)
+	}
+	if code, out := testutil.Do(t, r, http.MethodPost, path, map[string]any{
+		"vaccine_records": []map[string]any{{"vaccine_id": 1, "next_due_date": "next year"}},
+	}); code != http.StatusBadRequest {
+		t.Errorf("complete with a bad next_due_date = %d %v, want 400", code, out)
+	}
+
+	a := testutil.MustDo(t, r, http.MethodPost, path, map[string]any{
+		"weight": 12.5,
+		"vaccine_records": []map[string]any{{
+			"ID": 9999, "med_id": 9999, "vaccine_id": 1, "dose_number": 1, "next_due_date": "2030-01-15",
+		}},
+	})
+	if a["status"] != entity.AppointmentCompleted {
+		t.Fatalf("status = %v, want completed", a["status"])
+	}
+	var vrs []entity.VaccineRecord
+	configs.DB().Where("med_id = ?", uint(a["medical_record_id"].(float64))).Find(&vrs)
+	if len(vrs) != 1 {
+		t.Fatalf("vaccine records = %d, want 1", len(vrs))
+	}
+	if vrs[0].ID == 9999 || vrs[0].NextDueDate.Format("2006-01-02") != "2030-01-15" {
+		t.Errorf("vaccine record = id %d due %s, want a new id due 2030-01-15", vrs[0].ID, vrs[0].NextDueDate)
+	}
+}
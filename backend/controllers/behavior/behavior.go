@@ -0,0 +1,530 @@
+package behavior
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/middlewares"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+/* ========== DTOs ========== */
+
+type sectionInput struct {
+	Section string  `json:"section" binding:"required"`
+	Score   *int    `json:"score"`
+	Notes   *string `json:"notes"`
+}
+
+type AssessmentRequest struct {
+	AssessedAt string         `json:"assessed_at"` // "YYYY-MM-DD" หรือ RFC3339, ว่าง = วันนี้
+	Notes      *string        `json:"notes"`
+	Sections   []sectionInput `json:"sections"`
+	Complete   bool           `json:"complete"` // true = ปิดการประเมินทันที
+}
+
+type ApplyPersonalitiesRequest struct {
+	PersonalityIDs []uint `json:"personality_ids"` // ว่าง = ใช้ทุกตัวที่ระบบแนะนำ
+}
+
+/* ========== Rubric ========== */
+
+var knownSections = map[string]bool{
+	entity.AssessmentHandling:     true,
+	entity.AssessmentFoodGuarding: true,
+	entity.AssessmentDogDog:       true,
+	entity.AssessmentCat:          true,
+	entity.AssessmentChild:        true,
+}
+
+// หัวข้อที่ต้องมีคะแนนก่อน complete (cat/child อาจไม่ได้ทดสอบ)
+var requiredSections = []string{
+	entity.AssessmentHandling,
+	entity.AssessmentFoodGuarding,
+	entity.AssessmentDogDog,
+}
+
+// หัวข้อที่ถ้าได้ <= 2 ถือว่ายังไม่ปลอดภัยสำหรับการรับเลี้ยง
+var safetySections = map[string]bool{
+	entity.AssessmentHandling:     true,
+	entity.AssessmentFoodGuarding: true,
+	entity.AssessmentChild:        true,
+}
+
+const maxSectionScore = 5
+
+func validateSections(in []sectionInput) error {
+	seen := map[string]bool{}
+	for _, s := range in {
+		if !knownSections[s.Section] {
+			return fmt.Errorf("unknown section %q", s.Section)
+		}
+		if seen[s.Section] {
+			return fmt.Errorf("duplicate section %q", s.Section)
+		}
+		seen[s.Section] = true
+		if s.Score != nil && (*s.Score < 1 || *s.Score > maxSectionScore) {
+			return fmt.Errorf("%s: score must be 1-%d", s.Section, maxSectionScore)
+		}
+	}
+	return nil
+}
+
+func scoreMap(sections []entity.BehaviorAssessmentSection) map[string]int {
+	m := map[string]int{}
+	for _, s := range sections {
+		if s.Score != nil {
+			m[s.Section] = *s.Score
+		}
+	}
+	return m
+}
+
+// คำนวณคะแนนรวม + ความปลอดภัย + ข้อจำกัดบ้านผู้รับเลี้ยง
+func evaluate(sections []entity.BehaviorAssessmentSection) (total, max int, safe bool, restrictions []string) {
+	scores := scoreMap(sections)
+	safe = true
+	for sec, v := range scores {
+		total += v
+		max += maxSectionScore
+		if v == 1 || (safetySections[sec] && v <= 2) {
+			safe = false
+		}
+	}
+	if v, ok := scores[entity.AssessmentDogDog]; ok && v <= 2 {
+		restrictions = append(restrictions, "no_other_dogs")
+	}
+	if v, ok := scores[entity.AssessmentCat]; ok && v <= 2 {
+		restrictions = append(restrictions, "no_cats")
+	}
+	if v, ok := scores[entity.AssessmentChild]; !ok || v <= 3 {
+		restrictions = append(restrictions, "adults_only")
+	}
+	return total, max, safe, restrictions
+}
+
+func suggestPersonalities(db *gorm.DB, sections []entity.BehaviorAssessmentSection) ([]entity.Personality, error) {
+	scores := scoreMap(sections)
+	names := []string{}
+	for name, mins := range entity.PersonalityScoreMinimums {
+		match := true
+		for sec, min := range mins {
+			if v, ok := scores[sec]; !ok || v < min {
+				match = false
+				break
+			}
+		}
+		if match {
+			names = append(names, name)
+		}
+	}
+	out := []entity.Personality{}
+	if len(names) == 0 {
+		return out, nil
+	}
+	if err := db.Where("name IN ?", names).Order("id ASC").Find(&out).Error; err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func parseAssessedAt(s string) (time.Time, error) {
+	if s == "" {
+		return time.Now(), nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.New("invalid assessed_at (YYYY-MM-DD or RFC3339)")
+}
+
+func toSections(in []sectionInput) []entity.BehaviorAssessmentSection {
+	rows := make([]entity.BehaviorAssessmentSection, 0, len(in))
+	for _, s := range in {
+		rows = append(rows, entity.BehaviorAssessmentSection{Section: s.Section, Score: s.Score, Notes: s.Notes})
+	}
+	return rows
+}
+
+// ปิดการประเมิน: คำนวณผล และถ้าไม่ปลอดภัยจะปลด ready_to_adopt ของสุนัข
+func completeAssessment(tx *gorm.DB, a *entity.BehaviorAssessment, staffID uint) error {
+	scores := scoreMap(a.Sections)
+	for _, sec := range requiredSections {
+		if _, ok := scores[sec]; !ok {
+			return fmt.Errorf("section %s must be scored before completing", sec)
+		}
+	}
+
+	total, max, safe, restrictions := evaluate(a.Sections)
+	now := time.Now()
+	var restr *string
+	if len(restrictions) > 0 {
+		s := strings.Join(restrictions, ",")
+		restr = &s
+	}
+	if err := tx.Model(a).Updates(map[string]any{
+		"status":            entity.AssessmentCompleted,
+		"completed_at":      now,
+		"total_score":       total,
+		"max_score":         max,
+		"safe_for_adoption": safe,
+		"restrictions":      restr,
+	}).Error; err != nil {
+		return err
+	}
+
+	if safe {
+		return nil
+	}
+	var dog entity.Dog
+	if err := tx.First(&dog, a.DogID).Error; err != nil {
+		return err
+	}
+	if !dog.ReadyToAdopt {
+		return nil
+	}
+	if err := tx.Model(&dog).Updates(map[string]any{
+		"ready_to_adopt": false,
+		"updated_by_id":  staffID,
+	}).Error; err != nil {
+		return err
+	}
+	return entity.RecordDogChange(tx, dog.ID, "ready_to_adopt", true, false, &staffID)
+}
+
+func loadAssessment(db *gorm.DB, id string) (entity.BehaviorAssessment, error) {
+	var a entity.BehaviorAssessment
+	err := db.Preload("Sections").Preload("Assessor").First(&a, id).Error
+	return a, err
+}
+
+func respondAssessment(c *gin.Context, status int, a entity.BehaviorAssessment) {
+	suggested, err := suggestPersonalities(configs.DB(), a.Sections)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(status, gin.H{"data": a, "suggested_personalities": suggested})
+}
+
+/* ========== Handlers ========== */
+
+// POST /dogs/:id/assessments
+func CreateAssessment(c *gin.Context) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+
+	var req AssessmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	if err := validateSections(req.Sections); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	at, err := parseAssessedAt(req.AssessedAt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := configs.DB()
+	var dog entity.Dog
+	if err := db.First(&dog, c.Param("id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "dog not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+
+	a := entity.BehaviorAssessment{
+		DogID:      dog.ID,
+		AssessorID: *staffID,
+		AssessedAt: at,
+		Status:     entity.AssessmentDraft,
+		Notes:      req.Notes,
+		Sections:   toSections(req.Sections),
+	}
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&a).Error; err != nil {
+			return err
+		}
+		if req.Complete {
+			return completeAssessment(tx, &a, *staffID)
+		}
+		return nil
+	}); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "create failed: " + err.Error()})
+		return
+	}
+
+	out, err := loadAssessment(db, fmt.Sprint(a.ID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	respondAssessment(c, http.StatusCreated, out)
+}
+
+// PUT /assessments/:id (แก้ได้เฉพาะ draft)
+func UpdateAssessment(c *gin.Context) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+
+	db := configs.DB()
+	a, err := loadAssessment(db, c.Param("id"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "assessment not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	if a.Status != entity.AssessmentDraft {
+		c.JSON(http.StatusConflict, gin.H{"error": "completed assessments cannot be edited; create a re-assessment"})
+		return
+	}
+
+	var req AssessmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	if err := validateSections(req.Sections); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	// ไม่ส่ง notes/assessed_at = คงค่าเดิม
+	updates := map[string]any{}
+	if req.Notes != nil {
+		updates["notes"] = *req.Notes
+	}
+	if req.AssessedAt != "" {
+		at, err := parseAssessedAt(req.AssessedAt)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		updates["assessed_at"] = at
+	}
+
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		if len(updates) > 0 {
+			if err := tx.Model(&a).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+		if req.Sections != nil {
+			if err := tx.Unscoped().Where("assessment_id = ?", a.ID).
+				Delete(&entity.BehaviorAssessmentSection{}).Error; err != nil {
+				return err
+			}
+			rows := toSections(req.Sections)
+			for i := range rows {
+				rows[i].AssessmentID = a.ID
+			}
+			if len(rows) > 0 {
+				if err := tx.Create(&rows).Error; err != nil {
+					return err
+				}
+			}
+			a.Sections = rows
+		}
+		if req.Complete {
+			return completeAssessment(tx, &a, *staffID)
+		}
+		return nil
+	}); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "update failed: " + err.Error()})
+		return
+	}
+
+	out, err := loadAssessment(db, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	respondAssessment(c, http.StatusOK, out)
+}
+
+// POST /assessments/:id/complete
+func CompleteAssessment(c *gin.Context) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+
+	db := configs.DB()
+	a, err := loadAssessment(db, c.Param("id"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "assessment not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	if a.Status == entity.AssessmentCompleted {
+		c.JSON(http.StatusConflict, gin.H{"error": "assessment already completed"})
+		return
+	}
+
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		return completeAssessment(tx, &a, *staffID)
+	}); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "complete failed: " + err.Error()})
+		return
+	}
+
+	out, err := loadAssessment(db, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	respondAssessment(c, http.StatusOK, out)
+}
+
+// GET /assessments/:id
+func GetAssessment(c *gin.Context) {
+	a, err := loadAssessment(configs.DB(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "assessment not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	respondAssessment(c, http.StatusOK, a)
+}
+
+// GET /dogs/:id/assessments — ประวัติการประเมินทั้งหมด (ใหม่สุดก่อน) + ผลล่าสุด
+func GetDogAssessments(c *gin.Context) {
+	var list []entity.BehaviorAssessment
+	if err := configs.DB().
+		Preload("Sections").
+		Preload("Assessor").
+		Where("dog_id = ?", c.Param("id")).
+		Order("assessed_at DESC, id DESC").
+		Find(&list).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+
+	var latest *entity.BehaviorAssessment
+	for i := range list {
+		if list[i].Status == entity.AssessmentCompleted {
+			latest = &list[i]
+			break
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list, "latest_completed": latest})
+}
+
+// POST /assessments/:id/apply-personalities — เพิ่ม personality ที่แนะนำให้สุนัข
+func ApplySuggestedPersonalities(c *gin.Context) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+
+	db := configs.DB()
+	a, err := loadAssessment(db, c.Param("id"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "assessment not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	if a.Status != entity.AssessmentCompleted {
+		c.JSON(http.StatusConflict, gin.H{"error": "assessment is not completed"})
+		return
+	}
+
+	var req ApplyPersonalitiesRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+
+	suggested, err := suggestPersonalities(db, a.Sections)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	allowed := map[uint]bool{}
+	for _, p := range suggested {
+		allowed[p.ID] = true
+	}
+	ids := req.PersonalityIDs
+	if len(ids) == 0 {
+		for _, p := range suggested {
+			ids = append(ids, p.ID)
+		}
+	}
+	for _, id := range ids {
+		if !allowed[id] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("personality %d is not suggested by this assessment", id)})
+			return
+		}
+	}
+
+	added := []uint{}
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		var before []uint
+		if err := tx.Model(&entity.DogPersonality{}).
+			Where("dog_id = ?", a.DogID).
+			Pluck("personality_id", &before).Error; err != nil {
+			return err
+		}
+		for _, pid := range ids {
+			var n int64
+			if err := tx.Model(&entity.DogPersonality{}).
+				Where("dog_id = ? AND personality_id = ?", a.DogID, pid).
+				Count(&n).Error; err != nil {
+				return err
+			}
+			if n > 0 {
+				continue
+			}
+			if err := tx.Create(&entity.DogPersonality{DogID: a.DogID, PersonalityID: pid}).Error; err != nil {
+				return err
+			}
+			added = append(added, pid)
+		}
+		if len(added) == 0 {
+			return nil
+		}
+		if err := entity.RecordDogChange(tx, a.DogID, "personality_ids", before, append(append([]uint{}, before...), added...), staffID); err != nil {
+			return err
+		}
+		return tx.Model(&entity.Dog{}).Where("id = ?", a.DogID).Update("updated_by_id", *staffID).Error
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "apply failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"dog_id": a.DogID, "added_personality_ids": added})
+}
@@ -0,0 +1,72 @@
+package behavior
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/migrations"
+	"example.com/project-sa/seeds"
+	"example.com/project-sa/utils/testutil"
+	"github.com/gin-gonic/gin"
+)
+
+func TestMain(m *testing.M) { testutil.Main(m, migrations.AutoMigrate, seeds.SeedAll) }
+
+func TestAssessmentFlow(t *testing.T) {
+	db := configs.DB()
+	dog := entity.Dog{Name: "Assess", BreedID: 1, AnimalSexID: 1, AnimalSizeID: 1, Status: entity.DogStatusShelter, ReadyToAdopt: true}
+	if err := db.Create(&dog).Error; err != nil {
+		t.Fatalf("create dog: %v", err)
+	}
+
+	r := testutil.Router(1)
+	r.POST("/dogs/:id/assessments", CreateAssessment)
+	r.PUT("/assessments/:id", UpdateAssessment)
+
+	create := fmt.Sprintf("/dogs/%d/assessments", dog.ID)
+	if code, _ := testutil.Do(t, r, http.MethodPost, create, gin.H{"assessed_at": "10/06/2025"}); code != http.StatusBadRequest {
+		t.Errorf("create with bad assessed_at = %d, want %d", code, http.StatusBadRequest)
+	}
+	data := testutil.MustDo(t, r, http.MethodPost, create, gin.H{
+		"assessed_at": "2025-06-10",
+		"sections":    []gin.H{{"section": entity.AssessmentHandling, "score": 4}},
+	})
+	update := fmt.Sprintf("/assessments/%d", testutil.ID(data))
+
+	if code, _ := testutil.Do(t, r, http.MethodPut, update, gin.H{"assessed_at": "yesterday"}); code != http.StatusBadRequest {
+		t.Errorf("update with bad assessed_at = %d, want %d", code, http.StatusBadRequest)
+	}
+	if code, _ := testutil.Do(t, r, http.MethodPut, update, gin.H{"complete": true}); code != http.StatusUnprocessableEntity {
+		t.Errorf("complete without required sections = %d, want %d", code, http.StatusUnprocessableEntity)
+	}
+
+	testutil.MustDo(t, r, http.MethodPut, update, gin.H{
+		"assessed_at": "2025-06-11",
+		"complete":    true,
+		"sections": []gin.H{
+			{"section": entity.AssessmentHandling, "score": 4},
+			{"section": entity.AssessmentFoodGuarding, "score": 1},
+			{"section": entity.AssessmentDogDog, "score": 4},
+		},
+	})
+	var a entity.BehaviorAssessment
+	if err := db.First(&a, testutil.ID(data)).Error; err != nil {
+		t.Fatal(err)
+	}
+	if a.Status != entity.AssessmentCompleted || a.SafeForAdoption == nil || *a.SafeForAdoption || a.AssessedAt.Format("2006-01-02") != "2025-06-11" {
+		t.Errorf("assessment = status %q safe %v at %s, want completed, unsafe, 2025-06-11", a.Status, a.SafeForAdoption, a.AssessedAt)
+	}
+	var got entity.Dog
+	if err := db.First(&got, dog.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if got.ReadyToAdopt {
+		t.Error("dog still ready_to_adopt after an unsafe assessment")
+	}
+	if code, _ := testutil.Do(t, r, http.MethodPut, update, gin.H{"notes": "late edit"}); code != http.StatusConflict {
+		t.Errorf("edit completed assessment = %d, want %d", code, http.StatusConflict)
+	}
+}
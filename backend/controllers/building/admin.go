@@ -0,0 +1,159 @@
+package building
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/middlewares"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type buildingRequest struct {
+	BuildingName string `json:"building_name" binding:"required"`
+	Size         string `json:"size"` // small, medium, large
+}
+
+func validateBuilding(tx *gorm.DB, id uint, req *buildingRequest) (int, error) {
+	req.BuildingName = strings.TrimSpace(req.BuildingName)
+	if req.BuildingName == "" {
+		return http.StatusBadRequest, errors.New("building_name is required")
+	}
+	switch req.Size {
+	case "", "small", "medium", "large":
+	default:
+		return http.StatusBadRequest, errors.New("size must be small, medium or large")
+	}
+	var dup int64
+	if err := tx.Model(&entity.Building{}).
+		Where("LOWER(building_name) = LOWER(?) AND id <> ?", req.BuildingName, id).Count(&dup).Error; err != nil {
+		return 0, err
+	}
+	if dup > 0 {
+		return http.StatusConflict, errors.New("building " + req.BuildingName + " already exists")
+	}
+	return 0, nil
+}
+
+// GET /buildings/:id — พร้อมโซนในอาคาร
+func GetBuildingById(c *gin.Context) {
+	var b entity.Building
+	if err := configs.DB().Preload("Zones", func(db *gorm.DB) *gorm.DB { return db.Order("name ASC") }).
+		First(&b, c.Param("id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "building not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": b})
+}
+
+// POST /buildings
+func CreateBuilding(c *gin.Context) {
+	if middlewares.StaffID(c) == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req buildingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	var b entity.Building
+	var status int
+	err := configs.DB().Transaction(func(tx *gorm.DB) error {
+		var err error
+		if status, err = validateBuilding(tx, 0, &req); err != nil {
+			return err
+		}
+		b = entity.Building{BuildingName: req.BuildingName, Size: req.Size}
+		return tx.Create(&b).Error
+	})
+	if err != nil {
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, gin.H{"error": "create failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": b})
+}
+
+// PUT /buildings/:id
+func UpdateBuilding(c *gin.Context) {
+	if middlewares.StaffID(c) == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req buildingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	var b entity.Building
+	var status int
+	err := configs.DB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&b, c.Param("id")).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				status = http.StatusNotFound
+				return errors.New("building not found")
+			}
+			return err
+		}
+		var err error
+		if status, err = validateBuilding(tx, b.ID, &req); err != nil {
+			return err
+		}
+		return tx.Model(&b).Updates(map[string]any{"building_name": req.BuildingName, "size": req.Size}).Error
+	})
+	if err != nil {
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, gin.H{"error": "update failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": b})
+}
+
+// DELETE /buildings/:id — ลบไม่ได้ถ้ายังมีโซนผูกอยู่
+func DeleteBuilding(c *gin.Context) {
+	if middlewares.StaffID(c) == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var status int
+	err := configs.DB().Transaction(func(tx *gorm.DB) error {
+		var b entity.Building
+		if err := tx.First(&b, c.Param("id")).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				status = http.StatusNotFound
+				return errors.New("building not found")
+			}
+			return err
+		}
+		var zones int64
+		if err := tx.Model(&entity.Zone{}).Where("building_id = ?", b.ID).Count(&zones).Error; err != nil {
+			return err
+		}
+		if zones > 0 {
+			status = http.StatusConflict
+			return fmt.Errorf("building still has %d zone(s)", zones)
+		}
+		return tx.Delete(&b).Error
+	})
+	if err != nil {
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, gin.H{"error": "delete failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "building deleted"})
+}
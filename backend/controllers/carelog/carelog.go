@@ -0,0 +1,454 @@
+package carelog
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/utils/httpparse"
+	"example.com/project-sa/utils/timeutil"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+/* ========== DTOs ========== */
+
+type careLogInput struct {
+	Type            string   `json:"type"`
+	LoggedAt        string   `json:"logged_at"` // RFC3339, ว่าง = ตอนนี้
+	AmountGrams     *float64 `json:"amount_grams"`
+	DurationMinutes *int     `json:"duration_minutes"`
+	StoolScore      *int     `json:"stool_score"`
+	Note            *string  `json:"note"`
+}
+
+type CareLogCreateRequest struct {
+	DogID uint `json:"dog_id" binding:"required"`
+	careLogInput
+}
+
+// ค่าเฉพาะรายตัว (ทับค่ากลางของ bulk)
+type careLogOverride struct {
+	DogID           uint     `json:"dog_id" binding:"required"`
+	AmountGrams     *float64 `json:"amount_grams"`
+	DurationMinutes *int     `json:"duration_minutes"`
+	StoolScore      *int     `json:"stool_score"`
+	Note            *string  `json:"note"`
+}
+
+type KennelCareLogRequest struct {
+	careLogInput
+	ExcludeDogIDs []uint            `json:"exclude_dog_ids"`
+	PerDog        []careLogOverride `json:"per_dog"`
+}
+
+type DailyCareSummary struct {
+	DogID         uint       `json:"dog_id"`
+	DogName       string     `json:"dog_name"`
+	KennelID      *uint      `json:"kennel_id"`
+	Date          string     `json:"date"`
+	Meals         int        `json:"meals"`
+	FoodGrams     float64    `json:"food_grams"`
+	LastFedAt     *time.Time `json:"last_fed_at"`
+	Walks         int        `json:"walks"`
+	WalkMinutes   int        `json:"walk_minutes"`
+	StoolEntries  int        `json:"stool_entries"`
+	AbnormalStool int        `json:"abnormal_stool"`
+	Enrichments   int        `json:"enrichments"`
+	BehaviorNotes []string   `json:"behavior_notes"`
+	MissedMeals   bool       `json:"missed_meals"`
+}
+
+/* ========== Helpers ========== */
+
+// stool score ตั้งแต่ค่านี้ขึ้นไปถือว่าผิดปกติ (ท้องเสีย)
+const abnormalStoolScore = 6
+
+func validateInput(in careLogInput) error {
+	switch in.Type {
+	case entity.CareLogFeeding:
+		if in.AmountGrams == nil || *in.AmountGrams <= 0 {
+			return errors.New("amount_grams is required for feeding")
+		}
+	case entity.CareLogWalk:
+		if in.DurationMinutes == nil || *in.DurationMinutes <= 0 {
+			return errors.New("duration_minutes is required for walk")
+		}
+	case entity.CareLogStool:
+		if in.StoolScore == nil || *in.StoolScore < 1 || *in.StoolScore > 7 {
+			return errors.New("stool_score (1-7) is required for stool")
+		}
+	case entity.CareLogBehavior:
+		if in.Note == nil || strings.TrimSpace(*in.Note) == "" {
+			return errors.New("note is required for behavior")
+		}
+	case entity.CareLogEnrichment:
+		if (in.Note == nil || strings.TrimSpace(*in.Note) == "") && in.DurationMinutes == nil {
+			return errors.New("note or duration_minutes is required for enrichment")
+		}
+	default:
+		return fmt.Errorf("invalid type %q", in.Type)
+	}
+	return nil
+}
+
+// sqlite เทียบเวลาแบบข้อความ เก็บ/ค้นเป็น UTC ทั้งหมด
+func parseLoggedAt(s string) (time.Time, error) {
+	if s == "" {
+		return time.Now().UTC(), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, errors.New("invalid logged_at (RFC3339)")
+	}
+	return t.UTC(), nil
+}
+
+// ช่วงเวลาของวัน (เวลาไทย) จาก ?date=YYYY-MM-DD
+func dayRange(c *gin.Context) (string, time.Time, time.Time, error) {
+	date := c.Query("date")
+	if date == "" {
+		date = timeutil.TodayYMD()
+	}
+	start, err := time.ParseInLocation("2006-01-02", date, timeutil.TZBangkok())
+	if err != nil {
+		return "", time.Time{}, time.Time{}, errors.New("invalid date (YYYY-MM-DD)")
+	}
+	return date, start.UTC(), start.AddDate(0, 0, 1).UTC(), nil
+}
+
+// ผู้บันทึก: staff หรือ volunteer ที่อนุมัติแล้ว
+func resolveRecorder(c *gin.Context) (staffID *uint, volunteerID *uint, ok bool) {
+	if v, exists := c.Get("staff_id"); exists {
+		if id, ok2 := v.(uint); ok2 && id > 0 {
+			return &id, nil, true
+		}
+	}
+	v, exists := c.Get("user_id")
+	if !exists {
+		return nil, nil, false
+	}
+	uid, ok2 := v.(uint)
+	if !ok2 || uid == 0 {
+		return nil, nil, false
+	}
+	var vol entity.Volunteer
+	if err := configs.DB().
+		Joins("JOIN status_fvs ON status_fvs.id = volunteers.status_fv_id").
+		Where("volunteers.user_id = ? AND status_fvs.status = ?", uid, "approved").
+		Order("volunteers.id DESC").
+		First(&vol).Error; err != nil {
+		return nil, nil, false
+	}
+	return nil, &vol.ID, true
+}
+
+func newCareLog(dog entity.Dog, in careLogInput, at time.Time, staffID, volunteerID *uint) entity.CareLog {
+	return entity.CareLog{
+		Type:            in.Type,
+		LoggedAt:        at,
+		AmountGrams:     in.AmountGrams,
+		DurationMinutes: in.DurationMinutes,
+		StoolScore:      in.StoolScore,
+		Note:            in.Note,
+		DogID:           dog.ID,
+		KennelID:        dog.KennelID,
+		StaffID:         staffID,
+		VolunteerID:     volunteerID,
+	}
+}
+
+func summarize(dog entity.Dog, date string, logs []entity.CareLog) DailyCareSummary {
+	s := DailyCareSummary{
+		DogID:         dog.ID,
+		DogName:       dog.Name,
+		KennelID:      dog.KennelID,
+		Date:          date,
+		BehaviorNotes: []string{},
+	}
+	for i := range logs {
+		l := logs[i]
+		switch l.Type {
+		case entity.CareLogFeeding:
+			s.Meals++
+			if l.AmountGrams != nil {
+				s.FoodGrams += *l.AmountGrams
+			}
+			if s.LastFedAt == nil || l.LoggedAt.After(*s.LastFedAt) {
+				s.LastFedAt = &logs[i].LoggedAt
+			}
+		case entity.CareLogWalk:
+			s.Walks++
+			if l.DurationMinutes != nil {
+				s.WalkMinutes += *l.DurationMinutes
+			}
+		case entity.CareLogStool:
+			s.StoolEntries++
+			if l.StoolScore != nil && *l.StoolScore >= abnormalStoolScore {
+				s.AbnormalStool++
+			}
+		case entity.CareLogBehavior:
+			if l.Note != nil {
+				s.BehaviorNotes = append(s.BehaviorNotes, *l.Note)
+			}
+		case entity.CareLogEnrichment:
+			s.Enrichments++
+		}
+	}
+	s.MissedMeals = s.Meals == 0
+	return s
+}
+
+/* ========== Handlers ========== */
+
+// POST /care-logs
+func CreateCareLog(c *gin.Context) {
+	staffID, volunteerID, ok := resolveRecorder(c)
+	if !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "only staff or approved volunteers can record care logs"})
+		return
+	}
+
+	var req CareLogCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	if err := validateInput(req.careLogInput); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	at, err := parseLoggedAt(req.LoggedAt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := configs.DB()
+	var dog entity.Dog
+	if err := db.First(&dog, req.DogID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "dog not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+
+	row := newCareLog(dog, req.careLogInput, at, staffID, volunteerID)
+	if err := db.Create(&row).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "create failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": row})
+}
+
+// POST /kennels/:id/care-logs — บันทึกครั้งเดียวให้สุนัขทุกตัวในคอก
+func BulkCreateKennelCareLogs(c *gin.Context) {
+	staffID, volunteerID, ok := resolveRecorder(c)
+	if !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "only staff or approved volunteers can record care logs"})
+		return
+	}
+	kennelID, err := httpparse.ParamUint(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req KennelCareLogRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	at, err := parseLoggedAt(req.LoggedAt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := configs.DB()
+	var kennel entity.Kennel
+	if err := db.First(&kennel, kennelID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "kennel not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "kennel query failed: " + err.Error()})
+		return
+	}
+
+	var dogs []entity.Dog
+	q := db.Where("kennel_id = ?", kennelID)
+	if len(req.ExcludeDogIDs) > 0 {
+		q = q.Where("id NOT IN ?", req.ExcludeDogIDs)
+	}
+	if err := q.Order("id ASC").Find(&dogs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	if len(dogs) == 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "no dogs in kennel"})
+		return
+	}
+
+	overrides := map[uint]careLogOverride{}
+	for _, o := range req.PerDog {
+		overrides[o.DogID] = o
+	}
+
+	rows := make([]entity.CareLog, 0, len(dogs))
+	for _, d := range dogs {
+		in := req.careLogInput
+		if o, found := overrides[d.ID]; found {
+			if o.AmountGrams != nil {
+				in.AmountGrams = o.AmountGrams
+			}
+			if o.DurationMinutes != nil {
+				in.DurationMinutes = o.DurationMinutes
+			}
+			if o.StoolScore != nil {
+				in.StoolScore = o.StoolScore
+			}
+			if o.Note != nil {
+				in.Note = o.Note
+			}
+		}
+		if err := validateInput(in); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("dog %d: %s", d.ID, err.Error())})
+			return
+		}
+		rows = append(rows, newCareLog(d, in, at, staffID, volunteerID))
+	}
+
+	if err := db.Create(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "create failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"created": len(rows), "data": rows})
+}
+
+// GET /dogs/:id/care-logs?date=YYYY-MM-DD
+func GetDogCareLogs(c *gin.Context) {
+	date, start, end, err := dayRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := configs.DB()
+	var dog entity.Dog
+	if err := db.First(&dog, c.Param("id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "dog not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+
+	q := db.Preload("Staff").Preload("Volunteer.User").
+		Where("dog_id = ? AND logged_at >= ? AND logged_at < ?", dog.ID, start, end)
+	if t := c.Query("type"); t != "" {
+		q = q.Where("type = ?", t)
+	}
+	var logs []entity.CareLog
+	if err := q.Order("logged_at ASC").Find(&logs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": logs, "summary": summarize(dog, date, logs)})
+}
+
+// GET /zones/:id/care-summary?date=YYYY-MM-DD
+func GetZoneCareSummary(c *gin.Context) {
+	date, start, end, err := dayRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := configs.DB()
+	var zone entity.Zone
+	if err := db.First(&zone, c.Param("id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "zone not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+
+	var kennelIDs []uint
+	if err := db.Model(&entity.Kennel{}).Where("zone_id = ?", zone.ID).Pluck("id", &kennelIDs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "kennels query failed: " + err.Error()})
+		return
+	}
+
+	// สุนัขที่อยู่ในโซนตอนนี้ + สุนัขที่ถูกบันทึกในโซนวันนั้น (เผื่อย้ายคอกไปแล้ว)
+	var logs []entity.CareLog
+	if err := db.Where("kennel_id IN ? AND logged_at >= ? AND logged_at < ?", kennelIDs, start, end).
+		Or("dog_id IN (?) AND logged_at >= ? AND logged_at < ?",
+			db.Model(&entity.Dog{}).Select("id").Where("kennel_id IN ?", kennelIDs), start, end).
+		Order("logged_at ASC").
+		Find(&logs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	byDog := map[uint][]entity.CareLog{}
+	for _, l := range logs {
+		byDog[l.DogID] = append(byDog[l.DogID], l)
+	}
+	dogIDs := make([]uint, 0, len(byDog))
+	for id := range byDog {
+		dogIDs = append(dogIDs, id)
+	}
+
+	var dogs []entity.Dog
+	if err := db.Where("kennel_id IN ?", kennelIDs).Or("id IN ?", dogIDs).Find(&dogs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	sort.Slice(dogs, func(i, j int) bool { return dogs[i].ID < dogs[j].ID })
+
+	items := make([]DailyCareSummary, 0, len(dogs))
+	missed := 0
+	for _, d := range dogs {
+		s := summarize(d, date, byDog[d.ID])
+		if s.MissedMeals {
+			missed++
+		}
+		items = append(items, s)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"zone":         zone,
+		"date":         date,
+		"data":         items,
+		"missed_meals": missed,
+	})
+}
+
+// DELETE /care-logs/:id (staff เท่านั้น ใช้แก้บันทึกผิด)
+func DeleteCareLog(c *gin.Context) {
+	if v, ok := c.Get("staff_id"); !ok || v == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	res := configs.DB().Delete(&entity.CareLog{}, c.Param("id"))
+	if res.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": res.Error.Error()})
+		return
+	}
+	if res.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "care log not found"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,90 @@
+package carelog
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/migrations"
+	"example.com/project-sa/seeds"
+	"example.com/project-sa/utils/testutil"
+	"github.com/gin-gonic/gin"
+)
+
+func TestMain(m *testing.M) { testutil.Main(m, migrations.AutoMigrate, seeds.SeedAll) }
+
+func careRouter(staffID uint) *gin.Engine {
+	r := testutil.Router(staffID)
+	r.POST("/care-logs", CreateCareLog)
+	r.POST("/kennels/:id/care-logs", BulkCreateKennelCareLogs)
+	r.GET("/dogs/:id/care-logs", GetDogCareLogs)
+	r.GET("/zones/:id/care-summary", GetZoneCareSummary)
+	return r
+}
+
+func TestCareLogsAndDailySummary(t *testing.T) {
+	db := configs.DB()
+	zone := entity.Zone{Name: "Care zone"}
+	if err := db.Create(&zone).Error; err != nil {
+		t.Fatal(err)
+	}
+	kennel := entity.Kennel{Name: "CARE-1", Capacity: 4, ZoneID: zone.ID}
+	if err := db.Create(&kennel).Error; err != nil {
+		t.Fatal(err)
+	}
+	var dogs []entity.Dog
+	for _, name := range []string{"Care A", "Care B"} {
+		d := entity.Dog{Name: name, KennelID: &kennel.ID, BreedID: 1, AnimalSexID: 1, AnimalSizeID: 1}
+		if err := db.Create(&d).Error; err != nil {
+			t.Fatal(err)
+		}
+		dogs = append(dogs, d)
+	}
+
+	if code, _ := testutil.Do(t, careRouter(0), http.MethodPost, "/care-logs",
+		gin.H{"dog_id": dogs[0].ID, "type": "walk", "duration_minutes": 20}); code != http.StatusForbidden {
+		t.Errorf("care log without login = %d, want %d", code, http.StatusForbidden)
+	}
+
+	r := careRouter(1)
+	if code, _ := testutil.Do(t, r, http.MethodPost, "/care-logs",
+		gin.H{"dog_id": dogs[0].ID, "type": "feeding"}); code != http.StatusBadRequest {
+		t.Errorf("feeding without amount = %d, want %d", code, http.StatusBadRequest)
+	}
+
+	// 03:00 เวลาไทยของวันที่ 10 = 20:00 UTC ของวันที่ 9
+	code, out := testutil.Do(t, r, http.MethodPost, fmt.Sprintf("/kennels/%d/care-logs", kennel.ID), gin.H{
+		"type": "feeding", "amount_grams": 200, "logged_at": "2025-06-09T20:00:00Z",
+		"per_dog": []gin.H{{"dog_id": dogs[1].ID, "amount_grams": 150}},
+	})
+	if code != http.StatusCreated || out["created"] != float64(2) {
+		t.Fatalf("bulk feeding = %d %v, want 201 created 2", code, out)
+	}
+	for _, body := range []gin.H{
+		{"dog_id": dogs[0].ID, "type": "walk", "duration_minutes": 30, "logged_at": "2025-06-10T02:00:00Z"},
+		{"dog_id": dogs[0].ID, "type": "stool", "stool_score": 7, "logged_at": "2025-06-10T03:00:00Z"},
+		{"dog_id": dogs[0].ID, "type": "behavior", "note": "ขู่ตอนให้อาหาร", "logged_at": "2025-06-10T04:00:00Z"},
+		// วันถัดไปตามเวลาไทย ไม่นับในวันที่ 10
+		{"dog_id": dogs[0].ID, "type": "walk", "duration_minutes": 15, "logged_at": "2025-06-10T18:00:00Z"},
+	} {
+		testutil.MustDo(t, r, http.MethodPost, "/care-logs", body)
+	}
+
+	_, out = testutil.Do(t, r, http.MethodGet, fmt.Sprintf("/dogs/%d/care-logs?date=2025-06-10", dogs[0].ID), nil)
+	s, _ := out["summary"].(map[string]any)
+	if s["meals"] != float64(1) || s["food_grams"] != float64(200) || s["walks"] != float64(1) ||
+		s["walk_minutes"] != float64(30) || s["abnormal_stool"] != float64(1) || s["missed_meals"] != false {
+		t.Errorf("dog summary = %v, want 1 meal 200g, 1 walk 30 min, 1 abnormal stool", s)
+	}
+
+	_, out = testutil.Do(t, r, http.MethodGet, fmt.Sprintf("/zones/%d/care-summary?date=2025-06-10", zone.ID), nil)
+	items, _ := out["data"].([]any)
+	if len(items) != 2 || out["missed_meals"] != float64(0) {
+		t.Fatalf("zone summary = %v, want 2 dogs and no missed meals", out)
+	}
+	if b := items[1].(map[string]any); b["food_grams"] != float64(150) {
+		t.Errorf("per-dog override food_grams = %v, want 150", b["food_grams"])
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"net/http"
 
 	"example.com/project-sa/configs"
+	health_records "example.com/project-sa/controllers/health_record"
+	"example.com/project-sa/controllers/vaccination"
 	"example.com/project-sa/entity"
 	"github.com/gin-gonic/gin"
 )
@@ -14,6 +16,9 @@ type DashboardStats struct {
 	TotalMoneyDonated float64 `json:"total_money_donated"`
 	TotalItemsDonated int64   `json:"total_items_donated"`
 	VaccinationsGiven int64   `json:"vaccinations_given"`
+	VaccinationsDue   int64   `json:"vaccinations_due"`
+	VaccinationsLate  int64   `json:"vaccinations_overdue"`
+	HealthAlertsOpen  int64   `json:"health_alerts_open"`
 	DogsSponsored     int64   `json:"dogs_sponsored"`
 }
 
@@ -23,8 +28,10 @@ func GetDashboardStats(c *gin.Context) {
 
 	var stats DashboardStats
 
-	// 1. จำนวนสุนัขที่อยู่ในสถานรับเลี้ยง (is_adopted = false)
-	db.Model(&entity.Dog{}).Where("is_adopted = ?", false).Count(&stats.DogsInShelter)
+	// 1. จำนวนสุนัขที่อยู่ในความดูแล (ยังไม่ถูกรับเลี้ยง, อยู่ในศูนย์หรือบ้านอุปถัมภ์ — ไม่นับที่เสียชีวิต/ส่งต่อ)
+	db.Model(&entity.Dog{}).
+		Where("is_adopted = ? AND status IN ?", false, []string{entity.DogStatusShelter, entity.DogStatusFoster}).
+		Count(&stats.DogsInShelter)
 
 	// 2. จำนวนสุนัขที่รับเลี้ยงไปแล้ว (is_adopted = true)
 	db.Model(&entity.Dog{}).Where("is_adopted = ?", true).Count(&stats.DogsAdopted)
@@ -35,8 +42,23 @@ func GetDashboardStats(c *gin.Context) {
 	// 4. จำนวนสิ่งของที่ได้รับบริจาค (ผลรวม quantity จาก item_donations)
 	db.Model(&entity.ItemDonation{}).Select("COALESCE(SUM(quantity), 0)").Scan(&stats.TotalItemsDonated)
 
-	// 5. จำนวนวัคซีนที่ฉีดให้สุนัข (นับ vaccination = "YES" จาก medical_records)
-	db.Model(&entity.MedicalRecord{}).Where("vaccination = ?", "YES").Count(&stats.VaccinationsGiven)
+	// 5. จำนวนวัคซีนที่ฉีดให้สุนัข (นับจาก vaccine_records ของประวัติสุขภาพที่ยังไม่ถูกลบ)
+	db.Model(&entity.VaccineRecord{}).
+		Joins("JOIN medical_records ON medical_records.id = vaccine_records.med_id AND medical_records.deleted_at IS NULL").
+		Count(&stats.VaccinationsGiven)
+
+	// 5.1 วัคซีนที่ครบกำหนดภายใน 14 วัน / เลยกำหนดแล้ว
+	if due, err := vaccination.CountDue(db); err == nil {
+		stats.VaccinationsDue = due
+	}
+	if late, err := vaccination.CountOverdue(db); err == nil {
+		stats.VaccinationsLate = late
+	}
+
+	// 5.2 แจ้งเตือนสัญญาณชีพผิดปกติที่ยังไม่มีคนรับทราบ
+	if n, err := health_records.CountOpenAlerts(db); err == nil {
+		stats.HealthAlertsOpen = n
+	}
 
 	// 6. จำนวนสุนัขที่ถูกอุปถัม (นับ unique dog_id จาก sponsorships)
 	db.Model(&entity.Sponsorship{}).Distinct("dog_id").Count(&stats.DogsSponsored)
@@ -62,13 +84,13 @@ func GetDashboardRecentUpdates(c *gin.Context) {
 
 	// ดึงข้อมูลจาก medical_records ล่าสุด 5 รายการ
 	var medicalRecords []entity.MedicalRecord
-	db.Preload("Dog").Order("created_at DESC").Limit(5).Find(&medicalRecords)
+	db.Preload("Dog").Preload("VaccineRecords").Order("created_at DESC").Limit(5).Find(&medicalRecords)
 
 	for _, record := range medicalRecords {
 		var note string
 		var tag string
 
-		if record.Vaccination == "YES" {
+		if len(record.VaccineRecords) > 0 {
 			note = "Vaccination completed"
 			tag = "Health"
 		} else if record.Diagnosis != "" {
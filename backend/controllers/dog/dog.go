@@ -8,6 +8,8 @@ import (
 
 	"example.com/project-sa/configs"
 	"example.com/project-sa/entity"
+	"example.com/project-sa/middlewares"
+	"example.com/project-sa/utils/pointer"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
@@ -15,27 +17,42 @@ import (
 /* ========== DTOs ========== */
 
 type DogCreateRequest struct {
-	Name           string `json:"name" binding:"required"`
-	AnimalSexID    uint   `json:"animal_sex_id" binding:"required"`
-	AnimalSizeID   uint   `json:"animal_size_id" binding:"required"`
-	BreedID        uint   `json:"breed_id" binding:"required"`
-	DateOfBirth    string `json:"date_of_birth"` // "YYYY-MM-DD"
-	IsAdopted      bool   `json:"is_adopted"`
-	PhotoURL       string `json:"photo_url"`
-	PersonalityIDs []uint `json:"personality_ids"`
+	Name               string `json:"name" binding:"required"`
+	AnimalSexID        uint   `json:"animal_sex_id" binding:"required"`
+	AnimalSizeID       uint   `json:"animal_size_id" binding:"required"`
+	BreedID            uint   `json:"breed_id" binding:"required"`
+	DateOfBirth        string `json:"date_of_birth"` // "YYYY-MM-DD" | "YYYY-MM" | "YYYY"
+	DOBEstimated       *bool  `json:"dob_estimated"`
+	EstimatedAgeMonths *int   `json:"estimated_age_months"` // ไม่รู้วันเกิด: อายุโดยประมาณ (เดือน)
+	SterilizedAt       string `json:"sterilized_at"`        // "YYYY-MM-DD"
+	IsAdopted          bool   `json:"is_adopted"`
+	PhotoURL           string `json:"photo_url"`
+	Color              string `json:"color"`
+	IntakeDate         string `json:"intake_date"` // "YYYY-MM-DD"
+	IntakeArea         string `json:"intake_area"`
+	PersonalityIDs     []uint `json:"personality_ids"`
 }
 
 type DogUpdateRequest struct {
-	DogID          uint     `json:"dog_id"`
-	Name           *string  `json:"name,omitempty"`
-	AnimalSexID    *uint    `json:"animal_sex_id,omitempty"`
-	AnimalSizeID   *uint    `json:"animal_size_id,omitempty"`
-	BreedID        *uint    `json:"breed_id,omitempty"`
-	KennelID       *uint    `json:"kennel_id,omitempty"`
-	DateOfBirth    *string  `json:"date_of_birth,omitempty"` // "YYYY-MM-DD"
-	IsAdopted      *bool    `json:"is_adopted,omitempty"`
-	PhotoURL       *string  `json:"photo_url,omitempty"`
-	PersonalityIDs *[]uint  `json:"personality_ids,omitempty"`
+	DogID              uint    `json:"dog_id"`
+	Name               *string `json:"name,omitempty"`
+	AnimalSexID        *uint   `json:"animal_sex_id,omitempty"`
+	AnimalSizeID       *uint   `json:"animal_size_id,omitempty"`
+	BreedID            *uint   `json:"breed_id,omitempty"`
+	KennelID           *uint   `json:"kennel_id,omitempty"`
+	DateOfBirth        *string `json:"date_of_birth,omitempty"` // "YYYY-MM-DD" | "YYYY-MM" | "YYYY" ("" = ไม่ทราบ)
+	DOBEstimated       *bool   `json:"dob_estimated,omitempty"`
+	EstimatedAgeMonths *int    `json:"estimated_age_months,omitempty"`
+	SterilizedAt       *string `json:"sterilized_at,omitempty"` // "YYYY-MM-DD" ("" = ยังไม่ทำหมัน)
+	MotherID           *uint   `json:"mother_id,omitempty"`     // 0 = ไม่ทราบ
+	FatherID           *uint   `json:"father_id,omitempty"`
+	IsAdopted          *bool   `json:"is_adopted,omitempty"`
+	ReadyToAdopt       *bool   `json:"ready_to_adopt,omitempty"`
+	PhotoURL           *string `json:"photo_url,omitempty"`
+	Color              *string `json:"color,omitempty"`
+	IntakeDate         *string `json:"intake_date,omitempty"` // "YYYY-MM-DD"
+	IntakeArea         *string `json:"intake_area,omitempty"`
+	PersonalityIDs     *[]uint `json:"personality_ids,omitempty"`
 }
 
 /* ========== Helpers ========== */
@@ -64,27 +81,19 @@ func preloadDog(db *gorm.DB) *gorm.DB {
 		Preload("DeletedBy")
 }
 
-// ดึง staff_id จาก context (middleware ใส่มาแล้ว)
-func getStaffID(c *gin.Context) *uint {
-	v, ok := c.Get("staff_id")
-	if !ok || v == nil {
-		return nil
+// ผลประเมินพฤติกรรมล่าสุด (completed) ระบุว่ายังไม่ปลอดภัย -> ห้ามตั้ง ready_to_adopt
+func adoptionBlockedByAssessment(tx *gorm.DB, dogID uint) (bool, error) {
+	var a entity.BehaviorAssessment
+	err := tx.Where("dog_id = ? AND status = ?", dogID, entity.AssessmentCompleted).
+		Order("assessed_at DESC, id DESC").
+		First(&a).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
 	}
-	switch n := v.(type) {
-	case uint:
-		if n == 0 {
-			return nil
-		}
-		return &n
-	case int:
-		if n <= 0 {
-			return nil
-		}
-		u := uint(n)
-		return &u
-	default:
-		return nil
+	if err != nil {
+		return false, err
 	}
+	return a.SafeForAdoption != nil && !*a.SafeForAdoption, nil
 }
 
 /* ========== CRUD Handlers ========== */
@@ -97,10 +106,28 @@ func CreateDog(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
 		return
 	}
+	if _, err := parseYMD(req.IntakeDate); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid intake_date (YYYY-MM-DD)"})
+		return
+	}
+	dob := dobInput{DOBEstimated: req.DOBEstimated, EstimatedAgeMonths: req.EstimatedAgeMonths}
+	if req.DateOfBirth != "" {
+		dob.DateOfBirth = &req.DateOfBirth
+	}
+	dobCols, err := dob.resolve()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	sterilizedAt, err := parseSterilizedAt(req.SterilizedAt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	db := configs.DB()
 	var created entity.Dog
-	staffID := getStaffID(c)
+	staffID := middlewares.StaffID(c)
 
 	if err := db.Transaction(func(tx *gorm.DB) error {
 		d := entity.Dog{
@@ -108,9 +135,21 @@ func CreateDog(c *gin.Context) {
 			AnimalSexID:  req.AnimalSexID,
 			AnimalSizeID: req.AnimalSizeID,
 			BreedID:      req.BreedID,
-			DateOfBirth:  req.DateOfBirth,
+			SterilizedAt: sterilizedAt,
+			DOBPrecision: entity.DOBPrecisionDay,
 			IsAdopted:    req.IsAdopted,
 			PhotoURL:     req.PhotoURL,
+			Color:        req.Color,
+			IntakeDate:   req.IntakeDate,
+			IntakeArea:   req.IntakeArea,
+			ShareCode:    pointer.P(newShareCode()),
+		}
+		if v, ok := dobCols["date_of_birth"].(*entity.Date); ok {
+			d.DateOfBirth = v
+			d.DOBPrecision = dobCols["dob_precision"].(string)
+		}
+		if v, ok := dobCols["dob_estimated"].(bool); ok {
+			d.DOBEstimated = v
 		}
 		if err := tx.Create(&d).Error; err != nil {
 			return err
@@ -162,7 +201,7 @@ func CreateDog(c *gin.Context) {
 func GetDogById(c *gin.Context) {
 	id := c.Param("id")
 	var dog entity.Dog
-	if err := preloadDog(configs.DB()).First(&dog, id).Error; err != nil {
+	if err := preloadDog(configs.DB()).Preload("Litter").First(&dog, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "dog not found"})
 			return
@@ -170,6 +209,12 @@ func GetDogById(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
 		return
 	}
+	rel, err := loadRelatives(configs.DB(), dog)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	dog.Relatives = rel
 	c.JSON(http.StatusOK, dog)
 }
 
@@ -188,6 +233,17 @@ func GetAllDogs(c *gin.Context) {
 	if kennelID := c.Query("kennel_id"); kennelID != "" {
 		db = db.Where("kennel_id = ?", kennelID)
 	}
+	if status := c.Query("status"); status != "" {
+		db = db.Where("status = ?", status)
+	} else if c.Query("include_deceased") != "true" {
+		// สุนัขที่เสียชีวิตแล้วไม่แสดงในรายการปกติ
+		db = db.Where("status <> ?", entity.DogStatusDeceased)
+	}
+	db, err := applyAgeFilter(c, db)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	var dogs []entity.Dog
 	if err := preloadDog(db).Order("id DESC").Find(&dogs).Error; err != nil {
@@ -218,9 +274,26 @@ func UpdateDog(c *gin.Context) {
 	}
 
 	db := configs.DB()
-	staffID := getStaffID(c)
+	staffID := middlewares.StaffID(c)
 
-	if err := db.Transaction(func(tx *gorm.DB) error {
+	if req.ReadyToAdopt != nil && *req.ReadyToAdopt {
+		if existing.Status == entity.DogStatusDeceased {
+			c.JSON(http.StatusConflict, gin.H{"error": "dog is deceased"})
+			return
+		}
+		blocked, err := adoptionBlockedByAssessment(db, existing.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+			return
+		}
+		if blocked {
+			c.JSON(http.StatusConflict, gin.H{"error": "latest behavior assessment is not safe for adoption"})
+			return
+		}
+	}
+
+	var warnings []entity.KennelRuleViolation
+	err := db.Transaction(func(tx *gorm.DB) error {
 		updates := map[string]any{}
 
 		if req.Name != nil {
@@ -236,20 +309,91 @@ func UpdateDog(c *gin.Context) {
 			updates["breed_id"] = *req.BreedID
 		}
 		if req.KennelID != nil {
-			updates["kennel_id"] = *req.KennelID
+			// สุนัขที่อยู่บ้านอุปถัมภ์/ส่งต่อแล้ว ต้องรับกลับผ่าน placement/transfer ก่อน
+			if existing.Status != entity.DogStatusShelter {
+				return &updateError{http.StatusConflict, "dog is not in the shelter (status: " + existing.Status + ")"}
+			}
+			if *req.KennelID == 0 {
+				// 0 = เอาออกจากคอก
+				updates["kennel_id"] = nil
+			} else {
+				if err := entity.CheckKennelIsolation(tx, existing.ID, *req.KennelID); err != nil {
+					return err
+				}
+				if _, err := entity.ReserveKennelSlot(tx, *req.KennelID, existing.ID); err != nil {
+					if errors.Is(err, gorm.ErrRecordNotFound) {
+						return &updateError{http.StatusBadRequest, "invalid kennel_id"}
+					}
+					return err
+				}
+				updates["kennel_id"] = *req.KennelID
+			}
 		}
 		if req.IsAdopted != nil {
 			updates["is_adopted"] = *req.IsAdopted
 		}
+		if req.ReadyToAdopt != nil {
+			updates["ready_to_adopt"] = *req.ReadyToAdopt
+		}
 		if req.PhotoURL != nil {
 			updates["photo_url"] = *req.PhotoURL
 		}
-		if req.DateOfBirth != nil {
-			if _, err := parseYMD(*req.DateOfBirth); err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date_of_birth (YYYY-MM-DD)"})
-				return nil
+		dobCols, err := dobInput{
+			DateOfBirth:        req.DateOfBirth,
+			EstimatedAgeMonths: req.EstimatedAgeMonths,
+			DOBEstimated:       req.DOBEstimated,
+		}.resolve()
+		if err != nil {
+			return &updateError{http.StatusBadRequest, err.Error()}
+		}
+		for k, v := range dobCols {
+			updates[k] = v
+		}
+		for _, p := range []struct {
+			in    *uint
+			field string
+		}{{req.MotherID, "mother_id"}, {req.FatherID, "father_id"}} {
+			if p.in == nil {
+				continue
+			}
+			if *p.in == 0 {
+				updates[p.field] = nil
+				continue
+			}
+			if err := validateParent(tx, existing.ID, *p.in, p.field); err != nil {
+				return &updateError{http.StatusBadRequest, err.Error()}
+			}
+			updates[p.field] = *p.in
+		}
+		if req.SterilizedAt != nil {
+			d, err := parseSterilizedAt(*req.SterilizedAt)
+			if err != nil {
+				return &updateError{http.StatusBadRequest, err.Error()}
 			}
-			updates["date_of_birth"] = *req.DateOfBirth
+			updates["sterilized_at"] = d
+		}
+		if req.Color != nil {
+			updates["color"] = *req.Color
+		}
+		if req.IntakeDate != nil {
+			if _, err := parseYMD(*req.IntakeDate); err != nil {
+				return &updateError{http.StatusBadRequest, "invalid intake_date (YYYY-MM-DD)"}
+			}
+			updates["intake_date"] = *req.IntakeDate
+		}
+		if req.IntakeArea != nil {
+			updates["intake_area"] = *req.IntakeArea
+		}
+
+		// Updates เขียนค่าใหม่ทับ existing (รวมค่าใน pointer) เก็บคอกเดิมแบบค่าไว้ตรวจกฎตอนท้าย
+		var prevKennelID uint
+		if existing.KennelID != nil {
+			prevKennelID = *existing.KennelID
+		}
+
+		// เก็บประวัติรายฟิลด์ก่อนเขียนทับ
+		if err := recordDogChanges(tx, existing, updates, entity.DogChangeUpdate, staffID, nil); err != nil {
+			return err
 		}
 
 		// ติด audit ผู้แก้ไข
@@ -262,43 +406,69 @@ func UpdateDog(c *gin.Context) {
 				return err
 			}
 		}
+		if err := resolveDateIssues(tx, existing.ID, updates); err != nil {
+			return err
+		}
 
 		// personalities: replace ทั้งชุดถ้าส่งมา
 		if req.PersonalityIDs != nil {
-			if err := tx.Unscoped().
-				Where("dog_id = ?", existing.ID).
-				Delete(&entity.DogPersonality{}).Error; err != nil {
+			before, err := dogPersonalityIDs(tx, existing.ID)
+			if err != nil {
 				return err
 			}
-			if len(*req.PersonalityIDs) > 0 {
-				rows := make([]entity.DogPersonality, 0, len(*req.PersonalityIDs))
-				seen := map[uint]struct{}{}
-				for _, pid := range *req.PersonalityIDs {
-					if _, ok := seen[pid]; ok {
-						continue
-					}
-					seen[pid] = struct{}{}
-					rows = append(rows, entity.DogPersonality{
-						DogID: existing.ID, PersonalityID: pid,
-					})
-				}
-				if err := tx.Create(&rows).Error; err != nil {
-					return err
-				}
+			if err := replaceDogPersonalities(tx, existing.ID, *req.PersonalityIDs); err != nil {
+				return err
+			}
+			if err := recordPersonalityChange(tx, existing.ID, before, *req.PersonalityIDs, entity.DogChangeUpdate, staffID, nil); err != nil {
+				return err
 			}
 		}
 
-		var out entity.Dog
-		if err := preloadDog(tx).First(&out, existing.ID).Error; err != nil {
-			c.JSON(http.StatusOK, existing)
-			return nil
+		// ตรวจกฎการอยู่คอกหลังแก้ครบทุกฟิลด์ (เพศ/ทำหมัน/นิสัยอาจเปลี่ยนพร้อมกัน)
+		if req.KennelID != nil && *req.KennelID != 0 &&
+			prevKennelID != *req.KennelID {
+			if warnings, err = entity.CheckKennelRules(tx, existing.ID, *req.KennelID); err != nil {
+				return err
+			}
 		}
-		c.JSON(http.StatusOK, out)
 		return nil
-	}); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed: " + err.Error()})
+	})
+	if err != nil {
+		respondUpdateError(c, err)
+		return
+	}
+
+	var out entity.Dog
+	if err := preloadDog(db).First(&out, existing.ID).Error; err != nil {
+		existing.KennelWarnings = warnings
+		c.JSON(http.StatusOK, existing)
 		return
 	}
+	out.KennelWarnings = warnings
+	c.JSON(http.StatusOK, out)
+}
+
+// updateError ข้อผิดพลาดจากข้อมูลที่ส่งมา (rollback แล้วตอบตาม status)
+type updateError struct {
+	status int
+	msg    string
+}
+
+func (e *updateError) Error() string { return e.msg }
+
+func respondUpdateError(c *gin.Context, err error) {
+	var ue *updateError
+	var ce *entity.CompatibilityError
+	switch {
+	case errors.As(err, &ue):
+		c.JSON(ue.status, gin.H{"error": ue.msg})
+	case errors.As(err, &ce):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "violations": ce.Violations})
+	case entity.IsCapacityError(err), entity.IsIsolationError(err):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed: " + err.Error()})
+	}
 }
 
 // DeleteDog (D)
@@ -316,7 +486,7 @@ func DeleteDog(c *gin.Context) {
 	}
 
 	db := configs.DB()
-	staffID := getStaffID(c)
+	staffID := middlewares.StaffID(c)
 
 	if err := db.Transaction(func(tx *gorm.DB) error {
 		// ติด audit ผู้ลบ (ก่อน soft delete)
@@ -329,7 +499,8 @@ func DeleteDog(c *gin.Context) {
 		if err := tx.Delete(&dog).Error; err != nil {
 			return err
 		}
-		return nil
+		row := entity.NewDogChange(dog.ID, entity.DogChangeDelete, fieldDeletedAt, nil, time.Now(), staffID)
+		return tx.Create(&row).Error
 	}); err != nil {
 		c.JSON(http.StatusConflict, gin.H{"error": "cannot delete dog (maybe referenced by other records): " + err.Error()})
 		return
@@ -0,0 +1,175 @@
+package dog
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/utils/timeutil"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+/* ========== วันเกิด/อายุ ========== */
+
+var errFutureDate = errors.New("date cannot be in the future")
+
+func today() time.Time {
+	return entity.NewDate(time.Now().In(timeutil.TZBangkok())).Time
+}
+
+// parseDOB รับ "YYYY-MM-DD" | "YYYY-MM" | "YYYY" แล้วคืนวันที่ + precision
+// (เดือน/ปี ที่ไม่รู้วัน จะเก็บเป็นวันแรกของช่วง)
+func parseDOB(s string) (*entity.Date, string, error) {
+	for _, f := range []struct{ layout, precision string }{
+		{"2006-01-02", entity.DOBPrecisionDay},
+		{"2006-01", entity.DOBPrecisionMonth},
+		{"2006", entity.DOBPrecisionYear},
+	} {
+		t, err := time.Parse(f.layout, s)
+		if err != nil {
+			continue
+		}
+		if t.After(today()) {
+			return nil, "", errFutureDate
+		}
+		return &entity.Date{Time: t}, f.precision, nil
+	}
+	return nil, "", errors.New("invalid date_of_birth (YYYY-MM-DD, YYYY-MM or YYYY)")
+}
+
+// dobFromAge ประมาณวันเกิดจากอายุ (เดือน) ที่สัตวแพทย์ประเมิน
+func dobFromAge(months int) (*entity.Date, error) {
+	if months < 0 || months > 30*12 {
+		return nil, errors.New("invalid estimated_age_months")
+	}
+	t := today().AddDate(0, -months, 0)
+	return &entity.Date{Time: time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)}, nil
+}
+
+func parseSterilizedAt(s string) (*entity.Date, error) {
+	d, err := entity.ParseDate(s)
+	if err != nil {
+		return nil, errors.New("invalid sterilized_at (YYYY-MM-DD)")
+	}
+	if d != nil && d.After(today()) {
+		return nil, errFutureDate
+	}
+	return d, nil
+}
+
+// dobInput ค่าวันเกิดที่รับจาก request (ใช้ร่วมกันทั้ง create/update)
+type dobInput struct {
+	DateOfBirth        *string
+	EstimatedAgeMonths *int
+	DOBEstimated       *bool
+}
+
+// resolve คืนคอลัมน์ที่ต้องเขียน (date_of_birth, dob_precision, dob_estimated)
+func (in dobInput) resolve() (map[string]any, error) {
+	out := map[string]any{}
+	switch {
+	case in.EstimatedAgeMonths != nil:
+		d, err := dobFromAge(*in.EstimatedAgeMonths)
+		if err != nil {
+			return nil, err
+		}
+		out["date_of_birth"] = d
+		out["dob_precision"] = entity.DOBPrecisionMonth
+		out["dob_estimated"] = true
+	case in.DateOfBirth != nil && *in.DateOfBirth == "":
+		out["date_of_birth"] = nil
+		out["dob_precision"] = entity.DOBPrecisionDay
+		out["dob_estimated"] = false
+	case in.DateOfBirth != nil:
+		d, precision, err := parseDOB(*in.DateOfBirth)
+		if err != nil {
+			return nil, err
+		}
+		out["date_of_birth"] = d
+		out["dob_precision"] = precision
+		// ระบุแค่เดือน/ปี = ค่าประมาณโดยปริยาย
+		out["dob_estimated"] = precision != entity.DOBPrecisionDay
+	}
+	if in.DOBEstimated != nil {
+		out["dob_estimated"] = *in.DOBEstimated
+	}
+	return out, nil
+}
+
+// applyAgeFilter ?age_group=puppy|adult|senior และ/หรือ ?min_age_months=&max_age_months=
+// (สุนัขที่ไม่ทราบวันเกิดจะไม่ติดผลเมื่อกรองด้วยอายุ)
+func applyAgeFilter(c *gin.Context, db *gorm.DB) (*gorm.DB, error) {
+	min, max := -1, -1
+	if g := c.Query("age_group"); g != "" {
+		lo, hi, ok := entity.AgeGroupRange(g)
+		if !ok {
+			return nil, errors.New("invalid age_group")
+		}
+		min = lo
+		if hi >= 0 {
+			max = hi - 1
+		}
+	}
+	for _, p := range []struct {
+		key string
+		dst *int
+	}{{"min_age_months", &min}, {"max_age_months", &max}} {
+		v := c.Query(p.key)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return nil, errors.New("invalid " + p.key)
+		}
+		*p.dst = n
+	}
+	if min < 0 && max < 0 {
+		return db, nil
+	}
+
+	now := today()
+	db = db.Where("date_of_birth IS NOT NULL")
+	if min >= 0 {
+		// อายุ >= min  <=>  เกิดไม่หลัง (วันนี้ - min เดือน)
+		db = db.Where("date_of_birth <= ?", entity.Date{Time: now.AddDate(0, -min, 0)})
+	}
+	if max >= 0 {
+		// อายุ <= max  <=>  เกิดหลัง (วันนี้ - (max+1) เดือน)
+		db = db.Where("date_of_birth > ?", entity.Date{Time: now.AddDate(0, -(max + 1), 0)})
+	}
+	return db, nil
+}
+
+// แก้วันที่ผ่าน UpdateDog แล้ว ถือว่าปัญหาจากการ migrate ของฟิลด์นั้นจบ
+func resolveDateIssues(tx *gorm.DB, dogID uint, updates map[string]any) error {
+	for _, f := range []string{"date_of_birth", "sterilized_at"} {
+		if _, ok := updates[f]; !ok {
+			continue
+		}
+		if err := tx.Model(&entity.DogDateIssue{}).
+			Where("dog_id = ? AND field = ? AND resolved_at IS NULL", dogID, f).
+			Update("resolved_at", time.Now()).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GET /dogs/date-issues?all=true — ค่าวันที่เดิมที่ migrate ไม่ผ่าน (ค่าเริ่มต้น: เฉพาะที่ยังไม่แก้)
+func GetDogDateIssues(c *gin.Context) {
+	db := configs.DB().Preload("Dog")
+	if c.Query("all") != "true" {
+		db = db.Where("resolved_at IS NULL")
+	}
+	var rows []entity.DogDateIssue
+	if err := db.Order("dog_id ASC, id ASC").Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": rows})
+}
@@ -0,0 +1,420 @@
+package dog
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"example.com/project-sa/entity"
+	"github.com/go-pdf/fpdf"
+	"gorm.io/gorm"
+)
+
+/* ========== Transfer bundle (JSON + PDF + photos ใน zip เดียว) ========== */
+
+const (
+	bundleFormat  = "project-sa/dog-transfer"
+	bundleVersion = 1
+
+	bundleJSONName = "bundle.json"
+	bundlePDFName  = "profile.pdf"
+
+	// กันไฟล์ใหญ่ผิดปกติตอนนำเข้า
+	maxBundleSize = 32 << 20
+)
+
+type transferBundle struct {
+	Format     string             `json:"format"`
+	Version    int                `json:"version"`
+	ExportedAt time.Time          `json:"exported_at"`
+	Transfer   *bundleTransfer    `json:"transfer,omitempty"`
+	Dog        bundleDog          `json:"dog"`
+	Medical    []bundleMedical    `json:"medical_records"`
+	Photos     []bundlePhotoEntry `json:"photos"`
+}
+
+type bundleTransfer struct {
+	Direction    string    `json:"direction"` // out | in (มุมมองขององค์กรที่ export)
+	Partner      string    `json:"partner"`
+	TransferDate time.Time `json:"transfer_date"`
+	Reason       string    `json:"reason"`
+}
+
+// อ้างอิง lookup ด้วยชื่อ (id แต่ละองค์กรไม่ตรงกัน)
+type bundleDog struct {
+	SourceID      uint     `json:"source_id"`
+	Name          string   `json:"name"`
+	DateOfBirth   string   `json:"date_of_birth"`
+	DOBEstimated  bool     `json:"dob_estimated,omitempty"`
+	DOBPrecision  string   `json:"dob_precision,omitempty"`
+	SterilizedAt  string   `json:"sterilized_at"`
+	Color         string   `json:"color"`
+	IntakeDate    string   `json:"intake_date"`
+	IntakeArea    string   `json:"intake_area"`
+	Breed         string   `json:"breed"`
+	AnimalSex     string   `json:"animal_sex"`
+	AnimalSize    string   `json:"animal_size"`
+	Personalities []string `json:"personalities"`
+}
+
+type bundleMedical struct {
+	DateRecord  time.Time              `json:"date_record"`
+	Weight      float64                `json:"weight"`
+	Temperature float64                `json:"temperature"`
+	Symptoms    string                 `json:"symptoms"`
+	Diagnosis   string                 `json:"diagnosis"`
+	Treatment   string                 `json:"treatment"`
+	Medication  string                 `json:"medication"`
+	Vaccination entity.VaccinationFlag `json:"vaccination"`
+	Notes       string                 `json:"notes"`
+	RecordedBy  string                 `json:"recorded_by"`
+	Vaccines    []bundleVaccine        `json:"vaccine_records"`
+}
+
+type bundleVaccine struct {
+	Vaccine      string    `json:"vaccine"`
+	Manufacturer string    `json:"manufacturer"`
+	DoseNumber   int       `json:"dose_number"`
+	LotNumber    string    `json:"lot_number"`
+	NextDueDate  time.Time `json:"next_due_date"`
+}
+
+type bundlePhotoEntry struct {
+	File        string `json:"file"` // path ภายใน zip เช่น photos/photo-1.jpg
+	ContentType string `json:"content_type"`
+}
+
+var photoExtByType = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+func preloadBundleDog(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Breed").
+		Preload("AnimalSex").
+		Preload("AnimalSize").
+		Preload("DogPersonalities.Personality").
+		Preload("MedicalRecords", func(db *gorm.DB) *gorm.DB { return db.Order("date_record ASC, id ASC") }).
+		Preload("MedicalRecords.Staff").
+		Preload("MedicalRecords.VaccineRecords").
+		Preload("MedicalRecords.VaccineRecords.Vaccine")
+}
+
+// buildBundle คืน bundle + ไฟล์รูป (key = path ใน zip)
+func buildBundle(dog entity.Dog, tr *bundleTransfer) (transferBundle, map[string][]byte) {
+	b := transferBundle{
+		Format:     bundleFormat,
+		Version:    bundleVersion,
+		ExportedAt: time.Now(),
+		Transfer:   tr,
+		Dog: bundleDog{
+			SourceID:      dog.ID,
+			Name:          dog.Name,
+			DateOfBirth:   entity.FormatDogChangeValue(dog.DateOfBirth),
+			DOBEstimated:  dog.DOBEstimated,
+			DOBPrecision:  dog.DOBPrecision,
+			SterilizedAt:  entity.FormatDogChangeValue(dog.SterilizedAt),
+			Color:         dog.Color,
+			IntakeDate:    dog.IntakeDate,
+			IntakeArea:    dog.IntakeArea,
+			Personalities: []string{},
+		},
+		Medical: []bundleMedical{},
+		Photos:  []bundlePhotoEntry{},
+	}
+	if dog.Breed != nil {
+		b.Dog.Breed = dog.Breed.Name
+	}
+	if dog.AnimalSex != nil {
+		b.Dog.AnimalSex = dog.AnimalSex.Name
+	}
+	if dog.AnimalSize != nil {
+		b.Dog.AnimalSize = dog.AnimalSize.Name
+	}
+	for _, dp := range dog.DogPersonalities {
+		if dp.Personality != nil {
+			b.Dog.Personalities = append(b.Dog.Personalities, dp.Personality.Name)
+		}
+	}
+
+	for _, mr := range dog.MedicalRecords {
+		m := bundleMedical{
+			DateRecord:  mr.DateRecord,
+			Weight:      mr.Weight,
+			Temperature: mr.Temperature,
+			Symptoms:    mr.Symptoms,
+			Diagnosis:   mr.Diagnosis,
+			Treatment:   mr.TreatmentPlan,
+			Medication:  mr.Medication,
+			Vaccination: mr.Vaccination,
+			Notes:       mr.Notes,
+			Vaccines:    []bundleVaccine{},
+		}
+		if mr.Staff != nil {
+			m.RecordedBy = strings.TrimSpace(mr.Staff.FirstName + " " + mr.Staff.LastName)
+		}
+		for _, vr := range mr.VaccineRecords {
+			v := bundleVaccine{DoseNumber: vr.DoseNumber, LotNumber: vr.LotNumber, NextDueDate: vr.NextDueDate}
+			if vr.Vaccine != nil {
+				v.Vaccine = vr.Vaccine.Name
+				v.Manufacturer = vr.Vaccine.Manufacturer
+			}
+			m.Vaccines = append(m.Vaccines, v)
+		}
+		b.Medical = append(b.Medical, m)
+	}
+
+	files := map[string][]byte{}
+	if p := localPhotoPath(dog.PhotoURL); p != "" {
+		if data, err := os.ReadFile(p); err == nil {
+			ct := http.DetectContentType(data)
+			if ext, ok := photoExtByType[ct]; ok {
+				name := fmt.Sprintf("photos/photo-%d%s", dog.ID, ext)
+				files[name] = data
+				b.Photos = append(b.Photos, bundlePhotoEntry{File: name, ContentType: ct})
+			}
+		}
+	}
+	return b, files
+}
+
+// เอกสาร PDF สำหรับคนอ่าน (ข้อมูลชุดเดียวกับ bundle.json)
+func renderBundlePDF(b transferBundle, files map[string][]byte) ([]byte, error) {
+	cp, err := newPDF("A4")
+	if err != nil {
+		return nil, err
+	}
+	pdf, tr := cp.pdf, cp.tr
+	pdf.SetAutoPageBreak(true, 12)
+	pdf.AddPage()
+	pageW, _ := pdf.GetPageSize()
+	left, _, right, _ := pdf.GetMargins()
+	contentW := pageW - left - right
+
+	pdf.SetFont(cp.family, "B", 18)
+	pdf.CellFormat(contentW, 10, tr("Transfer record: "+b.Dog.Name), "", 1, "L", false, 0, "")
+	pdf.SetFont(cp.family, "", 9)
+	pdf.CellFormat(contentW, 5, tr(fmt.Sprintf("Source ID #%d  |  Exported %s", b.Dog.SourceID, b.ExportedAt.Format("2006-01-02 15:04"))), "", 1, "L", false, 0, "")
+	if b.Transfer != nil {
+		dir := "To"
+		if b.Transfer.Direction == entity.TransferIn {
+			dir = "From"
+		}
+		pdf.CellFormat(contentW, 5, tr(fmt.Sprintf("%s %s on %s", dir, b.Transfer.Partner, b.Transfer.TransferDate.Format("2006-01-02"))), "", 1, "L", false, 0, "")
+		if b.Transfer.Reason != "" {
+			pdf.MultiCell(contentW, 5, tr("Reason: "+b.Transfer.Reason), "", "L", false)
+		}
+	}
+	pdf.Ln(3)
+
+	top := pdf.GetY()
+	photoSize := 50.0
+	infoX := left
+	if len(b.Photos) > 0 {
+		ph := b.Photos[0]
+		if imgType := fpdfImageType(ph.ContentType); imgType != "" {
+			opts := fpdf.ImageOptions{ImageType: imgType}
+			pdf.RegisterImageOptionsReader(ph.File, opts, bytes.NewReader(files[ph.File]))
+			pdf.ImageOptions(ph.File, left, top, photoSize, photoSize, false, opts, 0, "")
+			infoX = left + photoSize + 6
+		}
+	}
+	rows := [][2]string{
+		{"Breed", b.Dog.Breed},
+		{"Sex", b.Dog.AnimalSex},
+		{"Size", b.Dog.AnimalSize},
+		{"Born", bornText(b.Dog)},
+		{"Sterilized", b.Dog.SterilizedAt},
+		{"Color", b.Dog.Color},
+		{"Intake", strings.TrimSpace(b.Dog.IntakeDate + " " + b.Dog.IntakeArea)},
+		{"Personality", strings.Join(b.Dog.Personalities, ", ")},
+	}
+	pdf.SetXY(infoX, top)
+	for _, r := range rows {
+		v := r[1]
+		if v == "" {
+			v = "-"
+		}
+		pdf.SetX(infoX)
+		pdf.SetFont(cp.family, "B", 10)
+		pdf.CellFormat(26, 6, tr(r[0]), "", 0, "L", false, 0, "")
+		pdf.SetFont(cp.family, "", 10)
+		pdf.CellFormat(contentW-(infoX-left)-26, 6, tr(v), "", 1, "L", false, 0, "")
+	}
+	if y := top + photoSize + 4; infoX != left && pdf.GetY() < y {
+		pdf.SetY(y)
+	}
+
+	pdf.Ln(4)
+	pdf.SetFont(cp.family, "B", 13)
+	pdf.CellFormat(contentW, 8, tr("Medical records"), "B", 1, "L", false, 0, "")
+	if len(b.Medical) == 0 {
+		pdf.SetFont(cp.family, "", 10)
+		pdf.CellFormat(contentW, 7, tr("No medical records"), "", 1, "L", false, 0, "")
+	}
+	for _, m := range b.Medical {
+		pdf.Ln(2)
+		pdf.SetFont(cp.family, "B", 10)
+		head := fmt.Sprintf("%s  |  %.1f kg  |  %.1f C", m.DateRecord.Format("2006-01-02"), m.Weight, m.Temperature)
+		if m.RecordedBy != "" {
+			head += "  |  " + m.RecordedBy
+		}
+		pdf.CellFormat(contentW, 6, tr(head), "", 1, "L", false, 0, "")
+		pdf.SetFont(cp.family, "", 9)
+		for _, f := range [][2]string{
+			{"Symptoms", m.Symptoms},
+			{"Diagnosis", m.Diagnosis},
+			{"Treatment", m.Treatment},
+			{"Medication", m.Medication},
+			{"Notes", m.Notes},
+		} {
+			if f[1] == "" {
+				continue
+			}
+			pdf.MultiCell(contentW, 5, tr(f[0]+": "+f[1]), "", "L", false)
+		}
+		for _, v := range m.Vaccines {
+			line := fmt.Sprintf("Vaccine: %s dose %d (lot %s)", v.Vaccine, v.DoseNumber, v.LotNumber)
+			if !v.NextDueDate.IsZero() {
+				line += ", next due " + v.NextDueDate.Format("2006-01-02")
+			}
+			pdf.MultiCell(contentW, 5, tr(line), "", "L", false)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func fpdfImageType(contentType string) string {
+	switch contentType {
+	case "image/jpeg":
+		return "JPG"
+	case "image/png":
+		return "PNG"
+	case "image/gif":
+		return "GIF"
+	}
+	return ""
+}
+
+func writeBundleZip(w io.Writer, b transferBundle, files map[string][]byte) error {
+	pdfData, err := renderBundlePDF(b, files)
+	if err != nil {
+		return err
+	}
+	js, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+	add := func(name string, data []byte) error {
+		f, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(data)
+		return err
+	}
+	if err := add(bundleJSONName, js); err != nil {
+		return err
+	}
+	if err := add(bundlePDFName, pdfData); err != nil {
+		return err
+	}
+	for _, p := range b.Photos {
+		if err := add(p.File, files[p.File]); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// readBundle รับได้ทั้ง zip (จาก export) และ bundle.json ไฟล์เดียว
+func readBundle(data []byte) (transferBundle, map[string][]byte, error) {
+	var b transferBundle
+	files := map[string][]byte{}
+
+	if !bytes.HasPrefix(data, []byte("PK")) {
+		if err := json.Unmarshal(data, &b); err != nil {
+			return b, nil, fmt.Errorf("invalid bundle json: %w", err)
+		}
+		return b, files, validateBundle(b)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return b, nil, fmt.Errorf("invalid bundle zip: %w", err)
+	}
+	var js []byte
+	for _, f := range zr.File {
+		name := path.Clean(f.Name)
+		if name != bundleJSONName && !strings.HasPrefix(name, "photos/") {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return b, nil, err
+		}
+		content, err := io.ReadAll(io.LimitReader(rc, maxBundleSize))
+		rc.Close()
+		if err != nil {
+			return b, nil, err
+		}
+		if name == bundleJSONName {
+			js = content
+		} else {
+			files[name] = content
+		}
+	}
+	if js == nil {
+		return b, nil, errors.New("bundle.json not found in zip")
+	}
+	if err := json.Unmarshal(js, &b); err != nil {
+		return b, nil, fmt.Errorf("invalid bundle json: %w", err)
+	}
+	return b, files, validateBundle(b)
+}
+
+func validateBundle(b transferBundle) error {
+	if b.Format != bundleFormat {
+		return fmt.Errorf("unsupported bundle format %q", b.Format)
+	}
+	if b.Version < 1 || b.Version > bundleVersion {
+		return fmt.Errorf("unsupported bundle version %d", b.Version)
+	}
+	if strings.TrimSpace(b.Dog.Name) == "" {
+		return errors.New("dog.name is required")
+	}
+	if b.Dog.DateOfBirth != "" {
+		if _, _, err := parseDOB(b.Dog.DateOfBirth); err != nil {
+			return errors.New("invalid dog.date_of_birth")
+		}
+	}
+	if _, err := parseSterilizedAt(b.Dog.SterilizedAt); err != nil {
+		return errors.New("invalid dog.sterilized_at")
+	}
+	return nil
+}
+
+func bornText(d bundleDog) string {
+	if d.DateOfBirth == "" || !d.DOBEstimated {
+		return d.DateOfBirth
+	}
+	return d.DateOfBirth + " (estimated)"
+}
@@ -0,0 +1,373 @@
+package dog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/go-pdf/fpdf"
+	qrcode "github.com/skip2/go-qrcode"
+	"gorm.io/gorm"
+)
+
+/* ========== Kennel door cards (PDF) ========== */
+
+// เตือนครั้งเดียวเมื่อไม่มีฟอนต์ไทย (ดู configs.CardFontPath)
+var warnNoCardFont sync.Once
+
+type vaccineDue struct {
+	VaccineName string
+	DoseNumber  int
+	NextDueDate time.Time
+}
+
+type cardPDF struct {
+	pdf    *fpdf.Fpdf
+	family string
+	tr     func(string) string
+}
+
+func newCardPDF() (*cardPDF, error) {
+	cp, err := newPDF("A5")
+	if err != nil {
+		return nil, err
+	}
+	cp.pdf.SetAutoPageBreak(false, 10)
+	return cp, nil
+}
+
+// newPDF ใช้ร่วมกับเอกสารอื่น (เช่น แฟ้มส่งต่อสุนัข) ให้ได้ฟอนต์เดียวกับการ์ด
+func newPDF(size string) (*cardPDF, error) {
+	pdf := fpdf.New("P", "mm", size, "")
+	pdf.SetMargins(10, 10, 10)
+
+	fontPath := configs.CardFontPath()
+	font, err := os.ReadFile(fontPath)
+	if err != nil {
+		warnNoCardFont.Do(func() {
+			log.Printf("warn: no Thai font at %s (set CARD_FONT_PATH); PDFs use Helvetica and print Thai text as ?", fontPath)
+		})
+		return &cardPDF{pdf: pdf, family: "Helvetica", tr: latinOnly(pdf.UnicodeTranslatorFromDescriptor(""))}, nil
+	}
+	// อ่านไฟล์เอง: AddUTF8Font ต่อ path เข้ากับโฟลเดอร์ฟอนต์ของ fpdf
+	pdf.AddUTF8FontFromBytes("card", "", font)
+	pdf.AddUTF8FontFromBytes("card", "B", font)
+	if err := pdf.Error(); err != nil {
+		return nil, fmt.Errorf("load font %s: %w", fontPath, err)
+	}
+	return &cardPDF{pdf: pdf, family: "card", tr: func(s string) string { return s }}, nil
+}
+
+// latinOnly อักษรที่ฟอนต์มาตรฐานไม่มี (เช่น ภาษาไทย) แทนด้วย "?" ช่วงละตัว แทนที่จะเป็นจุดทีละตัวอักษร
+func latinOnly(tr func(string) string) func(string) string {
+	return func(s string) string {
+		var b strings.Builder
+		missing := false
+		for _, r := range s {
+			t := tr(string(r))
+			if r >= 0x80 && t == "." {
+				if !missing {
+					b.WriteByte('?')
+				}
+				missing = true
+				continue
+			}
+			missing = false
+			b.WriteString(t)
+		}
+		return b.String()
+	}
+}
+
+// แปลง photo_url (/static/... หรือ http://host/static/...) เป็น path บนดิสก์
+func localPhotoPath(url string) string {
+	i := strings.Index(url, "/static/")
+	if i < 0 {
+		return ""
+	}
+	p := filepath.Clean(filepath.FromSlash(url[i+1:]))
+	if !strings.HasPrefix(p, "static"+string(filepath.Separator)) {
+		return ""
+	}
+	return p
+}
+
+// อ่านรูปและตรวจชนิดจากเนื้อไฟล์ (นามสกุลไฟล์อาจไม่ตรงกับชนิดจริง)
+// fpdf รองรับเฉพาะ JPG/PNG/GIF — ชนิดอื่น (เช่น webp) คืนค่า "" และข้ามรูปไป
+func loadPhoto(url string) ([]byte, string) {
+	p := localPhotoPath(url)
+	if p == "" {
+		return nil, ""
+	}
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return nil, ""
+	}
+	switch http.DetectContentType(data) {
+	case "image/jpeg":
+		return data, "JPG"
+	case "image/png":
+		return data, "PNG"
+	case "image/gif":
+		return data, "GIF"
+	}
+	return nil, ""
+}
+
+// อายุบนการ์ด: ค่าประมาณขึ้นต้นด้วย "~" และแสดงละเอียดไม่เกิน precision ของวันเกิด
+func ageText(dog entity.Dog, now time.Time) string {
+	if dog.DateOfBirth == nil || dog.DateOfBirth.After(now) {
+		return "-"
+	}
+	months := entity.AgeInMonths(*dog.DateOfBirth, now)
+	prefix := ""
+	if dog.DOBEstimated {
+		prefix = "~"
+	}
+	switch {
+	case months < 12:
+		return fmt.Sprintf("%s%d mo", prefix, months)
+	case dog.DOBPrecision == entity.DOBPrecisionYear:
+		return fmt.Sprintf("%s%d y", prefix, months/12)
+	}
+	return fmt.Sprintf("%s%d y %d mo", prefix, months/12, months%12)
+}
+
+// วัคซีนเข็มถัดไปของสุนัข (เอาเฉพาะรายการล่าสุดของแต่ละวัคซีน)
+func dueVaccines(db *gorm.DB, dogID uint) ([]vaccineDue, error) {
+	var rows []vaccineDue
+	if err := db.Table("vaccine_records").
+		Select("vaccines.name AS vaccine_name, vaccine_records.dose_number, vaccine_records.next_due_date").
+		Joins("JOIN medical_records ON medical_records.id = vaccine_records.med_id AND medical_records.deleted_at IS NULL").
+		Joins("JOIN vaccines ON vaccines.id = vaccine_records.vaccine_id").
+		Where("medical_records.dog_id = ? AND vaccine_records.deleted_at IS NULL", dogID).
+		Order("vaccine_records.next_due_date DESC").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	out := []vaccineDue{}
+	for _, r := range rows {
+		if seen[r.VaccineName] || r.NextDueDate.IsZero() {
+			continue
+		}
+		seen[r.VaccineName] = true
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].NextDueDate.Before(out[j].NextDueDate) })
+	return out, nil
+}
+
+func (cp *cardPDF) addCard(dog entity.Dog, vaccines []vaccineDue, now time.Time) error {
+	pdf, tr := cp.pdf, cp.tr
+	pdf.AddPage()
+	pageW, pageH := pdf.GetPageSize()
+	left, _, right, _ := pdf.GetMargins()
+	contentW := pageW - left - right
+
+	// หัวการ์ด: คอก / โซน
+	kennel, zone := "-", "-"
+	if dog.Kennel != nil {
+		kennel = dog.Kennel.Name
+		if dog.Kennel.Zone != nil {
+			zone = dog.Kennel.Zone.Name
+		}
+	}
+	pdf.SetFillColor(40, 40, 40)
+	pdf.SetTextColor(255, 255, 255)
+	pdf.SetFont(cp.family, "B", 12)
+	pdf.CellFormat(contentW, 9, tr(fmt.Sprintf("Zone %s  |  Kennel %s", zone, kennel)), "", 1, "C", true, 0, "")
+	pdf.SetTextColor(0, 0, 0)
+	pdf.Ln(4)
+
+	// รูป
+	photoY := pdf.GetY()
+	photoSize := 55.0
+	if data, imgType := loadPhoto(dog.PhotoURL); imgType != "" {
+		name := fmt.Sprintf("photo-%d", dog.ID)
+		opts := fpdf.ImageOptions{ImageType: imgType}
+		pdf.RegisterImageOptionsReader(name, opts, bytes.NewReader(data))
+		pdf.ImageOptions(name, left, photoY, photoSize, photoSize, false, opts, 0, "")
+	} else {
+		pdf.Rect(left, photoY, photoSize, photoSize, "D")
+	}
+
+	// ข้อมูลหลัก
+	infoX := left + photoSize + 6
+	pdf.SetXY(infoX, photoY)
+	pdf.SetFont(cp.family, "B", 22)
+	pdf.CellFormat(contentW-photoSize-6, 11, tr(dog.Name), "", 2, "L", false, 0, "")
+	pdf.Ln(2)
+
+	sex, size, breed := "-", "-", "-"
+	if dog.AnimalSex != nil {
+		sex = dog.AnimalSex.Name
+	}
+	if dog.AnimalSize != nil {
+		size = dog.AnimalSize.Name
+	}
+	if dog.Breed != nil {
+		breed = dog.Breed.Name
+	}
+	rows := [][2]string{
+		{"Sex", sex},
+		{"Age", ageText(dog, now)},
+		{"Size", size},
+		{"Breed", breed},
+		{"ID", fmt.Sprintf("#%d", dog.ID)},
+	}
+	for _, r := range rows {
+		pdf.SetX(infoX)
+		pdf.SetFont(cp.family, "B", 10)
+		pdf.CellFormat(16, 7, tr(r[0]), "", 0, "L", false, 0, "")
+		pdf.SetFont(cp.family, "", 10)
+		pdf.CellFormat(contentW-photoSize-22, 7, tr(r[1]), "", 1, "L", false, 0, "")
+	}
+
+	// วัคซีนที่ถึงกำหนด
+	pdf.SetXY(left, photoY+photoSize+6)
+	pdf.SetFont(cp.family, "B", 12)
+	pdf.CellFormat(contentW, 8, tr("Vaccinations due"), "B", 1, "L", false, 0, "")
+	pdf.SetFont(cp.family, "", 10)
+	if len(vaccines) == 0 {
+		pdf.CellFormat(contentW, 7, tr("No scheduled vaccinations"), "", 1, "L", false, 0, "")
+	}
+	for i, v := range vaccines {
+		if i >= 6 {
+			pdf.CellFormat(contentW, 7, tr(fmt.Sprintf("+ %d more", len(vaccines)-i)), "", 1, "L", false, 0, "")
+			break
+		}
+		due := v.NextDueDate.Format("2006-01-02")
+		if v.NextDueDate.Before(now) {
+			due += "  (OVERDUE)"
+			pdf.SetTextColor(200, 0, 0)
+		}
+		pdf.CellFormat(contentW*0.6, 7, tr(fmt.Sprintf("%s (dose %d)", v.VaccineName, v.DoseNumber)), "", 0, "L", false, 0, "")
+		pdf.CellFormat(contentW*0.4, 7, tr(due), "", 1, "R", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+	}
+
+	// QR ไปหน้าโปรไฟล์สาธารณะ
+	if dog.ShareCode == nil {
+		return errors.New("dog has no share code")
+	}
+	link := shareURL(*dog.ShareCode)
+	png, err := qrcode.Encode(link, qrcode.Medium, 512)
+	if err != nil {
+		return err
+	}
+	qrName := fmt.Sprintf("qr-%d", dog.ID)
+	pdf.RegisterImageOptionsReader(qrName, fpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(png))
+	qrSize := 42.0
+	qrY := pageH - 10 - qrSize - 6
+	pdf.ImageOptions(qrName, pageW-right-qrSize, qrY, qrSize, qrSize, false, fpdf.ImageOptions{ImageType: "PNG"}, 0, link)
+
+	pdf.SetXY(left, qrY+8)
+	pdf.SetFont(cp.family, "B", 14)
+	pdf.CellFormat(contentW-qrSize-4, 8, tr("Scan to meet me!"), "", 2, "L", false, 0, "")
+	pdf.SetFont(cp.family, "", 8)
+	pdf.MultiCell(contentW-qrSize-4, 4, tr(link), "", "L", false)
+	pdf.SetXY(left, pageH-10-5)
+	pdf.SetFont(cp.family, "", 7)
+	pdf.CellFormat(contentW, 5, tr("Printed "+now.Format("2006-01-02")), "", 0, "L", false, 0, "")
+
+	return pdf.Error()
+}
+
+func preloadCardDog(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Breed").
+		Preload("AnimalSex").
+		Preload("AnimalSize").
+		Preload("Kennel").
+		Preload("Kennel.Zone")
+}
+
+func renderCards(c *gin.Context, dogs []entity.Dog, filename string) {
+	db := configs.DB()
+	now := time.Now()
+	cp, err := newCardPDF()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "render failed: " + err.Error()})
+		return
+	}
+	for i := range dogs {
+		if err := ensureShareCode(db, &dogs[i]); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "share code failed: " + err.Error()})
+			return
+		}
+		vaccines, err := dueVaccines(db, dogs[i].ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "vaccine query failed: " + err.Error()})
+			return
+		}
+		if err := cp.addCard(dogs[i], vaccines, now); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "render failed: " + err.Error()})
+			return
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := cp.pdf.Output(&buf); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "render failed: " + err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="%s"`, filename))
+	c.Data(http.StatusOK, "application/pdf", buf.Bytes())
+}
+
+// GET /dogs/:id/card.pdf
+func GetDogCard(c *gin.Context) {
+	var dog entity.Dog
+	if err := preloadCardDog(configs.DB()).First(&dog, c.Param("id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "dog not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	renderCards(c, []entity.Dog{dog}, fmt.Sprintf("dog-%d-card.pdf", dog.ID))
+}
+
+// GET /zones/:id/cards.pdf?kennel_id= — การ์ดทุกตัวในโซน (1 หน้า/ตัว)
+func GetZoneCards(c *gin.Context) {
+	db := configs.DB()
+	var zone entity.Zone
+	if err := db.First(&zone, c.Param("id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "zone not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+
+	q := preloadCardDog(db).
+		Joins("JOIN kennels ON kennels.id = dogs.kennel_id").
+		Where("kennels.zone_id = ? AND dogs.is_adopted = ?", zone.ID, false)
+	if kennelID := c.Query("kennel_id"); kennelID != "" {
+		q = q.Where("kennels.id = ?", kennelID)
+	}
+	var dogs []entity.Dog
+	if err := q.Order("kennels.name ASC, dogs.name ASC").Find(&dogs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	if len(dogs) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no dogs in zone"})
+		return
+	}
+	renderCards(c, dogs, fmt.Sprintf("zone-%d-cards.pdf", zone.ID))
+}
@@ -0,0 +1,58 @@
+package dog
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"example.com/project-sa/migrations"
+	"example.com/project-sa/seeds"
+	"example.com/project-sa/utils/testutil"
+)
+
+func TestMain(m *testing.M) { testutil.Main(m, migrations.AutoMigrate, seeds.SeedAll) }
+
+func TestLatinOnly(t *testing.T) {
+	tr := latinOnly(func(s string) string {
+		if s == "é" {
+			return "\xe9"
+		}
+		if s[0] >= 0x80 {
+			return "."
+		}
+		return s
+	})
+	tests := []struct{ in, want string }{
+		{"Tor", "Tor"},
+		{"café", "caf\xe9"},
+		{"ต่อ", "?"},
+		{"Tor (ต่อ) A-1", "Tor (?) A-1"},
+		{"กรง ก", "? ?"},
+	}
+	for _, tt := range tests {
+		if got := tr(tt.in); got != tt.want {
+			t.Errorf("latinOnly(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+// ไม่มีฟอนต์ไทยก็ยังได้ PDF (ฟอนต์มาตรฐาน) ไม่ใช่ 500
+func TestCardsWithoutThaiFont(t *testing.T) {
+	t.Setenv("CARD_FONT_PATH", filepath.Join(t.TempDir(), "missing.ttf"))
+	r := testutil.Router(1)
+	r.GET("/dogs/:id/card.pdf", GetDogCard)
+	r.GET("/zones/:id/cards.pdf", GetZoneCards)
+
+	for _, path := range []string{"/dogs/1/card.pdf", "/zones/1/cards.pdf"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET %s = %d %s", path, w.Code, w.Body.String())
+		}
+		if ct := w.Header().Get("Content-Type"); ct != "application/pdf" || !bytes.HasPrefix(w.Body.Bytes(), []byte("%PDF")) {
+			t.Errorf("GET %s = %s %q..., want a PDF", path, ct, w.Body.Bytes()[:min(8, w.Body.Len())])
+		}
+	}
+}
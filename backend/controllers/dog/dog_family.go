@@ -0,0 +1,109 @@
+package dog
+
+import (
+	"errors"
+	"fmt"
+
+	"example.com/project-sa/entity"
+	"gorm.io/gorm"
+)
+
+/* ========== ครอบครัว (พ่อแม่/พี่น้อง/ลูก) ========== */
+
+// ไล่ขึ้นไปตามสายพ่อแม่ได้ไม่เกินกี่รุ่น (กันข้อมูลวนลูป)
+const maxAncestorDepth = 10
+
+// validateParent ตรวจว่าตั้ง parentID เป็นพ่อ/แม่ของ dogID ได้
+// (มีอยู่จริง, ไม่ใช่ตัวเอง, และไม่ใช่ลูกหลานของ dogID)
+func validateParent(tx *gorm.DB, dogID, parentID uint, field string) error {
+	if parentID == dogID {
+		return fmt.Errorf("%s cannot be the dog itself", field)
+	}
+	var parent entity.Dog
+	if err := tx.First(&parent, parentID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("invalid %s", field)
+		}
+		return err
+	}
+	if dogID == 0 {
+		return nil
+	}
+	frontier := []uint{parent.ID}
+	for depth := 0; depth < maxAncestorDepth && len(frontier) > 0; depth++ {
+		var ups []entity.Dog
+		if err := tx.Select("id, mother_id, father_id").Where("id IN ?", frontier).Find(&ups).Error; err != nil {
+			return err
+		}
+		frontier = frontier[:0]
+		for _, u := range ups {
+			for _, p := range []*uint{u.MotherID, u.FatherID} {
+				if p == nil {
+					continue
+				}
+				if *p == dogID {
+					return fmt.Errorf("%s would create a cycle in the family tree", field)
+				}
+				frontier = append(frontier, *p)
+			}
+		}
+	}
+	return nil
+}
+
+// loadRelatives สุนัขที่เกี่ยวข้องกับ dog (ใช้ข้อมูลแบบย่อ ไม่ preload ลึก)
+func loadRelatives(db *gorm.DB, dog entity.Dog) (*entity.DogRelatives, error) {
+	rel := &entity.DogRelatives{
+		Littermates:  []entity.Dog{},
+		HalfSiblings: []entity.Dog{},
+		Offspring:    []entity.Dog{},
+	}
+	for _, p := range []struct {
+		id  *uint
+		dst **entity.Dog
+	}{{dog.MotherID, &rel.Mother}, {dog.FatherID, &rel.Father}} {
+		if p.id == nil {
+			continue
+		}
+		var d entity.Dog
+		err := db.First(&d, *p.id).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		*p.dst = &d
+	}
+
+	if dog.LitterID != nil {
+		if err := db.Where("litter_id = ? AND id <> ?", *dog.LitterID, dog.ID).
+			Order("id ASC").Find(&rel.Littermates).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	if dog.MotherID != nil || dog.FatherID != nil {
+		q := db.Where("id <> ?", dog.ID)
+		switch {
+		case dog.MotherID != nil && dog.FatherID != nil:
+			q = q.Where("mother_id = ? OR father_id = ?", *dog.MotherID, *dog.FatherID)
+		case dog.MotherID != nil:
+			q = q.Where("mother_id = ?", *dog.MotherID)
+		default:
+			q = q.Where("father_id = ?", *dog.FatherID)
+		}
+		if dog.LitterID != nil {
+			q = q.Where("litter_id IS NULL OR litter_id <> ?", *dog.LitterID)
+		}
+		if err := q.Order("id ASC").Find(&rel.HalfSiblings).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	if err := db.Where("mother_id = ? OR father_id = ?", dog.ID, dog.ID).
+		Order("id ASC").Find(&rel.Offspring).Error; err != nil {
+		return nil, err
+	}
+	return rel, nil
+}
@@ -0,0 +1,481 @@
+package dog
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/middlewares"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+/* ========== Change history (audit รายฟิลด์) ========== */
+
+const (
+	fieldPersonalityIDs = "personality_ids"
+	fieldDeletedAt      = "deleted_at"
+)
+
+// ฟิลด์ที่เก็บประวัติ/ย้อนกลับได้ -> ชนิดข้อมูล (ใช้แปลงค่าตอน revert)
+var dogTrackedFields = map[string]string{
+	"name":           "string",
+	"date_of_birth":  "date_col",
+	"dob_estimated":  "bool",
+	"dob_precision":  "string",
+	"sterilized_at":  "date_col",
+	"photo_url":      "string",
+	"color":          "string",
+	"intake_date":    "date",
+	"intake_area":    "string",
+	"ready_to_adopt": "bool",
+	"is_adopted":     "bool",
+	"breed_id":       "uint",
+	"kennel_id":      "uint_ptr",
+	"animal_sex_id":  "uint",
+	"animal_size_id": "uint",
+	"litter_id":      "uint_ptr",
+	"mother_id":      "uint_ptr",
+	"father_id":      "uint_ptr",
+}
+
+func dogFieldValue(d entity.Dog, field string) string {
+	switch field {
+	case "name":
+		return d.Name
+	case "date_of_birth":
+		return entity.FormatDogChangeValue(d.DateOfBirth)
+	case "dob_estimated":
+		return strconv.FormatBool(d.DOBEstimated)
+	case "dob_precision":
+		return d.DOBPrecision
+	case "sterilized_at":
+		return entity.FormatDogChangeValue(d.SterilizedAt)
+	case "photo_url":
+		return d.PhotoURL
+	case "color":
+		return d.Color
+	case "intake_date":
+		return d.IntakeDate
+	case "intake_area":
+		return d.IntakeArea
+	case "ready_to_adopt":
+		return strconv.FormatBool(d.ReadyToAdopt)
+	case "is_adopted":
+		return strconv.FormatBool(d.IsAdopted)
+	case "breed_id":
+		return entity.FormatDogChangeValue(d.BreedID)
+	case "kennel_id":
+		return entity.FormatDogChangeValue(d.KennelID)
+	case "animal_sex_id":
+		return entity.FormatDogChangeValue(d.AnimalSexID)
+	case "animal_size_id":
+		return entity.FormatDogChangeValue(d.AnimalSizeID)
+	case "litter_id":
+		return entity.FormatDogChangeValue(d.LitterID)
+	case "mother_id":
+		return entity.FormatDogChangeValue(d.MotherID)
+	case "father_id":
+		return entity.FormatDogChangeValue(d.FatherID)
+	}
+	return ""
+}
+
+// แปลงค่าที่เก็บเป็น string กลับเป็นชนิดของคอลัมน์
+func parseChangeValue(field, s string) (any, error) {
+	switch dogTrackedFields[field] {
+	case "string":
+		return s, nil
+	case "date":
+		if _, err := parseYMD(s); err != nil {
+			return nil, fmt.Errorf("invalid %s", field)
+		}
+		return s, nil
+	case "date_col":
+		d, err := entity.ParseDate(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s", field)
+		}
+		return d, nil
+	case "bool":
+		return strconv.ParseBool(s)
+	case "uint":
+		u, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s", field)
+		}
+		return uint(u), nil
+	case "uint_ptr":
+		if s == "" {
+			return nil, nil
+		}
+		u, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s", field)
+		}
+		return uint(u), nil
+	}
+	return nil, fmt.Errorf("field %s cannot be reverted", field)
+}
+
+func parseIDList(s string) ([]uint, error) {
+	ids := []uint{}
+	if s == "" {
+		return ids, nil
+	}
+	for _, p := range strings.Split(s, ",") {
+		u, err := strconv.ParseUint(strings.TrimSpace(p), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s", fieldPersonalityIDs)
+		}
+		ids = append(ids, uint(u))
+	}
+	return ids, nil
+}
+
+func dogPersonalityIDs(tx *gorm.DB, dogID uint) ([]uint, error) {
+	var ids []uint
+	if err := tx.Model(&entity.DogPersonality{}).
+		Where("dog_id = ?", dogID).
+		Order("personality_id ASC").
+		Pluck("personality_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func uniqueSortedIDs(ids []uint) []uint {
+	seen := map[uint]struct{}{}
+	out := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		out = append(out, id)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+// บันทึก 1 แถวต่อฟิลด์ที่ค่าเปลี่ยนจริง (ข้ามฟิลด์ audit อย่าง updated_by_id)
+func recordDogChanges(tx *gorm.DB, before entity.Dog, updates map[string]any, action string, staffID *uint, revertOf *uint) error {
+	fields := make([]string, 0, len(updates))
+	for f := range updates {
+		if _, ok := dogTrackedFields[f]; ok {
+			fields = append(fields, f)
+		}
+	}
+	sort.Strings(fields)
+
+	rows := make([]entity.DogChange, 0, len(fields))
+	for _, f := range fields {
+		row := entity.NewDogChange(before.ID, action, f, dogFieldValue(before, f), updates[f], staffID)
+		if row.OldValue == row.NewValue {
+			continue
+		}
+		row.RevertOfID = revertOf
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	if err := tx.Create(&rows).Error; err != nil {
+		return err
+	}
+	// ย้ายคอกลงสมุดย้ายคอกด้วย
+	for _, r := range rows {
+		if r.Field != "kennel_id" {
+			continue
+		}
+		reason := ""
+		if action == entity.DogChangeRevert {
+			reason = "revert"
+		}
+		if err := entity.RecordKennelMove(tx, before.ID, kennelRef(r.OldValue), kennelRef(r.NewValue), staffID, reason); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func kennelRef(s string) *uint {
+	id, err := strconv.ParseUint(s, 10, 64)
+	if err != nil || id == 0 {
+		return nil
+	}
+	v := uint(id)
+	return &v
+}
+
+func recordPersonalityChange(tx *gorm.DB, dogID uint, oldIDs, newIDs []uint, action string, staffID *uint, revertOf *uint) error {
+	row := entity.NewDogChange(dogID, action, fieldPersonalityIDs, oldIDs, newIDs, staffID)
+	if row.OldValue == row.NewValue {
+		return nil
+	}
+	row.RevertOfID = revertOf
+	return tx.Create(&row).Error
+}
+
+// replace ทั้งชุด personality ของสุนัข
+func replaceDogPersonalities(tx *gorm.DB, dogID uint, ids []uint) error {
+	if err := tx.Unscoped().
+		Where("dog_id = ?", dogID).
+		Delete(&entity.DogPersonality{}).Error; err != nil {
+		return err
+	}
+	ids = uniqueSortedIDs(ids)
+	if len(ids) == 0 {
+		return nil
+	}
+	rows := make([]entity.DogPersonality, 0, len(ids))
+	for _, pid := range ids {
+		rows = append(rows, entity.DogPersonality{DogID: dogID, PersonalityID: pid})
+	}
+	return tx.Create(&rows).Error
+}
+
+/* ========== Handlers ========== */
+
+// GET /dogs/:id/history
+func GetDogHistory(c *gin.Context) {
+	id := c.Param("id")
+
+	// ดูประวัติได้แม้สุนัขถูก soft delete ไปแล้ว
+	var dog entity.Dog
+	if err := configs.DB().Unscoped().First(&dog, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "dog not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+
+	db := configs.DB().Preload("Staff").Where("dog_id = ?", dog.ID)
+	if field := c.Query("field"); field != "" {
+		db = db.Where("field = ?", field)
+	}
+
+	var changes []entity.DogChange
+	if err := db.Order("changed_at DESC, id DESC").Find(&changes).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": changes})
+}
+
+var dobFields = map[string]bool{"date_of_birth": true, "dob_estimated": true, "dob_precision": true}
+
+// dobSiblings แถววันเกิดที่บันทึกในครั้งเดียวกับ change (recordDogChanges สร้างเป็น batch
+// เรียงชื่อฟิลด์ ทั้งสามฟิลด์จึงมี id ติดกัน)
+func dobSiblings(tx *gorm.DB, change entity.DogChange) ([]entity.DogChange, error) {
+	lo := uint(1)
+	if change.ID > 2 {
+		lo = change.ID - 2
+	}
+	var rows []entity.DogChange
+	if err := tx.Where("dog_id = ? AND action = ? AND field IN ? AND id <> ? AND id BETWEEN ? AND ?",
+		change.DogID, change.Action, []string{"date_of_birth", "dob_estimated", "dob_precision"},
+		change.ID, lo, change.ID+2).
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := rows[:0]
+	for _, r := range rows {
+		if r.Field == change.Field || !sameStaff(r.StaffID, change.StaffID) {
+			continue
+		}
+		if d := r.ChangedAt.Sub(change.ChangedAt); d < -time.Second || d > time.Second {
+			continue
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+func sameStaff(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// POST /dogs/:id/history/:change_id/revert — เฉพาะ admin
+func RevertDogChange(c *gin.Context) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var staff entity.Staff
+	if err := configs.DB().Select("id", "role").First(&staff, *staffID).Error; err != nil || !staff.IsAdmin() {
+		c.JSON(http.StatusForbidden, gin.H{"error": "admin only"})
+		return
+	}
+
+	var change entity.DogChange
+	if err := configs.DB().
+		Where("id = ? AND dog_id = ?", c.Param("change_id"), c.Param("id")).
+		First(&change).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "change not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+
+	var status int
+	err := configs.DB().Transaction(func(tx *gorm.DB) error {
+		var dog entity.Dog
+		if err := tx.Unscoped().First(&dog, change.DogID).Error; err != nil {
+			return err
+		}
+
+		switch change.Field {
+		case fieldDeletedAt:
+			// ย้อนการลบ = กู้คืนสุนัข
+			if !dog.DeletedAt.Valid {
+				status = http.StatusConflict
+				return errors.New("dog is not deleted")
+			}
+			if err := tx.Unscoped().Model(&dog).Updates(map[string]any{
+				"deleted_at":    nil,
+				"deleted_by_id": nil,
+				"updated_by_id": *staffID,
+			}).Error; err != nil {
+				return err
+			}
+			row := entity.NewDogChange(dog.ID, entity.DogChangeRevert, fieldDeletedAt, change.NewValue, nil, staffID)
+			row.RevertOfID = &change.ID
+			return tx.Create(&row).Error
+
+		case fieldPersonalityIDs:
+			current, err := dogPersonalityIDs(tx, dog.ID)
+			if err != nil {
+				return err
+			}
+			if entity.FormatDogChangeValue(current) != change.NewValue {
+				status = http.StatusConflict
+				return fmt.Errorf("%s has changed since (current: %q)", change.Field, entity.FormatDogChangeValue(current))
+			}
+			target, err := parseIDList(change.OldValue)
+			if err != nil {
+				status = http.StatusUnprocessableEntity
+				return err
+			}
+			if err := replaceDogPersonalities(tx, dog.ID, target); err != nil {
+				return err
+			}
+			if err := tx.Model(&dog).Update("updated_by_id", *staffID).Error; err != nil {
+				return err
+			}
+			return recordPersonalityChange(tx, dog.ID, current, target, entity.DogChangeRevert, staffID, &change.ID)
+		}
+
+		if dog.DeletedAt.Valid {
+			status = http.StatusConflict
+			return errors.New("dog is deleted; revert the deletion first")
+		}
+		// status เปลี่ยนผ่าน flow ของตัวเองเท่านั้น (ส่งต่อ/บ้านอุปถัมภ์/เสียชีวิต)
+		if _, ok := dogTrackedFields[change.Field]; !ok {
+			status = http.StatusUnprocessableEntity
+			return fmt.Errorf("field %s cannot be reverted", change.Field)
+		}
+		if dog.Status == entity.DogStatusDeceased {
+			status = http.StatusConflict
+			return errors.New("dog is deceased")
+		}
+		// วันเกิด/ความละเอียด/ประมาณการ แก้พร้อมกันเสมอ ย้อนทั้งชุด
+		batch := []entity.DogChange{change}
+		if dobFields[change.Field] {
+			siblings, err := dobSiblings(tx, change)
+			if err != nil {
+				return err
+			}
+			batch = append(batch, siblings...)
+		}
+		// กันเขียนทับการแก้ไขที่เกิดขึ้นหลังจากรายการนี้ (ตรวจก่อนเงื่อนไขคอก)
+		for _, ch := range batch {
+			if current := dogFieldValue(dog, ch.Field); current != ch.NewValue {
+				status = http.StatusConflict
+				return fmt.Errorf("%s has changed since (current: %q)", ch.Field, current)
+			}
+		}
+		if change.Field == "kennel_id" && dog.Status != entity.DogStatusShelter {
+			status = http.StatusConflict
+			return errors.New("dog is not in the shelter (status: " + dog.Status + ")")
+		}
+		if change.Field == "kennel_id" {
+			if id, err := strconv.ParseUint(change.OldValue, 10, 64); err == nil && id > 0 {
+				if err := entity.CheckKennelIsolation(tx, dog.ID, uint(id)); err != nil {
+					if entity.IsIsolationError(err) {
+						status = http.StatusConflict
+					}
+					return err
+				}
+				if _, err := entity.ReserveKennelSlot(tx, uint(id), dog.ID); err != nil {
+					if entity.IsCapacityError(err) {
+						status = http.StatusConflict
+					}
+					return err
+				}
+				if _, err := entity.CheckKennelRules(tx, dog.ID, uint(id)); err != nil {
+					if entity.IsCompatibilityError(err) {
+						status = http.StatusConflict
+					}
+					return err
+				}
+			}
+		}
+		updates := map[string]any{}
+		for _, ch := range batch {
+			v, err := parseChangeValue(ch.Field, ch.OldValue)
+			if err != nil {
+				status = http.StatusUnprocessableEntity
+				return err
+			}
+			updates[ch.Field] = v
+		}
+		if ready, ok := updates["ready_to_adopt"].(bool); ok && ready {
+			blocked, err := adoptionBlockedByAssessment(tx, dog.ID)
+			if err != nil {
+				return err
+			}
+			if blocked {
+				status = http.StatusConflict
+				return errors.New("latest behavior assessment is not safe for adoption")
+			}
+		}
+		if err := recordDogChanges(tx, dog, updates, entity.DogChangeRevert, staffID, &change.ID); err != nil {
+			return err
+		}
+		updates["updated_by_id"] = *staffID
+		return tx.Model(&dog).Updates(updates).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "dog not found"})
+			return
+		}
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, gin.H{"error": "revert failed: " + err.Error()})
+		return
+	}
+
+	var out entity.Dog
+	if err := preloadDog(configs.DB()).First(&out, change.DogID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
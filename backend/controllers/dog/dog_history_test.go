@@ -0,0 +1,87 @@
+package dog
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/utils/testutil"
+	"github.com/gin-gonic/gin"
+)
+
+func historyRouter() *gin.Engine {
+	r := testutil.Router(1)
+	r.PUT("/dogs/:id", UpdateDog)
+	r.POST("/dogs/:id/history/:change_id/revert", RevertDogChange)
+	return r
+}
+
+func lastChange(t *testing.T, dogID uint, field string) entity.DogChange {
+	t.Helper()
+	var ch entity.DogChange
+	if err := configs.DB().Where("dog_id = ? AND field = ?", dogID, field).Order("id DESC").First(&ch).Error; err != nil {
+		t.Fatalf("change %s: %v", field, err)
+	}
+	return ch
+}
+
+func TestRevertDateOfBirthRevertsPrecisionAndEstimate(t *testing.T) {
+	db := configs.DB()
+	dob, err := entity.ParseDate("2020-05-10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dog := entity.Dog{Name: "Dob", BreedID: 1, AnimalSexID: 1, AnimalSizeID: 1, Status: entity.DogStatusShelter,
+		DateOfBirth: dob, DOBPrecision: entity.DOBPrecisionDay}
+	if err := db.Create(&dog).Error; err != nil {
+		t.Fatalf("create dog: %v", err)
+	}
+
+	r := historyRouter()
+	testutil.MustDo(t, r, http.MethodPut, fmt.Sprintf("/dogs/%d", dog.ID), gin.H{"date_of_birth": "2021-03", "dob_estimated": true})
+	ch := lastChange(t, dog.ID, "date_of_birth")
+	testutil.MustDo(t, r, http.MethodPost, fmt.Sprintf("/dogs/%d/history/%d/revert", dog.ID, ch.ID), nil)
+
+	var got entity.Dog
+	if err := db.First(&got, dog.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if entity.FormatDogChangeValue(got.DateOfBirth) != "2020-05-10" || got.DOBPrecision != entity.DOBPrecisionDay || got.DOBEstimated {
+		t.Errorf("after revert dob = %s precision = %q estimated = %v, want 2020-05-10 day false",
+			entity.FormatDogChangeValue(got.DateOfBirth), got.DOBPrecision, got.DOBEstimated)
+	}
+}
+
+func TestRevertStaleKennelChangeReportsConflictFirst(t *testing.T) {
+	db := configs.DB()
+	var kennels []entity.Kennel
+	for _, name := range []string{"RV-A", "RV-B", "RV-C"} {
+		k := entity.Kennel{Name: name, Capacity: 1, ZoneID: 1}
+		if err := db.Create(&k).Error; err != nil {
+			t.Fatalf("create kennel: %v", err)
+		}
+		kennels = append(kennels, k)
+	}
+	dog := entity.Dog{Name: "Rv", KennelID: &kennels[0].ID, BreedID: 1, AnimalSexID: 1, AnimalSizeID: 1, Status: entity.DogStatusShelter}
+	if err := db.Create(&dog).Error; err != nil {
+		t.Fatalf("create dog: %v", err)
+	}
+
+	r := historyRouter()
+	testutil.MustDo(t, r, http.MethodPut, fmt.Sprintf("/dogs/%d", dog.ID), gin.H{"kennel_id": kennels[1].ID})
+	ch := lastChange(t, dog.ID, "kennel_id")
+	testutil.MustDo(t, r, http.MethodPut, fmt.Sprintf("/dogs/%d", dog.ID), gin.H{"kennel_id": kennels[2].ID})
+
+	// คอกเดิมเต็มแล้ว แต่ต้องตอบว่ารายการนี้เก่าไปก่อน ไม่ใช่คอกเต็ม
+	other := entity.Dog{Name: "Rv2", KennelID: &kennels[0].ID, BreedID: 1, AnimalSexID: 1, AnimalSizeID: 1, Status: entity.DogStatusShelter}
+	if err := db.Create(&other).Error; err != nil {
+		t.Fatalf("create dog: %v", err)
+	}
+	code, body := testutil.Do(t, r, http.MethodPost, fmt.Sprintf("/dogs/%d/history/%d/revert", dog.ID, ch.ID), nil)
+	if code != http.StatusConflict || !strings.Contains(fmt.Sprint(body["error"]), "has changed since") {
+		t.Errorf("revert stale kennel change = %d %v, want 409 has changed since", code, body)
+	}
+}
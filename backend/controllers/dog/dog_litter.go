@@ -0,0 +1,652 @@
+package dog
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"example.com/project-sa/configs"
+	health_records "example.com/project-sa/controllers/health_record"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/middlewares"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+/* ========== Litters (ครอก) ========== */
+
+type litterRequest struct {
+	Name         *string `json:"name"`
+	BirthDate    *string `json:"birth_date"` // "YYYY-MM-DD" | "YYYY-MM" | "YYYY" ("" = ไม่ทราบ)
+	DOBEstimated *bool   `json:"dob_estimated"`
+	Notes        *string `json:"notes"`
+	MotherID     *uint   `json:"mother_id"` // 0 = ไม่ทราบ
+	FatherID     *uint   `json:"father_id"`
+	DogIDs       []uint  `json:"dog_ids"` // ใช้ตอนสร้าง/เพิ่มสมาชิก
+}
+
+type litterDogsRequest struct {
+	DogIDs []uint `json:"dog_ids" binding:"required,min=1"`
+}
+
+// บันทึกสุขภาพ/วัคซีนให้ทั้งครอกในครั้งเดียว
+type litterVaccineIn struct {
+	VaccineID   uint   `json:"vaccine_id" binding:"required"`
+	DoseNumber  int    `json:"dose_number"`
+	LotNumber   string `json:"lot_number"`
+	NextDueDate string `json:"next_due_date"` // "YYYY-MM-DD" หรือ RFC3339
+}
+
+type litterHealthRecordRequest struct {
+	DateRecord     string            `json:"date_record"` // ว่าง = ตอนนี้
+	Symptoms       string            `json:"symptoms"`
+	Diagnosis      string            `json:"diagnosis"`
+	Treatment      string            `json:"treatment"`
+	Medication     string            `json:"medication"`
+	Notes          string            `json:"notes"`
+	VaccineRecords []litterVaccineIn `json:"vaccine_records"`
+	IncludeMother  bool              `json:"include_mother"`
+	DogIDs         []uint            `json:"dog_ids"` // ว่าง = ทุกตัวในครอก
+}
+
+type litterVaccinationRequest struct {
+	litterVaccineIn
+	DateRecord    string `json:"date_record"`
+	Notes         string `json:"notes"`
+	IncludeMother bool   `json:"include_mother"`
+	DogIDs        []uint `json:"dog_ids"`
+}
+
+type bulkRecordOut struct {
+	DogID           uint   `json:"dog_id"`
+	DogName         string `json:"dog_name"`
+	MedicalRecordID uint   `json:"medical_record_id"`
+}
+
+type bulkSkippedOut struct {
+	DogID   uint   `json:"dog_id"`
+	DogName string `json:"dog_name"`
+	Reason  string `json:"reason"`
+}
+
+func parseRecordTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Now(), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", s)
+}
+
+func optionalID(p *uint) *uint {
+	if p == nil || *p == 0 {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
+func sameID(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// เติมพ่อแม่ของครอกเพื่อส่งออก
+func attachParents(db *gorm.DB, l *entity.Litter) error {
+	for _, p := range []struct {
+		id  *uint
+		dst **entity.Dog
+	}{{l.MotherID, &l.Mother}, {l.FatherID, &l.Father}} {
+		if p.id == nil {
+			continue
+		}
+		var d entity.Dog
+		err := db.First(&d, *p.id).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		*p.dst = &d
+	}
+	return nil
+}
+
+func loadLitter(db *gorm.DB, id any) (*entity.Litter, error) {
+	var l entity.Litter
+	if err := db.Preload("Dogs", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		First(&l, id).Error; err != nil {
+		return nil, err
+	}
+	if err := attachParents(db, &l); err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// applyLitterToDog ผูกสุนัขเข้าครอก: พ่อแม่ตามครอก และวันเกิดตามครอกถ้ายังไม่มี
+func applyLitterToDog(tx *gorm.DB, l *entity.Litter, dog entity.Dog, staffID *uint) error {
+	updates := map[string]any{"litter_id": l.ID}
+	if l.MotherID != nil {
+		updates["mother_id"] = *l.MotherID
+	}
+	if l.FatherID != nil {
+		updates["father_id"] = *l.FatherID
+	}
+	if dog.DateOfBirth == nil && l.BirthDate != nil {
+		updates["date_of_birth"] = l.BirthDate
+		updates["dob_precision"] = l.DOBPrecision
+		updates["dob_estimated"] = l.DOBEstimated
+	}
+	if err := recordDogChanges(tx, dog, updates, entity.DogChangeUpdate, staffID, nil); err != nil {
+		return err
+	}
+	if staffID != nil {
+		updates["updated_by_id"] = *staffID
+	}
+	return tx.Model(&dog).Updates(updates).Error
+}
+
+// addDogsToLitter คืน http status ที่เหมาะสมเมื่อผิดพลาด
+func addDogsToLitter(tx *gorm.DB, l *entity.Litter, ids []uint, staffID *uint) (int, error) {
+	for _, id := range uniqueSortedIDs(ids) {
+		var dog entity.Dog
+		if err := tx.First(&dog, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return http.StatusBadRequest, fmt.Errorf("dog %d not found", id)
+			}
+			return 0, err
+		}
+		if sameID(&dog.ID, l.MotherID) || sameID(&dog.ID, l.FatherID) {
+			return http.StatusBadRequest, fmt.Errorf("dog %d is a parent of this litter", id)
+		}
+		if dog.LitterID != nil && *dog.LitterID != l.ID {
+			return http.StatusConflict, fmt.Errorf("dog %d already belongs to litter %d", id, *dog.LitterID)
+		}
+		for _, p := range []struct {
+			id    *uint
+			field string
+		}{{l.MotherID, "mother_id"}, {l.FatherID, "father_id"}} {
+			if p.id == nil {
+				continue
+			}
+			if err := validateParent(tx, dog.ID, *p.id, p.field); err != nil {
+				return http.StatusBadRequest, err
+			}
+		}
+		if err := applyLitterToDog(tx, l, dog, staffID); err != nil {
+			return 0, err
+		}
+	}
+	return 0, nil
+}
+
+// applyLitterFields ใช้ร่วมกันระหว่าง create/update
+func applyLitterFields(l *entity.Litter, req litterRequest) error {
+	if req.Name != nil {
+		l.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Notes != nil {
+		l.Notes = *req.Notes
+	}
+	if req.MotherID != nil {
+		l.MotherID = optionalID(req.MotherID)
+	}
+	if req.FatherID != nil {
+		l.FatherID = optionalID(req.FatherID)
+	}
+	if l.MotherID != nil && sameID(l.MotherID, l.FatherID) {
+		return errors.New("mother_id and father_id must differ")
+	}
+	cols, err := dobInput{DateOfBirth: req.BirthDate, DOBEstimated: req.DOBEstimated}.resolve()
+	if err != nil {
+		return err
+	}
+	if _, ok := cols["date_of_birth"]; ok {
+		l.BirthDate, _ = cols["date_of_birth"].(*entity.Date)
+		l.DOBPrecision = cols["dob_precision"].(string)
+	}
+	if v, ok := cols["dob_estimated"].(bool); ok {
+		l.DOBEstimated = v
+	}
+	if l.DOBPrecision == "" {
+		l.DOBPrecision = entity.DOBPrecisionDay
+	}
+	return nil
+}
+
+func validateLitterParents(tx *gorm.DB, l *entity.Litter) error {
+	for _, p := range []struct {
+		id    *uint
+		field string
+	}{{l.MotherID, "mother_id"}, {l.FatherID, "father_id"}} {
+		if p.id == nil {
+			continue
+		}
+		if err := validateParent(tx, 0, *p.id, p.field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// POST /litters
+func CreateLitter(c *gin.Context) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req litterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	var l entity.Litter
+	if err := applyLitterFields(&l, req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if l.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+
+	var status int
+	err := configs.DB().Transaction(func(tx *gorm.DB) error {
+		if err := validateLitterParents(tx, &l); err != nil {
+			status = http.StatusBadRequest
+			return err
+		}
+		if err := tx.Create(&l).Error; err != nil {
+			return err
+		}
+		st, err := addDogsToLitter(tx, &l, req.DogIDs, staffID)
+		status = st
+		return err
+	})
+	if err != nil {
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, gin.H{"error": "create failed: " + err.Error()})
+		return
+	}
+	out, err := loadLitter(configs.DB(), l.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": out})
+}
+
+// GET /litters
+func GetLitters(c *gin.Context) {
+	var rows []entity.Litter
+	if err := configs.DB().
+		Preload("Dogs", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Order("id DESC").Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	for i := range rows {
+		if err := attachParents(configs.DB(), &rows[i]); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"data": rows})
+}
+
+// GET /litters/:id
+func GetLitter(c *gin.Context) {
+	l, err := loadLitter(configs.DB(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "litter not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": l})
+}
+
+// PUT /litters/:id — เปลี่ยนพ่อแม่/วันเกิดของครอก จะส่งต่อให้สมาชิกทุกตัว
+func UpdateLitter(c *gin.Context) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req litterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+
+	var l entity.Litter
+	var status int
+	err := configs.DB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Preload("Dogs").First(&l, c.Param("id")).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				status = http.StatusNotFound
+				return errors.New("litter not found")
+			}
+			return err
+		}
+		before := l
+		if err := applyLitterFields(&l, req); err != nil {
+			status = http.StatusBadRequest
+			return err
+		}
+		if l.Name == "" {
+			status = http.StatusBadRequest
+			return errors.New("name is required")
+		}
+		if err := validateLitterParents(tx, &l); err != nil {
+			status = http.StatusBadRequest
+			return err
+		}
+		members := l.Dogs
+		l.Dogs = nil
+		if err := tx.Save(&l).Error; err != nil {
+			return err
+		}
+
+		parentsChanged := !sameID(before.MotherID, l.MotherID) || !sameID(before.FatherID, l.FatherID)
+		dobChanged := entity.FormatDogChangeValue(before.BirthDate) != entity.FormatDogChangeValue(l.BirthDate) ||
+			before.DOBPrecision != l.DOBPrecision || before.DOBEstimated != l.DOBEstimated
+		for _, dog := range members {
+			if sameID(&dog.ID, l.MotherID) || sameID(&dog.ID, l.FatherID) {
+				status = http.StatusBadRequest
+				return fmt.Errorf("dog %d is a member of this litter", dog.ID)
+			}
+			updates := map[string]any{}
+			if parentsChanged {
+				for _, p := range []struct {
+					id    *uint
+					field string
+				}{{l.MotherID, "mother_id"}, {l.FatherID, "father_id"}} {
+					if p.id == nil {
+						updates[p.field] = nil
+						continue
+					}
+					if err := validateParent(tx, dog.ID, *p.id, p.field); err != nil {
+						status = http.StatusBadRequest
+						return err
+					}
+					updates[p.field] = *p.id
+				}
+			}
+			// สมาชิกที่ใช้วันเกิดตามครอก (หรือยังไม่มี) ให้เปลี่ยนตาม
+			if dobChanged && (dog.DateOfBirth == nil || entity.FormatDogChangeValue(dog.DateOfBirth) == entity.FormatDogChangeValue(before.BirthDate)) {
+				updates["date_of_birth"] = l.BirthDate
+				updates["dob_precision"] = l.DOBPrecision
+				updates["dob_estimated"] = l.DOBEstimated
+			}
+			if len(updates) == 0 {
+				continue
+			}
+			if err := recordDogChanges(tx, dog, updates, entity.DogChangeUpdate, staffID, nil); err != nil {
+				return err
+			}
+			updates["updated_by_id"] = *staffID
+			if err := tx.Model(&dog).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, gin.H{"error": "update failed: " + err.Error()})
+		return
+	}
+	out, err := loadLitter(configs.DB(), l.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": out})
+}
+
+// POST /litters/:id/dogs
+func AddLitterDogs(c *gin.Context) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req litterDogsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	var l entity.Litter
+	var status int
+	err := configs.DB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&l, c.Param("id")).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				status = http.StatusNotFound
+				return errors.New("litter not found")
+			}
+			return err
+		}
+		st, err := addDogsToLitter(tx, &l, req.DogIDs, staffID)
+		status = st
+		return err
+	})
+	if err != nil {
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, gin.H{"error": "add failed: " + err.Error()})
+		return
+	}
+	out, err := loadLitter(configs.DB(), l.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": out})
+}
+
+// DELETE /litters/:id/dogs/:dog_id — เอาออกจากครอก (พ่อแม่ยังคงเดิม)
+func RemoveLitterDog(c *gin.Context) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var dog entity.Dog
+	if err := configs.DB().Where("id = ? AND litter_id = ?", c.Param("dog_id"), c.Param("id")).
+		First(&dog).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "dog is not in this litter"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	err := configs.DB().Transaction(func(tx *gorm.DB) error {
+		updates := map[string]any{"litter_id": nil}
+		if err := recordDogChanges(tx, dog, updates, entity.DogChangeUpdate, staffID, nil); err != nil {
+			return err
+		}
+		updates["updated_by_id"] = *staffID
+		return tx.Model(&dog).Updates(updates).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed: " + err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// POST /litters/:id/health-records — สร้าง MedicalRecord (และวัคซีน ถ้ามี) ให้สมาชิกทุกตัว
+func CreateLitterHealthRecords(c *gin.Context) {
+	var req litterHealthRecordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	createLitterRecords(c, req)
+}
+
+// POST /litters/:id/vaccinations — ทางลัดสำหรับฉีดวัคซีนตัวเดียวกันทั้งครอก
+func CreateLitterVaccinations(c *gin.Context) {
+	var req litterVaccinationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	createLitterRecords(c, litterHealthRecordRequest{
+		DateRecord:     req.DateRecord,
+		Notes:          req.Notes,
+		VaccineRecords: []litterVaccineIn{req.litterVaccineIn},
+		IncludeMother:  req.IncludeMother,
+		DogIDs:         req.DogIDs,
+	})
+}
+
+func createLitterRecords(c *gin.Context, req litterHealthRecordRequest) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	recordedAt, err := parseRecordTime(req.DateRecord)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date_record"})
+		return
+	}
+	dues := make([]time.Time, len(req.VaccineRecords))
+	for i, v := range req.VaccineRecords {
+		if v.NextDueDate == "" {
+			continue
+		}
+		if dues[i], err = parseRecordTime(v.NextDueDate); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid next_due_date"})
+			return
+		}
+	}
+
+	l, err := loadLitter(configs.DB(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "litter not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+
+	// เลือกสมาชิก (ทั้งครอก หรือเฉพาะ dog_ids) + แม่ถ้าขอ
+	targets := l.Dogs
+	if len(req.DogIDs) > 0 {
+		want := map[uint]bool{}
+		for _, id := range req.DogIDs {
+			want[id] = true
+		}
+		targets = targets[:0:0]
+		for _, d := range l.Dogs {
+			if want[d.ID] {
+				targets = append(targets, d)
+				delete(want, d.ID)
+			}
+		}
+		if len(want) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "dog_ids must belong to this litter"})
+			return
+		}
+	}
+	if req.IncludeMother && l.Mother != nil {
+		targets = append(targets, *l.Mother)
+	}
+
+	records := []bulkRecordOut{}
+	skipped := []bulkSkippedOut{}
+	var status int
+	err = configs.DB().Transaction(func(tx *gorm.DB) error {
+		for _, v := range req.VaccineRecords {
+			var vac entity.Vaccine
+			if err := tx.First(&vac, v.VaccineID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					status = http.StatusBadRequest
+					return fmt.Errorf("invalid vaccine_id %d", v.VaccineID)
+				}
+				return err
+			}
+		}
+		vaccination := entity.VaccinationNo
+		if len(req.VaccineRecords) > 0 {
+			vaccination = entity.VaccinationYes
+		}
+		for _, dog := range targets {
+			// เฉพาะตัวที่ยังอยู่ในความดูแล
+			if dog.IsAdopted || (dog.Status != entity.DogStatusShelter && dog.Status != entity.DogStatusFoster) {
+				reason := dog.Status
+				if dog.IsAdopted {
+					reason = "adopted"
+				}
+				skipped = append(skipped, bulkSkippedOut{DogID: dog.ID, DogName: dog.Name, Reason: reason})
+				continue
+			}
+			mr := entity.MedicalRecord{
+				DateRecord:    recordedAt,
+				Symptoms:      req.Symptoms,
+				Diagnosis:     req.Diagnosis,
+				TreatmentPlan: req.Treatment,
+				Medication:    req.Medication,
+				Vaccination:   vaccination,
+				Notes:         req.Notes,
+				DogID:         dog.ID,
+				StaffID:       *staffID,
+			}
+			vrs := make([]entity.VaccineRecord, len(req.VaccineRecords))
+			for i, v := range req.VaccineRecords {
+				vrs[i] = entity.VaccineRecord{
+					VaccineID:   v.VaccineID,
+					DoseNumber:  v.DoseNumber,
+					LotNumber:   v.LotNumber,
+					NextDueDate: dues[i],
+				}
+			}
+			// ตรวจค่า/เปิดแจ้งเตือน/ตัดสต็อกแบบเดียวกับการบันทึกทีละตัว
+			if err := health_records.CreateRecord(tx, &mr, vrs); err != nil {
+				return err
+			}
+			records = append(records, bulkRecordOut{DogID: dog.ID, DogName: dog.Name, MedicalRecordID: mr.ID})
+		}
+		if len(records) == 0 {
+			status = http.StatusConflict
+			return errors.New("no dog in this litter is currently in our care")
+		}
+		return nil
+	})
+	if err != nil {
+		var fields health_records.FieldErrors
+		if errors.As(err, &fields) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid health record", "fields": fields})
+			return
+		}
+		if status == 0 && entity.IsStockError(err) {
+			status = http.StatusConflict
+		}
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, gin.H{"error": "create failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": gin.H{"litter_id": l.ID, "records": records, "skipped": skipped}})
+}
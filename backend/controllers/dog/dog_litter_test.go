@@ -0,0 +1,83 @@
+package dog
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/utils/testutil"
+	"github.com/gin-gonic/gin"
+)
+
+func TestLitterLinksFamilyAndRecordsWholeLitter(t *testing.T) {
+	db := configs.DB()
+	newDog := func(name string, adopted bool) entity.Dog {
+		t.Helper()
+		d := entity.Dog{Name: name, BreedID: 1, AnimalSexID: 2, AnimalSizeID: 1, Status: entity.DogStatusShelter, IsAdopted: adopted}
+		if err := db.Create(&d).Error; err != nil {
+			t.Fatalf("create dog: %v", err)
+		}
+		return d
+	}
+	mother := newDog("Mom", false)
+	pups := []entity.Dog{newDog("Pup 1", false), newDog("Pup 2", false), newDog("Pup 3", true)}
+
+	r := testutil.Router(1)
+	r.POST("/litters", CreateLitter)
+	r.POST("/litters/:id/vaccinations", CreateLitterVaccinations)
+	r.GET("/dogs/:id", GetDogById)
+
+	data := testutil.MustDo(t, r, http.MethodPost, "/litters", gin.H{
+		"name": "Spring litter", "birth_date": "2025-03", "mother_id": mother.ID,
+		"dog_ids": []uint{pups[0].ID, pups[1].ID, pups[2].ID},
+	})
+	litterID := testutil.ID(data)
+
+	var pup entity.Dog
+	if err := db.First(&pup, pups[0].ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if pup.LitterID == nil || *pup.LitterID != litterID || pup.MotherID == nil || *pup.MotherID != mother.ID ||
+		entity.FormatDogChangeValue(pup.DateOfBirth) != "2025-03-01" || pup.DOBPrecision != entity.DOBPrecisionMonth {
+		t.Errorf("pup = litter %v mother %v dob %s/%s, want litter %d mother %d dob 2025-03 month",
+			pup.LitterID, pup.MotherID, entity.FormatDogChangeValue(pup.DateOfBirth), pup.DOBPrecision, litterID, mother.ID)
+	}
+
+	// แม่จะเป็นลูกของลูกตัวเองไม่ได้
+	if code, _ := testutil.Do(t, r, http.MethodPost, "/litters", gin.H{
+		"name": "Cycle", "mother_id": pups[0].ID, "dog_ids": []uint{mother.ID},
+	}); code != http.StatusBadRequest {
+		t.Errorf("litter creating a family cycle = %d, want %d", code, http.StatusBadRequest)
+	}
+
+	_, out := testutil.Do(t, r, http.MethodGet, fmt.Sprintf("/dogs/%d", pups[0].ID), nil)
+	rel, _ := out["relatives"].(map[string]any)
+	mom, _ := rel["mother"].(map[string]any)
+	mates, _ := rel["littermates"].([]any)
+	if testutil.ID(mom) != mother.ID || len(mates) != 2 {
+		t.Errorf("relatives = %v, want mother %d and 2 littermates", rel, mother.ID)
+	}
+
+	var vaccine entity.Vaccine
+	if err := db.First(&vaccine).Error; err != nil {
+		t.Fatal(err)
+	}
+	data = testutil.MustDo(t, r, http.MethodPost, fmt.Sprintf("/litters/%d/vaccinations", litterID), gin.H{
+		"vaccine_id": vaccine.ID, "date_record": "2025-06-01",
+	})
+	records, _ := data["records"].([]any)
+	skipped, _ := data["skipped"].([]any)
+	if len(records) != 2 || len(skipped) != 1 {
+		t.Fatalf("litter vaccination records = %d skipped = %d, want 2 and 1 (adopted pup)", len(records), len(skipped))
+	}
+	var n int64
+	db.Model(&entity.VaccineRecord{}).
+		Joins("JOIN medical_records ON medical_records.id = vaccine_records.med_id").
+		Where("medical_records.dog_id IN ? AND vaccine_records.vaccine_id = ?", []uint{pups[0].ID, pups[1].ID}, vaccine.ID).
+		Count(&n)
+	if n != 2 {
+		t.Errorf("vaccine records for littermates = %d, want 2", n)
+	}
+}
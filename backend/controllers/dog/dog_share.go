@@ -0,0 +1,78 @@
+package dog
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+/* ========== Public share link (ใช้กับ QR บนการ์ดหน้าคอก) ========== */
+
+func newShareCode() string {
+	return strings.ReplaceAll(uuid.NewString(), "-", "")[:12]
+}
+
+// สุนัขที่สร้างก่อนมี share_code จะได้รหัสตอนขอใช้ครั้งแรก
+func ensureShareCode(tx *gorm.DB, d *entity.Dog) error {
+	if d.ShareCode != nil && *d.ShareCode != "" {
+		return nil
+	}
+	code := newShareCode()
+	if err := tx.Model(d).UpdateColumn("share_code", code).Error; err != nil {
+		return err
+	}
+	d.ShareCode = &code
+	return nil
+}
+
+func shareURL(code string) string {
+	return configs.PublicAPIURL() + "/share/dogs/" + code
+}
+
+func profileURL(dogID uint) string {
+	return fmt.Sprintf("%s/adoption/doglist/%d", configs.PublicSiteURL(), dogID)
+}
+
+// GET /dogs/:id/share
+func GetDogShareLink(c *gin.Context) {
+	var dog entity.Dog
+	if err := configs.DB().First(&dog, c.Param("id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "dog not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	if err := ensureShareCode(configs.DB(), &dog); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "share code failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"dog_id":      dog.ID,
+		"share_code":  *dog.ShareCode,
+		"share_url":   shareURL(*dog.ShareCode),
+		"profile_url": profileURL(dog.ID),
+	})
+}
+
+// GET /share/dogs/:code — ลิงก์ถาวรจาก QR -> redirect ไปหน้าโปรไฟล์บนเว็บ
+func OpenDogShareLink(c *gin.Context) {
+	var dog entity.Dog
+	if err := configs.DB().Where("share_code = ?", c.Param("code")).First(&dog).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "dog not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.Redirect(http.StatusFound, profileURL(dog.ID))
+}
@@ -0,0 +1,494 @@
+package dog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"example.com/project-sa/configs"
+	health_records "example.com/project-sa/controllers/health_record"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/middlewares"
+	"example.com/project-sa/utils/pointer"
+	"example.com/project-sa/utils/timeutil"
+	"example.com/project-sa/utils/upload"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+/* ========== Transfers to/from partner organizations ========== */
+
+type transferOutRequest struct {
+	PartnerID    uint   `json:"partner_id" binding:"required"`
+	TransferDate string `json:"transfer_date"` // "YYYY-MM-DD" (ว่าง = วันนี้)
+	Reason       string `json:"reason" binding:"required"`
+	ExternalRef  string `json:"external_ref"`
+}
+
+func parseTransferDate(s string) (time.Time, error) {
+	if s == "" {
+		s = timeutil.TodayYMD()
+	}
+	return time.ParseInLocation("2006-01-02", s, timeutil.TZBangkok())
+}
+
+func loadPartner(tx *gorm.DB, id uint) (*entity.PartnerOrganization, error) {
+	var p entity.PartnerOrganization
+	if err := tx.First(&p, id).Error; err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// POST /dogs/:id/transfer-out
+func TransferDogOut(c *gin.Context) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req transferOutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	date, err := parseTransferDate(req.TransferDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid transfer_date (YYYY-MM-DD)"})
+		return
+	}
+
+	var transfer entity.DogTransfer
+	var status int
+	err = configs.DB().Transaction(func(tx *gorm.DB) error {
+		var dog entity.Dog
+		if err := tx.First(&dog, c.Param("id")).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				status = http.StatusNotFound
+				return errors.New("dog not found")
+			}
+			return err
+		}
+		if dog.IsAdopted {
+			status = http.StatusConflict
+			return errors.New("dog is adopted")
+		}
+		// สุนัขที่อยู่บ้านอุปถัมภ์ต้องรับกลับก่อน
+		if dog.Status != entity.DogStatusShelter {
+			status = http.StatusConflict
+			return errors.New("dog is not in the shelter (status: " + dog.Status + ")")
+		}
+		partner, err := loadPartner(tx, req.PartnerID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				status = http.StatusBadRequest
+				return errors.New("invalid partner_id")
+			}
+			return err
+		}
+
+		transfer = entity.DogTransfer{
+			Direction:    entity.TransferOut,
+			DogID:        dog.ID,
+			PartnerID:    partner.ID,
+			TransferDate: date,
+			Reason:       req.Reason,
+			ExternalRef:  req.ExternalRef,
+			StaffID:      staffID,
+		}
+		if err := tx.Create(&transfer).Error; err != nil {
+			return err
+		}
+
+		// ออกจากคอกและไม่เปิดรับอุปการะที่นี่อีก
+		updates := map[string]any{"kennel_id": nil, "ready_to_adopt": false}
+		if err := recordDogChanges(tx, dog, updates, entity.DogChangeUpdate, staffID, nil); err != nil {
+			return err
+		}
+		updates["status"] = entity.DogStatusTransferred
+		updates["updated_by_id"] = *staffID
+		return tx.Model(&dog).Updates(updates).Error
+	})
+	if err != nil {
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, gin.H{"error": "transfer failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"data":       transfer,
+		"bundle_url": fmt.Sprintf("%s/transfers/%d/bundle", configs.PublicAPIURL(), transfer.ID),
+	})
+}
+
+// GET /transfers?direction=&partner_id=&dog_id=
+func GetTransfers(c *gin.Context) {
+	db := configs.DB().Preload("Dog").Preload("Partner").Preload("Staff")
+	if v := c.Query("direction"); v != "" {
+		db = db.Where("direction = ?", v)
+	}
+	if v := c.Query("partner_id"); v != "" {
+		db = db.Where("partner_id = ?", v)
+	}
+	if v := c.Query("dog_id"); v != "" {
+		db = db.Where("dog_id = ?", v)
+	}
+	var rows []entity.DogTransfer
+	if err := db.Order("transfer_date DESC, id DESC").Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": rows})
+}
+
+// GET /transfers/:id/bundle?format=json — ค่าเริ่มต้นเป็น zip (bundle.json + profile.pdf + photos/)
+func GetTransferBundle(c *gin.Context) {
+	db := configs.DB()
+	var t entity.DogTransfer
+	if err := db.Preload("Partner").First(&t, c.Param("id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "transfer not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	var dog entity.Dog
+	if err := preloadBundleDog(db).First(&dog, t.DogID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+
+	info := &bundleTransfer{Direction: t.Direction, TransferDate: t.TransferDate, Reason: t.Reason}
+	if t.Partner != nil {
+		info.Partner = t.Partner.Name
+	}
+	b, files := buildBundle(dog, info)
+
+	if c.Query("format") == "json" {
+		c.JSON(http.StatusOK, b)
+		return
+	}
+	var buf bytes.Buffer
+	if err := writeBundleZip(&buf, b, files); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "render failed: " + err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="transfer-%d-dog-%d.zip"`, t.ID, dog.ID))
+	c.Data(http.StatusOK, "application/zip", buf.Bytes())
+}
+
+// lookup ตามชื่อแบบไม่สนตัวพิมพ์
+func findByName(tx *gorm.DB, model any, name string) error {
+	return tx.Where("LOWER(name) = LOWER(?)", strings.TrimSpace(name)).First(model).Error
+}
+
+// POST /transfers/import (multipart: file, partner_id, transfer_date, reason, external_ref, kennel_id)
+// file เป็น zip จาก GET /transfers/:id/bundle หรือ bundle.json ก็ได้
+func ImportTransfer(c *gin.Context) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	fh, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	if fh.Size > maxBundleSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "bundle too large"})
+		return
+	}
+	partnerID, err := strconv.ParseUint(c.PostForm("partner_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid partner_id"})
+		return
+	}
+	date, err := parseTransferDate(c.PostForm("transfer_date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid transfer_date (YYYY-MM-DD)"})
+		return
+	}
+	var kennelID *uint
+	if v := c.PostForm("kennel_id"); v != "" {
+		k, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid kennel_id"})
+			return
+		}
+		kennelID = pointer.P(uint(k))
+	}
+
+	f, err := fh.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "cannot read file"})
+		return
+	}
+	data, err := io.ReadAll(io.LimitReader(f, maxBundleSize+1))
+	f.Close()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "cannot read file"})
+		return
+	}
+	b, files, err := readBundle(data)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	// เก็บรูปก่อนเปิด transaction (ไม่เขียนไฟล์ระหว่างถือ lock) ถ้า transaction ล้มเหลวลบไฟล์ทิ้ง
+	photoURL := ""
+	for _, p := range b.Photos {
+		content, ok := files[path.Clean(p.File)]
+		if !ok {
+			continue
+		}
+		ext, ok := photoExtByType[http.DetectContentType(content)]
+		if !ok {
+			continue
+		}
+		url, err := upload.SaveData("dog", "dog", ext, content)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "save photo failed: " + err.Error()})
+			return
+		}
+		photoURL = url
+		break
+	}
+
+	var warnings []string
+	var created entity.Dog
+	var transfer entity.DogTransfer
+	var status int
+	err = configs.DB().Transaction(func(tx *gorm.DB) error {
+		partner, err := loadPartner(tx, uint(partnerID))
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				status = http.StatusBadRequest
+				return errors.New("invalid partner_id")
+			}
+			return err
+		}
+
+		var sex entity.AnimalSex
+		if err := findByName(tx, &sex, b.Dog.AnimalSex); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				status = http.StatusUnprocessableEntity
+				return fmt.Errorf("unknown animal_sex %q", b.Dog.AnimalSex)
+			}
+			return err
+		}
+		var size entity.AnimalSize
+		if err := findByName(tx, &size, b.Dog.AnimalSize); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				status = http.StatusUnprocessableEntity
+				return fmt.Errorf("unknown animal_size %q", b.Dog.AnimalSize)
+			}
+			return err
+		}
+		// สายพันธุ์ที่ยังไม่มีในระบบ -> เพิ่มให้
+		var breed entity.Breed
+		if err := findByName(tx, &breed, b.Dog.Breed); err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			if strings.TrimSpace(b.Dog.Breed) == "" {
+				status = http.StatusUnprocessableEntity
+				return errors.New("dog.breed is required")
+			}
+			breed = entity.Breed{Name: strings.TrimSpace(b.Dog.Breed)}
+			if err := tx.Create(&breed).Error; err != nil {
+				return err
+			}
+			warnings = append(warnings, "created breed "+breed.Name)
+		}
+
+		if kennelID != nil {
+			if _, err := entity.ReserveKennelSlot(tx, *kennelID, 0); err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					status = http.StatusBadRequest
+					return errors.New("invalid kennel_id")
+				}
+				if entity.IsCapacityError(err) {
+					status = http.StatusConflict
+				}
+				return err
+			}
+		}
+
+		// validateBundle ตรวจรูปแบบวันที่ไว้แล้ว
+		dob, precision, _ := parseDOB(b.Dog.DateOfBirth)
+		switch b.Dog.DOBPrecision {
+		case entity.DOBPrecisionMonth, entity.DOBPrecisionYear:
+			precision = b.Dog.DOBPrecision
+		}
+		if precision == "" {
+			precision = entity.DOBPrecisionDay
+		}
+		sterilizedAt, _ := parseSterilizedAt(b.Dog.SterilizedAt)
+		created = entity.Dog{
+			Name:         strings.TrimSpace(b.Dog.Name),
+			DateOfBirth:  dob,
+			DOBEstimated: b.Dog.DOBEstimated || precision != entity.DOBPrecisionDay,
+			DOBPrecision: precision,
+			SterilizedAt: sterilizedAt,
+			Color:        b.Dog.Color,
+			IntakeDate:   date.Format("2006-01-02"),
+			IntakeArea:   b.Dog.IntakeArea,
+			PhotoURL:     photoURL,
+			Status:       entity.DogStatusShelter,
+			BreedID:      breed.ID,
+			AnimalSexID:  sex.ID,
+			AnimalSizeID: size.ID,
+			KennelID:     kennelID,
+			ShareCode:    pointer.P(newShareCode()),
+			CreatedByID:  staffID,
+			UpdatedByID:  staffID,
+		}
+		if err := tx.Create(&created).Error; err != nil {
+			return err
+		}
+		if err := entity.RecordKennelMove(tx, created.ID, nil, kennelID, staffID, "transfer in"); err != nil {
+			return err
+		}
+
+		var pids []uint
+		for _, name := range b.Dog.Personalities {
+			var p entity.Personality
+			if err := findByName(tx, &p, name); err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					warnings = append(warnings, "skipped unknown personality "+name)
+					continue
+				}
+				return err
+			}
+			pids = append(pids, p.ID)
+		}
+		if err := replaceDogPersonalities(tx, created.ID, pids); err != nil {
+			return err
+		}
+		if kennelID != nil {
+			kw, err := entity.CheckKennelRules(tx, created.ID, *kennelID)
+			if err != nil {
+				if entity.IsCompatibilityError(err) {
+					status = http.StatusConflict
+				}
+				return err
+			}
+			for _, w := range kw {
+				warnings = append(warnings, w.Message)
+			}
+		}
+
+		for i, m := range b.Medical {
+			notes := m.Notes
+			origin := "Imported from " + partner.Name
+			if m.RecordedBy != "" {
+				origin += " (recorded by " + m.RecordedBy + ")"
+			}
+			if notes != "" {
+				notes += "\n"
+			}
+			notes += origin
+
+			mr := entity.MedicalRecord{
+				DateRecord:    m.DateRecord,
+				Weight:        m.Weight,
+				Temperature:   m.Temperature,
+				Symptoms:      m.Symptoms,
+				Diagnosis:     m.Diagnosis,
+				TreatmentPlan: m.Treatment,
+				Medication:    m.Medication,
+				Vaccination:   m.Vaccination,
+				Notes:         notes,
+				DogID:         created.ID,
+				StaffID:       *staffID,
+			}
+			var vrs []entity.VaccineRecord
+			for _, v := range m.Vaccines {
+				var vac entity.Vaccine
+				if err := findByName(tx, &vac, v.Vaccine); err != nil {
+					if !errors.Is(err, gorm.ErrRecordNotFound) {
+						return err
+					}
+					if strings.TrimSpace(v.Vaccine) == "" {
+						warnings = append(warnings, "skipped vaccine record without vaccine name")
+						continue
+					}
+					vac = entity.Vaccine{Name: strings.TrimSpace(v.Vaccine), Manufacturer: v.Manufacturer}
+					if err := tx.Create(&vac).Error; err != nil {
+						return err
+					}
+					warnings = append(warnings, "created vaccine "+vac.Name)
+				}
+				vrs = append(vrs, entity.VaccineRecord{
+					DoseNumber:  v.DoseNumber,
+					LotNumber:   v.LotNumber,
+					NextDueDate: v.NextDueDate,
+					VaccineID:   vac.ID,
+				})
+			}
+			// ประวัติจากองค์กรอื่น: ตรวจค่าและเปิดแจ้งเตือนเหมือนบันทึกเอง แต่ไม่ตัดสต็อก
+			if err := health_records.ImportRecord(tx, &mr, vrs); err != nil {
+				var fields health_records.FieldErrors
+				if errors.As(err, &fields) {
+					status = http.StatusUnprocessableEntity
+					prefixed := health_records.FieldErrors{}
+					for k, v := range fields {
+						prefixed[fmt.Sprintf("medical[%d].%s", i, k)] = v
+					}
+					return prefixed
+				}
+				return err
+			}
+		}
+
+		ref := c.PostForm("external_ref")
+		if ref == "" && b.Dog.SourceID != 0 {
+			ref = strconv.FormatUint(uint64(b.Dog.SourceID), 10)
+		}
+		transfer = entity.DogTransfer{
+			Direction:    entity.TransferIn,
+			DogID:        created.ID,
+			PartnerID:    partner.ID,
+			TransferDate: date,
+			Reason:       c.PostForm("reason"),
+			ExternalRef:  ref,
+			StaffID:      staffID,
+		}
+		return tx.Create(&transfer).Error
+	})
+	if err != nil {
+		if photoURL != "" {
+			_ = upload.Remove(photoURL)
+		}
+		var fields health_records.FieldErrors
+		if errors.As(err, &fields) {
+			c.JSON(status, gin.H{"error": "invalid medical history", "fields": fields})
+			return
+		}
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, gin.H{"error": "import failed: " + err.Error()})
+		return
+	}
+
+	var out entity.Dog
+	if err := preloadDog(configs.DB()).First(&out, created.ID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	if warnings == nil {
+		warnings = []string{}
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": out, "transfer": transfer, "warnings": warnings})
+}
@@ -0,0 +1,107 @@
+package dog
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/utils/testutil"
+)
+
+// bundleZip zip แบบเดียวกับ GET /transfers/:id/bundle พร้อมรูป 1 รูป
+func bundleZip(t *testing.T, sex string) []byte {
+	t.Helper()
+	var img bytes.Buffer
+	if err := png.Encode(&img, image.NewRGBA(image.Rect(0, 0, 4, 4))); err != nil {
+		t.Fatal(err)
+	}
+	js, _ := json.Marshal(transferBundle{
+		Format:  bundleFormat,
+		Version: bundleVersion,
+		Dog:     bundleDog{Name: "Import", Breed: "Mixed", AnimalSex: sex, AnimalSize: "กลาง"},
+		Photos:  []bundlePhotoEntry{{File: "photos/photo-1.png", ContentType: "image/png"}},
+	})
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, data := range map[string][]byte{bundleJSONName: js, "photos/photo-1.png": img.Bytes()} {
+		f, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write(data)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func importBundle(t *testing.T, partnerID uint, bundle []byte) (int, map[string]any) {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, _ := mw.CreateFormFile("file", "bundle.zip")
+	fw.Write(bundle)
+	mw.WriteField("partner_id", strconv.FormatUint(uint64(partnerID), 10))
+	mw.Close()
+
+	r := testutil.Router(1)
+	r.POST("/transfers/import", ImportTransfer)
+	req := httptest.NewRequest(http.MethodPost, "/transfers/import", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var out map[string]any
+	json.Unmarshal(w.Body.Bytes(), &out)
+	return w.Code, out
+}
+
+func uploadedDogPhotos(t *testing.T) []string {
+	t.Helper()
+	var files []string
+	filepath.Walk(filepath.Join("static", "uploads", "dog"), func(p string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			files = append(files, p)
+		}
+		return nil
+	})
+	return files
+}
+
+// นำเข้าไม่สำเร็จต้องไม่เหลือไฟล์รูปค้างบนดิสก์
+func TestImportTransferRemovesPhotoOnFailure(t *testing.T) {
+	partner := entity.PartnerOrganization{Name: "Partner Shelter"}
+	if err := configs.DB().Create(&partner).Error; err != nil {
+		t.Fatal(err)
+	}
+	before := len(uploadedDogPhotos(t))
+
+	if code, out := importBundle(t, partner.ID, bundleZip(t, "no such sex")); code != http.StatusUnprocessableEntity {
+		t.Fatalf("import with unknown sex = %d %v, want 422", code, out)
+	}
+	if got := len(uploadedDogPhotos(t)); got != before {
+		t.Errorf("photos on disk after a failed import = %d, want %d", got, before)
+	}
+
+	code, out := importBundle(t, partner.ID, bundleZip(t, "ตัวผู้"))
+	if code != http.StatusCreated {
+		t.Fatalf("import = %d %v, want 201", code, out)
+	}
+	if got := len(uploadedDogPhotos(t)); got != before+1 {
+		t.Errorf("photos on disk after import = %d, want %d", got, before+1)
+	}
+	if url, _ := out["data"].(map[string]any)["photo_url"].(string); url == "" {
+		t.Error("imported dog has no photo_url")
+	}
+}
@@ -0,0 +1,641 @@
+package foster
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/middlewares"
+	"example.com/project-sa/utils/timeutil"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+/* ========== DTOs ========== */
+
+type fosterHomeInput struct {
+	UserID       *uint   `json:"user_id"` // staff ระบุ, user ใช้ของตัวเอง
+	Capacity     *uint   `json:"capacity"`
+	Address      *string `json:"address"`
+	HousingType  *string `json:"housing_type"`
+	HasYard      *bool   `json:"has_yard"`
+	HasChildren  *bool   `json:"has_children"`
+	HasOtherDogs *bool   `json:"has_other_dogs"`
+	HasCats      *bool   `json:"has_cats"`
+	Note         *string `json:"note"`
+	Active       *bool   `json:"active"`
+}
+
+type placementCreateRequest struct {
+	DogID           uint   `json:"dog_id" binding:"required"`
+	FosterHomeID    uint   `json:"foster_home_id" binding:"required"`
+	StartDate       string `json:"start_date"`        // "YYYY-MM-DD" (ว่าง = วันนี้)
+	ExpectedEndDate string `json:"expected_end_date"` // "YYYY-MM-DD"
+	Note            string `json:"note"`
+}
+
+type placementEndRequest struct {
+	EndDate  string `json:"end_date"`  // "YYYY-MM-DD" (ว่าง = วันนี้)
+	KennelID *uint  `json:"kennel_id"` // ว่าง = คอกเดิมก่อนออกไป
+	Reason   string `json:"reason"`
+}
+
+type checkInRequest struct {
+	ReportedAt     *time.Time `json:"reported_at"`
+	WeightKg       *float64   `json:"weight_kg"`
+	Appetite       string     `json:"appetite" binding:"omitempty,oneof=good fair poor"`
+	Behavior       string     `json:"behavior"`
+	HealthConcerns *string    `json:"health_concerns"`
+	PhotoURL       *string    `json:"photo_url"`
+	Note           string     `json:"note"`
+}
+
+// ข้อมูลบ้าน + จำนวนที่รับได้อีก
+type fosterHomeView struct {
+	entity.FosterHome
+	ActivePlacements int64 `json:"active_placements"`
+	AvailableSlots   int64 `json:"available_slots"`
+}
+
+var housingTypes = map[string]bool{"house": true, "townhouse": true, "condo": true, "other": true}
+
+/* ========== Helpers ========== */
+
+func getUserID(c *gin.Context) *uint {
+	if v, ok := c.Get("user_id"); ok {
+		if id, ok2 := v.(uint); ok2 && id > 0 {
+			return &id
+		}
+	}
+	return nil
+}
+
+func parseDate(s string) (time.Time, error) {
+	if s == "" {
+		s = timeutil.TodayYMD()
+	}
+	return time.ParseInLocation("2006-01-02", s, timeutil.TZBangkok())
+}
+
+func activePlacementCount(tx *gorm.DB, homeID uint) (int64, error) {
+	var n int64
+	err := tx.Model(&entity.FosterPlacement{}).
+		Where("foster_home_id = ? AND end_date IS NULL", homeID).
+		Count(&n).Error
+	return n, err
+}
+
+func toView(tx *gorm.DB, h entity.FosterHome) (fosterHomeView, error) {
+	n, err := activePlacementCount(tx, h.ID)
+	if err != nil {
+		return fosterHomeView{}, err
+	}
+	free := int64(h.Capacity) - n
+	if free < 0 || !h.Active {
+		free = 0
+	}
+	return fosterHomeView{FosterHome: h, ActivePlacements: n, AvailableSlots: free}, nil
+}
+
+func applyHomeInput(h *entity.FosterHome, in fosterHomeInput) error {
+	if in.Capacity != nil {
+		h.Capacity = *in.Capacity
+	}
+	if in.Address != nil {
+		h.Address = *in.Address
+	}
+	if in.HousingType != nil {
+		if !housingTypes[*in.HousingType] {
+			return errors.New("invalid housing_type")
+		}
+		h.HousingType = *in.HousingType
+	}
+	if in.HasYard != nil {
+		h.HasYard = *in.HasYard
+	}
+	if in.HasChildren != nil {
+		h.HasChildren = *in.HasChildren
+	}
+	if in.HasOtherDogs != nil {
+		h.HasOtherDogs = *in.HasOtherDogs
+	}
+	if in.HasCats != nil {
+		h.HasCats = *in.HasCats
+	}
+	if in.Note != nil {
+		h.Note = in.Note
+	}
+	return nil
+}
+
+// staff เห็นทุกบ้าน, user เห็นเฉพาะบ้านของตัวเอง
+func loadHomeForCaller(c *gin.Context, id string) (*entity.FosterHome, bool) {
+	var h entity.FosterHome
+	if err := configs.DB().Preload("User").First(&h, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "foster home not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return nil, false
+	}
+	if middlewares.StaffID(c) == nil {
+		uid := getUserID(c)
+		if uid == nil || *uid != h.UserID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return nil, false
+		}
+	}
+	return &h, true
+}
+
+func loadPlacementForCaller(c *gin.Context, tx *gorm.DB) (*entity.FosterPlacement, bool) {
+	var p entity.FosterPlacement
+	if err := tx.Preload("FosterHome").First(&p, c.Param("id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "placement not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return nil, false
+	}
+	if middlewares.StaffID(c) == nil {
+		uid := getUserID(c)
+		if uid == nil || p.FosterHome == nil || *uid != p.FosterHome.UserID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return nil, false
+		}
+	}
+	return &p, true
+}
+
+/* ========== Foster homes ========== */
+
+// POST /foster-homes
+func CreateFosterHome(c *gin.Context) {
+	var in fosterHomeInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+
+	var userID uint
+	switch {
+	case middlewares.StaffID(c) != nil:
+		if in.UserID == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required"})
+			return
+		}
+		userID = *in.UserID
+	case getUserID(c) != nil:
+		userID = *getUserID(c)
+	default:
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+
+	var user entity.User
+	if err := configs.DB().First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user_id"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	var exists int64
+	if err := configs.DB().Model(&entity.FosterHome{}).Where("user_id = ?", userID).Count(&exists).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	if exists > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "user already has a foster home profile"})
+		return
+	}
+
+	h := entity.FosterHome{UserID: userID, Capacity: 1, HousingType: "house", Active: true}
+	if err := applyHomeInput(&h, in); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	// เปิด/ปิดรับได้เฉพาะเจ้าหน้าที่
+	if in.Active != nil && middlewares.StaffID(c) != nil {
+		h.Active = *in.Active
+	}
+	if err := configs.DB().Create(&h).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "create failed: " + err.Error()})
+		return
+	}
+	h.User = &user
+	c.JSON(http.StatusCreated, gin.H{"data": h})
+}
+
+// GET /foster-homes?available=true
+func GetFosterHomes(c *gin.Context) {
+	if middlewares.StaffID(c) == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var homes []entity.FosterHome
+	if err := configs.DB().Preload("User").Order("id ASC").Find(&homes).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	onlyAvailable := c.Query("available") == "true"
+	out := make([]fosterHomeView, 0, len(homes))
+	for _, h := range homes {
+		v, err := toView(configs.DB(), h)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+			return
+		}
+		if onlyAvailable && v.AvailableSlots == 0 {
+			continue
+		}
+		out = append(out, v)
+	}
+	c.JSON(http.StatusOK, gin.H{"data": out})
+}
+
+// GET /foster-homes/me
+func GetMyFosterHome(c *gin.Context) {
+	uid := getUserID(c)
+	if uid == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var h entity.FosterHome
+	if err := configs.DB().
+		Preload("Placements", "end_date IS NULL").
+		Preload("Placements.Dog").
+		Where("user_id = ?", *uid).
+		First(&h).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "foster home not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	v, err := toView(configs.DB(), h)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": v})
+}
+
+// GET /foster-homes/:id
+func GetFosterHome(c *gin.Context) {
+	h, ok := loadHomeForCaller(c, c.Param("id"))
+	if !ok {
+		return
+	}
+	if err := configs.DB().
+		Preload("Placements", func(db *gorm.DB) *gorm.DB { return db.Order("start_date DESC") }).
+		Preload("Placements.Dog").
+		First(h, h.ID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	v, err := toView(configs.DB(), *h)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": v})
+}
+
+// PUT /foster-homes/:id
+func UpdateFosterHome(c *gin.Context) {
+	h, ok := loadHomeForCaller(c, c.Param("id"))
+	if !ok {
+		return
+	}
+	var in fosterHomeInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	if err := applyHomeInput(h, in); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if in.Active != nil {
+		if middlewares.StaffID(c) == nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": "only staff can change active"})
+			return
+		}
+		h.Active = *in.Active
+	}
+	if err := configs.DB().Omit("User", "Placements").Save(h).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": h})
+}
+
+/* ========== Placements ========== */
+
+// POST /foster-placements — ส่งสุนัขไปบ้านอุปถัมภ์ (ออกจากคอก)
+func CreatePlacement(c *gin.Context) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req placementCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	start, err := parseDate(req.StartDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start_date (YYYY-MM-DD)"})
+		return
+	}
+	var expected *time.Time
+	if req.ExpectedEndDate != "" {
+		t, err := time.ParseInLocation("2006-01-02", req.ExpectedEndDate, timeutil.TZBangkok())
+		if err != nil || t.Before(start) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid expected_end_date"})
+			return
+		}
+		expected = &t
+	}
+
+	var placement entity.FosterPlacement
+	var status int
+	err = configs.DB().Transaction(func(tx *gorm.DB) error {
+		var dog entity.Dog
+		if err := tx.First(&dog, req.DogID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				status = http.StatusNotFound
+				return errors.New("dog not found")
+			}
+			return err
+		}
+		if dog.IsAdopted {
+			status = http.StatusConflict
+			return errors.New("dog is adopted")
+		}
+		if dog.Status != entity.DogStatusShelter {
+			status = http.StatusConflict
+			return errors.New("dog is not in the shelter (status: " + dog.Status + ")")
+		}
+		if err := entity.CheckFosterIsolation(tx, dog.ID); err != nil {
+			if entity.IsIsolationError(err) {
+				status = http.StatusConflict
+			}
+			return err
+		}
+
+		var home entity.FosterHome
+		if err := tx.First(&home, req.FosterHomeID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				status = http.StatusNotFound
+				return errors.New("foster home not found")
+			}
+			return err
+		}
+		if !home.Active {
+			status = http.StatusConflict
+			return errors.New("foster home is not active")
+		}
+		n, err := activePlacementCount(tx, home.ID)
+		if err != nil {
+			return err
+		}
+		if n >= int64(home.Capacity) {
+			status = http.StatusConflict
+			return errors.New("foster home is at capacity")
+		}
+
+		placement = entity.FosterPlacement{
+			FosterHomeID:     home.ID,
+			DogID:            dog.ID,
+			StartDate:        start,
+			ExpectedEndDate:  expected,
+			Note:             req.Note,
+			PreviousKennelID: dog.KennelID,
+			StaffID:          staffID,
+		}
+		if err := tx.Create(&placement).Error; err != nil {
+			return err
+		}
+		// ออกจากคอก -> ไม่นับในความจุคอก
+		if err := tx.Model(&dog).Updates(map[string]any{
+			"status":         entity.DogStatusFoster,
+			"foster_home_id": home.ID,
+			"kennel_id":      nil,
+			"updated_by_id":  *staffID,
+		}).Error; err != nil {
+			return err
+		}
+		return entity.RecordDogKennelChange(tx, dog.ID, dog.KennelID, nil, staffID, "foster placement")
+	})
+	if err != nil {
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, gin.H{"error": "placement failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": placement})
+}
+
+// POST /foster-placements/:id/end — รับสุนัขกลับเข้าคอก
+func EndPlacement(c *gin.Context) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req placementEndRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	end, err := parseDate(req.EndDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end_date (YYYY-MM-DD)"})
+		return
+	}
+
+	var placement entity.FosterPlacement
+	var status int
+	var warnings []entity.KennelRuleViolation
+	err = configs.DB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&placement, c.Param("id")).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				status = http.StatusNotFound
+				return errors.New("placement not found")
+			}
+			return err
+		}
+		if placement.EndDate != nil {
+			status = http.StatusConflict
+			return errors.New("placement already ended")
+		}
+		if end.Before(placement.StartDate) {
+			status = http.StatusBadRequest
+			return errors.New("end_date is before start_date")
+		}
+
+		kennelID := req.KennelID
+		if kennelID == nil {
+			kennelID = placement.PreviousKennelID
+		}
+		if kennelID == nil {
+			status = http.StatusBadRequest
+			return errors.New("kennel_id is required")
+		}
+		kennel, err := entity.ReserveKennelSlot(tx, *kennelID, placement.DogID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				status = http.StatusBadRequest
+				return errors.New("invalid kennel_id")
+			}
+			if entity.IsCapacityError(err) {
+				status = http.StatusConflict
+			}
+			return err
+		}
+		if err := entity.CheckKennelIsolation(tx, placement.DogID, kennel.ID); err != nil {
+			if entity.IsIsolationError(err) {
+				status = http.StatusConflict
+			}
+			return err
+		}
+		if warnings, err = entity.CheckKennelRules(tx, placement.DogID, kennel.ID); err != nil {
+			if entity.IsCompatibilityError(err) {
+				status = http.StatusConflict
+			}
+			return err
+		}
+
+		if err := tx.Model(&placement).Updates(map[string]any{
+			"end_date":   end,
+			"end_reason": req.Reason,
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&entity.Dog{}).Where("id = ?", placement.DogID).Updates(map[string]any{
+			"status":         entity.DogStatusShelter,
+			"foster_home_id": nil,
+			"kennel_id":      kennel.ID,
+			"updated_by_id":  *staffID,
+		}).Error; err != nil {
+			return err
+		}
+		return entity.RecordDogKennelChange(tx, placement.DogID, nil, &kennel.ID, staffID, "foster return")
+	})
+	if err != nil {
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, gin.H{"error": "end placement failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": placement, "kennel_warnings": warnings})
+}
+
+// GET /foster-placements?active=true&foster_home_id=&dog_id=
+func GetPlacements(c *gin.Context) {
+	if middlewares.StaffID(c) == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	db := configs.DB().Preload("FosterHome").Preload("FosterHome.User").Preload("Dog")
+	if c.Query("active") == "true" {
+		db = db.Where("end_date IS NULL")
+	}
+	if v := c.Query("foster_home_id"); v != "" {
+		db = db.Where("foster_home_id = ?", v)
+	}
+	if v := c.Query("dog_id"); v != "" {
+		db = db.Where("dog_id = ?", v)
+	}
+	var rows []entity.FosterPlacement
+	if err := db.Order("start_date DESC, id DESC").Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": rows})
+}
+
+// GET /foster-placements/:id
+func GetPlacement(c *gin.Context) {
+	p, ok := loadPlacementForCaller(c, configs.DB())
+	if !ok {
+		return
+	}
+	if err := configs.DB().
+		Preload("FosterHome").
+		Preload("Dog").
+		Preload("Staff").
+		Preload("CheckIns", func(db *gorm.DB) *gorm.DB { return db.Order("reported_at DESC") }).
+		Preload("CheckIns.Staff").
+		Preload("Supplies", func(db *gorm.DB) *gorm.DB { return db.Order("handed_out_at DESC") }).
+		Preload("Supplies.Item").
+		Preload("Supplies.Unit").
+		First(p, p.ID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": p})
+}
+
+/* ========== Check-ins ========== */
+
+// POST /foster-placements/:id/check-ins — เจ้าของบ้านหรือเจ้าหน้าที่รายงาน
+func CreateCheckIn(c *gin.Context) {
+	p, ok := loadPlacementForCaller(c, configs.DB())
+	if !ok {
+		return
+	}
+	if p.EndDate != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "placement already ended"})
+		return
+	}
+	var req checkInRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	if req.WeightKg != nil && *req.WeightKg <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "weight_kg must be positive"})
+		return
+	}
+	at := time.Now()
+	if req.ReportedAt != nil {
+		if req.ReportedAt.After(at) || req.ReportedAt.Before(p.StartDate) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "reported_at is outside the placement"})
+			return
+		}
+		at = *req.ReportedAt
+	}
+
+	ci := entity.FosterCheckIn{
+		PlacementID:    p.ID,
+		ReportedAt:     at,
+		WeightKg:       req.WeightKg,
+		Appetite:       req.Appetite,
+		Behavior:       req.Behavior,
+		HealthConcerns: req.HealthConcerns,
+		PhotoURL:       req.PhotoURL,
+		Note:           req.Note,
+	}
+	if staffID := middlewares.StaffID(c); staffID != nil {
+		ci.StaffID = staffID
+	} else {
+		ci.UserID = getUserID(c)
+	}
+	if err := configs.DB().Create(&ci).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "create failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": ci})
+}
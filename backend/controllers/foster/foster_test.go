@@ -0,0 +1,52 @@
+package foster
+
+import (
+	"net/http"
+	"testing"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/migrations"
+	"example.com/project-sa/seeds"
+	"example.com/project-sa/utils/testutil"
+)
+
+func TestMain(m *testing.M) { testutil.Main(m, migrations.AutoMigrate, seeds.SeedAll) }
+
+// สุนัขที่กักโรคอยู่ส่งไปบ้านอุปถัมภ์ไม่ได้ (กฎเดียวกับการย้ายเข้าคอกนอกโซนแยกโรค)
+func TestCreatePlacementRejectsQuarantinedDog(t *testing.T) {
+	db := configs.DB()
+	user := entity.User{FirstName: "Foster", Username: "foster-home", Email: "foster@example.com", GenderID: 1}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+	home := entity.FosterHome{UserID: user.ID, Capacity: 2, Active: true}
+	if err := db.Create(&home).Error; err != nil {
+		t.Fatal(err)
+	}
+	q := entity.QuarantineRecord{DogID: 2, Condition: entity.ConditionParvo, StartDate: entity.NewDate(home.CreatedAt),
+		IncubationDays: 14, Status: entity.QuarantineActive}
+	if err := db.Create(&q).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	r := testutil.Router(1)
+	r.POST("/foster-placements", CreatePlacement)
+	if code, out := testutil.Do(t, r, http.MethodPost, "/foster-placements", map[string]any{
+		"dog_id": 2, "foster_home_id": home.ID,
+	}); code != http.StatusConflict {
+		t.Errorf("quarantined dog = %d %v, want 409", code, out)
+	}
+	var dog entity.Dog
+	db.First(&dog, 2)
+	if dog.Status != entity.DogStatusShelter || dog.KennelID == nil {
+		t.Errorf("quarantined dog moved: status %s kennel %v", dog.Status, dog.KennelID)
+	}
+
+	testutil.MustDo(t, r, http.MethodPost, "/foster-placements", map[string]any{"dog_id": 1, "foster_home_id": home.ID})
+	var placed entity.Dog
+	db.First(&placed, 1)
+	if placed.Status != entity.DogStatusFoster || placed.KennelID != nil {
+		t.Errorf("placed dog: status %s kennel %v, want foster without a kennel", placed.Status, placed.KennelID)
+	}
+}
@@ -0,0 +1,156 @@
+package foster
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/middlewares"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+/* ========== Supply handouts (เบิกจากของบริจาค) ========== */
+
+// สถานะบริจาคที่ถือว่าได้รับของเข้าคลังแล้ว
+var receivedDonationStatuses = []string{"complete", "success"}
+
+type supplyRequest struct {
+	ItemID   uint   `json:"item_id" binding:"required"`
+	UnitID   uint   `json:"unit_id" binding:"required"`
+	Quantity int    `json:"quantity" binding:"required,gt=0"`
+	Note     string `json:"note"`
+}
+
+type stockRow struct {
+	ItemID    uint   `json:"item_id"`
+	ItemName  string `json:"item_name"`
+	UnitID    uint   `json:"unit_id"`
+	UnitName  string `json:"unit_name"`
+	Received  int    `json:"received"`
+	HandedOut int    `json:"handed_out"`
+	Available int    `json:"available"`
+}
+
+func receivedQty(tx *gorm.DB, itemID, unitID uint) (int, error) {
+	var n int
+	err := tx.Model(&entity.ItemDonation{}).
+		Joins("JOIN donations ON donations.id = item_donations.donation_id AND donations.deleted_at IS NULL").
+		Where("item_donations.item_id = ? AND item_donations.unit_id = ? AND donations.status IN ?", itemID, unitID, receivedDonationStatuses).
+		Select("COALESCE(SUM(item_donations.quantity), 0)").
+		Scan(&n).Error
+	return n, err
+}
+
+func handedOutQty(tx *gorm.DB, itemID, unitID uint) (int, error) {
+	var n int
+	err := tx.Model(&entity.FosterSupply{}).
+		Where("item_id = ? AND unit_id = ?", itemID, unitID).
+		Select("COALESCE(SUM(quantity), 0)").
+		Scan(&n).Error
+	return n, err
+}
+
+// GET /foster-supplies/stock — คงเหลือต่อ (ของ, หน่วย)
+func GetSupplyStock(c *gin.Context) {
+	if middlewares.StaffID(c) == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	db := configs.DB()
+
+	var rows []stockRow
+	if err := db.Model(&entity.ItemDonation{}).
+		Joins("JOIN donations ON donations.id = item_donations.donation_id AND donations.deleted_at IS NULL").
+		Joins("JOIN items ON items.id = item_donations.item_id").
+		Joins("JOIN units ON units.id = item_donations.unit_id").
+		Where("donations.status IN ?", receivedDonationStatuses).
+		Group("item_donations.item_id, item_donations.unit_id, items.name, units.name").
+		Order("items.name ASC, units.name ASC").
+		Select("item_donations.item_id AS item_id, items.name AS item_name, " +
+			"item_donations.unit_id AS unit_id, units.name AS unit_name, " +
+			"SUM(item_donations.quantity) AS received").
+		Scan(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	for i := range rows {
+		out, err := handedOutQty(db, rows[i].ItemID, rows[i].UnitID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+			return
+		}
+		rows[i].HandedOut = out
+		rows[i].Available = rows[i].Received - out
+	}
+	c.JSON(http.StatusOK, gin.H{"data": rows})
+}
+
+// POST /foster-placements/:id/supplies
+func CreateSupplyHandout(c *gin.Context) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req supplyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+
+	var supply entity.FosterSupply
+	var status int
+	err := configs.DB().Transaction(func(tx *gorm.DB) error {
+		var p entity.FosterPlacement
+		if err := tx.First(&p, c.Param("id")).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				status = http.StatusNotFound
+				return errors.New("placement not found")
+			}
+			return err
+		}
+		if p.EndDate != nil {
+			status = http.StatusConflict
+			return errors.New("placement already ended")
+		}
+
+		received, err := receivedQty(tx, req.ItemID, req.UnitID)
+		if err != nil {
+			return err
+		}
+		out, err := handedOutQty(tx, req.ItemID, req.UnitID)
+		if err != nil {
+			return err
+		}
+		if received-out < req.Quantity {
+			status = http.StatusConflict
+			return errors.New("insufficient stock")
+		}
+
+		supply = entity.FosterSupply{
+			PlacementID: p.ID,
+			ItemID:      req.ItemID,
+			UnitID:      req.UnitID,
+			Quantity:    req.Quantity,
+			HandedOutAt: time.Now(),
+			Note:        req.Note,
+			StaffID:     staffID,
+		}
+		return tx.Create(&supply).Error
+	})
+	if err != nil {
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, gin.H{"error": "handout failed: " + err.Error()})
+		return
+	}
+	if err := configs.DB().Preload("Item").Preload("Unit").First(&supply, supply.ID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": supply})
+}
@@ -0,0 +1,316 @@
+package health_records
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/middlewares"
+)
+
+/* ========== ลงนาม / แก้ไขเพิ่มเติม (amendment) ========== */
+
+const errFinalized = "health record is finalized; submit an amendment instead"
+
+// ฟิลด์ที่แก้ไขผ่าน amendment ได้ -> ชื่อคอลัมน์ (วัคซีนแก้ไม่ได้ ต้องบันทึกประวัติใหม่)
+var amendableColumns = map[string]string{
+	"date_record": "date_record",
+	"weight":      "weight",
+	"temperature": "temperature",
+	"symptoms":    "symptoms",
+	"diagnosis":   "diagnosis",
+	"treatment":   "treatment_plan",
+	"medication":  "medication",
+	"notes":       "notes",
+}
+
+var amendableOrder = []string{"date_record", "weight", "temperature", "symptoms", "diagnosis", "treatment", "medication", "notes"}
+
+type amendmentRequest struct {
+	Reason      string   `json:"reason" binding:"required"`
+	DateRecord  *string  `json:"date_record"`
+	Weight      *float64 `json:"weight"`
+	Temperature *float64 `json:"temperature"`
+	Symptoms    *string  `json:"symptoms"`
+	Diagnosis   *string  `json:"diagnosis"`
+	Treatment   *string  `json:"treatment"`
+	Medication  *string  `json:"medication"`
+	Notes       *string  `json:"notes"`
+}
+
+// รุ่นของประวัติ (1 = ต้นฉบับที่ลงนาม)
+type recordVersion struct {
+	Version   int                            `json:"version"`
+	Amendment *entity.MedicalRecordAmendment `json:"amendment,omitempty"`
+	Record    HealthRecordResponse           `json:"record"`
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func fieldValue(r HealthRecordResponse, field string) string {
+	switch field {
+	case "date_record":
+		return r.DateRecord
+	case "weight":
+		return formatFloat(r.Weight)
+	case "temperature":
+		return formatFloat(r.Temperature)
+	case "symptoms":
+		return r.Symptoms
+	case "diagnosis":
+		return r.Diagnosis
+	case "treatment":
+		return r.Treatment
+	case "medication":
+		return r.Medication
+	case "notes":
+		return r.Notes
+	}
+	return ""
+}
+
+func setFieldValue(r *HealthRecordResponse, field, v string) {
+	switch field {
+	case "date_record":
+		r.DateRecord = v
+	case "weight":
+		r.Weight, _ = strconv.ParseFloat(v, 64)
+	case "temperature":
+		r.Temperature, _ = strconv.ParseFloat(v, 64)
+	case "symptoms":
+		r.Symptoms = v
+	case "diagnosis":
+		r.Diagnosis = v
+	case "treatment":
+		r.Treatment = v
+	case "medication":
+		r.Medication = v
+	case "notes":
+		r.Notes = v
+	}
+}
+
+// POST /health-records/:id/finalize — สัตวแพทย์ลงนาม หลังจากนี้แก้ได้เฉพาะ amendment
+func FinalizeHealthRecord(c *gin.Context) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var record entity.MedicalRecord
+	if err := configs.DB().First(&record, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "health record not found"})
+		return
+	}
+	if record.Status == entity.MedicalRecordFinalized {
+		c.JSON(http.StatusConflict, gin.H{"error": "health record is already finalized"})
+		return
+	}
+	now := time.Now()
+	res := configs.DB().Model(&entity.MedicalRecord{}).
+		Where("id = ? AND status = ?", record.ID, entity.MedicalRecordDraft).
+		Updates(map[string]any{
+			"status":          entity.MedicalRecordFinalized,
+			"finalized_at":    now,
+			"finalized_by_id": *staffID,
+		})
+	if res.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "finalize failed: " + res.Error.Error()})
+		return
+	}
+	if res.RowsAffected == 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "health record is already finalized"})
+		return
+	}
+	configs.DB().Preload("VaccineRecords").Preload("FinalizedBy").First(&record, record.ID)
+	c.JSON(http.StatusOK, gin.H{"data": convertToResponse(record)})
+}
+
+// POST /health-records/:id/amendments — แก้ไขประวัติที่ลงนามแล้ว (ต้องระบุเหตุผล เก็บค่าเดิมไว้)
+func AmendHealthRecord(c *gin.Context) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req amendmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Reason == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "reason is required"})
+		return
+	}
+	fields := FieldErrors{}
+	var weight, temperature float64
+	if req.Weight != nil {
+		weight = *req.Weight
+	}
+	if req.Temperature != nil {
+		temperature = *req.Temperature
+	}
+	checkVitals(fields, weight, temperature)
+	var dateRecord time.Time
+	if req.DateRecord != nil {
+		t, err := parseFlexibleTime(*req.DateRecord)
+		if err != nil {
+			fields["date_record"] = err.Error()
+		} else if t.After(time.Now().Add(24 * time.Hour)) {
+			fields["date_record"] = "is in the future"
+		}
+		dateRecord = t.UTC()
+	}
+	if len(fields) > 0 {
+		respondFieldErrors(c, fields)
+		return
+	}
+
+	var amendment entity.MedicalRecordAmendment
+	var status int
+	err := configs.DB().Transaction(func(tx *gorm.DB) error {
+		var record entity.MedicalRecord
+		if err := tx.First(&record, c.Param("id")).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				status = http.StatusNotFound
+				return errors.New("health record not found")
+			}
+			return err
+		}
+		if record.Status != entity.MedicalRecordFinalized {
+			status = http.StatusConflict
+			return errors.New("health record is still a draft; edit it directly")
+		}
+
+		before := convertToResponse(record)
+		after := before
+		updates := map[string]any{}
+		if req.DateRecord != nil {
+			after.DateRecord = dateRecord.Format("2006-01-02T15:04:05.000Z")
+			updates["date_record"] = dateRecord
+		}
+		for _, p := range []struct {
+			field string
+			in    *float64
+		}{{"weight", req.Weight}, {"temperature", req.Temperature}} {
+			if p.in != nil {
+				setFieldValue(&after, p.field, formatFloat(*p.in))
+				updates[amendableColumns[p.field]] = *p.in
+			}
+		}
+		for _, p := range []struct {
+			field string
+			in    *string
+		}{{"symptoms", req.Symptoms}, {"diagnosis", req.Diagnosis}, {"treatment", req.Treatment},
+			{"medication", req.Medication}, {"notes", req.Notes}} {
+			if p.in != nil {
+				setFieldValue(&after, p.field, *p.in)
+				updates[amendableColumns[p.field]] = *p.in
+			}
+		}
+
+		var changes []entity.MedicalRecordAmendmentChange
+		for _, f := range amendableOrder {
+			oldV, newV := fieldValue(before, f), fieldValue(after, f)
+			if oldV == newV {
+				delete(updates, amendableColumns[f])
+				continue
+			}
+			changes = append(changes, entity.MedicalRecordAmendmentChange{Field: f, OldValue: oldV, NewValue: newV})
+		}
+		if len(changes) == 0 {
+			status = http.StatusBadRequest
+			return errors.New("no changes")
+		}
+
+		amendment = entity.MedicalRecordAmendment{
+			MedicalRecordID: record.ID,
+			Version:         record.Version + 1,
+			Reason:          req.Reason,
+			AmendedAt:       time.Now(),
+			AmendedByID:     *staffID,
+			Changes:         changes,
+		}
+		if err := tx.Create(&amendment).Error; err != nil {
+			return err
+		}
+		updates["version"] = amendment.Version
+		// กันแก้ซ้อนกัน: ต้องเป็นเวอร์ชันเดิมที่อ่านมา
+		res := tx.Model(&entity.MedicalRecord{}).
+			Where("id = ? AND version = ?", record.ID, record.Version).
+			Updates(updates)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			status = http.StatusConflict
+			return errors.New("health record was amended concurrently; reload and retry")
+		}
+		if err := tx.First(&record, record.ID).Error; err != nil {
+			return err
+		}
+		return syncVitalAlerts(tx, &record)
+	})
+	if err != nil {
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, gin.H{"error": "amend failed: " + err.Error()})
+		return
+	}
+	configs.DB().Preload("Changes").Preload("AmendedBy").First(&amendment, amendment.ID)
+	c.JSON(http.StatusCreated, gin.H{"data": amendment})
+}
+
+// GET /health-records/:id/amendments
+func GetAmendments(c *gin.Context) {
+	var rows []entity.MedicalRecordAmendment
+	if err := configs.DB().Preload("Changes").Preload("AmendedBy").
+		Where("medical_record_id = ?", c.Param("id")).
+		Order("version ASC").Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": rows})
+}
+
+// GET /health-records/:id/versions — ทุกเวอร์ชันตั้งแต่ต้นฉบับ (ย้อนค่าเดิมจาก amendment)
+func GetHealthRecordVersions(c *gin.Context) {
+	var record entity.MedicalRecord
+	if err := configs.DB().Preload("VaccineRecords").Preload("FinalizedBy").
+		Preload("Amendments", func(db *gorm.DB) *gorm.DB { return db.Order("version ASC") }).
+		Preload("Amendments.Changes").Preload("Amendments.AmendedBy").
+		First(&record, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "health record not found"})
+		return
+	}
+	amendments := record.Amendments
+	record.Amendments = nil
+	cur := convertToResponse(record)
+
+	versions := make([]recordVersion, len(amendments)+1)
+	for i := len(amendments); i >= 0; i-- {
+		v := recordVersion{Version: i + 1, Record: cur}
+		v.Record.Version = i + 1
+		if i > 0 {
+			a := amendments[i-1]
+			v.Amendment = &a
+			// ย้อนไปเวอร์ชันก่อนหน้า
+			for _, ch := range a.Changes {
+				setFieldValue(&cur, ch.Field, ch.OldValue)
+			}
+		}
+		versions[i] = v
+	}
+	c.JSON(http.StatusOK, gin.H{"data": versions})
+}
@@ -0,0 +1,62 @@
+package health_records
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"example.com/project-sa/utils/testutil"
+	"github.com/gin-gonic/gin"
+)
+
+func TestFinalizedRecordChangesOnlyThroughAmendments(t *testing.T) {
+	r := testRouter()
+	r.POST("/health-records/:id/finalize", FinalizeHealthRecord)
+	r.POST("/health-records/:id/amendments", AmendHealthRecord)
+	r.GET("/health-records/:id/versions", GetHealthRecordVersions)
+
+	record := gin.H{
+		"dog_id": 3, "date_record": "2025-06-03T09:00:00Z",
+		"weight": 20, "temperature": 38.6, "symptoms": "limping", "vaccination": "NO",
+	}
+	id := testutil.ID(testutil.MustDo(t, r, http.MethodPost, "/health-records", gin.H{"health_record": record}))
+	path := fmt.Sprintf("/health-records/%d", id)
+
+	record["notes"] = "draft edit"
+	testutil.MustDo(t, r, http.MethodPut, path, record)
+	if code, _ := testutil.Do(t, r, http.MethodPost, path+"/amendments", gin.H{"reason": "typo", "weight": 21}); code != http.StatusConflict {
+		t.Errorf("amend a draft = %d, want %d", code, http.StatusConflict)
+	}
+
+	testutil.MustDo(t, r, http.MethodPost, path+"/finalize", nil)
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   any
+		want   int
+	}{
+		{"edit finalized", http.MethodPut, path, record, http.StatusConflict},
+		{"delete finalized", http.MethodDelete, path, nil, http.StatusConflict},
+		{"finalize twice", http.MethodPost, path + "/finalize", nil, http.StatusConflict},
+		{"amend without reason", http.MethodPost, path + "/amendments", gin.H{"weight": 21}, http.StatusBadRequest},
+		{"amend without changes", http.MethodPost, path + "/amendments", gin.H{"reason": "recheck", "weight": 20}, http.StatusBadRequest},
+		{"amend invalid vitals", http.MethodPost, path + "/amendments", gin.H{"reason": "recheck", "temperature": 60}, http.StatusBadRequest},
+		{"amend weight", http.MethodPost, path + "/amendments", gin.H{"reason": "scale was off", "weight": 21.5}, http.StatusCreated},
+	}
+	for _, tt := range tests {
+		if code, out := testutil.Do(t, r, tt.method, tt.path, tt.body); code != tt.want {
+			t.Errorf("%s: %s %s = %d %v, want %d", tt.name, tt.method, tt.path, code, out, tt.want)
+		}
+	}
+
+	_, out := testutil.Do(t, r, http.MethodGet, path+"/versions", nil)
+	versions, _ := out["data"].([]any)
+	if len(versions) != 2 {
+		t.Fatalf("versions = %v, want original and one amendment", out)
+	}
+	weight := func(v any) any { return v.(map[string]any)["record"].(map[string]any)["weight"] }
+	if weight(versions[0]) != 20.0 || weight(versions[1]) != 21.5 {
+		t.Errorf("version weights = %v, %v, want 20 then 21.5", weight(versions[0]), weight(versions[1]))
+	}
+}
@@ -0,0 +1,158 @@
+package health_records
+
+import (
+	"errors"
+	"log"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/middlewares"
+	"example.com/project-sa/utils/upload"
+)
+
+/* ========== ไฟล์แนบประวัติสุขภาพ (เฉพาะเจ้าหน้าที่) ========== */
+
+var attachmentExts = map[string]bool{".pdf": true, ".jpg": true, ".jpeg": true, ".png": true, ".webp": true}
+
+var attachmentTypes = map[string]bool{
+	entity.AttachmentLabResult: true,
+	entity.AttachmentXRay:      true,
+	entity.AttachmentInvoice:   true,
+	entity.AttachmentOther:     true,
+}
+
+// POST /health-records/:id/attachments (multipart: file, type, description)
+func UploadAttachment(c *gin.Context) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var record entity.MedicalRecord
+	if err := configs.DB().First(&record, c.Param("id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "health record not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	kind := c.DefaultPostForm("type", entity.AttachmentOther)
+	if !attachmentTypes[kind] {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "type must be lab_result, xray, invoice or other"})
+		return
+	}
+
+	path, fh, err := upload.SavePrivate(c, "health", "med", attachmentExts)
+	if err != nil {
+		if errors.Is(err, upload.ErrNoFile) || errors.Is(err, upload.ErrUnsupportedType) || errors.Is(err, upload.ErrTooLarge) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	a := entity.MedicalRecordAttachment{
+		MedicalRecordID: record.ID,
+		Type:            kind,
+		Description:     strings.TrimSpace(c.PostForm("description")),
+		FileName:        filepath.Base(fh.Filename),
+		FilePath:        path,
+		ContentType:     mime.TypeByExtension(strings.ToLower(filepath.Ext(fh.Filename))),
+		SizeBytes:       fh.Size,
+		UploadedByID:    *staffID,
+	}
+	if err := configs.DB().Create(&a).Error; err != nil {
+		_ = upload.Remove(path)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save attachment: " + err.Error()})
+		return
+	}
+	configs.DB().Preload("UploadedBy").First(&a, a.ID)
+	c.JSON(http.StatusCreated, gin.H{"data": a})
+}
+
+// GET /health-records/:id/attachments
+func GetAttachments(c *gin.Context) {
+	if middlewares.StaffID(c) == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var rows []entity.MedicalRecordAttachment
+	if err := configs.DB().Preload("UploadedBy").
+		Where("medical_record_id = ?", c.Param("id")).
+		Order("id ASC").Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": rows})
+}
+
+func loadAttachment(c *gin.Context) (*entity.MedicalRecordAttachment, bool) {
+	var a entity.MedicalRecordAttachment
+	if err := configs.DB().First(&a, c.Param("id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "attachment not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return &a, true
+}
+
+// GET /health-record-attachments/:id/file
+func DownloadAttachment(c *gin.Context) {
+	if middlewares.StaffID(c) == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	a, ok := loadAttachment(c)
+	if !ok {
+		return
+	}
+	if a.ContentType != "" {
+		c.Header("Content-Type", a.ContentType)
+	}
+	c.FileAttachment(a.FilePath, a.FileName)
+}
+
+// DELETE /health-record-attachments/:id
+func DeleteAttachment(c *gin.Context) {
+	if middlewares.StaffID(c) == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	a, ok := loadAttachment(c)
+	if !ok {
+		return
+	}
+	// ไฟล์แนบของประวัติที่ลงนามแล้วเป็นส่วนหนึ่งของประวัติ ลบไม่ได้
+	var record entity.MedicalRecord
+	if err := configs.DB().Select("id", "status").First(&record, a.MedicalRecordID).Error; err == nil &&
+		record.Status == entity.MedicalRecordFinalized {
+		c.JSON(http.StatusConflict, gin.H{"error": errFinalized})
+		return
+	}
+	if err := configs.DB().Unscoped().Delete(a).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	removeFiles([]entity.MedicalRecordAttachment{*a})
+	c.JSON(http.StatusOK, gin.H{"message": "Attachment deleted successfully"})
+}
+
+// removeFiles ลบไฟล์หลังลบแถวสำเร็จแล้ว (ลบไม่ได้แค่ log ไว้)
+func removeFiles(rows []entity.MedicalRecordAttachment) {
+	for _, a := range rows {
+		if err := upload.Remove(a.FilePath); err != nil {
+			log.Printf("remove attachment %d: %v", a.ID, err)
+		}
+	}
+}
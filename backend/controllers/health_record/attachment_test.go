@@ -0,0 +1,106 @@
+package health_records
+
+import (
+	"bytes"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/utils/testutil"
+	"github.com/gin-gonic/gin"
+)
+
+func uploadAttachment(t *testing.T, r http.Handler, recordID uint, name, content string) *httptest.ResponseRecorder {
+	t.Helper()
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	mw.WriteField("type", entity.AttachmentLabResult)
+	mw.WriteField("description", "CBC")
+	fw, err := mw.CreateFormFile("file", name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fw.Write([]byte(content))
+	mw.Close()
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/health-records/%d/attachments", recordID), &buf)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func attachmentRouter(r *gin.Engine) *gin.Engine {
+	r.GET("/health-records/:id", GetHealthRecordById)
+	r.DELETE("/health-records/:id", DeleteHealthRecord)
+	r.POST("/health-records/:id/attachments", UploadAttachment)
+	r.GET("/health-records/:id/attachments", GetAttachments)
+	r.GET("/health-record-attachments/:id/file", DownloadAttachment)
+	return r
+}
+
+func TestAttachmentsAreStaffOnly(t *testing.T) {
+	staff := attachmentRouter(testutil.Router(1))
+	// ผู้ใช้ทั่วไปที่ login แล้ว (มี user_id แต่ไม่มี staff_id)
+	user := gin.New()
+	user.Use(func(c *gin.Context) { c.Set("user_id", uint(1)) })
+	attachmentRouter(user)
+
+	record := entity.MedicalRecord{DogID: 3, StaffID: 1, Symptoms: "lab"}
+	if err := configs.DB().Create(&record).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if w := uploadAttachment(t, user, record.ID, "cbc.pdf", "%PDF-1.4"); w.Code != http.StatusForbidden {
+		t.Errorf("user upload = %d, want %d", w.Code, http.StatusForbidden)
+	}
+	if w := uploadAttachment(t, staff, record.ID, "cbc.exe", "MZ"); w.Code != http.StatusBadRequest {
+		t.Errorf("upload .exe = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	w := uploadAttachment(t, staff, record.ID, "cbc.pdf", "%PDF-1.4")
+	if w.Code != http.StatusCreated {
+		t.Fatalf("staff upload = %d %s", w.Code, w.Body.String())
+	}
+	var a entity.MedicalRecordAttachment
+	if err := configs.DB().Where("medical_record_id = ?", record.ID).First(&a).Error; err != nil {
+		t.Fatalf("attachment row: %v", err)
+	}
+	if strings.HasPrefix(a.FilePath, "/static") || strings.HasPrefix(a.FilePath, "static") {
+		t.Errorf("attachment stored under public static path %q", a.FilePath)
+	}
+
+	for _, path := range []string{
+		fmt.Sprintf("/health-records/%d", record.ID),
+		fmt.Sprintf("/health-records/%d/attachments", record.ID),
+		fmt.Sprintf("/health-record-attachments/%d/file", a.ID),
+	} {
+		if code, _ := testutil.Do(t, user, http.MethodGet, path, nil); code != http.StatusForbidden {
+			t.Errorf("user GET %s = %d, want %d", path, code, http.StatusForbidden)
+		}
+	}
+
+	data := testutil.MustDo(t, staff, http.MethodGet, fmt.Sprintf("/health-records/%d", record.ID), nil)
+	if got, _ := data["attachments"].([]any); len(got) != 1 {
+		t.Errorf("record detail attachments = %v, want 1", data["attachments"])
+	}
+	w = httptest.NewRecorder()
+	staff.ServeHTTP(w, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/health-record-attachments/%d/file", a.ID), nil))
+	if w.Code != http.StatusOK || w.Body.String() != "%PDF-1.4" {
+		t.Errorf("staff download = %d %q, want the uploaded file", w.Code, w.Body.String())
+	}
+
+	testutil.MustDo(t, staff, http.MethodDelete, fmt.Sprintf("/health-records/%d", record.ID), nil)
+	var n int64
+	configs.DB().Unscoped().Model(&entity.MedicalRecordAttachment{}).Where("medical_record_id = ?", record.ID).Count(&n)
+	if n != 0 {
+		t.Errorf("attachments left after deleting the record = %d, want 0", n)
+	}
+	if _, err := os.Stat(strings.TrimPrefix(a.FilePath, "/")); !os.IsNotExist(err) {
+		t.Errorf("attachment file still on disk after deleting the record (stat err %v)", err)
+	}
+}
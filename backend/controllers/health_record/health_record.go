@@ -1,13 +1,20 @@
 package health_records
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"example.com/project-sa/configs"
+	"example.com/project-sa/controllers/vaccination"
 	"example.com/project-sa/entity"
+	"example.com/project-sa/middlewares"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type CreateHealthRecordPayload struct {
@@ -28,7 +35,7 @@ func parseFlexibleTime(s string) (time.Time, error) {
 			return t, nil
 		}
 	}
-	return time.Time{}, nil // Return zero time and no error if parsing fails, can be adjusted
+	return time.Time{}, errors.New("invalid date (RFC3339 or YYYY-MM-DD)")
 }
 
 type vaccineRecordIn struct {
@@ -40,19 +47,28 @@ type vaccineRecordIn struct {
 }
 
 type HealthRecordResponse struct {
-	ID             uint                   `json:"ID"`
-	DogID          uint                   `json:"dog_id"`
-	StaffID        *uint                  `json:"staff_id"`
-	Weight         float64                `json:"weight"`
-	Temperature    float64                `json:"temperature"`
-	Symptoms       string                 `json:"symptoms"`
-	Diagnosis      string                 `json:"diagnosis"`
-	Treatment      string                 `json:"treatment"`
-	Medication     string                 `json:"medication"`
-	Vaccination    string                 `json:"vaccination"`
-	Notes          string                 `json:"notes"`
-	DateRecord     string                 `json:"date_record"`
-	VaccineRecords []entity.VaccineRecord `json:"vaccine_records,omitempty"`
+	ID             uint                             `json:"ID"`
+	DogID          uint                             `json:"dog_id"`
+	StaffID        *uint                            `json:"staff_id"`
+	Weight         float64                          `json:"weight"`
+	Temperature    float64                          `json:"temperature"`
+	Symptoms       string                           `json:"symptoms"`
+	Diagnosis      string                           `json:"diagnosis"`
+	Treatment      string                           `json:"treatment"`
+	Medication     string                           `json:"medication"`
+	Vaccination    entity.VaccinationFlag           `json:"vaccination"`
+	Notes          string                           `json:"notes"`
+	DateRecord     string                           `json:"date_record"`
+	VaccineRecords []entity.VaccineRecord           `json:"vaccine_records,omitempty"`
+	Attachments    []entity.MedicalRecordAttachment `json:"attachments,omitempty"`
+	Status         string                           `json:"status"`
+	FinalizedAt    *time.Time                       `json:"finalized_at"`
+	FinalizedByID  *uint                            `json:"finalized_by_id"`
+	FinalizedBy    *entity.Staff                    `json:"finalized_by,omitempty"`
+	UpdatedByID    *uint                            `json:"updated_by_id"`
+	Version        int                              `json:"version"`
+	Amendments     []entity.MedicalRecordAmendment  `json:"amendments,omitempty"`
+	LabOrders      []entity.LabOrder                `json:"lab_orders,omitempty"`
 }
 
 func convertToResponse(record entity.MedicalRecord) HealthRecordResponse {
@@ -80,13 +96,31 @@ func convertToResponse(record entity.MedicalRecord) HealthRecordResponse {
 		Notes:          record.Notes,
 		DateRecord:     dateString,
 		VaccineRecords: record.VaccineRecords,
+		Attachments:    record.Attachments,
+		Status:         record.Status,
+		FinalizedAt:    record.FinalizedAt,
+		FinalizedByID:  record.FinalizedByID,
+		FinalizedBy:    record.FinalizedBy,
+		UpdatedByID:    record.UpdatedByID,
+		Version:        record.Version,
+		Amendments:     record.Amendments,
+		LabOrders:      record.LabOrders,
 	}
 }
 
 func GetHealthRecordById(c *gin.Context) {
+	// มีไฟล์แนบและผลแล็บ เฉพาะเจ้าหน้าที่ (token ของผู้ใช้ทั่วไปก็ผ่าน protected ได้)
+	if middlewares.StaffID(c) == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
 	id := c.Param("id")
 	var healthRecord entity.MedicalRecord
-	if err := configs.DB().Preload("VaccineRecords").First(&healthRecord, id).Error; err != nil {
+	if err := configs.DB().Preload("VaccineRecords").Preload("Attachments").Preload("Attachments.UploadedBy").
+		Preload("FinalizedBy").Preload("Amendments", func(db *gorm.DB) *gorm.DB { return db.Order("version ASC") }).
+		Preload("Amendments.Changes").Preload("Amendments.AmendedBy").
+		Preload("LabOrders.Panel").Preload("LabOrders.Results").
+		First(&healthRecord, id).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "health record not found"})
 		return
 	}
@@ -108,30 +142,91 @@ func GetHealthRecordsByDogId(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"data": responses})
 }
 
+// CreateRecord บันทึกประวัติสุขภาพพร้อมวัคซีนภายใน tx (ใช้ร่วมกับการปิดนัดหมายสัตวแพทย์)
+// ข้อมูลไม่ถูกต้องคืน FieldErrors; ค่าผิดปกติจะเปิดแจ้งเตือนสุขภาพ
+// วัคซีนถือว่าฉีดในคลินิก จึงตัดสต็อกตาม lot
+func CreateRecord(tx *gorm.DB, record *entity.MedicalRecord, vaccines []entity.VaccineRecord) error {
+	return createRecord(tx, record, vaccines, true)
+}
+
+// ImportRecord เหมือน CreateRecord แต่ไม่ตัดสต็อก (ประวัติที่ได้รับมาจากองค์กรอื่น)
+func ImportRecord(tx *gorm.DB, record *entity.MedicalRecord, vaccines []entity.VaccineRecord) error {
+	return createRecord(tx, record, vaccines, false)
+}
+
+func createRecord(tx *gorm.DB, record *entity.MedicalRecord, vaccines []entity.VaccineRecord, consumeStock bool) error {
+	// วัคซีนที่ส่งมาซ้อนใน record ก็ต้องผ่านการตัดสต็อกเหมือนกัน
+	vaccines = append(record.VaccineRecords, vaccines...)
+	record.VaccineRecords = nil
+	if err := ValidateRecord(tx, record, len(vaccines)); err != nil {
+		return err
+	}
+	record.DateRecord = record.DateRecord.UTC()
+	if err := tx.Create(record).Error; err != nil {
+		return fmt.Errorf("Failed to create health record: %w", err)
+	}
+	for _, vr := range vaccines {
+		vr.MedID = record.ID
+		if err := tx.Create(&vr).Error; err != nil {
+			return fmt.Errorf("Failed to create vaccine record: %w", err)
+		}
+		if consumeStock {
+			if err := entity.ConsumeVaccineDose(tx, &vr, &record.StaffID); err != nil {
+				return err
+			}
+		}
+		record.VaccineRecords = append(record.VaccineRecords, vr)
+	}
+	if len(vaccines) > 0 {
+		if err := vaccination.SyncDogAlerts(tx, record.DogID); err != nil {
+			return err
+		}
+	}
+	return syncVitalAlerts(tx, record)
+}
+
+// stockStatus วัคซีนตัดสต็อกไม่ได้ = 409, อื่น ๆ = 500
+func stockStatus(err error) int {
+	if entity.IsStockError(err) {
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
 func CreateHealthRecord(c *gin.Context) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
 	var payload CreateHealthRecordPayload
 	if err := c.ShouldBindJSON(&payload); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format: " + err.Error()})
+		respondFieldErrors(c, bindErrorFields(err))
 		return
 	}
 
-	tx := configs.DB().Begin()
 	healthRecord := payload.HealthRecord
-	if err := tx.Create(healthRecord).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create health record: " + err.Error()})
+	if healthRecord == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "health_record is required"})
 		return
 	}
+	// ผู้บันทึก = staff ที่ login (ไม่รับจาก body) และสร้างเป็นร่างเสมอ การลงนามทำผ่าน /finalize
+	healthRecord.StaffID = *staffID
+	healthRecord.Status = entity.MedicalRecordDraft
+	healthRecord.FinalizedAt, healthRecord.FinalizedByID = nil, nil
+	healthRecord.Version = 1
+	healthRecord.Amendments = nil
 
-	if len(payload.VaccineRecords) > 0 {
-		for _, vr := range payload.VaccineRecords {
-			vr.MedID = healthRecord.ID
-			if err := tx.Create(&vr).Error; err != nil {
-				tx.Rollback()
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create vaccine record: " + err.Error()})
-				return
-			}
+	tx := configs.DB().Begin()
+	if err := CreateRecord(tx, healthRecord, payload.VaccineRecords); err != nil {
+		tx.Rollback()
+		var fields FieldErrors
+		if errors.As(err, &fields) {
+			respondFieldErrors(c, fields)
+			return
 		}
+		c.JSON(stockStatus(err), gin.H{"error": err.Error()})
+		return
 	}
 
 	if err := tx.Commit().Error; err != nil {
@@ -142,33 +237,87 @@ func CreateHealthRecord(c *gin.Context) {
 }
 
 func DeleteHealthRecord(c *gin.Context) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
 	id := c.Param("id")
-	if err := configs.DB().Delete(&entity.MedicalRecord{}, id).Error; err != nil {
+	var record entity.MedicalRecord
+	if err := configs.DB().First(&record, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "health record not found"})
+		return
+	}
+	if record.Status == entity.MedicalRecordFinalized {
+		c.JSON(http.StatusConflict, gin.H{"error": errFinalized})
+		return
+	}
+	// ไฟล์แนบลบไปพร้อมกับประวัติ
+	var attachments []entity.MedicalRecordAttachment
+	err := configs.DB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("medical_record_id = ?", id).Find(&attachments).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("medical_record_id = ?", id).Delete(&entity.MedicalRecordAttachment{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("medical_record_id = ?", id).Delete(&entity.HealthAlert{}).Error; err != nil {
+			return err
+		}
+		// คืนสต็อกวัคซีนที่ตัดไปกับประวัตินี้
+		var vrIDs []uint
+		if err := tx.Model(&entity.VaccineRecord{}).Where("med_id = ?", id).Pluck("id", &vrIDs).Error; err != nil {
+			return err
+		}
+		for _, vrID := range vrIDs {
+			if err := entity.ReleaseVaccineDose(tx, vrID); err != nil {
+				return err
+			}
+		}
+		// ใบสั่งแล็บยังอยู่ แค่ไม่ผูกกับประวัตินี้แล้ว
+		if err := tx.Model(&entity.LabOrder{}).Where("medical_record_id = ?", id).Update("medical_record_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&record).Update("deleted_by_id", *staffID).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&record).Error; err != nil {
+			return err
+		}
+		return vaccination.SyncDogAlerts(tx, record.DogID)
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	removeFiles(attachments)
 	c.JSON(http.StatusOK, gin.H{"message": "Health record deleted successfully"})
 }
 
 func UpdateHealthRecord(c *gin.Context) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
 	id := c.Param("id")
 	var updateData struct {
-		DogID          uint              `json:"dog_id"`
-		StaffID        *uint             `json:"staff_id"`
-		Weight         float64           `json:"weight"`
-		Temperature    float64           `json:"temperature"`
-		Symptoms       string            `json:"symptoms"`
-		Diagnosis      string            `json:"diagnosis"`
-		Treatment      string            `json:"treatment"`
-		Medication     string            `json:"medication"`
-		Vaccination    string            `json:"vaccination"`
-		Notes          string            `json:"notes"`
-		DateRecord     string            `json:"date_record"`
-		VaccineRecords []vaccineRecordIn `json:"vaccine_records"`
+		DogID          uint                   `json:"dog_id"`
+		StaffID        *uint                  `json:"staff_id"`
+		Weight         float64                `json:"weight"`
+		Temperature    float64                `json:"temperature"`
+		Symptoms       string                 `json:"symptoms"`
+		Diagnosis      string                 `json:"diagnosis"`
+		Treatment      string                 `json:"treatment"`
+		Medication     string                 `json:"medication"`
+		Vaccination    entity.VaccinationFlag `json:"vaccination"`
+		Notes          string                 `json:"notes"`
+		DateRecord     string                 `json:"date_record"`
+		VaccineRecords []vaccineRecordIn      `json:"vaccine_records"`
 	}
 
 	if err := c.ShouldBindJSON(&updateData); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondFieldErrors(c, bindErrorFields(err))
 		return
 	}
 
@@ -177,13 +326,33 @@ func UpdateHealthRecord(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "health record not found"})
 		return
 	}
+	if existingRecord.Status == entity.MedicalRecordFinalized {
+		c.JSON(http.StatusConflict, gin.H{"error": errFinalized})
+		return
+	}
+	originalDogID := existingRecord.DogID
 
-	tx := configs.DB().Begin()
-
+	fields := FieldErrors{}
 	if updateData.DateRecord != "" {
-		if t, err := parseFlexibleTime(updateData.DateRecord); err == nil {
-			existingRecord.DateRecord = t
+		t, err := parseFlexibleTime(updateData.DateRecord)
+		if err != nil {
+			fields["date_record"] = err.Error()
 		}
+		existingRecord.DateRecord = t.UTC()
+	}
+	nextDues := make([]time.Time, len(updateData.VaccineRecords))
+	for i, in := range updateData.VaccineRecords {
+		if in.VaccineID == 0 {
+			fields[fmt.Sprintf("vaccine_records[%d].vaccine_id", i)] = "is required"
+		}
+		if in.NextDueDate == "" {
+			continue // ว่าง = คำนวณจากตารางวัคซีน
+		}
+		t, err := parseFlexibleTime(in.NextDueDate)
+		if err != nil {
+			fields[fmt.Sprintf("vaccine_records[%d].next_due_date", i)] = err.Error()
+		}
+		nextDues[i] = t
 	}
 
 	if updateData.DogID != 0 {
@@ -199,15 +368,42 @@ func UpdateHealthRecord(c *gin.Context) {
 	existingRecord.TreatmentPlan = updateData.Treatment
 	existingRecord.Medication = updateData.Medication
 	existingRecord.Notes = updateData.Notes
-	existingRecord.Vaccination = updateData.Vaccination
+	if updateData.Vaccination != "" {
+		existingRecord.Vaccination = updateData.Vaccination
+	}
+	existingRecord.UpdatedByID = staffID
+
+	vaccineCount := 0
+	if existingRecord.Vaccination == entity.VaccinationYes {
+		vaccineCount = len(updateData.VaccineRecords)
+	}
+	if err := ValidateRecord(configs.DB(), &existingRecord, vaccineCount); err != nil {
+		var invalid FieldErrors
+		if !errors.As(err, &invalid) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		for k, v := range invalid {
+			if _, ok := fields[k]; !ok {
+				fields[k] = v
+			}
+		}
+	}
+	if len(fields) > 0 {
+		respondFieldErrors(c, fields)
+		return
+	}
+
+	tx := configs.DB().Begin()
 
-	if err := tx.Save(&existingRecord).Error; err != nil {
+	// ไม่ upsert VaccineRecords ที่ preload มา (จัดการเองด้านล่าง)
+	if err := tx.Omit(clause.Associations).Save(&existingRecord).Error; err != nil {
 		tx.Rollback()
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update health record: " + err.Error()})
 		return
 	}
 
-	if existingRecord.Vaccination == "YES" {
+	if existingRecord.Vaccination == entity.VaccinationYes {
 		incomingVaccineMap := make(map[uint]bool)
 		for _, in := range updateData.VaccineRecords {
 			if in.ID != 0 {
@@ -217,7 +413,7 @@ func UpdateHealthRecord(c *gin.Context) {
 
 		for _, existingVR := range existingRecord.VaccineRecords {
 			if _, found := incomingVaccineMap[existingVR.ID]; !found {
-				if err := tx.Delete(&existingVR).Error; err != nil {
+				if err := deleteVaccineRecord(tx, existingVR); err != nil {
 					tx.Rollback()
 					c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete old vaccine record: " + err.Error()})
 					return
@@ -225,18 +421,26 @@ func UpdateHealthRecord(c *gin.Context) {
 			}
 		}
 
-		for _, in := range updateData.VaccineRecords {
-			nextDueDate, _ := parseFlexibleTime(in.NextDueDate)
+		for i, in := range updateData.VaccineRecords {
+			nextDueDate := nextDues[i]
 			if in.ID != 0 {
 				var vrToUpdate entity.VaccineRecord
-				if tx.First(&vrToUpdate, in.ID).Error == nil {
+				if tx.Where("med_id = ?", existingRecord.ID).First(&vrToUpdate, in.ID).Error == nil {
+					// เปลี่ยน lot/วัคซีน = คืนโดสเดิมแล้วตัดใหม่ (แก้ฟิลด์อื่นไม่แตะสต็อก)
+					restock := !strings.EqualFold(vrToUpdate.LotNumber, in.LotNumber) || vrToUpdate.VaccineID != in.VaccineID
 					vrToUpdate.VaccineID = in.VaccineID
 					vrToUpdate.DoseNumber = in.DoseNumber
 					vrToUpdate.LotNumber = in.LotNumber
 					vrToUpdate.NextDueDate = nextDueDate
-					if err := tx.Save(&vrToUpdate).Error; err != nil {
+					err := tx.Omit(clause.Associations).Save(&vrToUpdate).Error
+					if err == nil && restock {
+						if err = entity.ReleaseVaccineDose(tx, vrToUpdate.ID); err == nil {
+							err = entity.ConsumeVaccineDose(tx, &vrToUpdate, staffID)
+						}
+					}
+					if err != nil {
 						tx.Rollback()
-						c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update vaccine record: " + err.Error()})
+						c.JSON(stockStatus(err), gin.H{"error": "Failed to update vaccine record: " + err.Error()})
 						return
 					}
 				}
@@ -248,17 +452,37 @@ func UpdateHealthRecord(c *gin.Context) {
 					LotNumber: in.LotNumber,
 					NextDueDate: nextDueDate,
 				}
-				if err := tx.Create(&newVR).Error; err != nil {
+				err := tx.Create(&newVR).Error
+				if err == nil {
+					err = entity.ConsumeVaccineDose(tx, &newVR, staffID)
+				}
+				if err != nil {
 					tx.Rollback()
-					c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create new vaccine record: " + err.Error()})
+					c.JSON(stockStatus(err), gin.H{"error": "Failed to create new vaccine record: " + err.Error()})
 					return
 				}
 			}
 		}
-	} else if existingRecord.Vaccination == "NO" {
-		if err := tx.Where("med_id = ?", existingRecord.ID).Delete(&entity.VaccineRecord{}).Error; err != nil {
+	} else if existingRecord.Vaccination == entity.VaccinationNo {
+		for _, existingVR := range existingRecord.VaccineRecords {
+			if err := deleteVaccineRecord(tx, existingVR); err != nil {
+				tx.Rollback()
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to clear vaccine records: " + err.Error()})
+				return
+			}
+		}
+	}
+
+	if err := syncVitalAlerts(tx, &existingRecord); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update health alerts: " + err.Error()})
+		return
+	}
+	// ย้ายประวัติไปสุนัขตัวอื่นได้ จึง sync ทั้งตัวเดิมและตัวใหม่
+	for _, dogID := range []uint{originalDogID, existingRecord.DogID} {
+		if err := vaccination.SyncDogAlerts(tx, dogID); err != nil {
 			tx.Rollback()
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to clear vaccine records: " + err.Error()})
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update vaccination alerts: " + err.Error()})
 			return
 		}
 	}
@@ -271,4 +495,12 @@ func UpdateHealthRecord(c *gin.Context) {
 	var updatedRecord entity.MedicalRecord
 	configs.DB().Preload("VaccineRecords").First(&updatedRecord, id)
 	c.JSON(http.StatusOK, gin.H{"message": "Health record updated successfully", "data": convertToResponse(updatedRecord)})
-}
\ No newline at end of file
+}
+
+// deleteVaccineRecord ลบเข็มวัคซีนพร้อมคืนสต็อกที่ตัดไป
+func deleteVaccineRecord(tx *gorm.DB, vr entity.VaccineRecord) error {
+	if err := entity.ReleaseVaccineDose(tx, vr.ID); err != nil {
+		return err
+	}
+	return tx.Delete(&vr).Error
+}
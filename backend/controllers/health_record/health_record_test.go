@@ -0,0 +1,126 @@
+package health_records
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/migrations"
+	"example.com/project-sa/seeds"
+	"example.com/project-sa/utils/testutil"
+	"github.com/gin-gonic/gin"
+)
+
+func TestMain(m *testing.M) { testutil.Main(m, migrations.AutoMigrate, seeds.SeedAll) }
+
+func testRouter() *gin.Engine {
+	r := testutil.Router(1)
+	r.POST("/health-records", CreateHealthRecord)
+	r.PUT("/health-records/:id", UpdateHealthRecord)
+	r.DELETE("/health-records/:id", DeleteHealthRecord)
+	return r
+}
+
+// stockOf lot -> คงเหลือ ของวัคซีนนี้
+func stockOf(t *testing.T, vaccineID uint) map[string]int {
+	t.Helper()
+	var lots []entity.ClinicLot
+	if err := configs.DB().Joins("JOIN clinic_items ON clinic_items.id = clinic_lots.item_id").
+		Where("clinic_items.vaccine_id = ?", vaccineID).Find(&lots).Error; err != nil {
+		t.Fatalf("query lots: %v", err)
+	}
+	out := map[string]int{}
+	for _, l := range lots {
+		out[l.LotNumber] = l.QuantityOnHand
+	}
+	return out
+}
+
+func TestUpdateHealthRecordDoesNotReDeductStock(t *testing.T) {
+	db := configs.DB()
+	var vaccine entity.Vaccine
+	if err := db.First(&vaccine).Error; err != nil {
+		t.Fatalf("seeded vaccine: %v", err)
+	}
+	item := entity.ClinicItem{Name: "test " + vaccine.Name, Kind: entity.ClinicItemVaccine, Unit: "dose", VaccineID: &vaccine.ID}
+	if err := db.Create(&item).Error; err != nil {
+		t.Fatalf("create item: %v", err)
+	}
+	for _, no := range []string{"LOT-A", "LOT-B"} {
+		if err := db.Create(&entity.ClinicLot{ItemID: item.ID, LotNumber: no, QuantityReceived: 10, QuantityOnHand: 10}).Error; err != nil {
+			t.Fatalf("create lot: %v", err)
+		}
+	}
+
+	r := testRouter()
+	data := testutil.MustDo(t, r, http.MethodPost, "/health-records", gin.H{
+		"health_record": gin.H{
+			"dog_id": 1, "staff_id": 1, "date_record": "2025-06-01T10:00:00Z",
+			"weight": 8, "temperature": 38.5, "symptoms": "booster", "vaccination": "YES",
+		},
+		"vaccine_records": []gin.H{{"vaccine_id": vaccine.ID, "lot_number": "LOT-A"}},
+	})
+	id := testutil.ID(data)
+	vrID := testutil.ID(data["vaccine_records"].([]any)[0].(map[string]any))
+	if got := stockOf(t, vaccine.ID); got["LOT-A"] != 9 || got["LOT-B"] != 10 {
+		t.Fatalf("after create stock = %v, want LOT-A 9, LOT-B 10", got)
+	}
+
+	update := func(notes, lot string) gin.H {
+		return gin.H{
+			"dog_id": 1, "weight": 8, "temperature": 38.5, "symptoms": "booster", "notes": notes,
+			"vaccination": "YES", "date_record": "2025-06-01T10:00:00Z",
+			"vaccine_records": []gin.H{{"ID": vrID, "vaccine_id": vaccine.ID, "lot_number": lot}},
+		}
+	}
+	tests := []struct {
+		name  string
+		body  gin.H
+		wantA int
+		wantB int
+	}{
+		{"edit notes", update("first edit", "LOT-A"), 9, 10},
+		{"edit notes again", update("second edit", "LOT-A"), 9, 10},
+		{"same lot in other case", update("second edit", "lot-a"), 9, 10},
+		{"move dose to another lot", update("wrong lot", "LOT-B"), 10, 9},
+		{"edit after lot change", update("third edit", "LOT-B"), 10, 9},
+	}
+	for _, tt := range tests {
+		testutil.MustDo(t, r, http.MethodPut, fmt.Sprintf("/health-records/%d", id), tt.body)
+		if got := stockOf(t, vaccine.ID); got["LOT-A"] != tt.wantA || got["LOT-B"] != tt.wantB {
+			t.Errorf("%s: stock = %v, want LOT-A %d, LOT-B %d", tt.name, got, tt.wantA, tt.wantB)
+		}
+	}
+
+	testutil.MustDo(t, r, http.MethodDelete, fmt.Sprintf("/health-records/%d", id), nil)
+	if got := stockOf(t, vaccine.ID); got["LOT-A"] != 10 || got["LOT-B"] != 10 {
+		t.Errorf("after delete stock = %v, want both lots back to 10", got)
+	}
+}
+
+func TestCreateHealthRecordTakesStaffFromLogin(t *testing.T) {
+	body := gin.H{
+		"health_record": gin.H{
+			"dog_id": 2, "staff_id": 1, "date_record": "2025-06-02T10:00:00Z",
+			"weight": 12, "temperature": 38.4, "symptoms": "checkup", "vaccination": "NO",
+		},
+	}
+	anon := testutil.Router(0)
+	anon.POST("/health-records", CreateHealthRecord)
+	if code, _ := testutil.Do(t, anon, http.MethodPost, "/health-records", body); code != http.StatusForbidden {
+		t.Errorf("POST without login = %d, want %d", code, http.StatusForbidden)
+	}
+
+	r := testutil.Router(2)
+	r.POST("/health-records", CreateHealthRecord)
+	data := testutil.MustDo(t, r, http.MethodPost, "/health-records", body)
+	var record entity.MedicalRecord
+	if err := configs.DB().First(&record, testutil.ID(data)).Error; err != nil {
+		t.Fatalf("created record: %v", err)
+	}
+	if record.StaffID != 2 || record.Status != entity.MedicalRecordDraft {
+		t.Errorf("record staff = %d status = %q, want staff 2 (login, not body) and draft", record.StaffID, record.Status)
+	}
+}
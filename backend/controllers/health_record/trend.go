@@ -0,0 +1,334 @@
+package health_records
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/utils/pointer"
+	"example.com/project-sa/utils/timeutil"
+)
+
+/* ========== กราฟน้ำหนัก/อุณหภูมิ และรายงานน้ำหนักลดรายโซน ========== */
+
+const daysPerMonth = 30.4375
+
+type vitalPoint struct {
+	Date        time.Time `json:"date"` // วันที่บันทึก หรือวันเริ่มช่วง (เมื่อ resample)
+	Records     int       `json:"records"`
+	RecordIDs   []uint    `json:"record_ids"`
+	Weight      *float64  `json:"weight"`      // kg (เฉลี่ยในช่วง)
+	Temperature *float64  `json:"temperature"` // °C (เฉลี่ยในช่วง)
+
+	// เทียบกับจุดก่อนหน้าที่มีน้ำหนัก
+	WeightDelta    *float64 `json:"weight_delta"`
+	WeightDeltaPct *float64 `json:"weight_delta_pct"`
+	DaysSincePrev  *int     `json:"days_since_prev"`
+
+	// เฉพาะลูกสุนัข (< 12 เดือน) ที่รู้วันเกิดและมีตารางอ้างอิงของขนาดตัว
+	AgeMonths        *float64 `json:"age_months,omitempty"`
+	GrowthPercentile *float64 `json:"growth_percentile,omitempty"`
+}
+
+type vitalSummary struct {
+	Points          int      `json:"points"`
+	FirstWeight     *float64 `json:"first_weight"`
+	LatestWeight    *float64 `json:"latest_weight"`
+	WeightChange    *float64 `json:"weight_change"`
+	WeightChangePct *float64 `json:"weight_change_pct"`
+	MinTemperature  *float64 `json:"min_temperature"`
+	MaxTemperature  *float64 `json:"max_temperature"`
+}
+
+type weightLossRow struct {
+	DogID        uint           `json:"dog_id"`
+	Dog          *entity.Dog    `json:"dog"`
+	Kennel       *entity.Kennel `json:"kennel"`
+	PeakWeight   float64        `json:"peak_weight"`
+	PeakDate     time.Time      `json:"peak_date"`
+	LatestWeight float64        `json:"latest_weight"`
+	LatestDate   time.Time      `json:"latest_date"`
+	LossKg       float64        `json:"loss_kg"`
+	LossPct      float64        `json:"loss_pct"`
+}
+
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
+
+// periodStart วันเริ่มช่วงตามเวลาไทย (week = วันจันทร์)
+func periodStart(t time.Time, resample string) time.Time {
+	loc := timeutil.TZBangkok()
+	t = t.In(loc)
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	switch resample {
+	case "week":
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	case "month":
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc)
+	}
+	return t
+}
+
+// growthPercentile เปอร์เซ็นไทล์โดยประมาณ (log-normal จาก P10/P50/P90 ที่ประมาณค่าระหว่างเดือน)
+func growthPercentile(refs []entity.PuppyGrowthReference, ageMonths, weight float64) (float64, bool) {
+	if len(refs) == 0 || ageMonths < float64(refs[0].AgeMonths) || ageMonths > float64(refs[len(refs)-1].AgeMonths) {
+		return 0, false
+	}
+	i := sort.Search(len(refs), func(i int) bool { return float64(refs[i].AgeMonths) >= ageMonths })
+	lo, hi := refs[i], refs[i]
+	if i > 0 && float64(refs[i].AgeMonths) > ageMonths {
+		lo = refs[i-1]
+	}
+	f := 0.0
+	if hi.AgeMonths != lo.AgeMonths {
+		f = (ageMonths - float64(lo.AgeMonths)) / float64(hi.AgeMonths-lo.AgeMonths)
+	}
+	lerp := func(a, b float64) float64 { return a + (b-a)*f }
+	p10, p50, p90 := lerp(lo.P10, hi.P10), lerp(lo.P50, hi.P50), lerp(lo.P90, hi.P90)
+	if p10 <= 0 || p50 <= 0 || p90 <= p10 {
+		return 0, false
+	}
+	sigma := (math.Log(p90) - math.Log(p10)) / (2 * 1.2816)
+	z := math.Log(weight/p50) / sigma
+	return round1(50 * (1 + math.Erf(z/math.Sqrt2))), true
+}
+
+// GET /dogs/:id/vitals?from=&to=&resample=none|week|month
+func GetDogVitals(c *gin.Context) {
+	resample := c.DefaultQuery("resample", "none")
+	if resample != "none" && resample != "week" && resample != "month" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "resample must be none, week or month"})
+		return
+	}
+	var dog entity.Dog
+	if err := configs.DB().Preload("AnimalSize").First(&dog, c.Param("id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "dog not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+
+	db := configs.DB().Where("dog_id = ? AND (weight > 0 OR temperature > 0)", dog.ID)
+	for _, p := range []struct{ param, cond string }{{"from", "date_record >= ?"}, {"to", "date_record < ?"}} {
+		v := c.Query(p.param)
+		if v == "" {
+			continue
+		}
+		t, err := parseFlexibleTime(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + p.param + " (RFC3339 or YYYY-MM-DD)"})
+			return
+		}
+		if p.param == "to" && len(v) == len("2006-01-02") {
+			t = t.AddDate(0, 0, 1) // to เป็นวันที่ = รวมทั้งวัน
+		}
+		db = db.Where(p.cond, t.UTC())
+	}
+	var records []entity.MedicalRecord
+	if err := db.Order("date_record ASC, id ASC").Find(&records).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+
+	var refs []entity.PuppyGrowthReference
+	if dog.DateOfBirth != nil {
+		if err := configs.DB().Where("animal_size_id = ?", dog.AnimalSizeID).
+			Order("age_months ASC").Find(&refs).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+			return
+		}
+	}
+
+	// รวมจุดตามช่วง (none = หนึ่งจุดต่อประวัติ)
+	type bucket struct {
+		point        vitalPoint
+		wSum, tSum   float64
+		wN, tN       int
+		lastRecorded time.Time
+	}
+	var buckets []*bucket
+	for _, r := range records {
+		key := r.DateRecord
+		if resample != "none" {
+			key = periodStart(r.DateRecord, resample)
+		}
+		var b *bucket
+		if n := len(buckets); resample != "none" && n > 0 && buckets[n-1].point.Date.Equal(key) {
+			b = buckets[n-1]
+		} else {
+			b = &bucket{point: vitalPoint{Date: key, RecordIDs: []uint{}}}
+			buckets = append(buckets, b)
+		}
+		b.point.Records++
+		b.point.RecordIDs = append(b.point.RecordIDs, r.ID)
+		b.lastRecorded = r.DateRecord
+		if r.Weight > 0 {
+			b.wSum += r.Weight
+			b.wN++
+		}
+		if r.Temperature > 0 {
+			b.tSum += r.Temperature
+			b.tN++
+		}
+	}
+
+	points := make([]vitalPoint, 0, len(buckets))
+	summary := vitalSummary{}
+	var prev *vitalPoint
+	for _, b := range buckets {
+		p := b.point
+		if b.tN > 0 {
+			p.Temperature = pointer.P(round1(b.tSum / float64(b.tN)))
+			if summary.MinTemperature == nil || *p.Temperature < *summary.MinTemperature {
+				summary.MinTemperature = pointer.P(*p.Temperature)
+			}
+			if summary.MaxTemperature == nil || *p.Temperature > *summary.MaxTemperature {
+				summary.MaxTemperature = pointer.P(*p.Temperature)
+			}
+		}
+		if b.wN > 0 {
+			w := math.Round(b.wSum/float64(b.wN)*100) / 100
+			p.Weight = &w
+			if prev != nil {
+				p.WeightDelta = pointer.P(math.Round((w-*prev.Weight)*100) / 100)
+				p.WeightDeltaPct = pointer.P(round1((w - *prev.Weight) / *prev.Weight * 100))
+				p.DaysSincePrev = pointer.P(int(p.Date.Sub(prev.Date).Hours() / 24))
+			}
+			if dog.DateOfBirth != nil {
+				age := b.lastRecorded.Sub(dog.DateOfBirth.Time).Hours() / 24 / daysPerMonth
+				if age >= 0 && age < entity.AdultFromMonths {
+					p.AgeMonths = pointer.P(round1(age))
+					if pct, ok := growthPercentile(refs, age, w); ok {
+						p.GrowthPercentile = &pct
+					}
+				}
+			}
+			if summary.FirstWeight == nil {
+				summary.FirstWeight = pointer.P(w)
+			}
+			summary.LatestWeight = pointer.P(w)
+		}
+		points = append(points, p)
+		if p.Weight != nil {
+			prev = &points[len(points)-1]
+		}
+	}
+	summary.Points = len(points)
+	if summary.FirstWeight != nil && *summary.FirstWeight > 0 {
+		summary.WeightChange = pointer.P(math.Round((*summary.LatestWeight-*summary.FirstWeight)*100) / 100)
+		summary.WeightChangePct = pointer.P(round1((*summary.LatestWeight - *summary.FirstWeight) / *summary.FirstWeight * 100))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{
+		"dog":           dog,
+		"resample":      resample,
+		"dob_estimated": dog.DOBEstimated,
+		"points":        points,
+		"summary":       summary,
+	}})
+}
+
+// GET /zones/:id/weight-loss?days=30&min_percent=5 — สุนัขในโซนที่น้ำหนักล่าสุดลดลงจากน้ำหนักสูงสุดในช่วง days วันก่อนหน้า
+func GetZoneWeightLoss(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days < 1 || days > 365 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "days must be between 1 and 365"})
+		return
+	}
+	minPct, err := strconv.ParseFloat(c.DefaultQuery("min_percent", "5"), 64)
+	if err != nil || minPct < 0 || minPct > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "min_percent must be between 0 and 100"})
+		return
+	}
+	var zone entity.Zone
+	if err := configs.DB().First(&zone, c.Param("id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "zone not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+
+	var dogs []entity.Dog
+	if err := configs.DB().Preload("Kennel").Preload("AnimalSize").
+		Joins("JOIN kennels ON kennels.id = dogs.kennel_id AND kennels.deleted_at IS NULL").
+		Where("kennels.zone_id = ?", zone.ID).
+		Where("dogs.is_adopted = ? AND dogs.status = ?", false, entity.DogStatusShelter).
+		Find(&dogs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	rows := []weightLossRow{}
+	if len(dogs) == 0 {
+		c.JSON(http.StatusOK, gin.H{"data": rows, "zone": zone})
+		return
+	}
+	ids := make([]uint, len(dogs))
+	for i, d := range dogs {
+		ids[i] = d.ID
+	}
+	var records []entity.MedicalRecord
+	if err := configs.DB().Select("id", "dog_id", "date_record", "weight").
+		Where("dog_id IN ? AND weight > 0", ids).
+		Order("date_record ASC, id ASC").Find(&records).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	byDog := map[uint][]entity.MedicalRecord{}
+	for _, r := range records {
+		byDog[r.DogID] = append(byDog[r.DogID], r)
+	}
+
+	for i := range dogs {
+		d := &dogs[i]
+		rs := byDog[d.ID]
+		if len(rs) < 2 {
+			continue
+		}
+		latest := rs[len(rs)-1]
+		since := latest.DateRecord.AddDate(0, 0, -days)
+		var peak *entity.MedicalRecord
+		for j := range rs[:len(rs)-1] {
+			r := &rs[j]
+			if r.DateRecord.Before(since) {
+				continue
+			}
+			if peak == nil || r.Weight > peak.Weight {
+				peak = r
+			}
+		}
+		if peak == nil || peak.Weight <= latest.Weight {
+			continue
+		}
+		loss := (peak.Weight - latest.Weight) / peak.Weight * 100
+		if loss < minPct {
+			continue
+		}
+		rows = append(rows, weightLossRow{
+			DogID:        d.ID,
+			Dog:          d,
+			Kennel:       d.Kennel,
+			PeakWeight:   peak.Weight,
+			PeakDate:     peak.DateRecord,
+			LatestWeight: latest.Weight,
+			LatestDate:   latest.DateRecord,
+			LossKg:       math.Round((peak.Weight-latest.Weight)*100) / 100,
+			LossPct:      round1(loss),
+		})
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].LossPct > rows[j].LossPct })
+	c.JSON(http.StatusOK, gin.H{"data": rows, "zone": zone, "days": days, "min_percent": minPct})
+}
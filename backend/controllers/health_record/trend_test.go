@@ -0,0 +1,44 @@
+package health_records
+
+import (
+	"testing"
+
+	"example.com/project-sa/entity"
+)
+
+func TestGrowthPercentile(t *testing.T) {
+	// ช่วง P10-P50-P90 เป็นอัตราส่วนคงที่ (x1.5) ทุกเดือน -> P10/P90 ได้ 10/90 พอดี
+	refs := []entity.PuppyGrowthReference{
+		{AgeMonths: 2, P10: 2, P50: 3, P90: 4.5},
+		{AgeMonths: 4, P10: 4, P50: 6, P90: 9},
+	}
+	tests := []struct {
+		name   string
+		refs   []entity.PuppyGrowthReference
+		age    float64
+		weight float64
+		want   float64
+		ok     bool
+	}{
+		{"median at reference month", refs, 2, 3, 50, true},
+		{"p90 at reference month", refs, 4, 9, 90, true},
+		{"p10 at reference month", refs, 4, 4, 10, true},
+		{"median between months", refs, 3, 4.5, 50, true},
+		{"p90 between months", refs, 3, 6.75, 90, true},
+		{"far below the curve", refs, 4, 1, 0, true},
+		{"far above the curve", refs, 4, 30, 100, true},
+		{"younger than the table", refs, 1.5, 2, 0, false},
+		{"older than the table", refs, 4.5, 6, 0, false},
+		{"no reference", nil, 3, 4, 0, false},
+		{"single reference month", refs[1:], 4, 6, 50, true},
+		{"broken reference", []entity.PuppyGrowthReference{{AgeMonths: 2, P10: 5, P50: 4, P90: 3}}, 2, 4, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := growthPercentile(tt.refs, tt.age, tt.weight)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("growthPercentile(age %v, %v kg) = %v, %v; want %v, %v", tt.age, tt.weight, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package health_records
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/controllers/vaccination"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/utils/testutil"
+	"github.com/gin-gonic/gin"
+)
+
+func TestVaccineRecordsSyncAlerts(t *testing.T) {
+	db := configs.DB()
+	dog := entity.Dog{Name: "Alert", BreedID: 1, AnimalSexID: 1, AnimalSizeID: 1, Status: entity.DogStatusShelter}
+	vaccine := entity.Vaccine{Name: "alert test vaccine"}
+	if err := db.Create(&dog).Error; err != nil {
+		t.Fatalf("create dog: %v", err)
+	}
+	if err := db.Create(&vaccine).Error; err != nil {
+		t.Fatalf("create vaccine: %v", err)
+	}
+
+	r := testRouter()
+	r.GET("/vaccinations/alerts", vaccination.GetAlerts)
+	record := func(date, nextDue string) uint {
+		return testutil.ID(testutil.MustDo(t, r, http.MethodPost, "/health-records", gin.H{
+			"health_record": gin.H{
+				"dog_id": dog.ID, "staff_id": 1, "date_record": date,
+				"weight": 10, "temperature": 38.5, "symptoms": "vaccine", "vaccination": "YES",
+			},
+			"vaccine_records": []gin.H{{"vaccine_id": vaccine.ID, "next_due_date": nextDue}},
+		}))
+	}
+	open := func() []entity.VaccinationAlert {
+		t.Helper()
+		var rows []entity.VaccinationAlert
+		if err := db.Where("dog_id = ? AND vaccine_id = ? AND resolved_at IS NULL", dog.ID, vaccine.ID).Find(&rows).Error; err != nil {
+			t.Fatalf("query alerts: %v", err)
+		}
+		return rows
+	}
+
+	record("2025-01-01T10:00:00Z", "2025-02-01T00:00:00Z")
+	if got := open(); len(got) != 1 {
+		t.Fatalf("after overdue dose open alerts = %+v, want one for vaccine %d", got, vaccine.ID)
+	}
+
+	// GET อ่านอย่างเดียว: ข้อมูลที่ไม่ได้ผ่าน health record ต้องไม่ถูกเปิดแจ้งเตือนตอนอ่าน
+	other := entity.Vaccine{Name: "alert test vaccine 2"}
+	if err := db.Create(&other).Error; err != nil {
+		t.Fatalf("create vaccine: %v", err)
+	}
+	med := entity.MedicalRecord{DogID: dog.ID, StaffID: 1, DateRecord: time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)}
+	if err := db.Create(&med).Error; err != nil {
+		t.Fatalf("create record: %v", err)
+	}
+	if err := db.Create(&entity.VaccineRecord{MedID: med.ID, VaccineID: other.ID, DoseNumber: 1, NextDueDate: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)}).Error; err != nil {
+		t.Fatalf("create vaccine record: %v", err)
+	}
+	var before, after int64
+	db.Model(&entity.VaccinationAlert{}).Count(&before)
+	testutil.MustDo(t, r, http.MethodGet, "/vaccinations/alerts", nil)
+	db.Model(&entity.VaccinationAlert{}).Count(&after)
+	if before != after {
+		t.Errorf("GET /vaccinations/alerts changed alert rows %d -> %d, want read-only", before, after)
+	}
+
+	booster := record("2025-03-01T10:00:00Z", "2099-01-01T00:00:00Z")
+	if got := open(); len(got) != 0 {
+		t.Errorf("after booster open alerts = %+v, want none", got)
+	}
+
+	testutil.MustDo(t, r, http.MethodDelete, fmt.Sprintf("/health-records/%d", booster), nil)
+	if got := open(); len(got) != 1 {
+		t.Errorf("after deleting booster open alerts = %+v, want the first dose overdue again", got)
+	}
+}
@@ -0,0 +1,118 @@
+package health_records
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"example.com/project-sa/entity"
+)
+
+/* ========== ตรวจข้อมูลประวัติสุขภาพ (แจ้ง error รายฟิลด์) ========== */
+
+// ขอบเขตค่าที่เป็นไปได้ (ไม่ใช่ค่าปกติ — ค่าปกติดูที่ VitalRange)
+const (
+	maxWeightKg = 150.0
+	minTempC    = 30.0
+	maxTempC    = 45.0
+)
+
+// FieldErrors ข้อผิดพลาดรายฟิลด์ -> 400 {"error", "fields"}
+type FieldErrors map[string]string
+
+func (f FieldErrors) Error() string {
+	keys := make([]string, 0, len(f))
+	for k := range f {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + ": " + f[k]
+	}
+	return "invalid health record (" + strings.Join(parts, "; ") + ")"
+}
+
+func respondFieldErrors(c *gin.Context, fields FieldErrors) {
+	c.JSON(http.StatusBadRequest, gin.H{"error": "invalid health record", "fields": fields})
+}
+
+// bindErrorFields แปลง error จาก ShouldBindJSON เป็นรายฟิลด์
+func bindErrorFields(err error) FieldErrors {
+	var te *json.UnmarshalTypeError
+	var pe *time.ParseError
+	switch {
+	case errors.As(err, &te):
+		field := te.Field
+		if i := strings.LastIndex(field, "."); i >= 0 {
+			field = field[i+1:]
+		}
+		return FieldErrors{field: "must be a " + te.Type.String()}
+	case errors.Is(err, entity.ErrInvalidVaccination):
+		return FieldErrors{"vaccination": entity.ErrInvalidVaccination.Error()}
+	case errors.As(err, &pe):
+		return FieldErrors{"date": "invalid date (RFC3339): " + pe.Value}
+	}
+	return FieldErrors{"body": err.Error()}
+}
+
+// checkVitals ค่าน้ำหนัก/อุณหภูมิต้องอยู่ในช่วงที่เป็นไปได้ (0 = ไม่ได้วัด)
+func checkVitals(fields FieldErrors, weight, temperature float64) {
+	if weight < 0 || weight > maxWeightKg {
+		fields["weight"] = "must be between 0 and 150 kg (0 = not measured)"
+	}
+	if temperature != 0 && (temperature < minTempC || temperature > maxTempC) {
+		fields["temperature"] = "must be between 30 and 45 °C (0 = not measured)"
+	}
+}
+
+// ValidateRecord ตรวจประวัติสุขภาพก่อนบันทึก (vaccineCount = จำนวนวัคซีนที่บันทึกพร้อมกัน)
+func ValidateRecord(tx *gorm.DB, r *entity.MedicalRecord, vaccineCount int) error {
+	fields := FieldErrors{}
+	if r.DogID == 0 {
+		fields["dog_id"] = "is required"
+	} else {
+		var n int64
+		if err := tx.Model(&entity.Dog{}).Where("id = ?", r.DogID).Count(&n).Error; err != nil {
+			return err
+		}
+		if n == 0 {
+			fields["dog_id"] = "dog not found"
+		}
+	}
+	if r.StaffID == 0 {
+		fields["staff_id"] = "is required"
+	} else {
+		var n int64
+		if err := tx.Model(&entity.Staff{}).Where("id = ?", r.StaffID).Count(&n).Error; err != nil {
+			return err
+		}
+		if n == 0 {
+			fields["staff_id"] = "staff not found"
+		}
+	}
+	if r.DateRecord.IsZero() {
+		fields["date_record"] = "is required"
+	} else if r.DateRecord.After(time.Now().Add(24 * time.Hour)) {
+		fields["date_record"] = "is in the future"
+	}
+	checkVitals(fields, r.Weight, r.Temperature)
+	switch {
+	case !r.Vaccination.Valid():
+		fields["vaccination"] = entity.ErrInvalidVaccination.Error()
+	case r.Vaccination == entity.VaccinationYes && vaccineCount == 0:
+		fields["vaccine_records"] = `required when vaccination is "YES"`
+	case r.Vaccination == entity.VaccinationNo && vaccineCount > 0:
+		fields["vaccination"] = `must be "YES" when vaccine_records are given`
+	}
+	if len(fields) > 0 {
+		return fields
+	}
+	return nil
+}
@@ -0,0 +1,278 @@
+package health_records
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/middlewares"
+)
+
+/* ========== ค่าปกติสัญญาณชีพ / แจ้งเตือนสุขภาพ ========== */
+
+type vitalRangeRequest struct {
+	TempMin           float64 `json:"temp_min" binding:"required"`
+	TempMax           float64 `json:"temp_max" binding:"required"`
+	WeightMin         float64 `json:"weight_min" binding:"required"`
+	WeightMax         float64 `json:"weight_max" binding:"required"`
+	WeightLossPercent float64 `json:"weight_loss_percent" binding:"required,gt=0,lte=100"`
+	WeightLossDays    int     `json:"weight_loss_days" binding:"required,min=1,max=365"`
+}
+
+// vitalRangeFor ค่าปกติตามขนาดตัวของสุนัข (ไม่ได้ตั้งไว้ = ค่าเริ่มต้น)
+func vitalRangeFor(tx *gorm.DB, dogID uint) (entity.VitalRange, error) {
+	var dog entity.Dog
+	if err := tx.Unscoped().Select("id", "animal_size_id").First(&dog, dogID).Error; err != nil {
+		return entity.VitalRange{}, err
+	}
+	var vr entity.VitalRange
+	err := tx.Where("animal_size_id = ?", dog.AnimalSizeID).First(&vr).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return entity.DefaultVitalRange, nil
+	}
+	return vr, err
+}
+
+type vitalFinding struct {
+	value   float64
+	message string
+}
+
+// evaluateVitals หาค่าผิดปกติของประวัตินี้ (อุณหภูมิ/น้ำหนัก เทียบช่วงปกติ และน้ำหนักที่ลดลงจากประวัติก่อนหน้า)
+func evaluateVitals(tx *gorm.DB, r *entity.MedicalRecord) (map[string]vitalFinding, error) {
+	vr, err := vitalRangeFor(tx, r.DogID)
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]vitalFinding{}
+	if r.Temperature > 0 {
+		switch {
+		case r.Temperature > vr.TempMax:
+			out[entity.HealthAlertFever] = vitalFinding{r.Temperature,
+				fmt.Sprintf("temperature %.1f °C is above normal (%.1f–%.1f)", r.Temperature, vr.TempMin, vr.TempMax)}
+		case r.Temperature < vr.TempMin:
+			out[entity.HealthAlertHypothermia] = vitalFinding{r.Temperature,
+				fmt.Sprintf("temperature %.1f °C is below normal (%.1f–%.1f)", r.Temperature, vr.TempMin, vr.TempMax)}
+		}
+	}
+	if r.Weight <= 0 {
+		return out, nil
+	}
+	if r.Weight < vr.WeightMin || r.Weight > vr.WeightMax {
+		out[entity.HealthAlertWeightRange] = vitalFinding{r.Weight,
+			fmt.Sprintf("weight %.1f kg is outside the normal range (%.1f–%.1f)", r.Weight, vr.WeightMin, vr.WeightMax)}
+	}
+	var prev struct{ Weight float64 }
+	if err := tx.Model(&entity.MedicalRecord{}).Select("COALESCE(MAX(weight), 0) AS weight").
+		Where("dog_id = ? AND id <> ? AND weight > 0", r.DogID, r.ID).
+		Where("date_record >= ? AND date_record < ?", r.DateRecord.AddDate(0, 0, -vr.WeightLossDays), r.DateRecord).
+		Scan(&prev).Error; err != nil {
+		return nil, err
+	}
+	if prev.Weight > 0 {
+		loss := (prev.Weight - r.Weight) / prev.Weight * 100
+		if loss >= vr.WeightLossPercent {
+			out[entity.HealthAlertWeightLoss] = vitalFinding{loss,
+				fmt.Sprintf("weight dropped %.1f%% (%.1f → %.1f kg) within %d days", loss, prev.Weight, r.Weight, vr.WeightLossDays)}
+		}
+	}
+	return out, nil
+}
+
+// syncVitalAlerts เปิด/อัปเดต/ปิดแจ้งเตือนของประวัตินี้ให้ตรงกับค่าปัจจุบัน (เรียกหลังสร้าง/แก้/amend)
+func syncVitalAlerts(tx *gorm.DB, r *entity.MedicalRecord) error {
+	findings, err := evaluateVitals(tx, r)
+	if err != nil {
+		return err
+	}
+	var existing []entity.HealthAlert
+	if err := tx.Where("medical_record_id = ?", r.ID).Find(&existing).Error; err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, a := range existing {
+		f, ok := findings[a.Kind]
+		if !ok {
+			if a.ResolvedAt == nil {
+				if err := tx.Model(&a).Update("resolved_at", now).Error; err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		delete(findings, a.Kind)
+		if err := tx.Model(&a).Updates(map[string]any{
+			"value":       f.value,
+			"message":     f.message,
+			"resolved_at": nil,
+		}).Error; err != nil {
+			return err
+		}
+	}
+	for kind, f := range findings {
+		if err := tx.Create(&entity.HealthAlert{
+			DogID:           r.DogID,
+			MedicalRecordID: r.ID,
+			Kind:            kind,
+			Value:           f.value,
+			Message:         f.message,
+		}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CountOpenAlerts แจ้งเตือนที่ยังไม่ปิดและยังไม่มีคนรับทราบ (ใช้บน dashboard)
+func CountOpenAlerts(db *gorm.DB) (int64, error) {
+	var n int64
+	err := db.Model(&entity.HealthAlert{}).
+		Where("resolved_at IS NULL AND acknowledged_at IS NULL").Count(&n).Error
+	return n, err
+}
+
+// GET /vital-ranges
+func GetVitalRanges(c *gin.Context) {
+	var rows []entity.VitalRange
+	if err := configs.DB().Preload("AnimalSize").Order("animal_size_id ASC").Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": rows, "default": entity.DefaultVitalRange})
+}
+
+// PUT /vital-ranges/:size_id
+func UpsertVitalRange(c *gin.Context) {
+	if middlewares.StaffID(c) == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req vitalRangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	fields := FieldErrors{}
+	if req.TempMin >= req.TempMax {
+		fields["temp_max"] = "must be greater than temp_min"
+	}
+	if req.TempMin < minTempC || req.TempMax > maxTempC {
+		fields["temp_min"] = "range must be within 30–45 °C"
+	}
+	if req.WeightMin <= 0 || req.WeightMin >= req.WeightMax {
+		fields["weight_max"] = "must be greater than weight_min (> 0)"
+	}
+	if req.WeightMax > maxWeightKg {
+		fields["weight_max"] = "must be at most 150 kg"
+	}
+	if len(fields) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid vital range", "fields": fields})
+		return
+	}
+	var size entity.AnimalSize
+	if err := configs.DB().First(&size, c.Param("size_id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "animal size not found"})
+		return
+	}
+	vr := entity.VitalRange{AnimalSizeID: size.ID}
+	if err := configs.DB().Where("animal_size_id = ?", size.ID).
+		Assign(entity.VitalRange{
+			TempMin:           req.TempMin,
+			TempMax:           req.TempMax,
+			WeightMin:         req.WeightMin,
+			WeightMax:         req.WeightMax,
+			WeightLossPercent: req.WeightLossPercent,
+			WeightLossDays:    req.WeightLossDays,
+		}).FirstOrCreate(&vr).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "save failed: " + err.Error()})
+		return
+	}
+	vr.AnimalSize = &size
+	c.JSON(http.StatusOK, gin.H{"data": vr})
+}
+
+func alertsQuery(c *gin.Context) (*gorm.DB, bool) {
+	db := configs.DB().Preload("Dog").Preload("MedicalRecord").Preload("AcknowledgedBy")
+	switch c.DefaultQuery("status", "open") {
+	case "open":
+		db = db.Where("resolved_at IS NULL AND acknowledged_at IS NULL")
+	case "acknowledged":
+		db = db.Where("resolved_at IS NULL AND acknowledged_at IS NOT NULL")
+	case "resolved":
+		db = db.Where("resolved_at IS NOT NULL")
+	case "all":
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be open, acknowledged, resolved or all"})
+		return nil, false
+	}
+	if v := c.Query("kind"); v != "" {
+		db = db.Where("kind = ?", v)
+	}
+	return db, true
+}
+
+// GET /health-alerts?status=open|acknowledged|resolved|all&kind=
+func GetHealthAlerts(c *gin.Context) {
+	db, ok := alertsQuery(c)
+	if !ok {
+		return
+	}
+	var rows []entity.HealthAlert
+	if err := db.Order("created_at DESC").Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": rows})
+}
+
+// GET /dogs/:id/health-alerts?status=
+func GetDogHealthAlerts(c *gin.Context) {
+	db, ok := alertsQuery(c)
+	if !ok {
+		return
+	}
+	var rows []entity.HealthAlert
+	if err := db.Where("dog_id = ?", c.Param("id")).Order("created_at DESC").Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": rows})
+}
+
+// POST /health-alerts/:id/ack
+func AcknowledgeHealthAlert(c *gin.Context) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var a entity.HealthAlert
+	if err := configs.DB().First(&a, c.Param("id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "alert not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	if a.AcknowledgedAt != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "alert is already acknowledged"})
+		return
+	}
+	now := time.Now()
+	if err := configs.DB().Model(&a).Updates(map[string]any{
+		"acknowledged_at":    now,
+		"acknowledged_by_id": *staffID,
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed: " + err.Error()})
+		return
+	}
+	configs.DB().Preload("Dog").Preload("AcknowledgedBy").First(&a, a.ID)
+	c.JSON(http.StatusOK, gin.H{"data": a})
+}
@@ -0,0 +1,111 @@
+package health_records
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"testing"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/utils/testutil"
+	"github.com/gin-gonic/gin"
+)
+
+func TestCreateHealthRecordFieldErrors(t *testing.T) {
+	r := testRouter()
+	code, out := testutil.Do(t, r, http.MethodPost, "/health-records", gin.H{
+		"health_record": gin.H{
+			"dog_id": 1, "date_record": "2025-06-01T10:00:00Z",
+			"weight": -1, "temperature": 50, "symptoms": "x", "vaccination": "NO",
+		},
+	})
+	fields, _ := out["fields"].(map[string]any)
+	for _, f := range []string{"weight", "temperature"} {
+		if _, ok := fields[f]; !ok {
+			t.Errorf("missing field error %q in %v", f, out)
+		}
+	}
+	if code != http.StatusBadRequest {
+		t.Errorf("invalid record = %d, want %d", code, http.StatusBadRequest)
+	}
+
+	code, out = testutil.Do(t, r, http.MethodPost, "/health-records", gin.H{
+		"health_record": gin.H{"dog_id": 1, "date_record": "2025-06-01T10:00:00Z", "vaccination": "MAYBE"},
+	})
+	if fields, _ := out["fields"].(map[string]any); code != http.StatusBadRequest || fields["vaccination"] == nil {
+		t.Errorf("vaccination MAYBE = %d %v, want 400 with a vaccination field error", code, out)
+	}
+
+	code, out = testutil.Do(t, r, http.MethodPost, "/health-records", gin.H{
+		"health_record": gin.H{"dog_id": 1, "date_record": "01/06/2025", "vaccination": "NO"},
+	})
+	if fields, _ := out["fields"].(map[string]any); code != http.StatusBadRequest || fields["date"] == nil {
+		t.Errorf("bad date_record = %d %v, want 400 with a date field error", code, out)
+	}
+}
+
+func TestVitalAlertsFollowSizeRanges(t *testing.T) {
+	db := configs.DB()
+	size := entity.AnimalSize{Name: "vitals test"}
+	if err := db.Create(&size).Error; err != nil {
+		t.Fatal(err)
+	}
+	dog := entity.Dog{Name: "Vitals", BreedID: 1, AnimalSexID: 1, AnimalSizeID: size.ID, Status: entity.DogStatusShelter}
+	if err := db.Create(&dog).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	r := testRouter()
+	r.PUT("/vital-ranges/:size_id", UpsertVitalRange)
+	r.GET("/dogs/:id/health-alerts", GetDogHealthAlerts)
+
+	rangeURL := fmt.Sprintf("/vital-ranges/%d", size.ID)
+	if code, _ := testutil.Do(t, r, http.MethodPut, rangeURL, gin.H{
+		"temp_min": 39.5, "temp_max": 38, "weight_min": 5, "weight_max": 20, "weight_loss_percent": 10, "weight_loss_days": 30,
+	}); code != http.StatusBadRequest {
+		t.Errorf("inverted temperature range = %d, want %d", code, http.StatusBadRequest)
+	}
+	testutil.MustDo(t, r, http.MethodPut, rangeURL, gin.H{
+		"temp_min": 37.5, "temp_max": 39.2, "weight_min": 5, "weight_max": 20, "weight_loss_percent": 10, "weight_loss_days": 30,
+	})
+
+	record := func(date string, weight, temp float64) uint {
+		return testutil.ID(testutil.MustDo(t, r, http.MethodPost, "/health-records", gin.H{
+			"health_record": gin.H{
+				"dog_id": dog.ID, "date_record": date, "weight": weight, "temperature": temp,
+				"symptoms": "checkup", "vaccination": "NO",
+			},
+		}))
+	}
+	openKinds := func() []string {
+		t.Helper()
+		_, out := testutil.Do(t, r, http.MethodGet, fmt.Sprintf("/dogs/%d/health-alerts", dog.ID), nil)
+		rows, _ := out["data"].([]any)
+		kinds := []string{}
+		for _, row := range rows {
+			kinds = append(kinds, row.(map[string]any)["kind"].(string))
+		}
+		sort.Strings(kinds)
+		return kinds
+	}
+
+	record("2025-06-01T10:00:00Z", 12, 38.5)
+	if got := openKinds(); len(got) != 0 {
+		t.Fatalf("normal record alerts = %v, want none", got)
+	}
+	// 40 °C สูงกว่าช่วงของขนาดนี้ และน้ำหนักลด 12→10.5 (12.5%) ภายใน 30 วัน
+	id := record("2025-06-10T10:00:00Z", 10.5, 40)
+	if got := openKinds(); fmt.Sprint(got) != fmt.Sprint([]string{entity.HealthAlertFever, entity.HealthAlertWeightLoss}) {
+		t.Errorf("abnormal record alerts = %v, want fever and weight loss", got)
+	}
+
+	// แก้อุณหภูมิให้ปกติ แจ้งเตือนไข้ต้องปิด
+	testutil.MustDo(t, r, http.MethodPut, fmt.Sprintf("/health-records/%d", id), gin.H{
+		"dog_id": dog.ID, "weight": 10.5, "temperature": 38.6, "symptoms": "checkup",
+		"vaccination": "NO", "date_record": "2025-06-10T10:00:00Z",
+	})
+	if got := openKinds(); fmt.Sprint(got) != fmt.Sprint([]string{entity.HealthAlertWeightLoss}) {
+		t.Errorf("after correcting temperature alerts = %v, want weight loss only", got)
+	}
+}
@@ -0,0 +1,385 @@
+package inventory
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/middlewares"
+	"example.com/project-sa/utils/timeutil"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+/* ========== Clinic inventory (ยา/วัคซีน/เวชภัณฑ์ แยก lot) ========== */
+
+// ค่าเริ่มต้นของ ?days= ในรายการใกล้หมดอายุ
+const defaultExpiryWindowDays = 30
+
+type itemRequest struct {
+	Name         string `json:"name" binding:"required"`
+	Kind         string `json:"kind" binding:"required,oneof=vaccine drug supply"`
+	Unit         string `json:"unit" binding:"required"`
+	ReorderLevel int    `json:"reorder_level" binding:"min=0"`
+	VaccineID    *uint  `json:"vaccine_id"`
+}
+
+type lotRequest struct {
+	LotNumber  string `json:"lot_number" binding:"required"`
+	ExpiryDate string `json:"expiry_date"` // YYYY-MM-DD
+	Quantity   int    `json:"quantity" binding:"required,min=1"`
+	Supplier   string `json:"supplier"`
+}
+
+type adjustRequest struct {
+	Quantity int    `json:"quantity" binding:"required"` // + เพิ่ม / - ลด
+	Reason   string `json:"reason" binding:"required,oneof=adjust dispose"`
+	Note     string `json:"note" binding:"required"`
+}
+
+type recallRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+func today() entity.Date {
+	return entity.NewDate(time.Now().In(timeutil.TZBangkok()))
+}
+
+func validateItem(tx *gorm.DB, req itemRequest) error {
+	if req.VaccineID == nil {
+		return nil
+	}
+	if req.Kind != entity.ClinicItemVaccine {
+		return errors.New("vaccine_id is only allowed for kind vaccine")
+	}
+	var v entity.Vaccine
+	if err := tx.First(&v, *req.VaccineID).Error; err != nil {
+		return errors.New("invalid vaccine_id")
+	}
+	return nil
+}
+
+// GET /inventory/items?kind=
+func GetItems(c *gin.Context) {
+	db := configs.DB().Preload("Vaccine").
+		Preload("Lots", func(db *gorm.DB) *gorm.DB { return db.Order("expiry_date IS NULL, expiry_date ASC, id ASC") })
+	if k := c.Query("kind"); k != "" {
+		db = db.Where("kind = ?", k)
+	}
+	var rows []entity.ClinicItem
+	if err := db.Order("name ASC").Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": rows})
+}
+
+// GET /inventory/items/:id
+func GetItem(c *gin.Context) {
+	var item entity.ClinicItem
+	if err := configs.DB().Preload("Vaccine").
+		Preload("Lots", func(db *gorm.DB) *gorm.DB { return db.Order("expiry_date IS NULL, expiry_date ASC, id ASC") }).
+		First(&item, c.Param("id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "item not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": item})
+}
+
+// POST /inventory/items
+func CreateItem(c *gin.Context) {
+	if middlewares.StaffID(c) == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req itemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	if err := validateItem(configs.DB(), req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	item := entity.ClinicItem{
+		Name:         strings.TrimSpace(req.Name),
+		Kind:         req.Kind,
+		Unit:         req.Unit,
+		ReorderLevel: req.ReorderLevel,
+		VaccineID:    req.VaccineID,
+	}
+	if err := configs.DB().Create(&item).Error; err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "create failed (duplicate name or vaccine?): " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": item})
+}
+
+// PUT /inventory/items/:id
+func UpdateItem(c *gin.Context) {
+	if middlewares.StaffID(c) == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req itemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	var item entity.ClinicItem
+	if err := configs.DB().First(&item, c.Param("id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "item not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	if err := validateItem(configs.DB(), req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := configs.DB().Model(&item).Updates(map[string]any{
+		"name":          strings.TrimSpace(req.Name),
+		"kind":          req.Kind,
+		"unit":          req.Unit,
+		"reorder_level": req.ReorderLevel,
+		"vaccine_id":    req.VaccineID,
+	}).Error; err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "update failed: " + err.Error()})
+		return
+	}
+	configs.DB().Preload("Vaccine").First(&item, item.ID)
+	c.JSON(http.StatusOK, gin.H{"data": item})
+}
+
+// POST /inventory/items/:id/lots — รับของเข้า lot ใหม่ (หรือเติม lot เดิมที่วันหมดอายุตรงกัน)
+func ReceiveLot(c *gin.Context) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req lotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	expiry, err := entity.ParseDate(req.ExpiryDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid expiry_date (YYYY-MM-DD)"})
+		return
+	}
+	if expiry != nil && expiry.Before(today().Time) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "lot is already expired"})
+		return
+	}
+	lotNo := strings.ToUpper(strings.TrimSpace(req.LotNumber))
+
+	var lot entity.ClinicLot
+	var status int
+	err = configs.DB().Transaction(func(tx *gorm.DB) error {
+		var item entity.ClinicItem
+		if err := tx.First(&item, c.Param("id")).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				status = http.StatusNotFound
+				return errors.New("item not found")
+			}
+			return err
+		}
+		err := tx.Where("item_id = ? AND UPPER(lot_number) = ?", item.ID, lotNo).First(&lot).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			lot = entity.ClinicLot{
+				ItemID:           item.ID,
+				LotNumber:        lotNo,
+				ExpiryDate:       expiry,
+				QuantityReceived: req.Quantity,
+				QuantityOnHand:   req.Quantity,
+				Supplier:         req.Supplier,
+				ReceivedAt:       time.Now(),
+			}
+			if err := tx.Create(&lot).Error; err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		default:
+			if lot.RecalledAt != nil {
+				status = http.StatusConflict
+				return errors.New("lot has been recalled")
+			}
+			if (lot.ExpiryDate == nil) != (expiry == nil) || (expiry != nil && !lot.ExpiryDate.Equal(expiry.Time)) {
+				status = http.StatusConflict
+				return errors.New("lot already exists with a different expiry_date")
+			}
+			if err := tx.Model(&lot).Updates(map[string]any{
+				"quantity_received": gorm.Expr("quantity_received + ?", req.Quantity),
+				"quantity_on_hand":  gorm.Expr("quantity_on_hand + ?", req.Quantity),
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(&entity.StockMovement{
+			LotID:    lot.ID,
+			Quantity: req.Quantity,
+			Reason:   entity.StockReceive,
+			Note:     req.Supplier,
+			At:       time.Now(),
+			StaffID:  staffID,
+		}).Error
+	})
+	if err != nil {
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, gin.H{"error": "receive failed: " + err.Error()})
+		return
+	}
+	configs.DB().Preload("Item").First(&lot, lot.ID)
+	c.JSON(http.StatusCreated, gin.H{"data": lot})
+}
+
+// POST /inventory/lots/:id/adjust — ปรับยอด/ทิ้ง
+func AdjustLot(c *gin.Context) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req adjustRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	if req.Reason == entity.StockDispose && req.Quantity > 0 {
+		req.Quantity = -req.Quantity
+	}
+	var lot entity.ClinicLot
+	var status int
+	err := configs.DB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&lot, c.Param("id")).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				status = http.StatusNotFound
+				return errors.New("lot not found")
+			}
+			return err
+		}
+		if lot.QuantityOnHand+req.Quantity < 0 {
+			status = http.StatusConflict
+			return errors.New("quantity on hand cannot go below zero")
+		}
+		if err := tx.Model(&lot).Update("quantity_on_hand", gorm.Expr("quantity_on_hand + ?", req.Quantity)).Error; err != nil {
+			return err
+		}
+		return tx.Create(&entity.StockMovement{
+			LotID:    lot.ID,
+			Quantity: req.Quantity,
+			Reason:   req.Reason,
+			Note:     req.Note,
+			At:       time.Now(),
+			StaffID:  staffID,
+		}).Error
+	})
+	if err != nil {
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, gin.H{"error": "adjust failed: " + err.Error()})
+		return
+	}
+	configs.DB().Preload("Item").First(&lot, lot.ID)
+	c.JSON(http.StatusOK, gin.H{"data": lot})
+}
+
+// GET /inventory/lots/:id/movements
+func GetLotMovements(c *gin.Context) {
+	var rows []entity.StockMovement
+	if err := configs.DB().Preload("Dog").Preload("Staff").
+		Where("lot_id = ?", c.Param("id")).
+		Order("at ASC, id ASC").Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": rows})
+}
+
+/* ========== Warnings ========== */
+
+type lowStockItem struct {
+	ItemID       uint   `json:"item_id"`
+	Name         string `json:"name"`
+	Kind         string `json:"kind"`
+	Unit         string `json:"unit"`
+	UsableOnHand int    `json:"usable_on_hand"`
+	ReorderLevel int    `json:"reorder_level"`
+}
+
+type expiringLot struct {
+	entity.ClinicLot
+	Expired      bool `json:"expired"`
+	DaysToExpiry int  `json:"days_to_expiry"`
+}
+
+// GET /inventory/warnings?days=30 — lot ที่หมดอายุ/ใกล้หมดอายุ และสินค้าที่ต่ำกว่าจุดสั่งซื้อ
+func GetWarnings(c *gin.Context) {
+	days := defaultExpiryWindowDays
+	if v := c.Query("days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid days"})
+			return
+		}
+		days = n
+	}
+	db := configs.DB()
+	now := today()
+
+	var lots []entity.ClinicLot
+	if err := db.Preload("Item").
+		Where("quantity_on_hand > 0 AND recalled_at IS NULL AND expiry_date IS NOT NULL AND expiry_date <= ?",
+			entity.Date{Time: now.AddDate(0, 0, days)}).
+		Order("expiry_date ASC, id ASC").Find(&lots).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	expiring := make([]expiringLot, 0, len(lots))
+	for _, l := range lots {
+		d := int(l.ExpiryDate.Sub(now.Time).Hours() / 24)
+		expiring = append(expiring, expiringLot{ClinicLot: l, Expired: d < 0, DaysToExpiry: d})
+	}
+
+	var items []entity.ClinicItem
+	if err := db.Preload("Lots").Order("name ASC").Find(&items).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	low := []lowStockItem{}
+	for _, it := range items {
+		usable := 0
+		for _, l := range it.Lots {
+			if l.Usable(now.Time) {
+				usable += l.QuantityOnHand
+			}
+		}
+		if usable < it.ReorderLevel {
+			low = append(low, lowStockItem{
+				ItemID:       it.ID,
+				Name:         it.Name,
+				Kind:         it.Kind,
+				Unit:         it.Unit,
+				UsableOnHand: usable,
+				ReorderLevel: it.ReorderLevel,
+			})
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"expiring_lots": expiring, "low_stock": low}})
+}
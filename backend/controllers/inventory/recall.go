@@ -0,0 +1,237 @@
+package inventory
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/middlewares"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+/* ========== Recall ========== */
+
+type receipt struct {
+	At               time.Time `json:"at"`
+	Source           string    `json:"source"` // vaccine | medication
+	Quantity         int       `json:"quantity"`
+	VaccineRecordID  *uint     `json:"vaccine_record_id,omitempty"`
+	AdministrationID *uint     `json:"administration_id,omitempty"`
+}
+
+type recipient struct {
+	DogID    uint        `json:"dog_id"`
+	Dog      *entity.Dog `json:"dog"`
+	Receipts []receipt   `json:"receipts"`
+}
+
+type lotRecipients struct {
+	Lot        entity.ClinicLot `json:"lot"`
+	Recipients []recipient      `json:"recipients"`
+}
+
+// recipientsOf สุนัขทุกตัวที่ได้รับยา/วัคซีนจาก lot นี้ (ไม่นับที่ถูกคืนสต็อกแล้ว)
+// รวม VaccineRecord เดิมที่กรอกเลข lot เองก่อนมีระบบคลัง
+func recipientsOf(db *gorm.DB, lot entity.ClinicLot) ([]recipient, error) {
+	var moves []entity.StockMovement
+	if err := db.Where("lot_id = ? AND dog_id IS NOT NULL", lot.ID).
+		Where("reason IN ?", []string{entity.StockVaccine, entity.StockMedication, entity.StockReversal}).
+		Order("at ASC, id ASC").Find(&moves).Error; err != nil {
+		return nil, err
+	}
+
+	// หักรายการที่ถูกคืนสต็อก (reversal) ออก
+	type key struct{ vr, admin uint }
+	net := map[key]int{}
+	for _, m := range moves {
+		k := key{}
+		if m.VaccineRecordID != nil {
+			k.vr = *m.VaccineRecordID
+		}
+		if m.AdministrationID != nil {
+			k.admin = *m.AdministrationID
+		}
+		net[k] += m.Quantity
+	}
+
+	byDog := map[uint]*recipient{}
+	order := []uint{}
+	add := func(dogID uint, r receipt) {
+		if _, ok := byDog[dogID]; !ok {
+			byDog[dogID] = &recipient{DogID: dogID, Receipts: []receipt{}}
+			order = append(order, dogID)
+		}
+		byDog[dogID].Receipts = append(byDog[dogID].Receipts, r)
+	}
+	seenVR := map[uint]bool{}
+	for _, m := range moves {
+		if m.Reason == entity.StockReversal {
+			continue
+		}
+		k := key{}
+		if m.VaccineRecordID != nil {
+			k.vr = *m.VaccineRecordID
+			seenVR[k.vr] = true
+		}
+		if m.AdministrationID != nil {
+			k.admin = *m.AdministrationID
+		}
+		if net[k] >= 0 {
+			continue
+		}
+		add(*m.DogID, receipt{
+			At:               m.At,
+			Source:           m.Reason,
+			Quantity:         -m.Quantity,
+			VaccineRecordID:  m.VaccineRecordID,
+			AdministrationID: m.AdministrationID,
+		})
+	}
+
+	var item entity.ClinicItem
+	if err := db.First(&item, lot.ItemID).Error; err != nil {
+		return nil, err
+	}
+	if item.VaccineID != nil {
+		var legacy []struct {
+			ID         uint
+			DogID      uint
+			DateRecord time.Time
+		}
+		if err := db.Table("vaccine_records").
+			Select("vaccine_records.id, medical_records.dog_id, medical_records.date_record").
+			Joins("JOIN medical_records ON medical_records.id = vaccine_records.med_id AND medical_records.deleted_at IS NULL").
+			Where("vaccine_records.deleted_at IS NULL AND vaccine_records.vaccine_id = ?", *item.VaccineID).
+			Where("UPPER(TRIM(vaccine_records.lot_number)) = ?", strings.ToUpper(lot.LotNumber)).
+			Scan(&legacy).Error; err != nil {
+			return nil, err
+		}
+		for _, l := range legacy {
+			if seenVR[l.ID] {
+				continue
+			}
+			id := l.ID
+			add(l.DogID, receipt{At: l.DateRecord, Source: entity.StockVaccine, Quantity: 1, VaccineRecordID: &id})
+		}
+	}
+
+	if len(order) == 0 {
+		return []recipient{}, nil
+	}
+	var dogs []entity.Dog
+	if err := db.Preload("Kennel").Preload("FosterHome").Where("id IN ?", order).Find(&dogs).Error; err != nil {
+		return nil, err
+	}
+	for i := range dogs {
+		if r, ok := byDog[dogs[i].ID]; ok {
+			r.Dog = &dogs[i]
+		}
+	}
+	out := make([]recipient, 0, len(order))
+	for _, id := range order {
+		r := byDog[id]
+		sort.Slice(r.Receipts, func(i, j int) bool { return r.Receipts[i].At.Before(r.Receipts[j].At) })
+		out = append(out, *r)
+	}
+	return out, nil
+}
+
+func loadLot(c *gin.Context) (*entity.ClinicLot, bool) {
+	var lot entity.ClinicLot
+	if err := configs.DB().Preload("Item").First(&lot, c.Param("id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "lot not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return nil, false
+	}
+	return &lot, true
+}
+
+// GET /inventory/lots/:id/recipients
+func GetLotRecipients(c *gin.Context) {
+	lot, ok := loadLot(c)
+	if !ok {
+		return
+	}
+	rs, err := recipientsOf(configs.DB(), *lot)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": lotRecipients{Lot: *lot, Recipients: rs}})
+}
+
+// GET /inventory/recall?lot_number=&item_id= — ค้นหาจากเลข lot (อาจมีหลายสินค้าที่ใช้เลขเดียวกัน)
+func SearchRecall(c *gin.Context) {
+	lotNo := strings.TrimSpace(c.Query("lot_number"))
+	if lotNo == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "lot_number is required"})
+		return
+	}
+	db := configs.DB().Preload("Item").Where("UPPER(lot_number) = UPPER(?)", lotNo)
+	if v := c.Query("item_id"); v != "" {
+		db = db.Where("item_id = ?", v)
+	}
+	var lots []entity.ClinicLot
+	if err := db.Order("id ASC").Find(&lots).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	if len(lots) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "lot not found"})
+		return
+	}
+	out := make([]lotRecipients, 0, len(lots))
+	for _, l := range lots {
+		rs, err := recipientsOf(configs.DB(), l)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+			return
+		}
+		out = append(out, lotRecipients{Lot: l, Recipients: rs})
+	}
+	c.JSON(http.StatusOK, gin.H{"data": out})
+}
+
+// POST /inventory/lots/:id/recall — ประกาศเรียกคืน lot (ห้ามใช้ต่อ) แล้วคืนรายชื่อสุนัขที่ได้รับ
+func RecallLot(c *gin.Context) {
+	if middlewares.StaffID(c) == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req recallRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	lot, ok := loadLot(c)
+	if !ok {
+		return
+	}
+	if lot.RecalledAt != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "lot is already recalled"})
+		return
+	}
+	now := time.Now()
+	if err := configs.DB().Model(lot).Updates(map[string]any{
+		"recalled_at":   now,
+		"recall_reason": req.Reason,
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed: " + err.Error()})
+		return
+	}
+	lot.RecalledAt, lot.RecallReason = &now, req.Reason
+	rs, err := recipientsOf(configs.DB(), *lot)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": lotRecipients{Lot: *lot, Recipients: rs}})
+}
@@ -0,0 +1,303 @@
+package lab
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/middlewares"
+	"example.com/project-sa/utils/timeutil"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+/* ========== ใบสั่งตรวจแล็บ ========== */
+
+type orderRequest struct {
+	PanelID           uint   `json:"panel_id" binding:"required"`
+	MedicalRecordID   *uint  `json:"medical_record_id"`
+	OrderedByID       *uint  `json:"ordered_by_id"` // ว่าง = ผู้บันทึก
+	Location          string `json:"location"`      // in_house (ค่าเริ่มต้น) | external
+	ExternalLab       string `json:"external_lab"`
+	SampleCollectedAt string `json:"sample_collected_at"` // RFC3339 หรือ "YYYY-MM-DD HH:MM" (เวลาไทย)
+	Notes             string `json:"notes"`
+}
+
+type sampleRequest struct {
+	CollectedAt string `json:"collected_at"` // ว่าง = ตอนนี้
+}
+
+type cancelRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+func parseTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.UTC(), nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02T15:04"} {
+		if t, err := time.ParseInLocation(layout, s, timeutil.TZBangkok()); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, errors.New("invalid time (RFC3339 or YYYY-MM-DD HH:MM)")
+}
+
+func preloadOrder(db *gorm.DB) *gorm.DB {
+	return db.Preload("Dog").Preload("Panel").Preload("OrderedBy").Preload("ResultedBy").
+		Preload("Results", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") })
+}
+
+// GET /lab-panels
+func GetPanels(c *gin.Context) {
+	var rows []entity.LabPanel
+	if err := configs.DB().Preload("Analytes", func(db *gorm.DB) *gorm.DB { return db.Order("sort_order ASC") }).
+		Order("code ASC").Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": rows})
+}
+
+// POST /dogs/:id/lab-orders
+func CreateOrder(c *gin.Context) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req orderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	if req.Location == "" {
+		req.Location = entity.LabInHouse
+	}
+	if req.Location != entity.LabInHouse && req.Location != entity.LabExternal {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "location must be in_house or external"})
+		return
+	}
+	if req.Location == entity.LabExternal && strings.TrimSpace(req.ExternalLab) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "external_lab is required for external orders"})
+		return
+	}
+	var collectedAt *time.Time
+	if req.SampleCollectedAt != "" {
+		t, err := parseTime(req.SampleCollectedAt)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sample_collected_at (RFC3339 or YYYY-MM-DD HH:MM)"})
+			return
+		}
+		if t.After(time.Now()) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "sample_collected_at is in the future"})
+			return
+		}
+		collectedAt = &t
+	}
+	orderedBy := *staffID
+	if req.OrderedByID != nil {
+		orderedBy = *req.OrderedByID
+	}
+
+	var o entity.LabOrder
+	var status int
+	err := configs.DB().Transaction(func(tx *gorm.DB) error {
+		var dog entity.Dog
+		if err := tx.First(&dog, c.Param("id")).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				status = http.StatusNotFound
+				return errors.New("dog not found")
+			}
+			return err
+		}
+		var panel entity.LabPanel
+		if err := tx.First(&panel, req.PanelID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				status = http.StatusBadRequest
+				return errors.New("invalid panel_id")
+			}
+			return err
+		}
+		var staff entity.Staff
+		if err := tx.First(&staff, orderedBy).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				status = http.StatusBadRequest
+				return errors.New("invalid ordered_by_id")
+			}
+			return err
+		}
+		if req.MedicalRecordID != nil {
+			var mr entity.MedicalRecord
+			if err := tx.Select("id", "dog_id").First(&mr, *req.MedicalRecordID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					status = http.StatusBadRequest
+					return errors.New("invalid medical_record_id")
+				}
+				return err
+			}
+			if mr.DogID != dog.ID {
+				status = http.StatusBadRequest
+				return errors.New("medical record belongs to another dog")
+			}
+		}
+		o = entity.LabOrder{
+			DogID:             dog.ID,
+			MedicalRecordID:   req.MedicalRecordID,
+			PanelID:           panel.ID,
+			OrderedByID:       orderedBy,
+			OrderedAt:         time.Now(),
+			Location:          req.Location,
+			ExternalLab:       strings.TrimSpace(req.ExternalLab),
+			Notes:             req.Notes,
+			SampleCollectedAt: collectedAt,
+			Status:            entity.LabOrdered,
+		}
+		if collectedAt != nil {
+			o.Status = entity.LabCollected
+		}
+		return tx.Create(&o).Error
+	})
+	if err != nil {
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, gin.H{"error": "create failed: " + err.Error()})
+		return
+	}
+	preloadOrder(configs.DB()).First(&o, o.ID)
+	c.JSON(http.StatusCreated, gin.H{"data": o})
+}
+
+// GET /lab-orders?status=&panel_id=&dog_id=&abnormal=true
+func GetOrders(c *gin.Context) {
+	db := preloadOrder(configs.DB())
+	if v := c.Query("status"); v != "" {
+		db = db.Where("status = ?", v)
+	}
+	if v := c.Query("panel_id"); v != "" {
+		db = db.Where("panel_id = ?", v)
+	}
+	if v := c.Query("dog_id"); v != "" {
+		db = db.Where("dog_id = ?", v)
+	}
+	if c.Query("abnormal") == "true" {
+		db = db.Where("abnormal = ?", true)
+	}
+	var rows []entity.LabOrder
+	if err := db.Order("ordered_at DESC, id DESC").Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": rows})
+}
+
+// GET /dogs/:id/lab-orders
+func GetDogOrders(c *gin.Context) {
+	var rows []entity.LabOrder
+	if err := preloadOrder(configs.DB()).Where("dog_id = ?", c.Param("id")).
+		Order("ordered_at DESC, id DESC").Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": rows})
+}
+
+func loadOrder(c *gin.Context) (*entity.LabOrder, bool) {
+	var o entity.LabOrder
+	if err := preloadOrder(configs.DB()).Preload("MedicalRecord").First(&o, c.Param("id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "lab order not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return nil, false
+	}
+	return &o, true
+}
+
+// GET /lab-orders/:id
+func GetOrder(c *gin.Context) {
+	o, ok := loadOrder(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": o})
+}
+
+// POST /lab-orders/:id/sample — บันทึกเวลาเก็บตัวอย่าง
+func CollectSample(c *gin.Context) {
+	if middlewares.StaffID(c) == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req sampleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	at := time.Now().UTC()
+	if req.CollectedAt != "" {
+		t, err := parseTime(req.CollectedAt)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid collected_at (RFC3339 or YYYY-MM-DD HH:MM)"})
+			return
+		}
+		if t.After(time.Now()) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "collected_at is in the future"})
+			return
+		}
+		at = t
+	}
+	o, ok := loadOrder(c)
+	if !ok {
+		return
+	}
+	if o.Status != entity.LabOrdered {
+		c.JSON(http.StatusConflict, gin.H{"error": "lab order is " + o.Status})
+		return
+	}
+	if err := configs.DB().Model(o).Updates(map[string]any{
+		"status":              entity.LabCollected,
+		"sample_collected_at": at,
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed: " + err.Error()})
+		return
+	}
+	preloadOrder(configs.DB()).First(o, o.ID)
+	c.JSON(http.StatusOK, gin.H{"data": o})
+}
+
+// POST /lab-orders/:id/cancel { reason }
+func CancelOrder(c *gin.Context) {
+	if middlewares.StaffID(c) == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req cancelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	o, ok := loadOrder(c)
+	if !ok {
+		return
+	}
+	if o.Status != entity.LabOrdered && o.Status != entity.LabCollected {
+		c.JSON(http.StatusConflict, gin.H{"error": "lab order is " + o.Status})
+		return
+	}
+	if err := configs.DB().Model(o).Updates(map[string]any{
+		"status":        entity.LabCancelled,
+		"cancelled_at":  time.Now(),
+		"cancel_reason": strings.TrimSpace(req.Reason),
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed: " + err.Error()})
+		return
+	}
+	preloadOrder(configs.DB()).First(o, o.ID)
+	c.JSON(http.StatusOK, gin.H{"data": o})
+}
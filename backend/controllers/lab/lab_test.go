@@ -0,0 +1,96 @@
+package lab
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/migrations"
+	"example.com/project-sa/seeds"
+	"example.com/project-sa/utils/testutil"
+	"github.com/gin-gonic/gin"
+)
+
+func TestMain(m *testing.M) { testutil.Main(m, migrations.AutoMigrate, seeds.SeedAll) }
+
+func TestLabResultsFlagAndNotifyOrderingVet(t *testing.T) {
+	db := configs.DB()
+	var panel entity.LabPanel
+	if err := db.Preload("Analytes").Where("code = ?", "CBC").First(&panel).Error; err != nil {
+		t.Fatalf("seeded panel: %v", err)
+	}
+	analyte := map[string]uint{}
+	for _, a := range panel.Analytes {
+		analyte[a.Code] = a.ID
+	}
+	record := entity.MedicalRecord{DogID: 3, StaffID: 2, Symptoms: "lethargy"}
+	other := entity.MedicalRecord{DogID: 4, StaffID: 2, Symptoms: "checkup"}
+	for _, mr := range []*entity.MedicalRecord{&record, &other} {
+		if err := db.Create(mr).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// สัตวแพทย์ 2 สั่งตรวจ เจ้าหน้าที่ 1 บันทึกผล
+	vet, tech := testutil.Router(2), testutil.Router(1)
+	vet.POST("/dogs/:id/lab-orders", CreateOrder)
+	vet.GET("/staff-notifications/my", GetMyNotifications)
+	tech.POST("/lab-orders/:id/results", EnterResults)
+	tech.GET("/lab-orders/:id/results/history", GetResultHistory)
+
+	if code, _ := testutil.Do(t, vet, http.MethodPost, "/dogs/3/lab-orders", gin.H{"panel_id": panel.ID, "medical_record_id": other.ID}); code != http.StatusBadRequest {
+		t.Errorf("order linked to another dog's record = %d, want %d", code, http.StatusBadRequest)
+	}
+	data := testutil.MustDo(t, vet, http.MethodPost, "/dogs/3/lab-orders", gin.H{"panel_id": panel.ID, "medical_record_id": record.ID})
+	results := fmt.Sprintf("/lab-orders/%d/results", testutil.ID(data))
+
+	if code, out := testutil.Do(t, tech, http.MethodPost, results, gin.H{"results": []gin.H{{"analyte_id": 99999, "value": 1}}}); code != http.StatusBadRequest || out["fields"] == nil {
+		t.Errorf("analyte outside the panel = %d %v, want 400 with field errors", code, out)
+	}
+
+	data = testutil.MustDo(t, tech, http.MethodPost, results, gin.H{"results": []gin.H{
+		{"analyte_id": analyte["WBC"], "value": 25},
+		{"analyte_id": analyte["HCT"], "value": 45},
+	}})
+	if data["status"] != entity.LabResulted || data["abnormal"] != true || data["medical_record_id"] != float64(record.ID) {
+		t.Errorf("resulted order = %v, want resulted, abnormal, linked to record %d", data, record.ID)
+	}
+	flags := map[string]string{}
+	var rows []entity.LabResult
+	db.Where("lab_order_id = ?", testutil.ID(data)).Find(&rows)
+	for _, r := range rows {
+		flags[r.Name] = r.Flag
+	}
+	if flags["White blood cells"] != entity.LabFlagHigh || flags["Hematocrit"] != entity.LabFlagNormal {
+		t.Errorf("flags = %v, want WBC high and HCT normal", flags)
+	}
+
+	_, out := testutil.Do(t, vet, http.MethodGet, "/staff-notifications/my", nil)
+	notes, _ := out["data"].([]any)
+	if len(notes) != 1 || notes[0].(map[string]any)["kind"] != entity.StaffNoticeLabResultAbnormal {
+		t.Fatalf("vet notifications = %v, want one abnormal lab result", notes)
+	}
+
+	// แก้ผล: ผลเดิมยังอยู่ในประวัติ และผลใหม่ชี้กลับไปหาค่าเดิม
+	testutil.MustDo(t, tech, http.MethodPost, results, gin.H{"results": []gin.H{
+		{"analyte_id": analyte["WBC"], "value": 12},
+		{"analyte_id": analyte["HCT"], "value": 45},
+	}})
+	_, out = testutil.Do(t, tech, http.MethodGet, results+"/history", nil)
+	history, _ := out["data"].([]any)
+	corrected := 0
+	for _, h := range history {
+		if h.(map[string]any)["corrected_from_id"] != nil {
+			corrected++
+		}
+	}
+	if len(history) != 4 || corrected != 2 {
+		t.Errorf("result history = %d rows with %d corrections, want 4 and 2", len(history), corrected)
+	}
+	_, out = testutil.Do(t, vet, http.MethodGet, "/staff-notifications/my", nil)
+	if notes, _ := out["data"].([]any); len(notes) != 2 || notes[0].(map[string]any)["kind"] != entity.StaffNoticeLabResult {
+		t.Errorf("after correction notifications = %v, want a second, normal lab result notice", notes)
+	}
+}
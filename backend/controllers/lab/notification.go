@@ -0,0 +1,59 @@
+package lab
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/middlewares"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GET /staff-notifications/my?unread=true
+func GetMyNotifications(c *gin.Context) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	db := configs.DB().Preload("Dog").Preload("LabOrder.Panel").Where("staff_id = ?", *staffID)
+	if c.Query("unread") == "true" {
+		db = db.Where("read_at IS NULL")
+	}
+	var rows []entity.StaffNotification
+	if err := db.Order("id DESC").Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": rows})
+}
+
+// POST /staff-notifications/:id/read
+func MarkNotificationRead(c *gin.Context) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var n entity.StaffNotification
+	if err := configs.DB().Where("id = ? AND staff_id = ?", c.Param("id"), *staffID).First(&n).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if n.ReadAt == nil {
+		now := time.Now()
+		if err := configs.DB().Model(&n).Update("read_at", now).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		n.ReadAt = &now
+	}
+	c.JSON(http.StatusOK, gin.H{"data": n})
+}
@@ -0,0 +1,283 @@
+package lab
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"example.com/project-sa/configs"
+	health_records "example.com/project-sa/controllers/health_record"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/middlewares"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+/* ========== บันทึกผลแล็บ ========== */
+
+type resultItem struct {
+	AnalyteID  *uint    `json:"analyte_id"` // ว่าง = ค่านอกชุด ต้องระบุ name
+	Name       string   `json:"name"`
+	Unit       string   `json:"unit"`
+	Value      *float64 `json:"value"`
+	ValueText  string   `json:"value_text"`
+	RefLow     *float64 `json:"ref_low"` // แล็บภายนอกอาจใช้ค่าอ้างอิงของตัวเอง
+	RefHigh    *float64 `json:"ref_high"`
+	NormalText string   `json:"normal_text"`
+	Comment    string   `json:"comment"`
+}
+
+type resultsRequest struct {
+	Results     []resultItem `json:"results" binding:"required,min=1"`
+	ExternalRef *string      `json:"external_ref"`
+	Notes       *string      `json:"notes"`
+}
+
+// flagOf เทียบค่ากับค่าอ้างอิง ("" = ไม่มีค่าอ้างอิงให้เทียบ)
+func flagOf(r entity.LabResult) string {
+	if r.Value != nil && (r.RefLow != nil || r.RefHigh != nil) {
+		switch {
+		case r.RefLow != nil && *r.Value < *r.RefLow:
+			return entity.LabFlagLow
+		case r.RefHigh != nil && *r.Value > *r.RefHigh:
+			return entity.LabFlagHigh
+		}
+		return entity.LabFlagNormal
+	}
+	if r.NormalText != "" && r.ValueText != "" {
+		if strings.EqualFold(strings.TrimSpace(r.ValueText), r.NormalText) {
+			return entity.LabFlagNormal
+		}
+		return entity.LabFlagAbnormal
+	}
+	return ""
+}
+
+// buildResults ตรวจรายการผลและเติมชื่อ/หน่วย/ค่าอ้างอิงจากชุดตรวจ
+func buildResults(items []resultItem, analytes []entity.LabAnalyte) ([]entity.LabResult, health_records.FieldErrors) {
+	byID := map[uint]entity.LabAnalyte{}
+	for _, a := range analytes {
+		byID[a.ID] = a
+	}
+	fields := health_records.FieldErrors{}
+	seen := map[uint]bool{}
+	out := make([]entity.LabResult, 0, len(items))
+	for i, it := range items {
+		key := fmt.Sprintf("results[%d]", i)
+		r := entity.LabResult{
+			Name:       strings.TrimSpace(it.Name),
+			Unit:       it.Unit,
+			Value:      it.Value,
+			ValueText:  strings.TrimSpace(it.ValueText),
+			RefLow:     it.RefLow,
+			RefHigh:    it.RefHigh,
+			NormalText: it.NormalText,
+			Comment:    it.Comment,
+		}
+		if it.AnalyteID != nil {
+			a, ok := byID[*it.AnalyteID]
+			if !ok {
+				fields[key+".analyte_id"] = "is not part of this panel"
+				continue
+			}
+			if seen[a.ID] {
+				fields[key+".analyte_id"] = "is listed more than once"
+				continue
+			}
+			seen[a.ID] = true
+			r.AnalyteID = &a.ID
+			if r.Name == "" {
+				r.Name = a.Name
+			}
+			if r.Unit == "" {
+				r.Unit = a.Unit
+			}
+			if r.RefLow == nil && r.RefHigh == nil {
+				r.RefLow, r.RefHigh = a.RefLow, a.RefHigh
+			}
+			if r.NormalText == "" {
+				r.NormalText = a.NormalText
+			}
+			if a.Qualitative() && r.ValueText == "" {
+				fields[key+".value_text"] = "is required for " + a.Name
+				continue
+			}
+			if !a.Qualitative() && r.Value == nil {
+				fields[key+".value"] = "is required for " + a.Name
+				continue
+			}
+		} else {
+			if r.Name == "" {
+				fields[key+".name"] = "is required when analyte_id is empty"
+				continue
+			}
+			if r.Value == nil && r.ValueText == "" {
+				fields[key+".value"] = "value or value_text is required"
+				continue
+			}
+		}
+		if r.RefLow != nil && r.RefHigh != nil && *r.RefLow > *r.RefHigh {
+			fields[key+".ref_high"] = "must not be less than ref_low"
+			continue
+		}
+		r.Flag = flagOf(r)
+		out = append(out, r)
+	}
+	if len(fields) > 0 {
+		return nil, fields
+	}
+	return out, nil
+}
+
+// resultKey จับคู่ค่าผลชุดใหม่กับชุดเดิม: ค่าในชุดตรวจใช้ analyte_id ค่านอกชุดใช้ชื่อ
+func resultKey(r entity.LabResult) string {
+	if r.AnalyteID != nil {
+		return fmt.Sprintf("analyte:%d", *r.AnalyteID)
+	}
+	return "name:" + strings.ToLower(r.Name)
+}
+
+// POST /lab-orders/:id/results — บันทึกผล (ส่งซ้ำ = แก้ผลทั้งชุด ผลเดิมยังดูได้ที่ /results/history) และแจ้งสัตวแพทย์ผู้สั่งตรวจ
+func EnterResults(c *gin.Context) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req resultsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+
+	var o entity.LabOrder
+	var status int
+	err := configs.DB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Preload("Dog").Preload("Panel.Analytes").First(&o, c.Param("id")).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				status = http.StatusNotFound
+				return errors.New("lab order not found")
+			}
+			return err
+		}
+		if o.Status == entity.LabCancelled {
+			status = http.StatusConflict
+			return errors.New("lab order is cancelled")
+		}
+		results, fields := buildResults(req.Results, o.Panel.Analytes)
+		if fields != nil {
+			status = http.StatusBadRequest
+			return fields
+		}
+		corrected := o.Status == entity.LabResulted
+
+		// เก็บผลชุดเดิมไว้เป็นประวัติ (soft delete) ผลใหม่ที่เป็นค่าเดียวกันชี้กลับไปหาค่าเดิม
+		var previous []entity.LabResult
+		if err := tx.Where("lab_order_id = ?", o.ID).Find(&previous).Error; err != nil {
+			return err
+		}
+		prevByKey := map[string]uint{}
+		for _, p := range previous {
+			prevByKey[resultKey(p)] = p.ID
+		}
+		if len(previous) > 0 {
+			if err := tx.Where("lab_order_id = ?", o.ID).Delete(&entity.LabResult{}).Error; err != nil {
+				return err
+			}
+		}
+		abnormal := 0
+		for i := range results {
+			results[i].LabOrderID = o.ID
+			results[i].EnteredByID = staffID
+			if id, ok := prevByKey[resultKey(results[i])]; ok {
+				results[i].CorrectedFromID = &id
+			}
+			if f := results[i].Flag; f != "" && f != entity.LabFlagNormal {
+				abnormal++
+			}
+			if err := tx.Create(&results[i]).Error; err != nil {
+				return err
+			}
+		}
+		now := time.Now()
+		updates := map[string]any{
+			"status":         entity.LabResulted,
+			"resulted_at":    now,
+			"resulted_by_id": *staffID,
+			"abnormal":       abnormal > 0,
+		}
+		if o.SampleCollectedAt == nil {
+			updates["sample_collected_at"] = now
+		}
+		if req.ExternalRef != nil {
+			updates["external_ref"] = strings.TrimSpace(*req.ExternalRef)
+		}
+		if req.Notes != nil {
+			updates["notes"] = *req.Notes
+		}
+		if err := tx.Model(&o).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		// แจ้งผู้สั่งตรวจ (ถ้าเป็นคนบันทึกผลเองก็ไม่ต้องแจ้ง)
+		if o.OrderedByID == *staffID {
+			return nil
+		}
+		dogName := ""
+		if o.Dog != nil {
+			dogName = o.Dog.Name
+		}
+		kind := entity.StaffNoticeLabResult
+		msg := fmt.Sprintf("Lab results for %s (%s) are ready", dogName, o.Panel.Name)
+		if corrected {
+			msg = fmt.Sprintf("Lab results for %s (%s) were corrected", dogName, o.Panel.Name)
+		}
+		if abnormal > 0 {
+			kind = entity.StaffNoticeLabResultAbnormal
+			msg += fmt.Sprintf(": %d abnormal value(s)", abnormal)
+		}
+		return tx.Create(&entity.StaffNotification{
+			StaffID:    o.OrderedByID,
+			DogID:      &o.DogID,
+			LabOrderID: &o.ID,
+			Kind:       kind,
+			Message:    msg,
+		}).Error
+	})
+	if err != nil {
+		var fields health_records.FieldErrors
+		if errors.As(err, &fields) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid lab results", "fields": fields})
+			return
+		}
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, gin.H{"error": "save failed: " + err.Error()})
+		return
+	}
+	preloadOrder(configs.DB()).Preload("MedicalRecord").First(&o, o.ID)
+	c.JSON(http.StatusOK, gin.H{"data": o})
+}
+
+// GET /lab-orders/:id/results/history — ผลทุกชุดรวมที่ถูกแก้ไปแล้ว (deleted_at ไม่ว่าง = ถูกแทนที่)
+func GetResultHistory(c *gin.Context) {
+	var o entity.LabOrder
+	if err := configs.DB().Select("id").First(&o, c.Param("id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "lab order not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	var rows []entity.LabResult
+	if err := configs.DB().Unscoped().Preload("EnteredBy").Where("lab_order_id = ?", o.ID).
+		Order("id ASC").Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": rows})
+}
@@ -0,0 +1,367 @@
+package lostfound
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/middlewares"
+	"example.com/project-sa/utils/upload"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+/* ========== DTOs ========== */
+
+type ReportCreateRequest struct {
+	Type          string   `json:"type" binding:"required,oneof=lost found"`
+	ReporterName  string   `json:"reporter_name" binding:"required"`
+	ReporterPhone string   `json:"reporter_phone" binding:"required"`
+	ReporterEmail string   `json:"reporter_email"`
+	DogName       string   `json:"dog_name"`
+	BreedID       *uint    `json:"breed_id"`
+	AnimalSexID   *uint    `json:"animal_sex_id"`
+	AnimalSizeID  *uint    `json:"animal_size_id"`
+	Color         string   `json:"color"`
+	EventDate     string   `json:"event_date" binding:"required"` // "YYYY-MM-DD"
+	Area          string   `json:"area" binding:"required"`
+	Location      string   `json:"location"`
+	Latitude      *float64 `json:"latitude"`
+	Longitude     *float64 `json:"longitude"`
+	Description   string   `json:"description"`
+	PhotoURLs     []string `json:"photo_urls"`
+}
+
+type reviewRequest struct {
+	Note string `json:"note"`
+}
+
+type statusRequest struct {
+	Status string `json:"status" binding:"required,oneof=open closed"`
+}
+
+const maxReportPhotos = 5
+
+/* ========== Helpers ========== */
+
+func preloadReport(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Breed").
+		Preload("AnimalSex").
+		Preload("AnimalSize").
+		Preload("Photos")
+}
+
+// ลิงก์รูปต้องมาจาก POST /lost-found/photos เท่านั้น
+func validPhotoURL(u string) bool {
+	return strings.HasPrefix(u, "/static/uploads/lostfound/")
+}
+
+func lookupExists(db *gorm.DB, model any, id *uint) (bool, error) {
+	if id == nil {
+		return true, nil
+	}
+	var n int64
+	if err := db.Model(model).Where("id = ?", *id).Count(&n).Error; err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+/* ========== Public ========== */
+
+// POST /lost-found/photos
+func UploadReportPhoto(c *gin.Context) {
+	url, err := upload.SaveImage(c, "lostfound", "lf")
+	if err != nil {
+		if errors.Is(err, upload.ErrNoFile) || errors.Is(err, upload.ErrUnsupportedType) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"url": url})
+}
+
+// POST /lost-found/reports
+func CreateReport(c *gin.Context) {
+	var req ReportCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	event, err := time.Parse("2006-01-02", req.EventDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event_date (YYYY-MM-DD)"})
+		return
+	}
+	if event.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "event_date cannot be in the future"})
+		return
+	}
+	if len(req.PhotoURLs) > maxReportPhotos {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "too many photos"})
+		return
+	}
+	for _, u := range req.PhotoURLs {
+		if !validPhotoURL(u) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid photo url: " + u})
+			return
+		}
+	}
+
+	db := configs.DB()
+	for _, chk := range []struct {
+		model any
+		id    *uint
+		field string
+	}{
+		{&entity.Breed{}, req.BreedID, "breed_id"},
+		{&entity.AnimalSex{}, req.AnimalSexID, "animal_sex_id"},
+		{&entity.AnimalSize{}, req.AnimalSizeID, "animal_size_id"},
+	} {
+		ok, err := lookupExists(db, chk.model, chk.id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+			return
+		}
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + chk.field})
+			return
+		}
+	}
+
+	report := entity.LostFoundReport{
+		Type:          req.Type,
+		Status:        entity.LostFoundOpen,
+		ReporterName:  strings.TrimSpace(req.ReporterName),
+		ReporterPhone: strings.TrimSpace(req.ReporterPhone),
+		ReporterEmail: strings.TrimSpace(req.ReporterEmail),
+		DogName:       req.DogName,
+		BreedID:       req.BreedID,
+		AnimalSexID:   req.AnimalSexID,
+		AnimalSizeID:  req.AnimalSizeID,
+		Color:         req.Color,
+		EventDate:     req.EventDate,
+		Area:          strings.TrimSpace(req.Area),
+		Location:      req.Location,
+		Latitude:      req.Latitude,
+		Longitude:     req.Longitude,
+		Description:   req.Description,
+	}
+	for _, u := range req.PhotoURLs {
+		report.Photos = append(report.Photos, entity.LostFoundPhoto{URL: u})
+	}
+
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&report).Error; err != nil {
+			return err
+		}
+		// จับคู่ทันทีกับสุนัขที่อยู่ในศูนย์ตอนนี้
+		_, err := matchReport(tx, &report)
+		return err
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "create failed: " + err.Error()})
+		return
+	}
+
+	// ผู้แจ้งเห็นแค่ประกาศของตัวเอง ผลจับคู่ให้เจ้าหน้าที่ตรวจก่อน
+	c.JSON(http.StatusCreated, gin.H{"data": report})
+}
+
+/* ========== Staff ========== */
+
+// GET /lost-found/reports?type=&status=
+func GetReports(c *gin.Context) {
+	if middlewares.StaffID(c) == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	db := preloadReport(configs.DB())
+	if t := c.Query("type"); t != "" {
+		db = db.Where("type = ?", t)
+	}
+	if s := c.Query("status"); s != "" {
+		db = db.Where("status = ?", s)
+	}
+	var reports []entity.LostFoundReport
+	if err := db.Order("id DESC").Find(&reports).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": reports})
+}
+
+// GET /lost-found/reports/:id
+func GetReport(c *gin.Context) {
+	if middlewares.StaffID(c) == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var report entity.LostFoundReport
+	if err := preloadReport(configs.DB()).
+		Preload("Matches", func(db *gorm.DB) *gorm.DB { return db.Order("score DESC, id ASC") }).
+		Preload("Matches.Dog").
+		Preload("Matches.Dog.Breed").
+		Preload("Matches.Dog.Kennel").
+		Preload("Matches.ReviewedBy").
+		First(&report, c.Param("id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "report not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
+
+// PUT /lost-found/reports/:id/status
+func UpdateReportStatus(c *gin.Context) {
+	if middlewares.StaffID(c) == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req statusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	var report entity.LostFoundReport
+	if err := configs.DB().First(&report, c.Param("id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "report not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	if report.Status == entity.LostFoundMatched {
+		c.JSON(http.StatusConflict, gin.H{"error": "report already matched"})
+		return
+	}
+	if err := configs.DB().Model(&report).Update("status", req.Status).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed: " + err.Error()})
+		return
+	}
+	report.Status = req.Status
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
+
+// POST /lost-found/match — สั่งรันจับคู่ทุกประกาศที่เปิดอยู่ทันที
+func RunMatchingNow(c *gin.Context) {
+	if middlewares.StaffID(c) == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	n, err := RunMatching(configs.DB())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "matching failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"created": n})
+}
+
+// GET /lost-found/matches?status=candidate
+func GetMatches(c *gin.Context) {
+	if middlewares.StaffID(c) == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	status := c.DefaultQuery("status", entity.MatchCandidate)
+	var matches []entity.LostFoundMatch
+	if err := configs.DB().
+		Preload("Report").
+		Preload("Report.Photos").
+		Preload("Dog").
+		Preload("Dog.Breed").
+		Preload("Dog.Kennel").
+		Where("status = ?", status).
+		Order("score DESC, id ASC").
+		Find(&matches).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": matches})
+}
+
+// POST /lost-found/matches/:id/confirm
+func ConfirmMatch(c *gin.Context) {
+	reviewMatch(c, entity.MatchConfirmed)
+}
+
+// POST /lost-found/matches/:id/reject
+func RejectMatch(c *gin.Context) {
+	reviewMatch(c, entity.MatchRejected)
+}
+
+func reviewMatch(c *gin.Context, decision string) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req reviewRequest
+	// body ไม่บังคับ
+	_ = c.ShouldBindJSON(&req)
+
+	var match entity.LostFoundMatch
+	var status int
+	err := configs.DB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&match, c.Param("id")).Error; err != nil {
+			return err
+		}
+		if match.Status != entity.MatchCandidate {
+			status = http.StatusConflict
+			return errors.New("match already reviewed")
+		}
+		now := time.Now()
+		if err := tx.Model(&match).Updates(map[string]any{
+			"status":         decision,
+			"reviewed_by_id": *staffID,
+			"reviewed_at":    now,
+			"note":           req.Note,
+		}).Error; err != nil {
+			return err
+		}
+		if decision != entity.MatchConfirmed {
+			return nil
+		}
+		// ยืนยันแล้ว -> ปิดประกาศ และตัด candidate อื่นของประกาศนี้ทิ้ง
+		if err := tx.Model(&entity.LostFoundReport{}).
+			Where("id = ?", match.ReportID).
+			Update("status", entity.LostFoundMatched).Error; err != nil {
+			return err
+		}
+		return tx.Model(&entity.LostFoundMatch{}).
+			Where("report_id = ? AND id <> ? AND status = ?", match.ReportID, match.ID, entity.MatchCandidate).
+			Updates(map[string]any{
+				"status":         entity.MatchRejected,
+				"reviewed_by_id": *staffID,
+				"reviewed_at":    now,
+				"note":           "another match confirmed",
+			}).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "match not found"})
+			return
+		}
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, gin.H{"error": "review failed: " + err.Error()})
+		return
+	}
+
+	if err := configs.DB().Preload("Report").Preload("Dog").Preload("ReviewedBy").
+		First(&match, match.ID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": match})
+}
@@ -0,0 +1,245 @@
+package lostfound
+
+import (
+	"log"
+	"strings"
+	"time"
+	"unicode"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+/* ========== Matching job: ประกาศ หาย/พบ <-> สุนัขที่รับเข้า ========== */
+
+// น้ำหนักคะแนนแต่ละเกณฑ์ (รวม 100)
+const (
+	weightBreed = 25
+	weightSex   = 15
+	weightSize  = 15
+	weightColor = 15
+	weightDate  = 15
+	weightArea  = 15
+
+	// คะแนนขั้นต่ำที่จะสร้าง candidate ให้เจ้าหน้าที่ตรวจ
+	minMatchScore = 50
+
+	// ห่างจากวันหาย/พบเกินนี้ไม่นับว่าเป็นตัวเดียวกัน
+	maxDateGapDays = 90
+)
+
+type scoredDog struct {
+	DogID   uint
+	Score   int
+	Reasons []string
+}
+
+func tokens(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	})
+}
+
+// สัดส่วนคำที่ตรงกัน (เทียบกับฝั่งที่สั้นกว่า) — ภาษาไทยไม่เว้นวรรค จึงเช็ค contains ด้วย
+func textOverlap(a, b string) float64 {
+	a, b = strings.ToLower(strings.TrimSpace(a)), strings.ToLower(strings.TrimSpace(b))
+	if a == "" || b == "" {
+		return 0
+	}
+	if strings.Contains(a, b) || strings.Contains(b, a) {
+		return 1
+	}
+	ta, tb := tokens(a), tokens(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+	set := map[string]struct{}{}
+	for _, t := range ta {
+		set[t] = struct{}{}
+	}
+	shared := 0
+	for _, t := range tb {
+		if _, ok := set[t]; ok {
+			shared++
+			delete(set, t)
+		}
+	}
+	n := len(ta)
+	if len(tb) < n {
+		n = len(tb)
+	}
+	return float64(shared) / float64(n)
+}
+
+// วันรับเข้าของสุนัข (ไม่มี intake_date ใช้วันที่สร้างเรคคอร์ด)
+func intakeDay(d entity.Dog) time.Time {
+	if t, err := time.Parse("2006-01-02", d.IntakeDate); err == nil {
+		return t
+	}
+	y, m, day := d.CreatedAt.Date()
+	return time.Date(y, m, day, 0, 0, 0, 0, time.UTC)
+}
+
+// คืน ok=false เมื่อขัดกันชัดเจน (เพศต่างกัน / วันห่างเกินไป)
+func scoreDog(r entity.LostFoundReport, d entity.Dog) (scoredDog, bool) {
+	out := scoredDog{DogID: d.ID}
+
+	if r.AnimalSexID != nil && d.AnimalSexID != 0 {
+		if *r.AnimalSexID != d.AnimalSexID {
+			return out, false
+		}
+		out.Score += weightSex
+		out.Reasons = append(out.Reasons, "sex")
+	}
+
+	if event, err := time.Parse("2006-01-02", r.EventDate); err == nil {
+		gap := int(intakeDay(d).Sub(event).Hours() / 24)
+		// หมาหายต้องถูกรับเข้าหลังวันที่หาย (เผื่อแจ้งวันคลาดเคลื่อน 3 วัน)
+		if r.Type == entity.LostFoundLost && gap < -3 {
+			return out, false
+		}
+		if gap < 0 {
+			gap = -gap
+		}
+		switch {
+		case gap > maxDateGapDays:
+			return out, false
+		case gap <= 7:
+			out.Score += weightDate
+			out.Reasons = append(out.Reasons, "date")
+		case gap <= 30:
+			out.Score += weightDate * 2 / 3
+			out.Reasons = append(out.Reasons, "date")
+		case gap <= 60:
+			out.Score += weightDate / 3
+		}
+	}
+
+	if r.BreedID != nil && *r.BreedID == d.BreedID {
+		out.Score += weightBreed
+		out.Reasons = append(out.Reasons, "breed")
+	}
+	if r.AnimalSizeID != nil && *r.AnimalSizeID == d.AnimalSizeID {
+		out.Score += weightSize
+		out.Reasons = append(out.Reasons, "size")
+	}
+	if o := textOverlap(r.Color, d.Color); o > 0 {
+		out.Score += int(float64(weightColor)*o + 0.5)
+		out.Reasons = append(out.Reasons, "color")
+	}
+	if o := textOverlap(r.Area+" "+r.Location, d.IntakeArea); o > 0 {
+		// area ตรงทั้งคำได้เต็ม, แค่บางส่วนได้ครึ่ง
+		if textOverlap(r.Area, d.IntakeArea) == 1 {
+			out.Score += weightArea
+		} else {
+			out.Score += weightArea / 2
+		}
+		out.Reasons = append(out.Reasons, "area")
+	}
+	return out, true
+}
+
+// matchReport คำนวณคู่ใหม่ของประกาศ 1 รายการ
+// candidate เดิมจะถูกอัปเดตคะแนน หรือลบทิ้งเมื่อคะแนนไม่ถึงเกณฑ์แล้ว/สุนัขออกจากความดูแล
+// ส่วนคู่ที่เจ้าหน้าที่ตัดสินแล้วไม่แตะ
+func matchReport(tx *gorm.DB, r *entity.LostFoundReport) (int, error) {
+	var dogs []entity.Dog
+	if err := tx.Where("is_adopted = ? AND status NOT IN ?", false, []string{entity.DogStatusTransferred, entity.DogStatusDeceased}).Find(&dogs).Error; err != nil {
+		return 0, err
+	}
+
+	var existing []entity.LostFoundMatch
+	if err := tx.Where("report_id = ?", r.ID).Find(&existing).Error; err != nil {
+		return 0, err
+	}
+	byDog := map[uint]entity.LostFoundMatch{}
+	for _, m := range existing {
+		byDog[m.DogID] = m
+	}
+
+	created := 0
+	kept := map[uint]bool{}
+	for _, d := range dogs {
+		s, ok := scoreDog(*r, d)
+		if !ok || s.Score < minMatchScore {
+			continue
+		}
+		kept[d.ID] = true
+		reasons := strings.Join(s.Reasons, ",")
+		if m, found := byDog[d.ID]; found {
+			if m.Status != entity.MatchCandidate {
+				continue
+			}
+			if err := tx.Model(&m).Updates(map[string]any{"score": s.Score, "reasons": reasons}).Error; err != nil {
+				return created, err
+			}
+			continue
+		}
+		m := entity.LostFoundMatch{
+			ReportID: r.ID,
+			DogID:    d.ID,
+			Score:    s.Score,
+			Reasons:  reasons,
+			Status:   entity.MatchCandidate,
+		}
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&m)
+		if res.Error != nil {
+			return created, res.Error
+		}
+		created += int(res.RowsAffected)
+	}
+
+	var stale []uint
+	for _, m := range existing {
+		if m.Status == entity.MatchCandidate && !kept[m.DogID] {
+			stale = append(stale, m.ID)
+		}
+	}
+	if len(stale) > 0 {
+		// ลบจริง: unique (report_id, dog_id) ต้องว่างไว้ให้จับคู่ใหม่ได้ถ้าคะแนนกลับมาถึงเกณฑ์
+		if err := tx.Unscoped().Delete(&entity.LostFoundMatch{}, stale).Error; err != nil {
+			return created, err
+		}
+	}
+
+	now := time.Now()
+	r.LastMatchedAt = &now
+	return created, tx.Model(r).UpdateColumn("last_matched_at", now).Error
+}
+
+// RunMatching จับคู่ทุกประกาศที่ยังเปิดอยู่ คืนจำนวน candidate ที่สร้างใหม่
+func RunMatching(db *gorm.DB) (int, error) {
+	var reports []entity.LostFoundReport
+	if err := db.Where("status = ?", entity.LostFoundOpen).Find(&reports).Error; err != nil {
+		return 0, err
+	}
+	total := 0
+	for i := range reports {
+		n, err := matchReport(db, &reports[i])
+		if err != nil {
+			return total, err
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// StartMatchingJob รันจับคู่ซ้ำทุก interval (สุนัขที่รับเข้าใหม่จะถูกเทียบกับประกาศเดิม)
+func StartMatchingJob(interval time.Duration) {
+	go func() {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for range t.C {
+			n, err := RunMatching(configs.DB())
+			if err != nil {
+				log.Println("lost-found matching failed:", err)
+				continue
+			}
+			if n > 0 {
+				log.Printf("lost-found matching: %d new candidate(s)", n)
+			}
+		}
+	}()
+}
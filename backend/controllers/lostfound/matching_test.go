@@ -0,0 +1,76 @@
+package lostfound
+
+import (
+	"testing"
+	"time"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/migrations"
+	"example.com/project-sa/seeds"
+	"example.com/project-sa/utils/testutil"
+)
+
+func TestMain(m *testing.M) { testutil.Main(m, migrations.AutoMigrate, seeds.SeedAll) }
+
+// จับคู่ซ้ำ: candidate ที่คะแนนตกเกณฑ์หรือสุนัขออกจากความดูแลถูกลบ ส่วนคู่ที่ตัดสินแล้วคงไว้
+func TestRematchDropsStaleCandidates(t *testing.T) {
+	db := configs.DB()
+	one := uint(1)
+	today := time.Now().Format("2006-01-02")
+	r := entity.LostFoundReport{
+		Type: entity.LostFoundLost, Status: entity.LostFoundOpen, ReporterName: "Owner",
+		BreedID: &one, AnimalSexID: &one, AnimalSizeID: &one, Color: "brindle", EventDate: today, Area: "Bang Khen",
+	}
+	if err := db.Create(&r).Error; err != nil {
+		t.Fatal(err)
+	}
+	newDog := func(name string) entity.Dog {
+		d := entity.Dog{Name: name, BreedID: 1, AnimalSexID: 1, AnimalSizeID: 1,
+			Color: "brindle", IntakeDate: today, IntakeArea: "Bang Khen"}
+		if err := db.Create(&d).Error; err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	adopted, changed, kept, reviewed := newDog("Adopted"), newDog("Changed"), newDog("Kept"), newDog("Reviewed")
+
+	if _, err := matchReport(db, &r); err != nil {
+		t.Fatal(err)
+	}
+	db.Model(&entity.LostFoundMatch{}).Where("report_id = ? AND dog_id = ?", r.ID, reviewed.ID).
+		Update("status", entity.MatchRejected)
+
+	db.Model(&adopted).Update("is_adopted", true)
+	db.Model(&reviewed).Update("status", entity.DogStatusTransferred)
+	db.Model(&changed).Updates(map[string]any{"breed_id": 2, "color": "white", "intake_area": "Phuket"})
+	if _, err := matchReport(db, &r); err != nil {
+		t.Fatal(err)
+	}
+
+	status := map[uint]string{}
+	var rows []entity.LostFoundMatch
+	db.Where("report_id = ?", r.ID).Find(&rows)
+	for _, m := range rows {
+		status[m.DogID] = m.Status
+	}
+	for _, tt := range []struct {
+		dog  entity.Dog
+		want string // "" = ต้องไม่มีคู่
+	}{
+		{adopted, ""},
+		{changed, ""},
+		{kept, entity.MatchCandidate},
+		{reviewed, entity.MatchRejected},
+	} {
+		if got := status[tt.dog.ID]; got != tt.want {
+			t.Errorf("%s: match status = %q, want %q", tt.dog.Name, got, tt.want)
+		}
+	}
+
+	// คะแนนกลับมาถึงเกณฑ์ จับคู่ใหม่ได้อีก
+	db.Model(&changed).Updates(map[string]any{"breed_id": 1, "color": "brindle", "intake_area": "Bang Khen"})
+	if n, err := matchReport(db, &r); err != nil || n != 1 {
+		t.Errorf("re-match after restoring = %d, %v; want 1 new candidate", n, err)
+	}
+}
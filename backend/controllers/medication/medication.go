@@ -0,0 +1,418 @@
+package medication
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/middlewares"
+	"example.com/project-sa/utils/timeutil"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+/* ========== Prescriptions / medication administrations ========== */
+
+type prescriptionRequest struct {
+	Drug            string   `json:"drug" binding:"required"`
+	Dose            string   `json:"dose" binding:"required"`
+	Route           string   `json:"route" binding:"required,oneof=oral injection topical eye ear other"`
+	TimesPerDay     int      `json:"times_per_day" binding:"omitempty,min=1,max=6"`
+	Times           []string `json:"times"` // "HH:MM" เวลาไทย (ว่าง = ใช้เวลามาตรฐานตาม times_per_day)
+	DurationDays    int      `json:"duration_days" binding:"required,min=1,max=365"`
+	StartDate       string   `json:"start_date"` // "YYYY-MM-DD" (ว่าง = วันนี้)
+	MedicalRecordID *uint    `json:"medical_record_id"`
+	Instructions    string   `json:"instructions"`
+	ClinicItemID    *uint    `json:"clinic_item_id"` // ตัดสต็อกจากคลังคลินิกเมื่อให้ยา
+	StockPerDose    int      `json:"stock_per_dose" binding:"omitempty,min=1"`
+}
+
+type stopRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+type administrationRequest struct {
+	Status    string `json:"status" binding:"required,oneof=given skipped refused"`
+	Note      string `json:"note"`
+	LotNumber string `json:"lot_number"` // ว่าง = ใช้ lot ที่หมดอายุก่อน
+}
+
+// เวลาให้ยามาตรฐานตามจำนวนมื้อต่อวัน
+var defaultTimes = map[int][]string{
+	1: {"08:00"},
+	2: {"08:00", "20:00"},
+	3: {"08:00", "14:00", "20:00"},
+	4: {"06:00", "12:00", "18:00", "22:00"},
+	5: {"06:00", "10:00", "14:00", "18:00", "22:00"},
+	6: {"02:00", "06:00", "10:00", "14:00", "18:00", "22:00"},
+}
+
+// บันทึกการให้ยาล่วงหน้าก่อนเวลาได้ไม่เกินนี้
+const earlyWindow = 2 * time.Hour
+
+func inCare(dog entity.Dog) bool {
+	return !dog.IsAdopted && (dog.Status == entity.DogStatusShelter || dog.Status == entity.DogStatusFoster)
+}
+
+// resolveTimes ตรวจ/เรียงเวลาให้ยา
+func resolveTimes(timesPerDay int, times []string) ([]string, error) {
+	if len(times) == 0 {
+		if timesPerDay == 0 {
+			timesPerDay = 1
+		}
+		return defaultTimes[timesPerDay], nil
+	}
+	if timesPerDay != 0 && timesPerDay != len(times) {
+		return nil, errors.New("times_per_day does not match number of times")
+	}
+	seen := map[string]bool{}
+	out := make([]string, 0, len(times))
+	for _, t := range times {
+		p, err := time.Parse("15:04", t)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time %q (HH:MM)", t)
+		}
+		s := p.Format("15:04")
+		if seen[s] {
+			return nil, fmt.Errorf("duplicate time %s", s)
+		}
+		seen[s] = true
+		out = append(out, s)
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// schedule สร้างมื้อยาทั้งคอร์ส (มื้อที่เลยเวลาไปแล้ว ณ ตอนสั่งยาไม่สร้าง)
+func schedule(p *entity.Prescription, times []string, now time.Time) []entity.MedicationAdministration {
+	var out []entity.MedicationAdministration
+	for day := 0; day < p.DurationDays; day++ {
+		d := p.StartDate.AddDate(0, 0, day)
+		for _, t := range times {
+			hm, _ := time.Parse("15:04", t)
+			at := time.Date(d.Year(), d.Month(), d.Day(), hm.Hour(), hm.Minute(), 0, 0, timeutil.TZBangkok())
+			if at.Before(now) {
+				continue
+			}
+			out = append(out, entity.MedicationAdministration{
+				PrescriptionID: p.ID,
+				DogID:          p.DogID,
+				ScheduledAt:    at.UTC(), // sqlite เทียบเวลาแบบข้อความ เก็บ UTC ทั้งหมด
+				Status:         entity.AdminScheduled,
+			})
+		}
+	}
+	return out
+}
+
+// POST /dogs/:id/prescriptions
+func CreatePrescription(c *gin.Context) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req prescriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	times, err := resolveTimes(req.TimesPerDay, req.Times)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	dateStr := req.StartDate
+	if dateStr == "" {
+		dateStr = timeutil.TodayYMD()
+	}
+	start, err := time.ParseInLocation("2006-01-02", dateStr, timeutil.TZBangkok())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start_date (YYYY-MM-DD)"})
+		return
+	}
+	if dateStr < timeutil.TodayYMD() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "start_date cannot be in the past"})
+		return
+	}
+
+	var p entity.Prescription
+	var status int
+	err = configs.DB().Transaction(func(tx *gorm.DB) error {
+		var dog entity.Dog
+		if err := tx.First(&dog, c.Param("id")).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				status = http.StatusNotFound
+				return errors.New("dog not found")
+			}
+			return err
+		}
+		if !inCare(dog) {
+			status = http.StatusConflict
+			return errors.New("dog is not in our care (status: " + dog.Status + ")")
+		}
+		if req.MedicalRecordID != nil {
+			var mr entity.MedicalRecord
+			if err := tx.First(&mr, *req.MedicalRecordID).Error; err != nil || mr.DogID != dog.ID {
+				status = http.StatusBadRequest
+				return errors.New("invalid medical_record_id")
+			}
+		}
+		stockPerDose := 0
+		if req.ClinicItemID != nil {
+			var item entity.ClinicItem
+			if err := tx.First(&item, *req.ClinicItemID).Error; err != nil {
+				status = http.StatusBadRequest
+				return errors.New("invalid clinic_item_id")
+			}
+			stockPerDose = req.StockPerDose
+			if stockPerDose == 0 {
+				stockPerDose = 1
+			}
+		}
+		p = entity.Prescription{
+			DogID:           dog.ID,
+			MedicalRecordID: req.MedicalRecordID,
+			Drug:            strings.TrimSpace(req.Drug),
+			Dose:            strings.TrimSpace(req.Dose),
+			Route:           req.Route,
+			TimesPerDay:     len(times),
+			Times:           strings.Join(times, ","),
+			DurationDays:    req.DurationDays,
+			StartDate:       start,
+			EndDate:         start.AddDate(0, 0, req.DurationDays-1),
+			Instructions:    req.Instructions,
+			ClinicItemID:    req.ClinicItemID,
+			StockPerDose:    stockPerDose,
+			Status:          entity.PrescriptionActive,
+			PrescribedByID:  staffID,
+		}
+		if err := tx.Create(&p).Error; err != nil {
+			return err
+		}
+		admins := schedule(&p, times, time.Now())
+		if len(admins) == 0 {
+			status = http.StatusBadRequest
+			return errors.New("no doses left to schedule")
+		}
+		return tx.Create(&admins).Error
+	})
+	if err != nil {
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, gin.H{"error": "create failed: " + err.Error()})
+		return
+	}
+	if err := preloadPrescription(configs.DB()).First(&p, p.ID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": p})
+}
+
+func preloadPrescription(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Dog").
+		Preload("PrescribedBy").
+		Preload("ClinicItem").
+		Preload("Administrations", func(db *gorm.DB) *gorm.DB { return db.Order("scheduled_at ASC") }).
+		Preload("Administrations.RecordedBy")
+}
+
+// GET /dogs/:id/prescriptions?status=
+func GetDogPrescriptions(c *gin.Context) {
+	db := configs.DB().Preload("PrescribedBy").Where("dog_id = ?", c.Param("id"))
+	if s := c.Query("status"); s != "" {
+		db = db.Where("status = ?", s)
+	}
+	var rows []entity.Prescription
+	if err := db.Order("start_date DESC, id DESC").Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": rows})
+}
+
+// GET /prescriptions/:id
+func GetPrescription(c *gin.Context) {
+	var p entity.Prescription
+	if err := preloadPrescription(configs.DB()).First(&p, c.Param("id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "prescription not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": p})
+}
+
+// POST /prescriptions/:id/stop — หยุดยา มื้อที่ยังไม่ถึงเวลาถูกยกเลิก
+func StopPrescription(c *gin.Context) {
+	if middlewares.StaffID(c) == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req stopRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	var p entity.Prescription
+	var status int
+	err := configs.DB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&p, c.Param("id")).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				status = http.StatusNotFound
+				return errors.New("prescription not found")
+			}
+			return err
+		}
+		if p.Status != entity.PrescriptionActive {
+			status = http.StatusConflict
+			return errors.New("prescription is " + p.Status)
+		}
+		return stopPrescription(tx, &p, req.Reason)
+	})
+	if err != nil {
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, gin.H{"error": "stop failed: " + err.Error()})
+		return
+	}
+	if err := preloadPrescription(configs.DB()).First(&p, p.ID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": p})
+}
+
+func stopPrescription(tx *gorm.DB, p *entity.Prescription, reason string) error {
+	now := time.Now()
+	if err := tx.Model(&entity.MedicationAdministration{}).
+		Where("prescription_id = ? AND status = ?", p.ID, entity.AdminScheduled).
+		Update("status", entity.AdminCancelled).Error; err != nil {
+		return err
+	}
+	return tx.Model(p).Updates(map[string]any{
+		"status":         entity.PrescriptionStopped,
+		"stopped_at":     now,
+		"stopped_reason": reason,
+	}).Error
+}
+
+// completeIfDone ปิดใบสั่งยาเมื่อไม่มีมื้อที่รอให้แล้ว
+func completeIfDone(tx *gorm.DB, prescriptionID uint) error {
+	var left int64
+	if err := tx.Model(&entity.MedicationAdministration{}).
+		Where("prescription_id = ? AND status = ?", prescriptionID, entity.AdminScheduled).
+		Count(&left).Error; err != nil {
+		return err
+	}
+	if left > 0 {
+		return nil
+	}
+	return tx.Model(&entity.Prescription{}).
+		Where("id = ? AND status = ?", prescriptionID, entity.PrescriptionActive).
+		Update("status", entity.PrescriptionCompleted).Error
+}
+
+// POST /medication-administrations/:id — บันทึกผลการให้ยา (given | skipped | refused)
+// มื้อที่ระบบตั้งเป็น missed แล้วยังบันทึกย้อนหลังได้
+func RecordAdministration(c *gin.Context) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req administrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	if req.Status != entity.AdminGiven && strings.TrimSpace(req.Note) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "note is required when a dose is skipped or refused"})
+		return
+	}
+
+	var a entity.MedicationAdministration
+	var status int
+	err := configs.DB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&a, c.Param("id")).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				status = http.StatusNotFound
+				return errors.New("administration not found")
+			}
+			return err
+		}
+		if a.Status != entity.AdminScheduled && a.Status != entity.AdminMissed {
+			status = http.StatusConflict
+			return errors.New("dose is already " + a.Status)
+		}
+		now := time.Now()
+		if a.ScheduledAt.After(now.Add(earlyWindow)) {
+			status = http.StatusConflict
+			return errors.New("dose is not due yet")
+		}
+		var p entity.Prescription
+		if err := tx.First(&p, a.PrescriptionID).Error; err != nil {
+			return err
+		}
+		// หยุดยา/ครบคอร์สแล้ว บันทึกย้อนหลังได้เฉพาะมื้อที่ถึงเวลาก่อนหยุดยา (กันตัดสต็อกหลังหยุดยา)
+		if p.Status != entity.PrescriptionActive && (p.StoppedAt == nil || !a.ScheduledAt.Before(*p.StoppedAt)) {
+			status = http.StatusConflict
+			return errors.New("prescription is " + p.Status)
+		}
+		updates := map[string]any{
+			"status":         req.Status,
+			"note":           req.Note,
+			"recorded_at":    now,
+			"recorded_by_id": *staffID,
+		}
+		if req.Status == entity.AdminGiven {
+			if p.ClinicItemID != nil {
+				dogID, adminID := a.DogID, a.ID
+				lot, err := entity.ConsumeStock(tx, entity.StockUse{
+					ItemID:           *p.ClinicItemID,
+					LotNumber:        req.LotNumber,
+					Quantity:         p.StockPerDose,
+					At:               now,
+					Reason:           entity.StockMedication,
+					DogID:            &dogID,
+					AdministrationID: &adminID,
+					StaffID:          staffID,
+				})
+				if err != nil {
+					if entity.IsStockError(err) {
+						status = http.StatusConflict
+					}
+					return err
+				}
+				updates["lot_id"] = lot.ID
+			}
+		}
+		if err := tx.Model(&a).Updates(updates).Error; err != nil {
+			return err
+		}
+		return completeIfDone(tx, a.PrescriptionID)
+	})
+	if err != nil {
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, gin.H{"error": "record failed: " + err.Error()})
+		return
+	}
+	if err := configs.DB().Preload("Prescription").Preload("RecordedBy").First(&a, a.ID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": a})
+}
@@ -0,0 +1,73 @@
+package medication
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/migrations"
+	"example.com/project-sa/seeds"
+	"example.com/project-sa/utils/testutil"
+)
+
+func TestMain(m *testing.M) { testutil.Main(m, migrations.AutoMigrate, seeds.SeedAll) }
+
+// หยุดยาแล้ว: มื้อที่ถึงเวลาก่อนหยุดยังบันทึกย้อนหลังได้ มื้อหลังหยุดยาต้องไม่ตัดสต็อก
+func TestRecordAdministrationAfterStop(t *testing.T) {
+	db := configs.DB()
+	item := entity.ClinicItem{Name: "Amoxicillin 250", Kind: "drug", Unit: "tablet"}
+	if err := db.Create(&item).Error; err != nil {
+		t.Fatal(err)
+	}
+	lot := entity.ClinicLot{ItemID: item.ID, LotNumber: "AMX-1", QuantityReceived: 10, QuantityOnHand: 10, ReceivedAt: time.Now()}
+	if err := db.Create(&lot).Error; err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now().UTC()
+	stoppedAt := now.Add(-time.Hour)
+	p := entity.Prescription{
+		DogID: 1, Drug: "Amoxicillin", Dose: "1 tablet", Route: entity.RouteOral, TimesPerDay: 2, DurationDays: 3,
+		StartDate: now.AddDate(0, 0, -1), EndDate: now.AddDate(0, 0, 1),
+		ClinicItemID: &item.ID, StockPerDose: 1,
+		Status: entity.PrescriptionStopped, StoppedAt: &stoppedAt, StoppedReason: "vomiting",
+	}
+	if err := db.Create(&p).Error; err != nil {
+		t.Fatal(err)
+	}
+	dose := func(at time.Time) entity.MedicationAdministration {
+		a := entity.MedicationAdministration{PrescriptionID: p.ID, DogID: 1, ScheduledAt: at, Status: entity.AdminMissed}
+		if err := db.Create(&a).Error; err != nil {
+			t.Fatal(err)
+		}
+		return a
+	}
+	before, after := dose(now.Add(-3*time.Hour)), dose(now.Add(-30*time.Minute))
+
+	r := testutil.Router(1)
+	r.POST("/medication-administrations/:id", RecordAdministration)
+	give := func(a entity.MedicationAdministration) int {
+		code, _ := testutil.Do(t, r, http.MethodPost, fmt.Sprintf("/medication-administrations/%d", a.ID), map[string]any{"status": "given"})
+		return code
+	}
+	onHand := func() int {
+		var l entity.ClinicLot
+		db.First(&l, lot.ID)
+		return l.QuantityOnHand
+	}
+
+	if code := give(after); code != http.StatusConflict {
+		t.Errorf("dose after stop = %d, want 409", code)
+	}
+	if got := onHand(); got != 10 {
+		t.Errorf("stock after rejected dose = %d, want 10", got)
+	}
+	if code := give(before); code != http.StatusOK {
+		t.Errorf("dose before stop = %d, want 200", code)
+	}
+	if got := onHand(); got != 9 {
+		t.Errorf("stock after late-recorded dose = %d, want 9", got)
+	}
+}
@@ -0,0 +1,265 @@
+package medication
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/middlewares"
+	"example.com/project-sa/utils/timeutil"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+/* ========== รอบให้ยาประจำวัน / มื้อที่พลาด ========== */
+
+// เลยเวลานัดเกินนี้โดยไม่มีการบันทึก = missed
+const missedAfter = 2 * time.Hour
+
+type roundItem struct {
+	AdministrationID uint       `json:"administration_id"`
+	PrescriptionID   uint       `json:"prescription_id"`
+	DogID            uint       `json:"dog_id"`
+	DogName          string     `json:"dog_name"`
+	ScheduledAt      time.Time  `json:"scheduled_at"`
+	Time             string     `json:"time"` // HH:MM เวลาไทย
+	Drug             string     `json:"drug"`
+	Dose             string     `json:"dose"`
+	Route            string     `json:"route"`
+	Instructions     string     `json:"instructions"`
+	Status           string     `json:"status"`
+	RecordedAt       *time.Time `json:"recorded_at"`
+}
+
+type roundKennel struct {
+	KennelID   *uint       `json:"kennel_id"`
+	KennelName string      `json:"kennel_name"`
+	Items      []roundItem `json:"items"`
+}
+
+type roundZone struct {
+	ZoneID   *uint         `json:"zone_id"`
+	ZoneName string        `json:"zone_name"`
+	Pending  int           `json:"pending"`
+	Done     int           `json:"done"`
+	Kennels  []roundKennel `json:"kennels"`
+}
+
+// GET /medications/round?date=YYYY-MM-DD&zone_id= — รายการให้ยาของวัน แยกตามโซน/คอก
+func GetDailyRound(c *gin.Context) {
+	dateStr := c.DefaultQuery("date", timeutil.TodayYMD())
+	day, err := time.ParseInLocation("2006-01-02", dateStr, timeutil.TZBangkok())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date (YYYY-MM-DD)"})
+		return
+	}
+	var zoneFilter uint
+	if v := c.Query("zone_id"); v != "" {
+		n, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid zone_id"})
+			return
+		}
+		zoneFilter = uint(n)
+	}
+
+	var admins []entity.MedicationAdministration
+	if err := configs.DB().
+		Preload("Prescription").
+		Preload("Dog").Preload("Dog.Kennel").Preload("Dog.Kennel.Zone").
+		Where("scheduled_at >= ? AND scheduled_at < ?", day.UTC(), day.AddDate(0, 0, 1).UTC()).
+		Where("status <> ?", entity.AdminCancelled).
+		Order("scheduled_at ASC, id ASC").
+		Find(&admins).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+
+	zones := []roundZone{}
+	zoneIdx := map[string]int{}
+	kennelIdx := map[string]int{}
+	for _, a := range admins {
+		if a.Dog == nil || a.Prescription == nil {
+			continue
+		}
+		var zoneID, kennelID *uint
+		zoneName, kennelName := "Unassigned", ""
+		switch {
+		case a.Dog.Kennel != nil:
+			kid := a.Dog.Kennel.ID
+			kennelID, kennelName = &kid, a.Dog.Kennel.Name
+			if a.Dog.Kennel.Zone != nil {
+				zid := a.Dog.Kennel.Zone.ID
+				zoneID, zoneName = &zid, a.Dog.Kennel.Zone.Name
+			}
+		case a.Dog.Status == entity.DogStatusFoster:
+			zoneName = "Foster care"
+		}
+		if zoneFilter != 0 && (zoneID == nil || *zoneID != zoneFilter) {
+			continue
+		}
+
+		zi, ok := zoneIdx[zoneName]
+		if !ok {
+			zones = append(zones, roundZone{ZoneID: zoneID, ZoneName: zoneName, Kennels: []roundKennel{}})
+			zi = len(zones) - 1
+			zoneIdx[zoneName] = zi
+		}
+		kk := zoneName + "/" + kennelName
+		ki, ok := kennelIdx[kk]
+		if !ok {
+			zones[zi].Kennels = append(zones[zi].Kennels, roundKennel{KennelID: kennelID, KennelName: kennelName, Items: []roundItem{}})
+			ki = len(zones[zi].Kennels) - 1
+			kennelIdx[kk] = ki
+		}
+		zones[zi].Kennels[ki].Items = append(zones[zi].Kennels[ki].Items, roundItem{
+			AdministrationID: a.ID,
+			PrescriptionID:   a.PrescriptionID,
+			DogID:            a.DogID,
+			DogName:          a.Dog.Name,
+			ScheduledAt:      a.ScheduledAt,
+			Time:             a.ScheduledAt.In(timeutil.TZBangkok()).Format("15:04"),
+			Drug:             a.Prescription.Drug,
+			Dose:             a.Prescription.Dose,
+			Route:            a.Prescription.Route,
+			Instructions:     a.Prescription.Instructions,
+			Status:           a.Status,
+			RecordedAt:       a.RecordedAt,
+		})
+		if a.Status == entity.AdminScheduled || a.Status == entity.AdminMissed {
+			zones[zi].Pending++
+		} else {
+			zones[zi].Done++
+		}
+	}
+	sort.SliceStable(zones, func(i, j int) bool { return zones[i].ZoneName < zones[j].ZoneName })
+	for _, z := range zones {
+		sort.SliceStable(z.Kennels, func(i, j int) bool { return z.Kennels[i].KennelName < z.Kennels[j].KennelName })
+	}
+	c.JSON(http.StatusOK, gin.H{"data": zones, "date": dateStr})
+}
+
+// SyncMissedDoses ตั้งมื้อที่เลยเวลาเป็น missed และหยุดใบสั่งยาของสุนัขที่ไม่ได้อยู่ในความดูแลแล้ว
+func SyncMissedDoses(db *gorm.DB) (missed int, err error) {
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var gone []entity.Prescription
+		if err := tx.Joins("JOIN dogs ON dogs.id = prescriptions.dog_id").
+			Where("prescriptions.status = ?", entity.PrescriptionActive).
+			Where("dogs.is_adopted = ? OR dogs.status NOT IN ?", true, []string{entity.DogStatusShelter, entity.DogStatusFoster}).
+			Find(&gone).Error; err != nil {
+			return err
+		}
+		for i := range gone {
+			if err := stopPrescription(tx, &gone[i], "dog left our care"); err != nil {
+				return err
+			}
+		}
+
+		var overdue []entity.MedicationAdministration
+		if err := tx.Where("status = ? AND scheduled_at < ?", entity.AdminScheduled, time.Now().UTC().Add(-missedAfter)).
+			Find(&overdue).Error; err != nil {
+			return err
+		}
+		ids := make([]uint, 0, len(overdue))
+		prescriptions := map[uint]bool{}
+		for _, a := range overdue {
+			ids = append(ids, a.ID)
+			prescriptions[a.PrescriptionID] = true
+		}
+		if len(ids) > 0 {
+			if err := tx.Model(&entity.MedicationAdministration{}).
+				Where("id IN ?", ids).
+				Update("status", entity.AdminMissed).Error; err != nil {
+				return err
+			}
+		}
+		for id := range prescriptions {
+			if err := completeIfDone(tx, id); err != nil {
+				return err
+			}
+		}
+		missed = len(ids)
+		return nil
+	})
+	return missed, err
+}
+
+// StartMissedDoseJob ตรวจมื้อยาที่พลาดทันทีและทุก interval
+func StartMissedDoseJob(interval time.Duration) {
+	run := func() {
+		n, err := SyncMissedDoses(configs.DB())
+		if err != nil {
+			log.Printf("missed doses: %v", err)
+			return
+		}
+		if n > 0 {
+			log.Printf("missed doses: marked %d", n)
+		}
+	}
+	go func() {
+		run()
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for range t.C {
+			run()
+		}
+	}()
+}
+
+// GET /medications/missed?all=true — แจ้งเตือนมื้อที่พลาด (ค่าเริ่มต้น: ยังไม่มีผู้รับทราบ)
+func GetMissedDoses(c *gin.Context) {
+	if _, err := SyncMissedDoses(configs.DB()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "sync failed: " + err.Error()})
+		return
+	}
+	db := configs.DB().Preload("Prescription").Preload("Dog").
+		Where("status = ?", entity.AdminMissed)
+	if c.Query("all") != "true" {
+		db = db.Where("missed_acknowledged_at IS NULL")
+	}
+	var rows []entity.MedicationAdministration
+	if err := db.Order("scheduled_at ASC, id ASC").Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": rows})
+}
+
+// POST /medication-administrations/:id/ack-missed
+func AcknowledgeMissedDose(c *gin.Context) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var a entity.MedicationAdministration
+	if err := configs.DB().First(&a, c.Param("id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "administration not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	if a.Status != entity.AdminMissed {
+		c.JSON(http.StatusConflict, gin.H{"error": "dose is not missed"})
+		return
+	}
+	if a.MissedAcknowledgedAt == nil {
+		now := time.Now()
+		if err := configs.DB().Model(&a).Updates(map[string]any{
+			"missed_acknowledged_at":    now,
+			"missed_acknowledged_by_id": *staffID,
+		}).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed: " + err.Error()})
+			return
+		}
+		a.MissedAcknowledgedAt, a.MissedAcknowledgedByID = &now, staffID
+	}
+	c.JSON(http.StatusOK, gin.H{"data": a})
+}
@@ -0,0 +1,457 @@
+package outcome
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/middlewares"
+	"example.com/project-sa/utils/timeutil"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+/* ========== End-of-life outcomes ========== */
+
+type outcomeCreateRequest struct {
+	Type           string `json:"type" binding:"required,oneof=death euthanasia"`
+	Cause          string `json:"cause" binding:"required"`
+	OutcomeDate    string `json:"outcome_date"` // "YYYY-MM-DD" (ว่าง = วันนี้)
+	AttendingVetID uint   `json:"attending_vet_id" binding:"required"`
+	Notes          string `json:"notes"`
+}
+
+type decisionRequest struct {
+	Comment string `json:"comment"`
+}
+
+const (
+	decisionApprove = "approve"
+	decisionReject  = "reject"
+)
+
+func preloadOutcome(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Dog").
+		Preload("AttendingVet").
+		Preload("RequestedBy").
+		Preload("Approvals", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Preload("Approvals.Staff")
+}
+
+// inCare สุนัขยังอยู่ในความดูแล (ในศูนย์หรือบ้านอุปถัมภ์) จึงบันทึก/อนุมัติ outcome ได้
+func inCare(dog entity.Dog) bool {
+	return !dog.IsAdopted && (dog.Status == entity.DogStatusShelter || dog.Status == entity.DogStatusFoster)
+}
+
+// due ถึงวันที่ของ outcome แล้ว (เทียบวันตามเวลาไทย)
+func due(o entity.DogOutcome, now time.Time) bool {
+	loc := timeutil.TZBangkok()
+	return o.OutcomeDate.In(loc).Format("2006-01-02") <= now.In(loc).Format("2006-01-02")
+}
+
+// FinalizeScheduledOutcomes ให้นัดการุณยฆาตที่ถึงวันแล้วมีผล
+// สุนัขที่ออกจากความดูแลไปก่อน (รับเลี้ยง/ส่งต่อ) นัดถูกยกเลิก
+func FinalizeScheduledOutcomes(db *gorm.DB, now time.Time) (int, error) {
+	var rows []entity.DogOutcome
+	if err := db.Where("status = ?", entity.OutcomeScheduled).Order("id ASC").Find(&rows).Error; err != nil {
+		return 0, err
+	}
+	n := 0
+	for i := range rows {
+		o := &rows[i]
+		if !due(*o, now) {
+			continue
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			var dog entity.Dog
+			if err := tx.First(&dog, o.DogID).Error; err != nil {
+				return err
+			}
+			if !inCare(dog) {
+				return tx.Model(o).Update("status", entity.OutcomeRejected).Error
+			}
+			return finalize(tx, o, nil)
+		})
+		if err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// StartScheduledOutcomeJob ตรวจนัดการุณยฆาตที่ถึงวันเป็นระยะ
+func StartScheduledOutcomeJob(interval time.Duration) {
+	run := func() {
+		n, err := FinalizeScheduledOutcomes(configs.DB(), time.Now())
+		if err != nil {
+			log.Printf("scheduled outcomes: %v", err)
+			return
+		}
+		if n > 0 {
+			log.Printf("scheduled outcomes: finalized %d", n)
+		}
+	}
+	go func() {
+		run()
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for range t.C {
+			run()
+		}
+	}()
+}
+
+// finalize ให้บันทึกมีผล: สุนัขเป็น deceased, ออกจากคอก/บ้านอุปถัมภ์,
+// ยุติการอุปถัมภ์รายงวด และแจ้งผู้อุปถัมภ์ทุกคนของสุนัขตัวนี้
+func finalize(tx *gorm.DB, o *entity.DogOutcome, staffID *uint) error {
+	var dog entity.Dog
+	if err := tx.First(&dog, o.DogID).Error; err != nil {
+		return err
+	}
+	now := time.Now()
+
+	if dog.FosterHomeID != nil {
+		if err := tx.Model(&entity.FosterPlacement{}).
+			Where("dog_id = ? AND end_date IS NULL", dog.ID).
+			Updates(map[string]any{"end_date": o.OutcomeDate, "end_reason": "deceased"}).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := entity.RecordDogKennelChange(tx, dog.ID, dog.KennelID, nil, staffID, "outcome: "+o.Type); err != nil {
+		return err
+	}
+	if err := entity.RecordDogChange(tx, dog.ID, "ready_to_adopt", dog.ReadyToAdopt, false, staffID); err != nil {
+		return err
+	}
+	if err := entity.RecordDogChange(tx, dog.ID, "status", dog.Status, entity.DogStatusDeceased, staffID); err != nil {
+		return err
+	}
+	updates := map[string]any{
+		"status":         entity.DogStatusDeceased,
+		"kennel_id":      nil,
+		"foster_home_id": nil,
+		"ready_to_adopt": false,
+	}
+	if staffID != nil {
+		updates["updated_by_id"] = *staffID
+	}
+	if err := tx.Model(&dog).Updates(updates).Error; err != nil {
+		return err
+	}
+
+	// การอุปถัมภ์รายงวดที่ยัง active -> ยกเลิกทันที ไม่ตัดเงินงวดถัดไป (ประวัติการจ่ายเดิมยังอยู่)
+	var sps []entity.Sponsorship
+	if err := tx.Preload("Subscription").Where("dog_id = ?", dog.ID).Find(&sps).Error; err != nil {
+		return err
+	}
+	notified := map[uint]bool{}
+	for _, sp := range sps {
+		ended := false
+		if sub := sp.Subscription; sub != nil && sub.Status == "active" {
+			if err := tx.Model(sub).Updates(map[string]any{
+				"status":               "cancelled",
+				"cancel_at_period_end": false,
+				"ended_at":             now,
+				"next_payment_at":      nil,
+			}).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&entity.Sponsorship{}).Where("id = ?", sp.ID).
+				Update("status", "cancelled").Error; err != nil {
+				return err
+			}
+			ended = true
+		}
+		if notified[sp.SponsorID] && !ended {
+			continue
+		}
+		notified[sp.SponsorID] = true
+
+		msg := fmt.Sprintf("เราเสียใจที่ต้องแจ้งว่า %s ได้จากไปแล้วเมื่อวันที่ %s ขอบคุณที่ร่วมดูแลน้องมาโดยตลอด",
+			dog.Name, o.OutcomeDate.In(timeutil.TZBangkok()).Format("2006-01-02"))
+		if ended {
+			msg += " การอุปถัมภ์รายงวดของคุณถูกยกเลิกแล้วและจะไม่มีการตัดเงินอีก"
+		}
+		spID := sp.ID
+		if err := tx.Create(&entity.SponsorNotification{
+			SponsorID:     sp.SponsorID,
+			SponsorshipID: &spID,
+			DogID:         dog.ID,
+			Kind:          entity.SponsorNoticeDogDeceased,
+			Message:       msg,
+		}).Error; err != nil {
+			return err
+		}
+	}
+
+	o.Status = entity.OutcomeApproved
+	o.FinalizedAt = &now
+	return tx.Model(o).Updates(map[string]any{"status": o.Status, "finalized_at": now}).Error
+}
+
+// POST /dogs/:id/outcomes
+func CreateOutcome(c *gin.Context) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req outcomeCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	dateStr := req.OutcomeDate
+	if dateStr == "" {
+		dateStr = timeutil.TodayYMD()
+	}
+	date, err := time.ParseInLocation("2006-01-02", dateStr, timeutil.TZBangkok())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid outcome_date (YYYY-MM-DD)"})
+		return
+	}
+	// การเสียชีวิตบันทึกย้อนหลังได้ แต่ห้ามเป็นวันในอนาคต
+	if req.Type == entity.OutcomeDeath && dateStr > timeutil.TodayYMD() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "outcome_date cannot be in the future"})
+		return
+	}
+
+	var o entity.DogOutcome
+	var status int
+	err = configs.DB().Transaction(func(tx *gorm.DB) error {
+		var dog entity.Dog
+		if err := tx.First(&dog, c.Param("id")).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				status = http.StatusNotFound
+				return errors.New("dog not found")
+			}
+			return err
+		}
+		if !inCare(dog) {
+			status = http.StatusConflict
+			return errors.New("dog is not in our care (status: " + dog.Status + ")")
+		}
+		var pending int64
+		if err := tx.Model(&entity.DogOutcome{}).
+			Where("dog_id = ? AND status IN ?", dog.ID, []string{entity.OutcomePending, entity.OutcomeScheduled}).
+			Count(&pending).Error; err != nil {
+			return err
+		}
+		if pending > 0 {
+			status = http.StatusConflict
+			return errors.New("dog already has a pending outcome")
+		}
+		var vet entity.Staff
+		if err := tx.First(&vet, req.AttendingVetID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				status = http.StatusBadRequest
+				return errors.New("invalid attending_vet_id")
+			}
+			return err
+		}
+
+		o = entity.DogOutcome{
+			DogID:          dog.ID,
+			Type:           req.Type,
+			Status:         entity.OutcomePending,
+			Cause:          req.Cause,
+			OutcomeDate:    date,
+			Notes:          req.Notes,
+			AttendingVetID: vet.ID,
+			RequestedByID:  staffID,
+		}
+		if err := tx.Create(&o).Error; err != nil {
+			return err
+		}
+		// เสียชีวิตเองมีผลทันที, การุณยฆาตรออนุมัติ
+		if o.Type == entity.OutcomeDeath {
+			return finalize(tx, &o, staffID)
+		}
+		return nil
+	})
+	if err != nil {
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, gin.H{"error": "create failed: " + err.Error()})
+		return
+	}
+	if err := preloadOutcome(configs.DB()).First(&o, o.ID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": o})
+}
+
+// POST /outcomes/:id/approve
+func ApproveOutcome(c *gin.Context) {
+	decide(c, decisionApprove)
+}
+
+// POST /outcomes/:id/reject
+func RejectOutcome(c *gin.Context) {
+	decide(c, decisionReject)
+}
+
+func decide(c *gin.Context, decision string) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req decisionRequest
+	// body ไม่บังคับ
+	_ = c.ShouldBindJSON(&req)
+	if decision == decisionReject && req.Comment == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "comment is required when rejecting"})
+		return
+	}
+
+	var o entity.DogOutcome
+	var status int
+	err := configs.DB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&o, c.Param("id")).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				status = http.StatusNotFound
+				return errors.New("outcome not found")
+			}
+			return err
+		}
+		// นัดการุณยฆาตที่อนุมัติแล้วยังยกเลิก (reject) ได้ก่อนถึงวัน
+		if o.Status != entity.OutcomePending && !(o.Status == entity.OutcomeScheduled && decision == decisionReject) {
+			status = http.StatusConflict
+			return errors.New("outcome is not pending approval")
+		}
+		// ผู้ขอไม่นับเป็นผู้อนุมัติ
+		if o.RequestedByID != nil && *o.RequestedByID == *staffID {
+			status = http.StatusForbidden
+			return errors.New("requester cannot approve their own request")
+		}
+		var already int64
+		if err := tx.Model(&entity.DogOutcomeApproval{}).
+			Where("outcome_id = ? AND staff_id = ?", o.ID, *staffID).
+			Count(&already).Error; err != nil {
+			return err
+		}
+		if already > 0 {
+			status = http.StatusConflict
+			return errors.New("you have already decided on this outcome")
+		}
+		if err := tx.Create(&entity.DogOutcomeApproval{
+			OutcomeID: o.ID,
+			StaffID:   *staffID,
+			Decision:  decision,
+			Comment:   req.Comment,
+		}).Error; err != nil {
+			return err
+		}
+
+		if decision == decisionReject {
+			o.Status = entity.OutcomeRejected
+			return tx.Model(&o).Update("status", o.Status).Error
+		}
+		// ระหว่างรออนุมัติ สุนัขอาจถูกรับเลี้ยง/ส่งต่อ/เสียชีวิตไปแล้ว
+		var dog entity.Dog
+		if err := tx.First(&dog, o.DogID).Error; err != nil {
+			return err
+		}
+		if dog.IsAdopted {
+			status = http.StatusConflict
+			return errors.New("dog has been adopted")
+		}
+		if !inCare(dog) {
+			status = http.StatusConflict
+			return errors.New("dog is no longer in our care (status: " + dog.Status + ")")
+		}
+		var approvals int64
+		if err := tx.Model(&entity.DogOutcomeApproval{}).
+			Where("outcome_id = ? AND decision = ?", o.ID, decisionApprove).
+			Count(&approvals).Error; err != nil {
+			return err
+		}
+		if approvals < entity.EuthanasiaApprovalsRequired {
+			return nil
+		}
+		// วันที่นัดยังไม่ถึง: รอ job ให้มีผลในวันนั้น
+		if !due(o, time.Now()) {
+			o.Status = entity.OutcomeScheduled
+			return tx.Model(&o).Update("status", o.Status).Error
+		}
+		return finalize(tx, &o, staffID)
+	})
+	if err != nil {
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, gin.H{"error": "decision failed: " + err.Error()})
+		return
+	}
+	if err := preloadOutcome(configs.DB()).First(&o, o.ID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": o})
+}
+
+// GET /outcomes/:id
+func GetOutcome(c *gin.Context) {
+	var o entity.DogOutcome
+	if err := preloadOutcome(configs.DB()).First(&o, c.Param("id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "outcome not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": o})
+}
+
+// GET /outcomes?status=&type=&dog_id=&from=&to= — รายงานย้อนหลัง (from/to อิง outcome_date)
+func GetOutcomes(c *gin.Context) {
+	db := preloadOutcome(configs.DB())
+	if v := c.Query("status"); v != "" {
+		db = db.Where("status = ?", v)
+	}
+	if v := c.Query("type"); v != "" {
+		db = db.Where("type = ?", v)
+	}
+	if v := c.Query("dog_id"); v != "" {
+		db = db.Where("dog_id = ?", v)
+	}
+	for _, p := range []struct{ key, op string }{{"from", ">="}, {"to", "<"}} {
+		v := c.Query(p.key)
+		if v == "" {
+			continue
+		}
+		t, err := time.ParseInLocation("2006-01-02", v, timeutil.TZBangkok())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + p.key + " (YYYY-MM-DD)"})
+			return
+		}
+		if p.key == "to" {
+			t = t.AddDate(0, 0, 1) // รวมวันสุดท้าย
+		}
+		db = db.Where("outcome_date "+p.op+" ?", t)
+	}
+
+	var rows []entity.DogOutcome
+	if err := db.Order("outcome_date DESC, id DESC").Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	byType := map[string]int{}
+	for _, o := range rows {
+		if o.Status == entity.OutcomeApproved {
+			byType[o.Type]++
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"data": rows, "summary": gin.H{"total": len(rows), "finalized_by_type": byType}})
+}
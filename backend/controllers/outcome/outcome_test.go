@@ -0,0 +1,97 @@
+package outcome
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/migrations"
+	"example.com/project-sa/seeds"
+	"example.com/project-sa/utils/testutil"
+	"example.com/project-sa/utils/timeutil"
+	"github.com/gin-gonic/gin"
+)
+
+func TestMain(m *testing.M) { testutil.Main(m, migrations.AutoMigrate, seeds.SeedAll) }
+
+func outcomeRouter(staffID uint) *gin.Engine {
+	r := testutil.Router(staffID)
+	r.POST("/dogs/:id/outcomes", CreateOutcome)
+	r.POST("/outcomes/:id/approve", ApproveOutcome)
+	r.POST("/outcomes/:id/reject", RejectOutcome)
+	return r
+}
+
+func dogStatus(t *testing.T, id uint) string {
+	t.Helper()
+	var d entity.Dog
+	if err := configs.DB().Unscoped().First(&d, id).Error; err != nil {
+		t.Fatal(err)
+	}
+	return d.Status
+}
+
+// การุณยฆาตล่วงหน้า: อนุมัติครบแล้วยังไม่มีผลจนถึงวันที่นัด
+func TestFutureEuthanasiaWaitsForItsDate(t *testing.T) {
+	approver := entity.Staff{FirstName: "Third", Username: "third-approver", Email: "third@example.com", ZoneID: 1, GenderID: 1}
+	if err := configs.DB().Create(&approver).Error; err != nil {
+		t.Fatal(err)
+	}
+	const dogID = 3
+	tomorrow := time.Now().In(timeutil.TZBangkok()).AddDate(0, 0, 1)
+
+	o := testutil.MustDo(t, outcomeRouter(1), http.MethodPost, fmt.Sprintf("/dogs/%d/outcomes", dogID), map[string]any{
+		"type": entity.OutcomeEuthanasia, "cause": "end-stage renal failure",
+		"outcome_date": tomorrow.Format("2006-01-02"), "attending_vet_id": 1,
+	})
+	approve := fmt.Sprintf("/outcomes/%d/approve", testutil.ID(o))
+	testutil.MustDo(t, outcomeRouter(2), http.MethodPost, approve, nil)
+	o = testutil.MustDo(t, outcomeRouter(approver.ID), http.MethodPost, approve, nil)
+
+	if o["status"] != entity.OutcomeScheduled {
+		t.Fatalf("status after approvals = %v, want %s", o["status"], entity.OutcomeScheduled)
+	}
+	if got := dogStatus(t, dogID); got != entity.DogStatusShelter {
+		t.Errorf("dog status before the date = %s, want %s", got, entity.DogStatusShelter)
+	}
+	if code, _ := testutil.Do(t, outcomeRouter(1), http.MethodPost, fmt.Sprintf("/dogs/%d/outcomes", dogID), map[string]any{
+		"type": entity.OutcomeEuthanasia, "cause": "again", "attending_vet_id": 1,
+	}); code != http.StatusConflict {
+		t.Errorf("second request while scheduled = %d, want 409", code)
+	}
+
+	if n, err := FinalizeScheduledOutcomes(configs.DB(), time.Now()); err != nil || n != 0 {
+		t.Fatalf("finalize today = %d, %v; want 0", n, err)
+	}
+	if n, err := FinalizeScheduledOutcomes(configs.DB(), tomorrow); err != nil || n != 1 {
+		t.Fatalf("finalize on the date = %d, %v; want 1", n, err)
+	}
+	if got := dogStatus(t, dogID); got != entity.DogStatusDeceased {
+		t.Errorf("dog status on the date = %s, want %s", got, entity.DogStatusDeceased)
+	}
+}
+
+func TestScheduledEuthanasiaCanBeCancelled(t *testing.T) {
+	const dogID = 4
+	nextWeek := time.Now().In(timeutil.TZBangkok()).AddDate(0, 0, 7)
+	o := testutil.MustDo(t, outcomeRouter(1), http.MethodPost, fmt.Sprintf("/dogs/%d/outcomes", dogID), map[string]any{
+		"type": entity.OutcomeEuthanasia, "cause": "chronic pain",
+		"outcome_date": nextWeek.Format("2006-01-02"), "attending_vet_id": 1,
+	})
+	id := testutil.ID(o)
+	configs.DB().Model(&entity.DogOutcome{}).Where("id = ?", id).Update("status", entity.OutcomeScheduled)
+
+	o = testutil.MustDo(t, outcomeRouter(2), http.MethodPost, fmt.Sprintf("/outcomes/%d/reject", id), map[string]any{"comment": "responding to treatment"})
+	if o["status"] != entity.OutcomeRejected {
+		t.Errorf("status = %v, want %s", o["status"], entity.OutcomeRejected)
+	}
+	if n, _ := FinalizeScheduledOutcomes(configs.DB(), nextWeek); n != 0 {
+		t.Errorf("finalized %d cancelled outcomes", n)
+	}
+	if got := dogStatus(t, dogID); got != entity.DogStatusShelter {
+		t.Errorf("dog status = %s, want %s", got, entity.DogStatusShelter)
+	}
+}
@@ -0,0 +1,135 @@
+package partner
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/middlewares"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type partnerInput struct {
+	Name        *string `json:"name"`
+	ContactName *string `json:"contact_name"`
+	Phone       *string `json:"phone"`
+	Email       *string `json:"email"`
+	Address     *string `json:"address"`
+	Note        *string `json:"note"`
+}
+
+func apply(p *entity.PartnerOrganization, in partnerInput) {
+	if in.Name != nil {
+		p.Name = strings.TrimSpace(*in.Name)
+	}
+	if in.ContactName != nil {
+		p.ContactName = *in.ContactName
+	}
+	if in.Phone != nil {
+		p.Phone = *in.Phone
+	}
+	if in.Email != nil {
+		p.Email = *in.Email
+	}
+	if in.Address != nil {
+		p.Address = *in.Address
+	}
+	if in.Note != nil {
+		p.Note = in.Note
+	}
+}
+
+func nameTaken(name string, exceptID uint) (bool, error) {
+	var n int64
+	err := configs.DB().Model(&entity.PartnerOrganization{}).
+		Where("LOWER(name) = LOWER(?) AND id <> ?", name, exceptID).
+		Count(&n).Error
+	return n > 0, err
+}
+
+// GET /partners
+func GetAllPartners(c *gin.Context) {
+	var rows []entity.PartnerOrganization
+	if err := configs.DB().Order("name ASC").Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": rows})
+}
+
+// POST /partners
+func CreatePartner(c *gin.Context) {
+	if middlewares.StaffID(c) == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var in partnerInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	var p entity.PartnerOrganization
+	apply(&p, in)
+	if p.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+	taken, err := nameTaken(p.Name, 0)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	if taken {
+		c.JSON(http.StatusConflict, gin.H{"error": "partner name already exists"})
+		return
+	}
+	if err := configs.DB().Create(&p).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "create failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": p})
+}
+
+// PUT /partners/:id
+func UpdatePartner(c *gin.Context) {
+	if middlewares.StaffID(c) == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var p entity.PartnerOrganization
+	if err := configs.DB().First(&p, c.Param("id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "partner not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	var in partnerInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	apply(&p, in)
+	if p.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+	taken, err := nameTaken(p.Name, p.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	if taken {
+		c.JSON(http.StatusConflict, gin.H{"error": "partner name already exists"})
+		return
+	}
+	if err := configs.DB().Save(&p).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": p})
+}
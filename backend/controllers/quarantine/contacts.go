@@ -0,0 +1,187 @@
+package quarantine
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/middlewares"
+	"example.com/project-sa/utils/timeutil"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+/* ========== สุนัขที่เคยอยู่คอกเดียวกันในช่วงฟักตัว ========== */
+
+// stay ช่วงเวลาที่สุนัขอยู่คอกหนึ่ง (To = nil ยังอยู่)
+type stay struct {
+	KennelID uint
+	From     time.Time
+	To       *time.Time
+}
+
+type overlap struct {
+	KennelID uint           `json:"kennel_id"`
+	Kennel   *entity.Kennel `json:"kennel,omitempty"`
+	From     time.Time      `json:"from"`
+	To       time.Time      `json:"to"`
+}
+
+type contact struct {
+	DogID         uint        `json:"dog_id"`
+	Dog           *entity.Dog `json:"dog"`
+	Quarantined   bool        `json:"quarantined"` // กักโรคอยู่แล้ว
+	LastContactAt time.Time   `json:"last_contact_at"`
+	Overlaps      []overlap   `json:"overlaps"`
+}
+
+// kennelStays ช่วงที่สุนัขแต่ละตัวอยู่ในคอก จากสมุดย้ายคอก (ไม่นับช่วงที่ไม่มีคอก)
+func kennelStays(tx *gorm.DB, dogs []entity.Dog) (map[uint][]stay, error) {
+	ids := make([]uint, len(dogs))
+	for i, d := range dogs {
+		ids[i] = d.ID
+	}
+	moves, err := entity.LoadKennelMoves(tx, ids)
+	if err != nil {
+		return nil, err
+	}
+	out := map[uint][]stay{}
+	for _, d := range dogs {
+		for _, s := range entity.KennelStays(d, moves[d.ID]) {
+			if s.KennelID != nil {
+				out[d.ID] = append(out[d.ID], stay{KennelID: *s.KennelID, From: s.From, To: s.To})
+			}
+		}
+	}
+	return out, nil
+}
+
+// clip ตัดช่วงการอยู่คอกให้อยู่ในช่วง [from, to)
+func clip(s stay, from, to time.Time) (time.Time, time.Time, bool) {
+	a, b := s.From, to
+	if s.To != nil && s.To.Before(b) {
+		b = *s.To
+	}
+	if a.Before(from) {
+		a = from
+	}
+	return a, b, a.Before(b)
+}
+
+// GET /quarantines/:id/contacts — สุนัขที่อยู่คอกเดียวกันตั้งแต่ (วันเริ่ม - ระยะฟักตัว) จนพ้นกักโรค
+func GetContacts(c *gin.Context) {
+	if middlewares.StaffID(c) == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	q, ok := loadQuarantine(c)
+	if !ok {
+		return
+	}
+	db := configs.DB()
+	sd := q.StartDate.Time
+	windowFrom := time.Date(sd.Year(), sd.Month(), sd.Day(), 0, 0, 0, 0, timeutil.TZBangkok()).
+		AddDate(0, 0, -q.IncubationDays)
+	windowTo := time.Now()
+	if q.ClearedAt != nil {
+		windowTo = *q.ClearedAt
+	}
+
+	var sick entity.Dog
+	if err := db.Unscoped().First(&sick, q.DogID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	stays, err := kennelStays(db, []entity.Dog{sick})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	// คอกที่สุนัขป่วยอยู่ในช่วงที่ต้องตรวจ
+	sickStays := []stay{}
+	kennelSet := map[uint]bool{}
+	for _, s := range stays[sick.ID] {
+		a, b, ok := clip(s, windowFrom, windowTo)
+		if !ok {
+			continue
+		}
+		sickStays = append(sickStays, stay{KennelID: s.KennelID, From: a, To: &b})
+		kennelSet[s.KennelID] = true
+	}
+	if len(sickStays) == 0 {
+		c.JSON(http.StatusOK, gin.H{"data": []contact{}, "window_from": windowFrom, "window_to": windowTo})
+		return
+	}
+	kennelIDs := make([]uint, 0, len(kennelSet))
+	for id := range kennelSet {
+		kennelIDs = append(kennelIDs, id)
+	}
+
+	// ผู้ที่อาจสัมผัส: อยู่คอกนั้นตอนนี้ หรือเคยย้ายเข้า/ออกคอกนั้น
+	var dogs []entity.Dog
+	if err := db.Preload("Kennel").Preload("Kennel.Zone").
+		Where("id <> ?", sick.ID).
+		Where("kennel_id IN ? OR id IN (?)", kennelIDs, db.Model(&entity.KennelManagement{}).Select("dog_id").
+			Where("from_kennel_id IN ? OR to_kennel_id IN ?", kennelIDs, kennelIDs)).
+		Find(&dogs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	if len(dogs) == 0 {
+		c.JSON(http.StatusOK, gin.H{"data": []contact{}, "window_from": windowFrom, "window_to": windowTo})
+		return
+	}
+	others, err := kennelStays(db, dogs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+
+	var kennels []entity.Kennel
+	if err := db.Where("id IN ?", kennelIDs).Find(&kennels).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	kennelByID := map[uint]*entity.Kennel{}
+	for i := range kennels {
+		kennelByID[kennels[i].ID] = &kennels[i]
+	}
+	var quarantined []uint
+	if err := db.Model(&entity.QuarantineRecord{}).Where("status = ?", entity.QuarantineActive).
+		Pluck("dog_id", &quarantined).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	isQuarantined := map[uint]bool{}
+	for _, id := range quarantined {
+		isQuarantined[id] = true
+	}
+
+	out := []contact{}
+	for i := range dogs {
+		d := &dogs[i]
+		ct := contact{DogID: d.ID, Dog: d, Quarantined: isQuarantined[d.ID], Overlaps: []overlap{}}
+		for _, ss := range sickStays {
+			for _, os := range others[d.ID] {
+				if os.KennelID != ss.KennelID {
+					continue
+				}
+				a, b, ok := clip(os, ss.From, *ss.To)
+				if !ok {
+					continue
+				}
+				ct.Overlaps = append(ct.Overlaps, overlap{KennelID: os.KennelID, Kennel: kennelByID[os.KennelID], From: a, To: b})
+				if b.After(ct.LastContactAt) {
+					ct.LastContactAt = b
+				}
+			}
+		}
+		if len(ct.Overlaps) > 0 {
+			out = append(out, ct)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].LastContactAt.After(out[j].LastContactAt) })
+	c.JSON(http.StatusOK, gin.H{"data": out, "window_from": windowFrom, "window_to": windowTo})
+}
@@ -0,0 +1,70 @@
+package quarantine
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"testing"
+	"time"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/migrations"
+	"example.com/project-sa/seeds"
+	"example.com/project-sa/utils/testutil"
+)
+
+func TestMain(m *testing.M) { testutil.Main(m, migrations.AutoMigrate, seeds.SeedAll) }
+
+// ผู้สัมผัสต้องมาจากสมุดย้ายคอก: รวมสุนัขเก่าที่ไม่มีรายการย้าย และสุนัขที่ย้ายผ่านคอกโดยไม่มี DogChange
+func TestContactsFromKennelLedger(t *testing.T) {
+	db := configs.DB()
+	ago := func(h int) time.Time { return time.Now().UTC().Add(-time.Duration(h) * time.Hour) }
+	newKennel := func(name string) entity.Kennel {
+		k := entity.Kennel{Name: name, Capacity: 5, ZoneID: 1}
+		if err := db.Create(&k).Error; err != nil {
+			t.Fatal(err)
+		}
+		return k
+	}
+	newDog := func(name string, kennelID uint) entity.Dog {
+		d := entity.Dog{Name: name, KennelID: &kennelID, BreedID: 1, AnimalSexID: 1, AnimalSizeID: 1}
+		d.CreatedAt = ago(5)
+		if err := db.Create(&d).Error; err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	k1, k2 := newKennel("CT-1"), newKennel("CT-2")
+	sick := newDog("Sick", k1.ID)
+	roommate := newDog("Roommate", k1.ID) // ย้ายเข้ามาก่อนมีสมุดย้ายคอก
+	visitor := newDog("Visitor", k2.ID)   // เคยแวะคอก k1 สองชั่วโมง
+	stranger := newDog("Stranger", k2.ID)
+	for _, m := range []entity.KennelManagement{
+		{DogID: visitor.ID, FromKennelID: &k2.ID, ToKennelID: &k1.ID, MovedAt: ago(3), Action: entity.KennelMoveTransfer},
+		{DogID: visitor.ID, FromKennelID: &k1.ID, ToKennelID: &k2.ID, MovedAt: ago(1), Action: entity.KennelMoveTransfer},
+	} {
+		if err := db.Create(&m).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := testutil.Router(1)
+	r.POST("/dogs/:id/quarantines", CreateQuarantine)
+	r.GET("/quarantines/:id/contacts", GetContacts)
+	q := testutil.MustDo(t, r, http.MethodPost, fmt.Sprintf("/dogs/%d/quarantines", sick.ID), map[string]any{"condition": "parvo", "clearance_criteria": "negative antigen test"})
+
+	code, out := testutil.Do(t, r, http.MethodGet, fmt.Sprintf("/quarantines/%d/contacts", testutil.ID(q)), nil)
+	if code != http.StatusOK {
+		t.Fatalf("contacts = %d %v", code, out)
+	}
+	var got []uint
+	for _, row := range out["data"].([]any) {
+		got = append(got, uint(row.(map[string]any)["dog_id"].(float64)))
+	}
+	sort.Slice(got, func(i, j int) bool { return got[i] < got[j] })
+	want := []uint{roommate.ID, visitor.ID}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("contacts = %v, want %v (stranger %d must not be listed)", got, want, stranger.ID)
+	}
+}
@@ -0,0 +1,309 @@
+package quarantine
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/middlewares"
+	"example.com/project-sa/utils/timeutil"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+/* ========== กักโรค / โซนแยกโรค ========== */
+
+type quarantineRequest struct {
+	Condition         string `json:"condition" binding:"required"` // parvo | distemper | kennel_cough | other
+	StartDate         string `json:"start_date"`                   // YYYY-MM-DD (ว่าง = วันนี้)
+	IncubationDays    int    `json:"incubation_days" binding:"omitempty,min=1,max=60"`
+	ClearanceCriteria string `json:"clearance_criteria" binding:"required"`
+	Notes             string `json:"notes"`
+	MedicalRecordID   *uint  `json:"medical_record_id"`
+	KennelID          *uint  `json:"kennel_id"` // ย้ายเข้าคอกแยกโรคพร้อมกัน (ถ้าระบุ)
+}
+
+type clearRequest struct {
+	Note string `json:"note" binding:"required"` // ผลตรวจ/เหตุผลที่ผ่านเกณฑ์พ้นกักโรค
+}
+
+type isolationRequest struct {
+	IsIsolation *bool `json:"is_isolation" binding:"required"`
+}
+
+func preloadQuarantine(db *gorm.DB) *gorm.DB {
+	return db.Preload("Dog").Preload("Dog.Kennel").Preload("Dog.Kennel.Zone").
+		Preload("CreatedBy").Preload("ClearedBy")
+}
+
+func loadQuarantine(c *gin.Context) (*entity.QuarantineRecord, bool) {
+	var q entity.QuarantineRecord
+	if err := preloadQuarantine(configs.DB()).First(&q, c.Param("id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "quarantine not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return nil, false
+	}
+	return &q, true
+}
+
+// POST /dogs/:id/quarantines
+func CreateQuarantine(c *gin.Context) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req quarantineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	condition := strings.ToLower(strings.TrimSpace(req.Condition))
+	defaultDays, ok := entity.IncubationDays[condition]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "condition must be parvo, distemper, kennel_cough or other"})
+		return
+	}
+	if req.StartDate == "" {
+		req.StartDate = timeutil.TodayYMD()
+	}
+	start, err := entity.ParseDate(req.StartDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start_date (YYYY-MM-DD)"})
+		return
+	}
+	if start.String() > timeutil.TodayYMD() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "start_date is in the future"})
+		return
+	}
+	if req.IncubationDays == 0 {
+		req.IncubationDays = defaultDays
+	}
+
+	var q entity.QuarantineRecord
+	var status int
+	var warnings []entity.KennelRuleViolation
+	err = configs.DB().Transaction(func(tx *gorm.DB) error {
+		var dog entity.Dog
+		if err := tx.First(&dog, c.Param("id")).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				status = http.StatusNotFound
+				return errors.New("dog not found")
+			}
+			return err
+		}
+		var open int64
+		if err := tx.Model(&entity.QuarantineRecord{}).
+			Where("dog_id = ? AND status = ? AND condition = ?", dog.ID, entity.QuarantineActive, condition).
+			Count(&open).Error; err != nil {
+			return err
+		}
+		if open > 0 {
+			status = http.StatusConflict
+			return errors.New("dog is already quarantined for " + condition)
+		}
+		if req.MedicalRecordID != nil {
+			var n int64
+			if err := tx.Model(&entity.MedicalRecord{}).
+				Where("id = ? AND dog_id = ?", *req.MedicalRecordID, dog.ID).Count(&n).Error; err != nil {
+				return err
+			}
+			if n == 0 {
+				status = http.StatusBadRequest
+				return errors.New("invalid medical_record_id")
+			}
+		}
+
+		q = entity.QuarantineRecord{
+			DogID:             dog.ID,
+			MedicalRecordID:   req.MedicalRecordID,
+			Condition:         condition,
+			StartDate:         *start,
+			IncubationDays:    req.IncubationDays,
+			ClearanceCriteria: strings.TrimSpace(req.ClearanceCriteria),
+			Notes:             req.Notes,
+			Status:            entity.QuarantineActive,
+			CreatedByID:       staffID,
+		}
+		if err := tx.Create(&q).Error; err != nil {
+			return err
+		}
+
+		if req.KennelID == nil {
+			return nil
+		}
+		if dog.Status != entity.DogStatusShelter {
+			status = http.StatusConflict
+			return errors.New("dog is not in the shelter (status: " + dog.Status + ")")
+		}
+		kennel, err := entity.ReserveKennelSlot(tx, *req.KennelID, dog.ID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				status = http.StatusBadRequest
+				return errors.New("invalid kennel_id")
+			}
+			if entity.IsCapacityError(err) {
+				status = http.StatusConflict
+			}
+			return err
+		}
+		if err := entity.CheckKennelIsolation(tx, dog.ID, kennel.ID); err != nil {
+			if entity.IsIsolationError(err) {
+				status = http.StatusConflict
+			}
+			return err
+		}
+		if warnings, err = entity.CheckKennelRules(tx, dog.ID, kennel.ID); err != nil {
+			if entity.IsCompatibilityError(err) {
+				status = http.StatusConflict
+			}
+			return err
+		}
+		if err := tx.Model(&dog).Updates(map[string]any{
+			"kennel_id":     kennel.ID,
+			"updated_by_id": *staffID,
+		}).Error; err != nil {
+			return err
+		}
+		return entity.RecordDogKennelChange(tx, dog.ID, dog.KennelID, &kennel.ID, staffID, "quarantine")
+	})
+	if err != nil {
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, gin.H{"error": "create failed: " + err.Error()})
+		return
+	}
+
+	preloadQuarantine(configs.DB()).First(&q, q.ID)
+	// เตือนถ้าสุนัขยังอยู่คอกทั่วไป (ต้องย้ายเข้าโซนแยกโรค)
+	isolated := q.Dog != nil && q.Dog.Kennel != nil && q.Dog.Kennel.Isolated()
+	c.JSON(http.StatusCreated, gin.H{"data": q, "isolated": isolated, "kennel_warnings": warnings})
+}
+
+// GET /quarantines?status=active|cleared|all (ค่าเริ่มต้น active)
+func GetQuarantines(c *gin.Context) {
+	db := preloadQuarantine(configs.DB())
+	switch s := c.DefaultQuery("status", entity.QuarantineActive); s {
+	case entity.QuarantineActive, entity.QuarantineCleared:
+		db = db.Where("status = ?", s)
+	case "all":
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be active, cleared or all"})
+		return
+	}
+	if v := c.Query("condition"); v != "" {
+		db = db.Where("condition = ?", v)
+	}
+	var rows []entity.QuarantineRecord
+	if err := db.Order("start_date DESC, id DESC").Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": rows})
+}
+
+// GET /dogs/:id/quarantines
+func GetDogQuarantines(c *gin.Context) {
+	var rows []entity.QuarantineRecord
+	if err := configs.DB().Preload("CreatedBy").Preload("ClearedBy").
+		Where("dog_id = ?", c.Param("id")).
+		Order("start_date DESC, id DESC").Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": rows})
+}
+
+// GET /quarantines/:id
+func GetQuarantine(c *gin.Context) {
+	q, ok := loadQuarantine(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": q})
+}
+
+// POST /quarantines/:id/clear — ลงนามพ้นกักโรค (ต้องระบุผลตรวจตามเกณฑ์)
+func ClearQuarantine(c *gin.Context) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req clearRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	q, ok := loadQuarantine(c)
+	if !ok {
+		return
+	}
+	if q.Status != entity.QuarantineActive {
+		c.JSON(http.StatusConflict, gin.H{"error": "quarantine is already cleared"})
+		return
+	}
+	now := time.Now()
+	if err := configs.DB().Model(q).Updates(map[string]any{
+		"status":         entity.QuarantineCleared,
+		"cleared_at":     now,
+		"cleared_by_id":  *staffID,
+		"clearance_note": strings.TrimSpace(req.Note),
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed: " + err.Error()})
+		return
+	}
+	preloadQuarantine(configs.DB()).First(q, q.ID)
+	c.JSON(http.StatusOK, gin.H{"data": q})
+}
+
+// PUT /zones/:id/isolation { is_isolation }
+func SetZoneIsolation(c *gin.Context) {
+	if middlewares.StaffID(c) == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req isolationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	var zone entity.Zone
+	if err := configs.DB().First(&zone, c.Param("id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "zone not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	// ยกเลิกโซนแยกโรคไม่ได้ถ้ายังมีสุนัขกักโรคอยู่ในคอกของโซนนี้
+	if !*req.IsIsolation && zone.IsIsolation {
+		var n int64
+		if err := configs.DB().Model(&entity.Dog{}).
+			Joins("JOIN kennels ON kennels.id = dogs.kennel_id AND kennels.deleted_at IS NULL").
+			Where("kennels.zone_id = ? AND kennels.is_isolation = ?", zone.ID, false).
+			Where("dogs.id IN (?)", configs.DB().Model(&entity.QuarantineRecord{}).
+				Select("dog_id").Where("status = ?", entity.QuarantineActive)).
+			Count(&n).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+			return
+		}
+		if n > 0 {
+			c.JSON(http.StatusConflict, gin.H{"error": "zone still houses quarantined dogs"})
+			return
+		}
+	}
+	if err := configs.DB().Model(&zone).Update("is_isolation", *req.IsIsolation).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": zone})
+}
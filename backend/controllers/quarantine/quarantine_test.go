@@ -0,0 +1,81 @@
+package quarantine
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/controllers/zcmanagement"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/utils/testutil"
+	"github.com/gin-gonic/gin"
+)
+
+func TestQuarantineBlocksGeneralKennels(t *testing.T) {
+	db := configs.DB()
+	iso := entity.Zone{Name: "Isolation", IsIsolation: true}
+	if err := db.Create(&iso).Error; err != nil {
+		t.Fatal(err)
+	}
+	isoKennel := entity.Kennel{Name: "ISO-1", Capacity: 2, ZoneID: iso.ID}
+	general := entity.Kennel{Name: "GEN-1", Capacity: 2, ZoneID: 1}
+	for _, k := range []*entity.Kennel{&isoKennel, &general} {
+		if err := db.Create(k).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	dog := entity.Dog{Name: "Parvo", BreedID: 1, AnimalSexID: 1, AnimalSizeID: 1, Status: entity.DogStatusShelter}
+	if err := db.Create(&dog).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	r := testutil.Router(1)
+	r.POST("/dogs/:id/quarantines", CreateQuarantine)
+	r.POST("/quarantines/:id/clear", ClearQuarantine)
+	r.PUT("/zones/:id/isolation", SetZoneIsolation)
+	r.PUT("/kennels/:id/dog", zcmanagement.UpdateDogInKennel)
+
+	create := fmt.Sprintf("/dogs/%d/quarantines", dog.ID)
+	body := func(kennelID uint) gin.H {
+		return gin.H{"condition": "parvo", "clearance_criteria": "ตรวจ parvo ลบ 2 ครั้ง", "kennel_id": kennelID}
+	}
+	if code, _ := testutil.Do(t, r, http.MethodPost, create, body(general.ID)); code != http.StatusConflict {
+		t.Errorf("quarantine into a general kennel = %d, want %d", code, http.StatusConflict)
+	}
+	var n int64
+	db.Model(&entity.QuarantineRecord{}).Where("dog_id = ?", dog.ID).Count(&n)
+	if n != 0 {
+		t.Errorf("quarantine rows after a refused move = %d, want 0 (rolled back)", n)
+	}
+
+	code, out := testutil.Do(t, r, http.MethodPost, create, body(isoKennel.ID))
+	if code != http.StatusCreated || out["isolated"] != true {
+		t.Fatalf("quarantine into isolation kennel = %d %v, want 201 isolated", code, out)
+	}
+	qID := testutil.ID(out["data"].(map[string]any))
+	if code, _ := testutil.Do(t, r, http.MethodPost, create, body(isoKennel.ID)); code != http.StatusConflict {
+		t.Errorf("second active parvo quarantine = %d, want %d", code, http.StatusConflict)
+	}
+
+	move := fmt.Sprintf("/kennels/%d/dog", general.ID)
+	if code, _ := testutil.Do(t, r, http.MethodPut, move, gin.H{"dog_id": dog.ID}); code != http.StatusConflict {
+		t.Errorf("move quarantined dog to general kennel = %d, want %d", code, http.StatusConflict)
+	}
+	if code, _ := testutil.Do(t, r, http.MethodPut, fmt.Sprintf("/zones/%d/isolation", iso.ID), gin.H{"is_isolation": false}); code != http.StatusConflict {
+		t.Errorf("unflag isolation zone with a quarantined dog = %d, want %d", code, http.StatusConflict)
+	}
+
+	clear := fmt.Sprintf("/quarantines/%d/clear", qID)
+	if code, _ := testutil.Do(t, r, http.MethodPost, clear, gin.H{}); code != http.StatusBadRequest {
+		t.Errorf("clear without sign-off note = %d, want %d", code, http.StatusBadRequest)
+	}
+	data := testutil.MustDo(t, r, http.MethodPost, clear, gin.H{"note": "ผลตรวจลบ 2 ครั้ง"})
+	if data["status"] != entity.QuarantineCleared || data["cleared_by_id"] != float64(1) {
+		t.Errorf("cleared quarantine = %v, want cleared by staff 1", data)
+	}
+	if code, _ := testutil.Do(t, r, http.MethodPost, clear, gin.H{"note": "again"}); code != http.StatusConflict {
+		t.Errorf("clear twice = %d, want %d", code, http.StatusConflict)
+	}
+	testutil.MustDo(t, r, http.MethodPut, move, gin.H{"dog_id": dog.ID})
+}
@@ -7,6 +7,7 @@ import (
 
 	"example.com/project-sa/configs"
 	"example.com/project-sa/entity"
+	"example.com/project-sa/middlewares"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
@@ -18,7 +19,7 @@ type deleteSponsorshipResp struct {
 }
 
 func DeleteSponsorship(c *gin.Context) {
-	sid := getStaffIDFromCtx(c)
+	sid := middlewares.StaffID(c)
 	if sid == nil {
 		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "forbidden"})
 		return
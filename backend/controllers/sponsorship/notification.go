@@ -0,0 +1,67 @@
+package sponsorship
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// แจ้งเตือนของผู้ใช้ (ผ่าน sponsor ที่ผูกกับ user)
+func myNotifications(uid uint) *gorm.DB {
+	return configs.DB().Model(&entity.SponsorNotification{}).
+		Joins("JOIN sponsors s ON s.id = sponsor_notifications.sponsor_id").
+		Where("s.kind = ? AND s.user_id = ?", entity.SponsorKindUser, uid)
+}
+
+// GET /sponsorships/notifications/my?unread=true
+func GetMyNotifications(c *gin.Context) {
+	uid := getUserIDFromCtx(c)
+	if uid == nil || *uid == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	db := myNotifications(*uid).Preload("Dog")
+	if c.Query("unread") == "true" {
+		db = db.Where("sponsor_notifications.read_at IS NULL")
+	}
+	var rows []entity.SponsorNotification
+	if err := db.Order("sponsor_notifications.id DESC").Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": rows})
+}
+
+// POST /sponsorships/notifications/:id/read
+func MarkNotificationRead(c *gin.Context) {
+	uid := getUserIDFromCtx(c)
+	if uid == nil || *uid == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	var n entity.SponsorNotification
+	if err := myNotifications(*uid).
+		Where("sponsor_notifications.id = ?", c.Param("id")).
+		First(&n).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if n.ReadAt == nil {
+		now := time.Now()
+		if err := configs.DB().Model(&n).Update("read_at", now).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		n.ReadAt = &now
+	}
+	c.JSON(http.StatusOK, gin.H{"data": n})
+}
@@ -298,9 +298,19 @@ func ensurePaymentMethod(tx *gorm.DB, id uint) error {
 	var pm entity.PaymentMethod
 	return tx.First(&pm, id).Error
 }
+
+// errDogDeceased: ไม่รับการอุปถัมภ์ใหม่สำหรับสุนัขที่เสียชีวิตแล้ว
+var errDogDeceased = errors.New("dog is deceased")
+
 func ensureDog(tx *gorm.DB, id uint) error {
 	var dog entity.Dog
-	return tx.First(&dog, id).Error
+	if err := tx.First(&dog, id).Error; err != nil {
+		return err
+	}
+	if dog.Status == entity.DogStatusDeceased {
+		return errDogDeceased
+	}
+	return nil
 }
 
 func safeDogName(sp entity.Sponsorship) string {
@@ -394,6 +404,10 @@ func CreateOneTimeSponsorship(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
 			return
 		}
+		if errors.Is(err, errDogDeceased) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 	}
 }
@@ -495,6 +509,10 @@ func CreateSubscriptionSponsorship(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
 			return
 		}
+		if errors.Is(err, errDogDeceased) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 	}
 }
@@ -162,6 +162,16 @@ func ReactivateSubscription(c *gin.Context) {
 		return
 	}
 
+	// สุนัขเสียชีวิตแล้ว -> ไม่เปิดการอุปถัมภ์รายงวดกลับมา
+	if err := ensureDog(db, sp.DogID); err != nil {
+		if errors.Is(err, errDogDeceased) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		return
+	}
+
 	// ------- แกะ body -------
 	var req reactivateReq
 	if err := c.ShouldBindJSON(&req); err != nil && !strings.Contains(err.Error(), "EOF") {
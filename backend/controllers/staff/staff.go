@@ -25,6 +25,7 @@ func staffResp(s entity.Staff) gin.H {
 		"photo_url":     s.PhotoURL,
 		"note":          s.Note,
 		"status":        s.Status,
+		"role":          s.Role,
 		"gender":        s.Gender, // object
 		"zone":          s.Zone,   // object
 	}
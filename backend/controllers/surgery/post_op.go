@@ -0,0 +1,100 @@
+package surgery
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/middlewares"
+	"example.com/project-sa/utils/timeutil"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+/* ========== งานตรวจหลังผ่าตัด ========== */
+
+type postOpCheckView struct {
+	entity.PostOpCheck
+	Overdue bool `json:"overdue"`
+}
+
+// GET /post-op-checks?status=pending|done|cancelled|all&due_before=YYYY-MM-DD&dog_id=
+// due_before ว่าง = ทั้งหมด; รายการ pending ที่เลยกำหนดจะมี overdue=true
+func GetPostOpChecks(c *gin.Context) {
+	db := configs.DB().Preload("Dog").Preload("Surgery").Preload("CompletedBy")
+	switch v := c.DefaultQuery("status", entity.PostOpPending); v {
+	case entity.PostOpPending, entity.PostOpDone, entity.PostOpCancelled:
+		db = db.Where("status = ?", v)
+	case "all":
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be pending, done, cancelled or all"})
+		return
+	}
+	if v := c.Query("due_before"); v != "" {
+		d, err := entity.ParseDate(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid due_before (YYYY-MM-DD)"})
+			return
+		}
+		db = db.Where("due_date <= ?", d)
+	}
+	if v := c.Query("dog_id"); v != "" {
+		db = db.Where("dog_id = ?", v)
+	}
+	var rows []entity.PostOpCheck
+	if err := db.Order("due_date ASC, id ASC").Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	today := timeutil.TodayYMD()
+	out := make([]postOpCheckView, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, postOpCheckView{
+			PostOpCheck: r,
+			Overdue:     r.Status == entity.PostOpPending && r.DueDate.String() < today,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"data": out})
+}
+
+// POST /post-op-checks/:id/complete { findings, concern }
+func CompletePostOpCheck(c *gin.Context) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req checkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	var chk entity.PostOpCheck
+	if err := configs.DB().First(&chk, c.Param("id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "post-op check not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	if chk.Status != entity.PostOpPending {
+		c.JSON(http.StatusConflict, gin.H{"error": "post-op check is " + chk.Status})
+		return
+	}
+	if err := configs.DB().Model(&chk).Updates(map[string]any{
+		"status":          entity.PostOpDone,
+		"findings":        strings.TrimSpace(req.Findings),
+		"concern":         req.Concern,
+		"completed_at":    time.Now(),
+		"completed_by_id": *staffID,
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed: " + err.Error()})
+		return
+	}
+	configs.DB().Preload("Dog").Preload("Surgery").Preload("CompletedBy").First(&chk, chk.ID)
+	c.JSON(http.StatusOK, gin.H{"data": chk})
+}
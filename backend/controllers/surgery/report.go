@@ -0,0 +1,149 @@
+package surgery
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/utils/timeutil"
+	"github.com/gin-gonic/gin"
+)
+
+/* ========== รายงานอัตราการทำหมัน ========== */
+
+type rateRow struct {
+	Key        string  `json:"key"`
+	Total      int     `json:"total"`
+	Sterilized int     `json:"sterilized"`
+	Scheduled  int     `json:"scheduled"` // ยังไม่ทำหมัน แต่มีนัดผ่าตัดแล้ว
+	Rate       float64 `json:"rate"`      // % ของ total
+}
+
+func (r *rateRow) add(sterilized, scheduled bool) {
+	r.Total++
+	if sterilized {
+		r.Sterilized++
+	} else if scheduled {
+		r.Scheduled++
+	}
+}
+
+func (r *rateRow) finish() {
+	if r.Total > 0 {
+		r.Rate = float64(r.Sterilized*1000/r.Total) / 10
+	}
+}
+
+func sortedRows(m map[string]*rateRow) []rateRow {
+	out := make([]rateRow, 0, len(m))
+	for _, r := range m {
+		r.finish()
+		out = append(out, *r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out
+}
+
+// GET /reports/sterilization?from=&to=
+// สุนัขที่อยู่ในความดูแล (ในศูนย์/บ้านอุปถัมภ์) แยกตามเพศ ขนาด โซน
+// + จำนวนการทำหมันที่ทำเสร็จในช่วง from–to (ค่าเริ่มต้น 30 วันล่าสุด)
+func GetSterilizationReport(c *gin.Context) {
+	loc := timeutil.TZBangkok()
+	today, _ := time.ParseInLocation("2006-01-02", timeutil.TodayYMD(), loc)
+	from, to := today.AddDate(0, 0, -29), today
+	for _, p := range []struct {
+		name string
+		dst  *time.Time
+	}{{"from", &from}, {"to", &to}} {
+		if v := c.Query(p.name); v != "" {
+			d, err := time.ParseInLocation("2006-01-02", v, loc)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + p.name + " (YYYY-MM-DD)"})
+				return
+			}
+			*p.dst = d
+		}
+	}
+	if to.Before(from) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must not be before from"})
+		return
+	}
+
+	var dogs []entity.Dog
+	if err := configs.DB().Preload("AnimalSex").Preload("AnimalSize").Preload("Kennel.Zone").
+		Where("is_adopted = ? AND status IN ?", false, []string{entity.DogStatusShelter, entity.DogStatusFoster}).
+		Find(&dogs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	var scheduledIDs []uint
+	if err := configs.DB().Model(&entity.SurgeryRecord{}).
+		Where("status = ? AND procedure IN ?", entity.SurgeryScheduled, []string{entity.ProcedureSpay, entity.ProcedureNeuter}).
+		Distinct().Pluck("dog_id", &scheduledIDs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	scheduled := map[uint]bool{}
+	for _, id := range scheduledIDs {
+		scheduled[id] = true
+	}
+
+	overall := rateRow{Key: "all"}
+	bySex, bySize, byZone := map[string]*rateRow{}, map[string]*rateRow{}, map[string]*rateRow{}
+	group := func(m map[string]*rateRow, key string) *rateRow {
+		if m[key] == nil {
+			m[key] = &rateRow{Key: key}
+		}
+		return m[key]
+	}
+	var unsterilized []entity.Dog
+	for _, d := range dogs {
+		st, sch := d.SterilizedAt != nil, scheduled[d.ID]
+		overall.add(st, sch)
+
+		sex, size, zone := "ไม่ระบุ", "ไม่ระบุ", "ไม่มีคอก"
+		if d.AnimalSex != nil {
+			sex = d.AnimalSex.Name
+		}
+		if d.AnimalSize != nil {
+			size = d.AnimalSize.Name
+		}
+		if d.Status == entity.DogStatusFoster {
+			zone = "บ้านอุปถัมภ์"
+		} else if d.Kennel != nil && d.Kennel.Zone != nil {
+			zone = d.Kennel.Zone.Name
+		}
+		group(bySex, sex).add(st, sch)
+		group(bySize, size).add(st, sch)
+		group(byZone, zone).add(st, sch)
+
+		if !st && !sch {
+			unsterilized = append(unsterilized, d)
+		}
+	}
+	overall.finish()
+
+	var performed int64
+	if err := configs.DB().Model(&entity.SurgeryRecord{}).
+		Where("status = ? AND procedure IN ?", entity.SurgeryCompleted, []string{entity.ProcedureSpay, entity.ProcedureNeuter}).
+		Where("performed_at >= ? AND performed_at < ?", from.UTC(), to.AddDate(0, 0, 1).UTC()).
+		Count(&performed).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{
+		"overall": overall,
+		"by_sex":  sortedRows(bySex),
+		"by_size": sortedRows(bySize),
+		"by_zone": sortedRows(byZone),
+		"performed": gin.H{
+			"from":  from.Format("2006-01-02"),
+			"to":    to.Format("2006-01-02"),
+			"count": performed,
+		},
+		"unsterilized_unscheduled": unsterilized,
+	}})
+}
@@ -0,0 +1,511 @@
+package surgery
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"example.com/project-sa/configs"
+	health_records "example.com/project-sa/controllers/health_record"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/middlewares"
+	"example.com/project-sa/utils/timeutil"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+/* ========== บันทึกการผ่าตัด / ทำหมัน ========== */
+
+var procedures = map[string]bool{
+	entity.ProcedureSpay:        true,
+	entity.ProcedureNeuter:      true,
+	entity.ProcedureDental:      true,
+	entity.ProcedureMassRemoval: true,
+	entity.ProcedureOrthopedic:  true,
+	entity.ProcedureWoundRepair: true,
+	entity.ProcedureOther:       true,
+}
+
+type surgeryRequest struct {
+	Procedure          string `json:"procedure" binding:"required"`
+	SurgeonID          uint   `json:"surgeon_id" binding:"required"`
+	ScheduledAt        string `json:"scheduled_at"` // RFC3339 หรือ "YYYY-MM-DD HH:MM" (เวลาไทย)
+	Description        string `json:"description"`
+	AnesthesiaProtocol string `json:"anesthesia_protocol"`
+	Notes              string `json:"notes"`
+}
+
+type updateRequest struct {
+	SurgeonID          *uint   `json:"surgeon_id"`
+	ScheduledAt        *string `json:"scheduled_at"`
+	Description        *string `json:"description"`
+	AnesthesiaProtocol *string `json:"anesthesia_protocol"`
+	Notes              *string `json:"notes"`
+}
+
+type completeRequest struct {
+	PerformedAt        string  `json:"performed_at"` // ว่าง = ตอนนี้
+	AnesthesiaProtocol *string `json:"anesthesia_protocol"`
+	AnesthesiaNotes    string  `json:"anesthesia_notes"`
+	Complications      string  `json:"complications"`
+	Diagnosis          string  `json:"diagnosis"`
+	Notes              string  `json:"notes"`
+	Weight             float64 `json:"weight"`
+	Temperature        float64 `json:"temperature"`
+}
+
+type cancelRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+type checkRequest struct {
+	Findings string `json:"findings" binding:"required"`
+	Concern  bool   `json:"concern"`
+}
+
+func parseTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.UTC(), nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02T15:04"} {
+		if t, err := time.ParseInLocation(layout, s, timeutil.TZBangkok()); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, errors.New("invalid time (RFC3339 or YYYY-MM-DD HH:MM)")
+}
+
+func preloadSurgery(db *gorm.DB) *gorm.DB {
+	return db.Preload("Dog").Preload("Surgeon").Preload("CreatedBy").
+		Preload("PostOpChecks", func(db *gorm.DB) *gorm.DB { return db.Order("due_date ASC") })
+}
+
+func loadStaff(tx *gorm.DB, id uint) error {
+	var s entity.Staff
+	if err := tx.First(&s, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("invalid surgeon_id")
+		}
+		return err
+	}
+	return nil
+}
+
+func respondError(c *gin.Context, status int, prefix string, err error) {
+	var fields health_records.FieldErrors
+	if errors.As(err, &fields) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": prefix + "invalid health record", "fields": fields})
+		return
+	}
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+	c.JSON(status, gin.H{"error": prefix + err.Error()})
+}
+
+// checkDogForSurgery ตรวจว่าสุนัขยังอยู่ในความดูแล และการทำหมันตรงกับเพศ (ไม่ทราบเพศ = ไม่ตรวจ)
+// คืนข้อความเมื่อผ่าตัดไม่ได้ (409) แยกจาก error ของ DB
+func checkDogForSurgery(tx *gorm.DB, dog entity.Dog, procedure string) (string, error) {
+	if dog.IsAdopted || (dog.Status != entity.DogStatusShelter && dog.Status != entity.DogStatusFoster) {
+		return "dog is not in care", nil
+	}
+	if !entity.IsSterilization(procedure) {
+		return "", nil
+	}
+	if dog.SterilizedAt != nil {
+		return "dog is already sterilized (" + dog.SterilizedAt.String() + ")", nil
+	}
+	var sex entity.AnimalSex
+	if err := tx.Select("id", "name").Limit(1).Find(&sex, dog.AnimalSexID).Error; err != nil {
+		return "", err
+	}
+	if want := sex.SterilizationProcedure(); want != "" && want != procedure {
+		return procedure + " does not match the dog's sex (" + sex.Name + ")", nil
+	}
+	return "", nil
+}
+
+// POST /dogs/:id/surgeries — นัดผ่าตัด
+func CreateSurgery(c *gin.Context) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req surgeryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	if !procedures[req.Procedure] {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "procedure must be spay, neuter, dental, mass_removal, orthopedic, wound_repair or other"})
+		return
+	}
+	var scheduledAt *time.Time
+	if req.ScheduledAt != "" {
+		t, err := parseTime(req.ScheduledAt)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid scheduled_at (RFC3339 or YYYY-MM-DD HH:MM)"})
+			return
+		}
+		scheduledAt = &t
+	}
+
+	var s entity.SurgeryRecord
+	var status int
+	err := configs.DB().Transaction(func(tx *gorm.DB) error {
+		var dog entity.Dog
+		if err := tx.First(&dog, c.Param("id")).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				status = http.StatusNotFound
+				return errors.New("dog not found")
+			}
+			return err
+		}
+		if msg, err := checkDogForSurgery(tx, dog, req.Procedure); err != nil {
+			return err
+		} else if msg != "" {
+			status = http.StatusConflict
+			return errors.New(msg)
+		}
+		if entity.IsSterilization(req.Procedure) {
+			var open int64
+			if err := tx.Model(&entity.SurgeryRecord{}).
+				Where("dog_id = ? AND status = ? AND procedure IN ?", dog.ID, entity.SurgeryScheduled,
+					[]string{entity.ProcedureSpay, entity.ProcedureNeuter}).
+				Count(&open).Error; err != nil {
+				return err
+			}
+			if open > 0 {
+				status = http.StatusConflict
+				return errors.New("a sterilization is already scheduled for this dog")
+			}
+		}
+		if err := loadStaff(tx, req.SurgeonID); err != nil {
+			status = http.StatusBadRequest
+			return err
+		}
+		s = entity.SurgeryRecord{
+			DogID:              dog.ID,
+			Procedure:          req.Procedure,
+			Description:        req.Description,
+			SurgeonID:          req.SurgeonID,
+			AnesthesiaProtocol: req.AnesthesiaProtocol,
+			Notes:              req.Notes,
+			ScheduledAt:        scheduledAt,
+			Status:             entity.SurgeryScheduled,
+			CreatedByID:        staffID,
+		}
+		return tx.Create(&s).Error
+	})
+	if err != nil {
+		respondError(c, status, "create failed: ", err)
+		return
+	}
+	preloadSurgery(configs.DB()).First(&s, s.ID)
+	c.JSON(http.StatusCreated, gin.H{"data": s})
+}
+
+// GET /surgeries?status=&procedure=&from=&to= (from/to = วันที่นัด/วันที่ผ่าตัด YYYY-MM-DD)
+func GetSurgeries(c *gin.Context) {
+	db := preloadSurgery(configs.DB())
+	if v := c.Query("status"); v != "" {
+		db = db.Where("status = ?", v)
+	}
+	if v := c.Query("procedure"); v != "" {
+		db = db.Where("procedure = ?", v)
+	}
+	loc := timeutil.TZBangkok()
+	for _, p := range []struct{ param, cond string }{
+		{"from", "COALESCE(performed_at, scheduled_at) >= ?"},
+		{"to", "COALESCE(performed_at, scheduled_at) < ?"},
+	} {
+		v := c.Query(p.param)
+		if v == "" {
+			continue
+		}
+		d, err := time.ParseInLocation("2006-01-02", v, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + p.param + " (YYYY-MM-DD)"})
+			return
+		}
+		if p.param == "to" {
+			d = d.AddDate(0, 0, 1)
+		}
+		db = db.Where(p.cond, d.UTC())
+	}
+	var rows []entity.SurgeryRecord
+	if err := db.Order("COALESCE(performed_at, scheduled_at, created_at) DESC, id DESC").Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": rows})
+}
+
+// GET /dogs/:id/surgeries
+func GetDogSurgeries(c *gin.Context) {
+	var rows []entity.SurgeryRecord
+	if err := preloadSurgery(configs.DB()).Where("dog_id = ?", c.Param("id")).
+		Order("COALESCE(performed_at, scheduled_at, created_at) DESC, id DESC").Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": rows})
+}
+
+func loadSurgery(c *gin.Context) (*entity.SurgeryRecord, bool) {
+	var s entity.SurgeryRecord
+	if err := preloadSurgery(configs.DB()).Preload("MedicalRecord").First(&s, c.Param("id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "surgery not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return nil, false
+	}
+	return &s, true
+}
+
+// GET /surgeries/:id
+func GetSurgery(c *gin.Context) {
+	s, ok := loadSurgery(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": s})
+}
+
+// PUT /surgeries/:id — แก้นัดที่ยังไม่ผ่าตัด
+func UpdateSurgery(c *gin.Context) {
+	if middlewares.StaffID(c) == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req updateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	s, ok := loadSurgery(c)
+	if !ok {
+		return
+	}
+	if s.Status != entity.SurgeryScheduled {
+		c.JSON(http.StatusConflict, gin.H{"error": "surgery is " + s.Status})
+		return
+	}
+	updates := map[string]any{}
+	if req.SurgeonID != nil {
+		if err := loadStaff(configs.DB(), *req.SurgeonID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		updates["surgeon_id"] = *req.SurgeonID
+	}
+	if req.ScheduledAt != nil {
+		if *req.ScheduledAt == "" {
+			updates["scheduled_at"] = nil
+		} else {
+			t, err := parseTime(*req.ScheduledAt)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid scheduled_at (RFC3339 or YYYY-MM-DD HH:MM)"})
+				return
+			}
+			updates["scheduled_at"] = t
+		}
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if req.AnesthesiaProtocol != nil {
+		updates["anesthesia_protocol"] = *req.AnesthesiaProtocol
+	}
+	if req.Notes != nil {
+		updates["notes"] = *req.Notes
+	}
+	if len(updates) > 0 {
+		if err := configs.DB().Model(s).Updates(updates).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed: " + err.Error()})
+			return
+		}
+	}
+	preloadSurgery(configs.DB()).First(s, s.ID)
+	c.JSON(http.StatusOK, gin.H{"data": s})
+}
+
+// POST /surgeries/:id/cancel { reason }
+func CancelSurgery(c *gin.Context) {
+	if middlewares.StaffID(c) == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req cancelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	s, ok := loadSurgery(c)
+	if !ok {
+		return
+	}
+	if s.Status != entity.SurgeryScheduled {
+		c.JSON(http.StatusConflict, gin.H{"error": "surgery is " + s.Status})
+		return
+	}
+	if err := configs.DB().Model(s).Updates(map[string]any{
+		"status":        entity.SurgeryCancelled,
+		"cancelled_at":  time.Now(),
+		"cancel_reason": strings.TrimSpace(req.Reason),
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed: " + err.Error()})
+		return
+	}
+	preloadSurgery(configs.DB()).First(s, s.ID)
+	c.JSON(http.StatusOK, gin.H{"data": s})
+}
+
+// POST /surgeries/:id/complete — บันทึกผลผ่าตัด สร้างประวัติสุขภาพ + นัดตรวจหลังผ่าตัด
+// ทำหมันเสร็จ = ตั้งวันทำหมันของสุนัข
+func CompleteSurgery(c *gin.Context) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req completeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	performedAt := time.Now().UTC()
+	if req.PerformedAt != "" {
+		t, err := parseTime(req.PerformedAt)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid performed_at (RFC3339 or YYYY-MM-DD HH:MM)"})
+			return
+		}
+		if t.After(time.Now()) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "performed_at is in the future"})
+			return
+		}
+		performedAt = t
+	}
+
+	var s entity.SurgeryRecord
+	var status int
+	err := configs.DB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&s, c.Param("id")).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				status = http.StatusNotFound
+				return errors.New("surgery not found")
+			}
+			return err
+		}
+		if s.Status != entity.SurgeryScheduled {
+			status = http.StatusConflict
+			return errors.New("surgery is " + s.Status)
+		}
+		var dog entity.Dog
+		if err := tx.First(&dog, s.DogID).Error; err != nil {
+			return err
+		}
+		// สุนัขอาจถูกรับเลี้ยง/เปลี่ยนข้อมูลเพศระหว่างนัดกับวันผ่าตัด
+		if msg, err := checkDogForSurgery(tx, dog, s.Procedure); err != nil {
+			return err
+		} else if msg != "" {
+			status = http.StatusConflict
+			return errors.New(msg)
+		}
+		sterilization := entity.IsSterilization(s.Procedure)
+
+		protocol := s.AnesthesiaProtocol
+		if req.AnesthesiaProtocol != nil {
+			protocol = *req.AnesthesiaProtocol
+		}
+		symptoms := s.Description
+		if strings.TrimSpace(symptoms) == "" {
+			symptoms = s.Procedure
+		}
+		notes := "Surgery #" + strconv.FormatUint(uint64(s.ID), 10)
+		if req.Complications != "" {
+			notes += "\nComplications: " + req.Complications
+		}
+		if req.Notes != "" {
+			notes += "\n" + req.Notes
+		}
+		record := entity.MedicalRecord{
+			DateRecord:    performedAt,
+			Weight:        req.Weight,
+			Temperature:   req.Temperature,
+			Symptoms:      symptoms,
+			Diagnosis:     req.Diagnosis,
+			TreatmentPlan: "Surgery: " + s.Procedure,
+			Medication:    protocol,
+			Vaccination:   entity.VaccinationNo,
+			Notes:         notes,
+			DogID:         s.DogID,
+			StaffID:       s.SurgeonID,
+		}
+		if err := health_records.CreateRecord(tx, &record, nil); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&s).Updates(map[string]any{
+			"status":              entity.SurgeryCompleted,
+			"performed_at":        performedAt,
+			"anesthesia_protocol": protocol,
+			"anesthesia_notes":    req.AnesthesiaNotes,
+			"complications":       req.Complications,
+			"medical_record_id":   record.ID,
+		}).Error; err != nil {
+			return err
+		}
+
+		// นัดตรวจหลังผ่าตัด นับจากวันผ่าตัด (เวลาไทย)
+		day := entity.NewDate(performedAt.In(timeutil.TZBangkok()))
+		schedule := entity.PostOpSchedule(s.Procedure)
+		days := make([]int, 0, len(schedule))
+		for d := range schedule {
+			days = append(days, d)
+		}
+		sort.Ints(days)
+		for _, d := range days {
+			if err := tx.Create(&entity.PostOpCheck{
+				SurgeryID: s.ID,
+				DogID:     s.DogID,
+				DayAfter:  d,
+				DueDate:   entity.Date{Time: day.AddDate(0, 0, d)},
+				Title:     schedule[d],
+				Status:    entity.PostOpPending,
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		if !sterilization {
+			return nil
+		}
+		if err := tx.Model(&dog).Updates(map[string]any{
+			"sterilized_at": day,
+			"updated_by_id": *staffID,
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&entity.DogDateIssue{}).
+			Where("dog_id = ? AND field = ? AND resolved_at IS NULL", dog.ID, "sterilized_at").
+			Update("resolved_at", time.Now()).Error; err != nil {
+			return err
+		}
+		// ให้ขึ้นในประวัติการแก้ไขสุนัขด้วย
+		return entity.RecordDogChange(tx, dog.ID, "sterilized_at", dog.SterilizedAt, day, staffID)
+	})
+	if err != nil {
+		respondError(c, status, "complete failed: ", err)
+		return
+	}
+	preloadSurgery(configs.DB()).Preload("MedicalRecord").First(&s, s.ID)
+	c.JSON(http.StatusOK, gin.H{"data": s})
+}
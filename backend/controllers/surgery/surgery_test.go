@@ -0,0 +1,75 @@
+package surgery
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/migrations"
+	"example.com/project-sa/seeds"
+	"example.com/project-sa/utils/testutil"
+	"github.com/gin-gonic/gin"
+)
+
+func TestMain(m *testing.M) { testutil.Main(m, migrations.AutoMigrate, seeds.SeedAll) }
+
+func TestCompleteSpaySetsSterilizedDateAndPostOpChecks(t *testing.T) {
+	db := configs.DB()
+	var female entity.AnimalSex
+	if err := db.Where("name = ?", entity.AnimalSexFemale).First(&female).Error; err != nil {
+		t.Fatalf("seeded sex: %v", err)
+	}
+	dog := entity.Dog{Name: "Spay", BreedID: 1, AnimalSexID: female.ID, AnimalSizeID: 1, Status: entity.DogStatusShelter}
+	if err := db.Create(&dog).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	r := testutil.Router(1)
+	r.POST("/dogs/:id/surgeries", CreateSurgery)
+	r.POST("/surgeries/:id/complete", CompleteSurgery)
+	r.GET("/reports/sterilization", GetSterilizationReport)
+
+	create := fmt.Sprintf("/dogs/%d/surgeries", dog.ID)
+	if code, _ := testutil.Do(t, r, http.MethodPost, create, gin.H{"procedure": entity.ProcedureNeuter, "surgeon_id": 1}); code != http.StatusConflict {
+		t.Errorf("neuter a female = %d, want %d", code, http.StatusConflict)
+	}
+	data := testutil.MustDo(t, r, http.MethodPost, create, gin.H{
+		"procedure": entity.ProcedureSpay, "surgeon_id": 1, "scheduled_at": "2025-06-01 09:00",
+	})
+	complete := fmt.Sprintf("/surgeries/%d/complete", testutil.ID(data))
+	if code, _ := testutil.Do(t, r, http.MethodPost, create, gin.H{"procedure": entity.ProcedureSpay, "surgeon_id": 1}); code != http.StatusConflict {
+		t.Errorf("second scheduled spay = %d, want %d", code, http.StatusConflict)
+	}
+
+	// 01:00 เวลาไทยของวันที่ 1 มิ.ย. (ยังเป็นวันที่ 31 พ.ค. ใน UTC)
+	data = testutil.MustDo(t, r, http.MethodPost, complete, gin.H{
+		"performed_at": "2025-06-01 01:00", "complications": "none", "weight": 14, "temperature": 38.6,
+	})
+	if data["status"] != entity.SurgeryCompleted || data["medical_record_id"] == nil {
+		t.Errorf("completed surgery = %v, want completed with a medical record", data)
+	}
+	var got entity.Dog
+	if err := db.First(&got, dog.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if got.SterilizedAt == nil || got.SterilizedAt.String() != "2025-06-01" {
+		t.Errorf("sterilized_at = %v, want 2025-06-01", got.SterilizedAt)
+	}
+	var checks []entity.PostOpCheck
+	db.Where("surgery_id = ?", testutil.ID(data)).Order("day_after ASC").Find(&checks)
+	if len(checks) != len(entity.PostOpSchedule(entity.ProcedureSpay)) || checks[0].DueDate.String() != "2025-06-02" {
+		t.Errorf("post-op checks = %+v, want spay schedule starting 2025-06-02", checks)
+	}
+	if code, _ := testutil.Do(t, r, http.MethodPost, complete, gin.H{}); code != http.StatusConflict {
+		t.Errorf("complete twice = %d, want %d", code, http.StatusConflict)
+	}
+
+	_, out := testutil.Do(t, r, http.MethodGet, "/reports/sterilization?from=2025-06-01&to=2025-06-01", nil)
+	report, _ := out["data"].(map[string]any)
+	performed, _ := report["performed"].(map[string]any)
+	if performed["count"] != float64(1) {
+		t.Errorf("sterilizations performed on 2025-06-01 = %v, want 1", performed["count"])
+	}
+}
@@ -0,0 +1,475 @@
+package vaccination
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/middlewares"
+	"example.com/project-sa/utils/timeutil"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+/* ========== Vaccination schedule: due/overdue worklists ========== */
+
+// ค่าเริ่มต้นของ ?before= (ล่วงหน้ากี่วัน)
+const defaultDueWindowDays = 14
+
+type dueItem struct {
+	VaccineRecordID uint      `json:"vaccine_record_id"`
+	DogID           uint      `json:"dog_id"`
+	DogName         string    `json:"dog_name"`
+	DogStatus       string    `json:"dog_status"`
+	VaccineID       uint      `json:"vaccine_id"`
+	VaccineName     string    `json:"vaccine_name"`
+	LastDose        int       `json:"last_dose"`
+	LastGivenAt     time.Time `json:"last_given_at"`
+	DueDate         string    `json:"due_date"`
+	Overdue         bool      `json:"overdue"`
+	DaysOverdue     int       `json:"days_overdue"`
+
+	zoneID, kennelID uint
+	zoneName         string
+	kennelName       string
+	due              time.Time
+}
+
+type kennelGroup struct {
+	KennelID   *uint     `json:"kennel_id"`
+	KennelName string    `json:"kennel_name"`
+	Items      []dueItem `json:"items"`
+}
+
+type zoneGroup struct {
+	ZoneID   *uint         `json:"zone_id"`
+	ZoneName string        `json:"zone_name"`
+	Total    int           `json:"total"`
+	Overdue  int           `json:"overdue"`
+	Kennels  []kennelGroup `json:"kennels"`
+}
+
+type protocolRequest struct {
+	DoseCount           int `json:"dose_count" binding:"min=1"`
+	DoseIntervalDays    int `json:"dose_interval_days" binding:"min=0"`
+	BoosterIntervalDays int `json:"booster_interval_days" binding:"min=0"`
+}
+
+func startOfToday() time.Time {
+	now := time.Now().In(timeutil.TZBangkok())
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// latestDue เข็มล่าสุดของแต่ละ (สุนัข, วัคซีน) ที่มีวันครบกำหนดก่อน before
+// เฉพาะสุนัขที่ยังอยู่ในความดูแล (ศูนย์/บ้านอุปถัมภ์) และเฉพาะ dogIDs ถ้าระบุ
+func latestDue(db *gorm.DB, before time.Time, dogIDs ...uint) ([]dueItem, error) {
+	type row struct {
+		ID          uint
+		DogID       uint
+		VaccineID   uint
+		DoseNumber  int
+		NextDueDate time.Time
+		DateRecord  time.Time
+	}
+	var rows []row
+	q := db.Table("vaccine_records").
+		Select("vaccine_records.id, medical_records.dog_id, vaccine_records.vaccine_id, vaccine_records.dose_number, vaccine_records.next_due_date, medical_records.date_record").
+		Joins("JOIN medical_records ON medical_records.id = vaccine_records.med_id AND medical_records.deleted_at IS NULL").
+		Joins("JOIN dogs ON dogs.id = medical_records.dog_id AND dogs.deleted_at IS NULL").
+		Where("vaccine_records.deleted_at IS NULL").
+		Where("dogs.is_adopted = ? AND dogs.status IN ?", false, []string{entity.DogStatusShelter, entity.DogStatusFoster})
+	if len(dogIDs) > 0 {
+		q = q.Where("medical_records.dog_id IN ?", dogIDs)
+	}
+	if err := q.Order("medical_records.date_record ASC, vaccine_records.id ASC").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	type key struct{ dog, vaccine uint }
+	latest := map[key]row{}
+	for _, r := range rows {
+		latest[key{r.DogID, r.VaccineID}] = r
+	}
+
+	today := startOfToday()
+	var items []dueItem
+	dogIDs, vaccineIDs := []uint{}, []uint{}
+	for _, r := range latest {
+		if r.NextDueDate.IsZero() || !r.NextDueDate.Before(before) {
+			continue
+		}
+		due := time.Date(r.NextDueDate.Year(), r.NextDueDate.Month(), r.NextDueDate.Day(), 0, 0, 0, 0, time.UTC)
+		it := dueItem{
+			VaccineRecordID: r.ID,
+			DogID:           r.DogID,
+			VaccineID:       r.VaccineID,
+			LastDose:        r.DoseNumber,
+			LastGivenAt:     r.DateRecord,
+			DueDate:         due.Format("2006-01-02"),
+			due:             due,
+		}
+		if due.Before(today) {
+			it.Overdue = true
+			it.DaysOverdue = int(today.Sub(due).Hours() / 24)
+		}
+		items = append(items, it)
+		dogIDs = append(dogIDs, r.DogID)
+		vaccineIDs = append(vaccineIDs, r.VaccineID)
+	}
+	if len(items) == 0 {
+		return items, nil
+	}
+
+	var dogs []entity.Dog
+	if err := db.Preload("Kennel").Preload("Kennel.Zone").Where("id IN ?", dogIDs).Find(&dogs).Error; err != nil {
+		return nil, err
+	}
+	dogByID := map[uint]entity.Dog{}
+	for _, d := range dogs {
+		dogByID[d.ID] = d
+	}
+	var vaccines []entity.Vaccine
+	if err := db.Where("id IN ?", vaccineIDs).Find(&vaccines).Error; err != nil {
+		return nil, err
+	}
+	vaccineByID := map[uint]string{}
+	for _, v := range vaccines {
+		vaccineByID[v.ID] = v.Name
+	}
+
+	for i := range items {
+		d := dogByID[items[i].DogID]
+		items[i].DogName = d.Name
+		items[i].DogStatus = d.Status
+		items[i].VaccineName = vaccineByID[items[i].VaccineID]
+		switch {
+		case d.Kennel != nil:
+			items[i].kennelID = d.Kennel.ID
+			items[i].kennelName = d.Kennel.Name
+			if d.Kennel.Zone != nil {
+				items[i].zoneID = d.Kennel.Zone.ID
+				items[i].zoneName = d.Kennel.Zone.Name
+			}
+		case d.Status == entity.DogStatusFoster:
+			items[i].zoneName = "Foster care"
+		default:
+			items[i].zoneName = "Unassigned"
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if !items[i].due.Equal(items[j].due) {
+			return items[i].due.Before(items[j].due)
+		}
+		return items[i].DogName < items[j].DogName
+	})
+	return items, nil
+}
+
+func groupByZone(items []dueItem) []zoneGroup {
+	zones := []zoneGroup{}
+	zoneIdx := map[string]int{}
+	kennelIdx := map[string]int{}
+	for _, it := range items {
+		zk := it.zoneName
+		zi, ok := zoneIdx[zk]
+		if !ok {
+			zg := zoneGroup{ZoneName: it.zoneName, Kennels: []kennelGroup{}}
+			if it.zoneID != 0 {
+				id := it.zoneID
+				zg.ZoneID = &id
+			}
+			zones = append(zones, zg)
+			zi = len(zones) - 1
+			zoneIdx[zk] = zi
+		}
+		kk := zk + "/" + it.kennelName
+		ki, ok := kennelIdx[kk]
+		if !ok {
+			kg := kennelGroup{KennelName: it.kennelName, Items: []dueItem{}}
+			if it.kennelID != 0 {
+				id := it.kennelID
+				kg.KennelID = &id
+			}
+			zones[zi].Kennels = append(zones[zi].Kennels, kg)
+			ki = len(zones[zi].Kennels) - 1
+			kennelIdx[kk] = ki
+		}
+		zones[zi].Kennels[ki].Items = append(zones[zi].Kennels[ki].Items, it)
+		zones[zi].Total++
+		if it.Overdue {
+			zones[zi].Overdue++
+		}
+	}
+	sort.SliceStable(zones, func(i, j int) bool { return zones[i].ZoneName < zones[j].ZoneName })
+	for _, z := range zones {
+		sort.SliceStable(z.Kennels, func(i, j int) bool { return z.Kennels[i].KennelName < z.Kennels[j].KennelName })
+	}
+	return zones
+}
+
+// GET /vaccinations/due?before=YYYY-MM-DD — ครบกำหนดก่อนวันที่ระบุ รวมที่เลยกำหนดแล้วด้วย
+// (ค่าเริ่มต้น: ภายใน 14 วันข้างหน้า)
+func GetDueVaccinations(c *gin.Context) {
+	before := startOfToday().AddDate(0, 0, defaultDueWindowDays+1)
+	if v := c.Query("before"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid before (YYYY-MM-DD)"})
+			return
+		}
+		before = t
+	}
+	items, err := latestDue(configs.DB(), before)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	overdue := 0
+	for _, it := range items {
+		if it.Overdue {
+			overdue++
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":    groupByZone(items),
+		"summary": gin.H{"total": len(items), "overdue": overdue, "before": before.Format("2006-01-02")},
+	})
+}
+
+/* ========== Overdue alerts ========== */
+
+// SyncOverdueAlerts เปิดแจ้งเตือนให้เข็มที่เลยกำหนด และปิดแจ้งเตือนที่ไม่เลยกำหนดแล้ว
+// (ฉีดเข็มใหม่แล้ว, ถูกรับเลี้ยง/ส่งต่อ/เสียชีวิต)
+func SyncOverdueAlerts(db *gorm.DB) (opened, resolved int, err error) {
+	return syncAlerts(db, nil)
+}
+
+// SyncDogAlerts เหมือน SyncOverdueAlerts แต่เฉพาะสุนัขตัวเดียว
+// เรียกใน tx เดียวกับที่เพิ่ม/แก้/ลบเข็มวัคซีน แจ้งเตือนจึงตรงกับข้อมูลทันทีไม่ต้องรอรอบ job
+func SyncDogAlerts(tx *gorm.DB, dogID uint) error {
+	_, _, err := syncAlerts(tx, []uint{dogID})
+	return err
+}
+
+func syncAlerts(db *gorm.DB, dogIDs []uint) (opened, resolved int, err error) {
+	items, err := latestDue(db, startOfToday(), dogIDs...)
+	if err != nil {
+		return 0, 0, err
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		current := make([]uint, 0, len(items))
+		for _, it := range items {
+			current = append(current, it.VaccineRecordID)
+			alert := entity.VaccinationAlert{
+				VaccineRecordID: it.VaccineRecordID,
+				DogID:           it.DogID,
+				VaccineID:       it.VaccineID,
+				DueDate:         it.due,
+			}
+			res := tx.Where(entity.VaccinationAlert{VaccineRecordID: it.VaccineRecordID}).FirstOrCreate(&alert)
+			if res.Error != nil {
+				return res.Error
+			}
+			opened += int(res.RowsAffected)
+			// เข็มใหม่กว่าถูกลบ เข็มเดิมกลับมาเลยกำหนดอีกครั้ง
+			if alert.ResolvedAt != nil {
+				if err := tx.Model(&alert).Update("resolved_at", nil).Error; err != nil {
+					return err
+				}
+				opened++
+			}
+		}
+		q := tx.Model(&entity.VaccinationAlert{}).Where("resolved_at IS NULL")
+		if len(dogIDs) > 0 {
+			q = q.Where("dog_id IN ?", dogIDs)
+		}
+		if len(current) > 0 {
+			q = q.Where("vaccine_record_id NOT IN ?", current)
+		}
+		res := q.Update("resolved_at", time.Now())
+		resolved = int(res.RowsAffected)
+		return res.Error
+	})
+	return opened, resolved, err
+}
+
+// StartOverdueAlertJob ตรวจวัคซีนเลยกำหนดทันทีและทุก interval
+func StartOverdueAlertJob(interval time.Duration) {
+	run := func() {
+		opened, resolved, err := SyncOverdueAlerts(configs.DB())
+		if err != nil {
+			log.Printf("vaccination alerts: %v", err)
+			return
+		}
+		if opened > 0 || resolved > 0 {
+			log.Printf("vaccination alerts: opened %d, resolved %d", opened, resolved)
+		}
+	}
+	go func() {
+		run()
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for range t.C {
+			run()
+		}
+	}()
+}
+
+// GET /vaccinations/alerts?status=open|acknowledged|resolved|all (ค่าเริ่มต้น open = ยังไม่ปิด)
+// อ่านอย่างเดียว: แจ้งเตือนเปิด/ปิดตอนบันทึกเข็มวัคซีน (SyncDogAlerts) และจาก job รายชั่วโมง
+func GetAlerts(c *gin.Context) {
+	db := configs.DB().Preload("Dog").Preload("Vaccine").Preload("VaccineRecord").Preload("AcknowledgedBy")
+	switch c.DefaultQuery("status", "open") {
+	case "open":
+		db = db.Where("resolved_at IS NULL")
+	case "acknowledged":
+		db = db.Where("resolved_at IS NULL AND acknowledged_at IS NOT NULL")
+	case "resolved":
+		db = db.Where("resolved_at IS NOT NULL")
+	case "all":
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status"})
+		return
+	}
+	var rows []entity.VaccinationAlert
+	if err := db.Order("due_date ASC, id ASC").Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": rows})
+}
+
+// POST /vaccinations/alerts/:id/ack — รับทราบ (ยังเปิดอยู่จนกว่าจะฉีดเข็มใหม่)
+func AcknowledgeAlert(c *gin.Context) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var a entity.VaccinationAlert
+	if err := configs.DB().First(&a, c.Param("id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "alert not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	if a.AcknowledgedAt == nil {
+		now := time.Now()
+		if err := configs.DB().Model(&a).Updates(map[string]any{
+			"acknowledged_at":    now,
+			"acknowledged_by_id": *staffID,
+		}).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed: " + err.Error()})
+			return
+		}
+		a.AcknowledgedAt, a.AcknowledgedByID = &now, staffID
+	}
+	c.JSON(http.StatusOK, gin.H{"data": a})
+}
+
+/* ========== Protocols ========== */
+
+// GET /vaccine-protocols?vaccine_id=
+func GetProtocols(c *gin.Context) {
+	db := configs.DB().Preload("Vaccine")
+	if v := c.Query("vaccine_id"); v != "" {
+		db = db.Where("vaccine_id = ?", v)
+	}
+	var rows []entity.VaccineProtocol
+	if err := db.Order("vaccine_id ASC, age_group DESC").Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": rows})
+}
+
+// PUT /vaccines/:id/protocols/:age_group — สร้างหรือแก้ protocol ของช่วงวัยนั้น
+func UpsertProtocol(c *gin.Context) {
+	if middlewares.StaffID(c) == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	group := c.Param("age_group")
+	if group != entity.ProtocolPuppy && group != entity.ProtocolAdult {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "age_group must be puppy or adult"})
+		return
+	}
+	var req protocolRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	if req.DoseCount > 1 && req.DoseIntervalDays == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "dose_interval_days is required when dose_count > 1"})
+		return
+	}
+	var vac entity.Vaccine
+	if err := configs.DB().First(&vac, c.Param("id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "vaccine not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+
+	var p entity.VaccineProtocol
+	err := configs.DB().Where("vaccine_id = ? AND age_group = ?", vac.ID, group).First(&p).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	created := p.ID == 0
+	p.VaccineID = vac.ID
+	p.AgeGroup = group
+	p.DoseCount = req.DoseCount
+	p.DoseIntervalDays = req.DoseIntervalDays
+	p.BoosterIntervalDays = req.BoosterIntervalDays
+	if err := configs.DB().Save(&p).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "save failed: " + err.Error()})
+		return
+	}
+	if created {
+		c.JSON(http.StatusCreated, gin.H{"data": p})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": p})
+}
+
+// DELETE /vaccines/:id/protocols/:age_group
+func DeleteProtocol(c *gin.Context) {
+	if middlewares.StaffID(c) == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	res := configs.DB().Unscoped().
+		Where("vaccine_id = ? AND age_group = ?", c.Param("id"), c.Param("age_group")).
+		Delete(&entity.VaccineProtocol{})
+	if res.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "delete failed: " + res.Error.Error()})
+		return
+	}
+	if res.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "protocol not found"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// CountDue จำนวนเข็มที่ครบกำหนดภายใน defaultDueWindowDays วัน (รวมที่เลยกำหนด) สำหรับ dashboard
+func CountDue(db *gorm.DB) (int64, error) {
+	items, err := latestDue(db, startOfToday().AddDate(0, 0, defaultDueWindowDays+1))
+	return int64(len(items)), err
+}
+
+// CountOverdue นับเข็มที่เลยกำหนดแล้ว ณ ตอนนี้ (คำนวณสด ไม่รอ job เปิดแจ้งเตือน)
+func CountOverdue(db *gorm.DB) (int64, error) {
+	items, err := latestDue(db, startOfToday())
+	return int64(len(items)), err
+}
@@ -0,0 +1,568 @@
+package zcmanagement
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/middlewares"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+/* ========== จัดการโซน / คอก ========== */
+
+type zoneRequest struct {
+	Name        string `json:"name" binding:"required"`
+	IsIsolation bool   `json:"is_isolation"`
+	BuildingID  *uint  `json:"building_id"` // 0 = ไม่ผูกอาคาร (แก้ไข: ไม่ส่ง = คงเดิม)
+}
+
+type kennelRequest struct {
+	Name           string  `json:"name" binding:"required"`
+	Capacity       uint    `json:"capacity" binding:"required,min=1"`
+	Color          string  `json:"color"`
+	Note           *string `json:"note"`
+	ZoneID         uint    `json:"zone_id" binding:"required"`
+	Environment    string  `json:"environment"` // indoor (ค่าเริ่มต้น) | outdoor | covered
+	IsIsolation    bool    `json:"is_isolation"`
+	AllowedSizeIDs []uint  `json:"allowed_size_ids"` // ว่าง = รับทุกขนาด
+}
+
+type zoneView struct {
+	entity.Zone
+	KennelCount int   `json:"kennel_count"`
+	Capacity    int64 `json:"capacity"`
+	Occupied    int64 `json:"occupied"`
+}
+
+type kennelView struct {
+	entity.Kennel
+	Occupied int64 `json:"occupied"`
+	Free     int64 `json:"free"`
+}
+
+// occupancy จำนวนสุนัขในแต่ละคอก
+func occupancy(db *gorm.DB) (map[uint]int64, error) {
+	var rows []struct {
+		KennelID uint
+		N        int64
+	}
+	if err := db.Model(&entity.Dog{}).Select("kennel_id, COUNT(*) AS n").
+		Where("kennel_id IS NOT NULL").Group("kennel_id").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := map[uint]int64{}
+	for _, r := range rows {
+		out[r.KennelID] = r.N
+	}
+	return out, nil
+}
+
+func toKennelView(k entity.Kennel, occ map[uint]int64) kennelView {
+	n := occ[k.ID]
+	free := int64(k.Capacity) - n
+	if free < 0 {
+		free = 0
+	}
+	return kennelView{Kennel: k, Occupied: n, Free: free}
+}
+
+// quarantinedOutsideIsolation สุนัขกักโรคที่อยู่ในคอกที่ไม่ใช่คอกแยกโรคแล้ว (ตรวจหลังแก้โซน/คอก ก่อน commit)
+func quarantinedOutsideIsolation(tx *gorm.DB, kennelIDs []uint) (int64, error) {
+	if len(kennelIDs) == 0 {
+		return 0, nil
+	}
+	var n int64
+	err := tx.Model(&entity.Dog{}).
+		Joins("JOIN kennels ON kennels.id = dogs.kennel_id").
+		Joins("JOIN zones ON zones.id = kennels.zone_id").
+		Where("kennels.id IN ? AND kennels.is_isolation = ? AND zones.is_isolation = ?", kennelIDs, false, false).
+		Where("dogs.id IN (?)", tx.Model(&entity.QuarantineRecord{}).
+			Select("dog_id").Where("status = ?", entity.QuarantineActive)).
+		Count(&n).Error
+	return n, err
+}
+
+func validateZone(tx *gorm.DB, id uint, req *zoneRequest) (int, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return http.StatusBadRequest, errors.New("name is required")
+	}
+	var dup int64
+	if err := tx.Model(&entity.Zone{}).Where("LOWER(name) = LOWER(?) AND id <> ?", req.Name, id).Count(&dup).Error; err != nil {
+		return 0, err
+	}
+	if dup > 0 {
+		return http.StatusConflict, errors.New("zone " + req.Name + " already exists")
+	}
+	if req.BuildingID != nil && *req.BuildingID == 0 {
+		req.BuildingID = nil
+	}
+	if req.BuildingID != nil {
+		var b entity.Building
+		if err := tx.First(&b, *req.BuildingID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return http.StatusBadRequest, errors.New("invalid building_id")
+			}
+			return 0, err
+		}
+	}
+	return 0, nil
+}
+
+func validateKennel(tx *gorm.DB, id uint, req *kennelRequest) ([]entity.AnimalSize, int, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return nil, http.StatusBadRequest, errors.New("name is required")
+	}
+	if req.Environment == "" {
+		req.Environment = entity.KennelIndoor
+	}
+	switch req.Environment {
+	case entity.KennelIndoor, entity.KennelOutdoor, entity.KennelCovered:
+	default:
+		return nil, http.StatusBadRequest, errors.New("environment must be indoor, outdoor or covered")
+	}
+	var dup int64
+	if err := tx.Model(&entity.Kennel{}).Where("LOWER(name) = LOWER(?) AND id <> ?", req.Name, id).Count(&dup).Error; err != nil {
+		return nil, 0, err
+	}
+	if dup > 0 {
+		return nil, http.StatusConflict, errors.New("kennel " + req.Name + " already exists")
+	}
+	var zone entity.Zone
+	if err := tx.First(&zone, req.ZoneID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, http.StatusBadRequest, errors.New("invalid zone_id")
+		}
+		return nil, 0, err
+	}
+	sizes := []entity.AnimalSize{}
+	if len(req.AllowedSizeIDs) > 0 {
+		if err := tx.Where("id IN ?", req.AllowedSizeIDs).Find(&sizes).Error; err != nil {
+			return nil, 0, err
+		}
+		seen := map[uint]bool{}
+		for _, id := range req.AllowedSizeIDs {
+			seen[id] = true
+		}
+		if len(sizes) != len(seen) {
+			return nil, http.StatusBadRequest, errors.New("invalid allowed_size_ids")
+		}
+	}
+	return sizes, 0, nil
+}
+
+// GET /zones?building_id=
+func GetZones(c *gin.Context) {
+	db := configs.DB().Preload("Building").Preload("Kennels")
+	if v := c.Query("building_id"); v != "" {
+		db = db.Where("building_id = ?", v)
+	}
+	var zones []entity.Zone
+	if err := db.Order("name ASC").Find(&zones).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	occ, err := occupancy(configs.DB())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	out := make([]zoneView, 0, len(zones))
+	for _, z := range zones {
+		v := zoneView{}
+		for _, k := range z.Kennels {
+			if k.Unassigned() {
+				continue
+			}
+			v.KennelCount++
+			v.Capacity += int64(k.Capacity)
+			v.Occupied += occ[k.ID]
+		}
+		z.Kennels = nil
+		v.Zone = z
+		out = append(out, v)
+	}
+	c.JSON(http.StatusOK, gin.H{"data": out})
+}
+
+// GET /zones/:id
+func GetZone(c *gin.Context) {
+	var z entity.Zone
+	if err := configs.DB().Preload("Building").
+		Preload("Kennels", func(db *gorm.DB) *gorm.DB { return db.Order("name ASC") }).
+		Preload("Kennels.AllowedSizes").First(&z, c.Param("id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "zone not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	occ, err := occupancy(configs.DB())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	kennels := make([]kennelView, 0, len(z.Kennels))
+	for _, k := range z.Kennels {
+		kennels = append(kennels, toKennelView(k, occ))
+	}
+	z.Kennels = nil
+	c.JSON(http.StatusOK, gin.H{"data": z, "kennels": kennels})
+}
+
+// POST /zones
+func CreateZone(c *gin.Context) {
+	if middlewares.StaffID(c) == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req zoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	var z entity.Zone
+	var status int
+	err := configs.DB().Transaction(func(tx *gorm.DB) error {
+		var err error
+		if status, err = validateZone(tx, 0, &req); err != nil {
+			return err
+		}
+		z = entity.Zone{Name: req.Name, IsIsolation: req.IsIsolation, BuildingID: req.BuildingID}
+		return tx.Create(&z).Error
+	})
+	if err != nil {
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, gin.H{"error": "create failed: " + err.Error()})
+		return
+	}
+	configs.DB().Preload("Building").First(&z, z.ID)
+	c.JSON(http.StatusCreated, gin.H{"data": z})
+}
+
+// PUT /zones/:id
+func UpdateZone(c *gin.Context) {
+	if middlewares.StaffID(c) == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req zoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	var z entity.Zone
+	var status int
+	err := configs.DB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&z, c.Param("id")).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				status = http.StatusNotFound
+				return errors.New("zone not found")
+			}
+			return err
+		}
+		if req.BuildingID == nil {
+			req.BuildingID = z.BuildingID
+		}
+		var err error
+		if status, err = validateZone(tx, z.ID, &req); err != nil {
+			return err
+		}
+		if err := tx.Model(&z).Updates(map[string]any{
+			"name":         req.Name,
+			"is_isolation": req.IsIsolation,
+			"building_id":  req.BuildingID,
+		}).Error; err != nil {
+			return err
+		}
+		// ยกเลิกโซนแยกโรคไม่ได้ถ้ายังมีสุนัขกักโรคอยู่ในคอกของโซนนี้
+		var kennelIDs []uint
+		if err := tx.Model(&entity.Kennel{}).Where("zone_id = ?", z.ID).Pluck("id", &kennelIDs).Error; err != nil {
+			return err
+		}
+		n, err := quarantinedOutsideIsolation(tx, kennelIDs)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			status = http.StatusConflict
+			return errors.New("zone still houses quarantined dogs")
+		}
+		return nil
+	})
+	if err != nil {
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, gin.H{"error": "update failed: " + err.Error()})
+		return
+	}
+	configs.DB().Preload("Building").First(&z, z.ID)
+	c.JSON(http.StatusOK, gin.H{"data": z})
+}
+
+// DELETE /zones/:id — ลบได้เฉพาะโซนที่ไม่มีคอกและไม่มีเจ้าหน้าที่ประจำ
+func DeleteZone(c *gin.Context) {
+	if middlewares.StaffID(c) == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var status int
+	err := configs.DB().Transaction(func(tx *gorm.DB) error {
+		var z entity.Zone
+		if err := tx.First(&z, c.Param("id")).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				status = http.StatusNotFound
+				return errors.New("zone not found")
+			}
+			return err
+		}
+		var kennels, staffs int64
+		if err := tx.Model(&entity.Kennel{}).Where("zone_id = ?", z.ID).Count(&kennels).Error; err != nil {
+			return err
+		}
+		if kennels > 0 {
+			status = http.StatusConflict
+			return fmt.Errorf("zone still has %d kennel(s)", kennels)
+		}
+		if err := tx.Model(&entity.Staff{}).Where("zone_id = ?", z.ID).Count(&staffs).Error; err != nil {
+			return err
+		}
+		if staffs > 0 {
+			status = http.StatusConflict
+			return fmt.Errorf("zone still has %d staff assigned", staffs)
+		}
+		return tx.Delete(&z).Error
+	})
+	if err != nil {
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, gin.H{"error": "delete failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "zone deleted"})
+}
+
+// GET /kennels?zone_id=&environment=&available=true
+func GetKennels(c *gin.Context) {
+	db := configs.DB().Preload("Zone").Preload("AllowedSizes")
+	if v := c.Query("zone_id"); v != "" {
+		db = db.Where("zone_id = ?", v)
+	}
+	if v := c.Query("environment"); v != "" {
+		db = db.Where("environment = ?", v)
+	}
+	var kennels []entity.Kennel
+	if err := db.Order("name ASC").Find(&kennels).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	occ, err := occupancy(configs.DB())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	out := make([]kennelView, 0, len(kennels))
+	for _, k := range kennels {
+		v := toKennelView(k, occ)
+		if c.Query("available") == "true" && v.Free == 0 {
+			continue
+		}
+		out = append(out, v)
+	}
+	c.JSON(http.StatusOK, gin.H{"data": out})
+}
+
+// GET /kennels/:id
+func GetKennel(c *gin.Context) {
+	var k entity.Kennel
+	if err := configs.DB().Preload("Zone").Preload("Zone.Building").Preload("AllowedSizes").
+		First(&k, c.Param("id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "kennel not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	occ, err := occupancy(configs.DB().Where("kennel_id = ?", k.ID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": toKennelView(k, occ)})
+}
+
+// POST /kennels
+func CreateKennel(c *gin.Context) {
+	if middlewares.StaffID(c) == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req kennelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	var k entity.Kennel
+	var status int
+	err := configs.DB().Transaction(func(tx *gorm.DB) error {
+		sizes, st, err := validateKennel(tx, 0, &req)
+		if err != nil {
+			status = st
+			return err
+		}
+		k = entity.Kennel{
+			Name:         req.Name,
+			Capacity:     req.Capacity,
+			Color:        req.Color,
+			Note:         req.Note,
+			ZoneID:       req.ZoneID,
+			Environment:  req.Environment,
+			IsIsolation:  req.IsIsolation,
+			AllowedSizes: sizes,
+		}
+		return tx.Create(&k).Error
+	})
+	if err != nil {
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, gin.H{"error": "create failed: " + err.Error()})
+		return
+	}
+	configs.DB().Preload("Zone").Preload("AllowedSizes").First(&k, k.ID)
+	c.JSON(http.StatusCreated, gin.H{"data": toKennelView(k, nil)})
+}
+
+// PUT /kennels/:id — ความจุต้องไม่น้อยกว่าจำนวนสุนัขที่อยู่ตอนนี้
+func UpdateKennel(c *gin.Context) {
+	if middlewares.StaffID(c) == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req kennelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	var k *entity.Kennel
+	var status int
+	err := configs.DB().Transaction(func(tx *gorm.DB) error {
+		var err error
+		// ล็อกคอกไว้ กันย้ายสุนัขเข้าระหว่างลดความจุ
+		if k, err = entity.LockKennel(tx, parseUintParam(c.Param("id"))); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				status = http.StatusNotFound
+				return errors.New("kennel not found")
+			}
+			return err
+		}
+		sizes, st, err := validateKennel(tx, k.ID, &req)
+		if err != nil {
+			status = st
+			return err
+		}
+		var occupied int64
+		if err := tx.Model(&entity.Dog{}).Where("kennel_id = ?", k.ID).Count(&occupied).Error; err != nil {
+			return err
+		}
+		if int64(req.Capacity) < occupied {
+			status = http.StatusConflict
+			return fmt.Errorf("capacity %d is below current occupancy (%d)", req.Capacity, occupied)
+		}
+		if err := tx.Model(k).Updates(map[string]any{
+			"name":         req.Name,
+			"capacity":     req.Capacity,
+			"color":        req.Color,
+			"note":         req.Note,
+			"zone_id":      req.ZoneID,
+			"environment":  req.Environment,
+			"is_isolation": req.IsIsolation,
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(k).Association("AllowedSizes").Replace(sizes); err != nil {
+			return err
+		}
+		// ย้ายโซน/ยกเลิกคอกแยกโรค แล้วสุนัขกักโรคในคอกต้องยังอยู่ในที่แยกโรค
+		n, err := quarantinedOutsideIsolation(tx, []uint{k.ID})
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			status = http.StatusConflict
+			return errors.New("kennel houses quarantined dogs and would no longer be isolated")
+		}
+		return nil
+	})
+	if err != nil {
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, gin.H{"error": "update failed: " + err.Error()})
+		return
+	}
+	var out entity.Kennel
+	configs.DB().Preload("Zone").Preload("AllowedSizes").First(&out, k.ID)
+	occ, _ := occupancy(configs.DB().Where("kennel_id = ?", k.ID))
+	c.JSON(http.StatusOK, gin.H{"data": toKennelView(out, occ)})
+}
+
+// DELETE /kennels/:id — ลบไม่ได้ถ้ายังมีสุนัขอยู่
+func DeleteKennel(c *gin.Context) {
+	if middlewares.StaffID(c) == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var status int
+	err := configs.DB().Transaction(func(tx *gorm.DB) error {
+		k, err := entity.LockKennel(tx, parseUintParam(c.Param("id")))
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				status = http.StatusNotFound
+				return errors.New("kennel not found")
+			}
+			return err
+		}
+		var occupied int64
+		if err := tx.Model(&entity.Dog{}).Where("kennel_id = ?", k.ID).Count(&occupied).Error; err != nil {
+			return err
+		}
+		if occupied > 0 {
+			status = http.StatusConflict
+			return fmt.Errorf("kennel %s still houses %d dog(s)", k.Name, occupied)
+		}
+		if err := tx.Model(k).Association("AllowedSizes").Clear(); err != nil {
+			return err
+		}
+		// ประวัติการย้ายยังอ้างคอกนี้ได้ (soft delete)
+		return tx.Delete(k).Error
+	})
+	if err != nil {
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, gin.H{"error": "delete failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "kennel deleted"})
+}
+
+func parseUintParam(s string) uint {
+	var id uint
+	if _, err := fmt.Sscan(s, &id); err != nil {
+		return 0
+	}
+	return id
+}
@@ -0,0 +1,119 @@
+package zcmanagement
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/utils/testutil"
+	"github.com/gin-gonic/gin"
+)
+
+func adminRouter(staffID uint) *gin.Engine {
+	r := testutil.Router(staffID)
+	r.GET("/zones/:id", GetZone)
+	r.POST("/zones", CreateZone)
+	r.PUT("/zones/:id", UpdateZone)
+	r.DELETE("/zones/:id", DeleteZone)
+	r.POST("/kennels", CreateKennel)
+	r.PUT("/kennels/:id", UpdateKennel)
+	r.DELETE("/kennels/:id", DeleteKennel)
+	return r
+}
+
+func TestZoneAndKennelAdministration(t *testing.T) {
+	r := adminRouter(1)
+
+	if code, _ := testutil.Do(t, adminRouter(0), http.MethodPost, "/zones", gin.H{"name": "Admin Zone"}); code != http.StatusForbidden {
+		t.Errorf("anonymous create zone = %d, want 403", code)
+	}
+	var b entity.Building
+	if err := configs.DB().First(&b).Error; err != nil {
+		t.Fatal(err)
+	}
+	zone := testutil.MustDo(t, r, http.MethodPost, "/zones", gin.H{"name": " Admin Zone ", "building_id": b.ID})
+	zoneID := testutil.ID(zone)
+	if zone["name"] != "Admin Zone" || zone["building_id"] != float64(b.ID) {
+		t.Errorf("created zone = %v, want trimmed name linked to building %d", zone, b.ID)
+	}
+	if code, out := testutil.Do(t, r, http.MethodPost, "/zones", gin.H{"name": "admin zone"}); code != http.StatusConflict {
+		t.Errorf("duplicate zone = %d %v, want 409", code, out)
+	}
+	if code, out := testutil.Do(t, r, http.MethodPost, "/zones", gin.H{"name": "Nowhere", "building_id": 9999}); code != http.StatusBadRequest {
+		t.Errorf("zone with unknown building = %d %v, want 400", code, out)
+	}
+	// ไม่ส่ง building_id ตอนแก้ = คงอาคารเดิม
+	zone = testutil.MustDo(t, r, http.MethodPut, fmt.Sprintf("/zones/%d", zoneID), gin.H{"name": "Admin Zone"})
+	if zone["building_id"] != float64(b.ID) {
+		t.Errorf("update without building_id = %v, want building kept", zone["building_id"])
+	}
+
+	kennel := func(body gin.H) (int, map[string]any) {
+		return testutil.Do(t, r, http.MethodPost, "/kennels", body)
+	}
+	if code, out := kennel(gin.H{"name": "ADM-1", "capacity": 2, "zone_id": zoneID, "environment": "roof"}); code != http.StatusBadRequest {
+		t.Errorf("bad environment = %d %v, want 400", code, out)
+	}
+	if code, out := kennel(gin.H{"name": "ADM-1", "capacity": 2, "zone_id": 9999}); code != http.StatusBadRequest {
+		t.Errorf("unknown zone = %d %v, want 400", code, out)
+	}
+	if code, out := kennel(gin.H{"name": "ADM-1", "capacity": 2, "zone_id": zoneID, "allowed_size_ids": []uint{1, 9999}}); code != http.StatusBadRequest {
+		t.Errorf("unknown size = %d %v, want 400", code, out)
+	}
+	code, out := kennel(gin.H{"name": "ADM-1", "capacity": 2, "zone_id": zoneID, "allowed_size_ids": []uint{1}})
+	if code != http.StatusCreated {
+		t.Fatalf("create kennel = %d %v", code, out)
+	}
+	created := out["data"].(map[string]any)
+	kennelID := testutil.ID(created)
+	if sizes, _ := created["allowed_sizes"].([]any); created["environment"] != entity.KennelIndoor || len(sizes) != 1 {
+		t.Errorf("created kennel = %v, want indoor with one allowed size", created)
+	}
+	if code, out := kennel(gin.H{"name": "adm-1", "capacity": 1, "zone_id": zoneID}); code != http.StatusConflict {
+		t.Errorf("duplicate kennel = %d %v, want 409", code, out)
+	}
+
+	// มีสุนัขอยู่ ลดความจุต่ำกว่าจำนวนไม่ได้ ลบคอก/โซนไม่ได้
+	_, dogs := newMoveFixture(t, "ADM", nil, 2)
+	for _, d := range dogs {
+		if err := configs.DB().Model(&d).Update("kennel_id", kennelID).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	path := fmt.Sprintf("/kennels/%d", kennelID)
+	if code, out := testutil.Do(t, r, http.MethodPut, path, gin.H{"name": "ADM-1", "capacity": 1, "zone_id": zoneID}); code != http.StatusConflict {
+		t.Errorf("shrink below occupancy = %d %v, want 409", code, out)
+	}
+	updated := testutil.MustDo(t, r, http.MethodPut, path, gin.H{"name": "ADM-1", "capacity": 3, "zone_id": zoneID, "environment": "covered"})
+	if sizes, _ := updated["allowed_sizes"].([]any); updated["environment"] != entity.KennelCovered ||
+		updated["occupied"] != float64(2) || updated["free"] != float64(1) || len(sizes) != 0 {
+		t.Errorf("updated kennel = %v, want covered, 2 occupied, 1 free, any size", updated)
+	}
+	if code, out := testutil.Do(t, r, http.MethodDelete, path, nil); code != http.StatusConflict {
+		t.Errorf("delete occupied kennel = %d %v, want 409", code, out)
+	}
+	zonePath := fmt.Sprintf("/zones/%d", zoneID)
+	if code, out := testutil.Do(t, r, http.MethodDelete, zonePath, nil); code != http.StatusConflict {
+		t.Errorf("delete zone with kennels = %d %v, want 409", code, out)
+	}
+
+	if err := configs.DB().Model(&entity.Dog{}).Where("kennel_id = ?", kennelID).Update("kennel_id", nil).Error; err != nil {
+		t.Fatal(err)
+	}
+	if code, out := testutil.Do(t, r, http.MethodDelete, path, nil); code != http.StatusOK {
+		t.Fatalf("delete empty kennel = %d %v", code, out)
+	}
+	var links int64
+	configs.DB().Table("kennel_allowed_sizes").Where("kennel_id = ?", kennelID).Count(&links)
+	if links != 0 {
+		t.Errorf("allowed size links left = %d, want 0", links)
+	}
+	if code, out := testutil.Do(t, r, http.MethodDelete, zonePath, nil); code != http.StatusOK {
+		t.Fatalf("delete empty zone = %d %v", code, out)
+	}
+	if code, _ := testutil.Do(t, r, http.MethodGet, zonePath, nil); code != http.StatusNotFound {
+		t.Errorf("GET deleted zone = %d, want 404", code)
+	}
+}
@@ -0,0 +1,244 @@
+package zcmanagement
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"time"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/utils/timeutil"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+/* ========== ประวัติตำแหน่งสุนัข / คอก ========== */
+
+func parseAt(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.UTC(), nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02T15:04"} {
+		if t, err := time.ParseInLocation(layout, s, timeutil.TZBangkok()); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, errors.New("invalid time (RFC3339 or YYYY-MM-DD HH:MM)")
+}
+
+// existedAt มีอยู่ในระบบ ณ เวลา at (เทียบใน Go เพราะแถวเก่าอาจเก็บเวลาพร้อม offset ท้องถิ่น)
+func existedAt(created time.Time, deleted gorm.DeletedAt, at time.Time) bool {
+	return !created.After(at) && (!deleted.Valid || deleted.Time.After(at))
+}
+
+// GET /dogs/:id/locations — ช่วงเวลาที่อยู่แต่ละคอก (ล่าสุดก่อน) + รายการย้าย
+func GetDogLocations(c *gin.Context) {
+	var dog entity.Dog
+	if err := configs.DB().Unscoped().First(&dog, c.Param("id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "dog not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	var moves []entity.KennelManagement
+	if err := configs.DB().Preload("FromKennel").Preload("ToKennel").Preload("Staff").
+		Where("dog_id = ?", dog.ID).Find(&moves).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	entity.SortKennelMoves(moves)
+	out := entity.KennelStays(dog, moves)
+
+	var kennels []entity.Kennel
+	if err := configs.DB().Unscoped().Preload("Zone").Find(&kennels).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	byID := map[uint]*entity.Kennel{}
+	for i := range kennels {
+		byID[kennels[i].ID] = &kennels[i]
+	}
+	for i := range out {
+		if out[i].KennelID != nil {
+			out[i].Kennel = byID[*out[i].KennelID]
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].From.After(out[j].From) })
+
+	// ?at= ตอบด้วยว่า ณ เวลานั้นอยู่คอกไหน
+	if v := c.Query("at"); v != "" {
+		at, err := parseAt(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid at (RFC3339 or YYYY-MM-DD HH:MM)"})
+			return
+		}
+		var current *entity.KennelStay
+		for i := range out {
+			if !out[i].From.After(at) && (out[i].To == nil || out[i].To.After(at)) {
+				current = &out[i]
+				break
+			}
+		}
+		c.JSON(http.StatusOK, gin.H{"data": out, "at": at, "location_at": current})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": out})
+}
+
+// GET /kennels/:id/history?from=&to= (YYYY-MM-DD) — สุนัขที่ย้ายเข้า/ออกคอกนี้
+func GetKennelHistory(c *gin.Context) {
+	var k entity.Kennel
+	if err := configs.DB().Unscoped().First(&k, c.Param("id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "kennel not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	db := configs.DB().Preload("Dog", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("FromKennel").Preload("ToKennel").Preload("Staff").
+		Where("from_kennel_id = ? OR to_kennel_id = ?", k.ID, k.ID)
+	loc := timeutil.TZBangkok()
+	var from, to *time.Time
+	for _, p := range []struct {
+		param string
+		dst   **time.Time
+	}{{"from", &from}, {"to", &to}} {
+		v := c.Query(p.param)
+		if v == "" {
+			continue
+		}
+		d, err := time.ParseInLocation("2006-01-02", v, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + p.param + " (YYYY-MM-DD)"})
+			return
+		}
+		if p.param == "to" {
+			d = d.AddDate(0, 0, 1)
+		}
+		*p.dst = &d
+	}
+	var all []entity.KennelManagement
+	if err := db.Find(&all).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	// กรองช่วงวันที่ใน Go: แถวเก่าอาจเก็บ moved_at พร้อม offset ท้องถิ่น เทียบแบบข้อความใน sqlite ไม่ได้
+	rows := make([]entity.KennelManagement, 0, len(all))
+	for _, r := range all {
+		if (from != nil && r.MovedAt.Before(*from)) || (to != nil && !r.MovedAt.Before(*to)) {
+			continue
+		}
+		rows = append(rows, r)
+	}
+	entity.SortKennelMoves(rows)
+	for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+		rows[i], rows[j] = rows[j], rows[i]
+	}
+	type entry struct {
+		entity.KennelManagement
+		Direction string `json:"direction"` // in | out
+	}
+	out := make([]entry, 0, len(rows))
+	for _, r := range rows {
+		dir := "in"
+		if r.FromKennelID != nil && *r.FromKennelID == k.ID {
+			dir = "out"
+		}
+		out = append(out, entry{KennelManagement: r, Direction: dir})
+	}
+	c.JSON(http.StatusOK, gin.H{"kennel": k, "data": out})
+}
+
+// GET /kennels/occupancy?at=&zone_id=&kennel_id= — สุนัขในแต่ละคอก ณ เวลาที่ระบุ (ค่าเริ่มต้น = ตอนนี้)
+func GetOccupancyAt(c *gin.Context) {
+	at := time.Now().UTC()
+	if v := c.Query("at"); v != "" {
+		t, err := parseAt(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid at (RFC3339 or YYYY-MM-DD HH:MM)"})
+			return
+		}
+		at = t
+	}
+
+	// created_at/deleted_at เก็บตามเวลาท้องถิ่นของเซิร์ฟเวอร์ กรองใน Go แทนการเทียบข้อความใน sqlite
+	kdb := configs.DB().Unscoped().Preload("Zone")
+	if v := c.Query("zone_id"); v != "" {
+		kdb = kdb.Where("zone_id = ?", v)
+	}
+	if v := c.Query("kennel_id"); v != "" {
+		kdb = kdb.Where("id = ?", v)
+	}
+	var allKennels []entity.Kennel
+	if err := kdb.Order("id ASC").Find(&allKennels).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	kennels := make([]entity.Kennel, 0, len(allKennels))
+	for _, k := range allKennels {
+		if existedAt(k.CreatedAt, k.DeletedAt, at) {
+			kennels = append(kennels, k)
+		}
+	}
+
+	var allDogs []entity.Dog
+	if err := configs.DB().Unscoped().Find(&allDogs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	dogs := make([]entity.Dog, 0, len(allDogs))
+	ids := make([]uint, 0, len(allDogs))
+	for _, d := range allDogs {
+		if existedAt(d.CreatedAt, d.DeletedAt, at) {
+			dogs = append(dogs, d)
+			ids = append(ids, d.ID)
+		}
+	}
+	moves, err := entity.LoadKennelMoves(configs.DB(), ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+
+	type dogRow struct {
+		ID   uint   `json:"id"`
+		Name string `json:"name"`
+	}
+	byKennel := map[uint][]dogRow{}
+	for _, d := range dogs {
+		if kid := entity.KennelAt(d, moves[d.ID], at); kid != nil {
+			byKennel[*kid] = append(byKennel[*kid], dogRow{ID: d.ID, Name: d.Name})
+		}
+	}
+
+	type kennelRow struct {
+		KennelID uint     `json:"kennel_id"`
+		Name     string   `json:"name"`
+		ZoneID   uint     `json:"zone_id"`
+		Zone     string   `json:"zone"`
+		Capacity uint     `json:"capacity"`
+		Count    int      `json:"count"`
+		Dogs     []dogRow `json:"dogs"`
+	}
+	out := make([]kennelRow, 0, len(kennels))
+	for _, k := range kennels {
+		zone := ""
+		if k.Zone != nil {
+			zone = k.Zone.Name
+		}
+		ds := byKennel[k.ID]
+		if ds == nil {
+			ds = []dogRow{}
+		}
+		out = append(out, kennelRow{
+			KennelID: k.ID, Name: k.Name, ZoneID: k.ZoneID, Zone: zone,
+			Capacity: k.Capacity, Count: len(ds), Dogs: ds,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"at": at, "data": out})
+}
@@ -0,0 +1,72 @@
+package zcmanagement
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/migrations"
+	"example.com/project-sa/seeds"
+	"example.com/project-sa/utils/testutil"
+)
+
+func TestMain(m *testing.M) { testutil.Main(m, migrations.AutoMigrate, seeds.SeedAll) }
+
+// เซิร์ฟเวอร์ตั้งเวลาท้องถิ่นไม่ใช่ UTC: created_at เก็บพร้อม offset +07:00 แต่ตัวกรองส่งเวลา UTC
+func TestHistoryWithLocalTimezone(t *testing.T) {
+	bkk, err := time.LoadLocation("Asia/Bangkok")
+	if err != nil {
+		t.Skipf("no tzdata: %v", err)
+	}
+	old := time.Local
+	time.Local = bkk
+	t.Cleanup(func() { time.Local = old })
+
+	db := configs.DB()
+	k := entity.Kennel{Name: "TZ-1", Capacity: 2, ZoneID: 1}
+	if err := db.Create(&k).Error; err != nil {
+		t.Fatal(err)
+	}
+	dog := entity.Dog{Name: "Tz", KennelID: &k.ID, BreedID: 1, AnimalSexID: 1, AnimalSizeID: 1}
+	if err := db.Create(&dog).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := entity.RecordKennelMove(db, dog.ID, nil, &k.ID, nil, "test"); err != nil {
+		t.Fatal(err)
+	}
+
+	r := testutil.Router(1)
+	r.GET("/kennels/occupancy", GetOccupancyAt)
+	r.GET("/kennels/:id/history", GetKennelHistory)
+
+	occupants := func(at time.Time) int {
+		t.Helper()
+		path := fmt.Sprintf("/kennels/occupancy?kennel_id=%d&at=%s", k.ID, url.QueryEscape(at.UTC().Format(time.RFC3339)))
+		code, out := testutil.Do(t, r, http.MethodGet, path, nil)
+		if code != http.StatusOK {
+			t.Fatalf("GET %s = %d %v", path, code, out)
+		}
+		rows, _ := out["data"].([]any)
+		if len(rows) == 0 {
+			return -1 // คอกยังไม่มีในระบบ
+		}
+		return int(rows[0].(map[string]any)["count"].(float64))
+	}
+	if got := occupants(time.Now().Add(time.Minute)); got != 1 {
+		t.Errorf("occupancy now = %d, want 1", got)
+	}
+	if got := occupants(time.Now().Add(-time.Hour)); got != -1 {
+		t.Errorf("occupancy an hour ago = %d, want the kennel not to exist yet", got)
+	}
+
+	today := time.Now().In(bkk).Format("2006-01-02")
+	path := fmt.Sprintf("/kennels/%d/history?from=%s&to=%s", k.ID, today, today)
+	code, out := testutil.Do(t, r, http.MethodGet, path, nil)
+	if rows, _ := out["data"].([]any); code != http.StatusOK || len(rows) != 1 {
+		t.Errorf("GET %s = %d %v, want one move", path, code, out)
+	}
+}
@@ -0,0 +1,213 @@
+package zcmanagement
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/middlewares"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+/* ========== กฎการอยู่คอกเดียวกัน ========== */
+
+type kennelRuleRequest struct {
+	Name               string `json:"name" binding:"required"`
+	Kind               string `json:"kind" binding:"required"`
+	Severity           string `json:"severity"` // warn (ค่าเริ่มต้น) | block
+	Enabled            *bool  `json:"enabled"`  // ไม่ส่ง = เปิด
+	Note               string `json:"note"`
+	SexID              *uint  `json:"sex_id"`
+	OtherSexID         *uint  `json:"other_sex_id"`
+	PersonalityID      *uint  `json:"personality_id"`
+	OtherPersonalityID *uint  `json:"other_personality_id"`
+	MaxSizeGap         int    `json:"max_size_gap"`
+}
+
+type fieldErrors map[string]string
+
+// validateRule ตรวจพารามิเตอร์ที่แต่ละประเภทกฎต้องใช้
+func validateRule(tx *gorm.DB, req *kennelRuleRequest) (fieldErrors, error) {
+	fields := fieldErrors{}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		fields["name"] = "is required"
+	}
+	if req.Severity == "" {
+		req.Severity = entity.KennelRuleWarn
+	}
+	if req.Severity != entity.KennelRuleWarn && req.Severity != entity.KennelRuleBlock {
+		fields["severity"] = "must be warn or block"
+	}
+	switch req.Kind {
+	case entity.KennelRuleIntactSameSex:
+		if req.SexID == nil {
+			fields["sex_id"] = "is required"
+		}
+	case entity.KennelRuleIntactMixedSex:
+		if req.SexID == nil {
+			fields["sex_id"] = "is required"
+		}
+		if req.OtherSexID == nil {
+			fields["other_sex_id"] = "is required"
+		} else if req.SexID != nil && *req.SexID == *req.OtherSexID {
+			fields["other_sex_id"] = "must differ from sex_id (use intact_same_sex)"
+		}
+	case entity.KennelRuleSizeGap:
+		if req.MaxSizeGap < 0 {
+			fields["max_size_gap"] = "must be 0 or more"
+		}
+	case entity.KennelRulePersonality:
+		if req.PersonalityID == nil {
+			fields["personality_id"] = "is required"
+		}
+	case entity.KennelRuleQuarantineMix, entity.KennelRuleKennelSize:
+	default:
+		fields["kind"] = "must be intact_same_sex, intact_mixed_sex, size_gap, personality_conflict, quarantine_mix or kennel_size"
+	}
+	for _, ref := range []struct {
+		field string
+		id    *uint
+		model any
+	}{
+		{"sex_id", req.SexID, &entity.AnimalSex{}},
+		{"other_sex_id", req.OtherSexID, &entity.AnimalSex{}},
+		{"personality_id", req.PersonalityID, &entity.Personality{}},
+		{"other_personality_id", req.OtherPersonalityID, &entity.Personality{}},
+	} {
+		if ref.id == nil {
+			continue
+		}
+		if err := tx.First(ref.model, *ref.id).Error; err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, err
+			}
+			fields[ref.field] = "not found"
+		}
+	}
+	return fields, nil
+}
+
+func (req kennelRuleRequest) columns() map[string]any {
+	enabled := req.Enabled == nil || *req.Enabled
+	return map[string]any{
+		"name":                 req.Name,
+		"kind":                 req.Kind,
+		"severity":             req.Severity,
+		"enabled":              enabled,
+		"note":                 req.Note,
+		"sex_id":               req.SexID,
+		"other_sex_id":         req.OtherSexID,
+		"personality_id":       req.PersonalityID,
+		"other_personality_id": req.OtherPersonalityID,
+		"max_size_gap":         req.MaxSizeGap,
+	}
+}
+
+func preloadRule(db *gorm.DB) *gorm.DB {
+	return db.Preload("Sex").Preload("OtherSex").Preload("Personality").Preload("OtherPersonality")
+}
+
+// GET /kennel-rules
+func GetKennelRules(c *gin.Context) {
+	var rules []entity.KennelRule
+	if err := preloadRule(configs.DB()).Order("id ASC").Find(&rules).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": rules})
+}
+
+// POST /kennel-rules
+func CreateKennelRule(c *gin.Context) {
+	if middlewares.StaffID(c) == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var req kennelRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	fields, err := validateRule(configs.DB(), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	if len(fields) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid kennel rule", "fields": fields})
+		return
+	}
+	rule := entity.KennelRule{Name: req.Name, Kind: req.Kind, Severity: req.Severity}
+	err = configs.DB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&rule).Error; err != nil {
+			return err
+		}
+		// enabled มี default:true สร้างด้วย struct แล้วค่า false จะหาย ต้องเขียนซ้ำด้วย map
+		return tx.Model(&rule).Updates(req.columns()).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "create failed: " + err.Error()})
+		return
+	}
+	preloadRule(configs.DB()).First(&rule, rule.ID)
+	c.JSON(http.StatusCreated, gin.H{"data": rule})
+}
+
+// PUT /kennel-rules/:id — แทนที่ทั้งกฎ (enabled=false = ปิดชั่วคราว)
+func UpdateKennelRule(c *gin.Context) {
+	if middlewares.StaffID(c) == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	var rule entity.KennelRule
+	if err := configs.DB().First(&rule, c.Param("id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "kennel rule not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	var req kennelRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	fields, err := validateRule(configs.DB(), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	if len(fields) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid kennel rule", "fields": fields})
+		return
+	}
+	if err := configs.DB().Model(&rule).Updates(req.columns()).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed: " + err.Error()})
+		return
+	}
+	preloadRule(configs.DB()).First(&rule, rule.ID)
+	c.JSON(http.StatusOK, gin.H{"data": rule})
+}
+
+// DELETE /kennel-rules/:id
+func DeleteKennelRule(c *gin.Context) {
+	if middlewares.StaffID(c) == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	res := configs.DB().Delete(&entity.KennelRule{}, c.Param("id"))
+	if res.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "delete failed: " + res.Error.Error()})
+		return
+	}
+	if res.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "kennel rule not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "kennel rule deleted"})
+}
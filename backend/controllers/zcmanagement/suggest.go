@@ -0,0 +1,205 @@
+package zcmanagement
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+/* ========== ตรวจ/แนะนำคอกสำหรับสุนัข ========== */
+
+type kennelSuggestion struct {
+	KennelID    uint                         `json:"kennel_id"`
+	Name        string                       `json:"name"`
+	ZoneID      uint                         `json:"zone_id"`
+	Zone        string                       `json:"zone"`
+	Environment string                       `json:"environment"`
+	Capacity    uint                         `json:"capacity"`
+	Occupied    int                          `json:"occupied"`
+	Free        int                          `json:"free"`
+	Score       int                          `json:"score"`
+	Reasons     []string                     `json:"reasons"`
+	Warnings    []entity.KennelRuleViolation `json:"warnings"`
+}
+
+func loadPlacementDog(c *gin.Context) (*entity.PlacementDog, bool) {
+	dogs, err := entity.LoadPlacementDogs(configs.DB(), "id = ?", c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return nil, false
+	}
+	if len(dogs) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "dog not found"})
+		return nil, false
+	}
+	return &dogs[0], true
+}
+
+// GET /dogs/:id/kennel-check?kennel_id= — ตรวจกฎก่อนย้ายจริง (ไม่บันทึกอะไร)
+func CheckDogKennel(c *gin.Context) {
+	dog, ok := loadPlacementDog(c)
+	if !ok {
+		return
+	}
+	var kennel entity.Kennel
+	if err := configs.DB().Preload("Zone").Preload("AllowedSizes").First(&kennel, c.Query("kennel_id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid kennel_id"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	rules, err := entity.LoadKennelRules(configs.DB())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	occupants, err := entity.LoadPlacementDogs(configs.DB(), "kennel_id = ? AND id <> ?", kennel.ID, dog.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	if kennel.Unassigned() {
+		c.JSON(http.StatusOK, gin.H{"allowed": true, "full": false, "isolation": !dog.Quarantined, "violations": []entity.KennelRuleViolation{}})
+		return
+	}
+	violations := entity.EvaluatePlacement(rules, *dog, kennel, occupants)
+	allowed := true
+	for _, v := range violations {
+		if v.Severity == entity.KennelRuleBlock {
+			allowed = false
+		}
+	}
+	full := len(occupants) >= int(kennel.Capacity)
+	isolation := !dog.Quarantined || kennel.Isolated()
+	c.JSON(http.StatusOK, gin.H{
+		"allowed":    allowed && !full && isolation,
+		"full":       full,
+		"isolation":  isolation,
+		"violations": violations,
+	})
+}
+
+// GET /dogs/:id/kennel-suggestions?zone_id=&limit= — คอกที่ยังว่างและไม่ขัดกฎ block เรียงตามความเหมาะสม
+// คะแนนเริ่ม 100: หักตามคำเตือน คอกแยกโรคสำหรับตัวที่ไม่ได้กักโรค และความแน่นของคอก
+// บวกเมื่อคอกกำหนดขนาดตรงกับตัว หรืออยู่โซนเดิม
+func SuggestKennels(c *gin.Context) {
+	dog, ok := loadPlacementDog(c)
+	if !ok {
+		return
+	}
+	limit := 5
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+		limit = n
+	}
+
+	kdb := configs.DB().Preload("Zone").Preload("AllowedSizes")
+	if v := c.Query("zone_id"); v != "" {
+		kdb = kdb.Where("zone_id = ?", v)
+	}
+	var kennels []entity.Kennel
+	if err := kdb.Order("name ASC").Find(&kennels).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	rules, err := entity.LoadKennelRules(configs.DB())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	all, err := entity.LoadPlacementDogs(configs.DB(), "kennel_id IS NOT NULL AND id <> ?", dog.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "query failed: " + err.Error()})
+		return
+	}
+	byKennel := map[uint][]entity.PlacementDog{}
+	for _, d := range all {
+		byKennel[*d.KennelID] = append(byKennel[*d.KennelID], d)
+	}
+	var currentZone uint
+	if dog.KennelID != nil {
+		var cur entity.Kennel
+		if err := configs.DB().Select("id", "zone_id").First(&cur, *dog.KennelID).Error; err == nil {
+			currentZone = cur.ZoneID
+		}
+	}
+
+	out := []kennelSuggestion{}
+	for _, k := range kennels {
+		if k.Unassigned() || (dog.KennelID != nil && k.ID == *dog.KennelID) {
+			continue
+		}
+		occupants := byKennel[k.ID]
+		free := int(k.Capacity) - len(occupants)
+		if free <= 0 {
+			continue
+		}
+		isolated := k.Isolated()
+		// ตัวที่กักโรคอยู่ต้องเข้าคอกแยกโรคเท่านั้น
+		if dog.Quarantined && !isolated {
+			continue
+		}
+		violations := entity.EvaluatePlacement(rules, *dog, k, occupants)
+		blocked := false
+		for _, v := range violations {
+			if v.Severity == entity.KennelRuleBlock {
+				blocked = true
+			}
+		}
+		if blocked {
+			continue
+		}
+
+		s := kennelSuggestion{
+			KennelID: k.ID, Name: k.Name, ZoneID: k.ZoneID, Environment: k.Environment,
+			Capacity: k.Capacity, Occupied: len(occupants), Free: free,
+			Score: 100, Reasons: []string{}, Warnings: violations,
+		}
+		if k.Zone != nil {
+			s.Zone = k.Zone.Name
+		}
+		if len(violations) > 0 {
+			s.Score -= 20 * len(violations)
+			s.Reasons = append(s.Reasons, strconv.Itoa(len(violations))+" rule warning(s)")
+		}
+		if isolated && !dog.Quarantined {
+			s.Score -= 40
+			s.Reasons = append(s.Reasons, "isolation kennel (keep free for quarantine)")
+		}
+		if len(k.AllowedSizes) > 0 && k.AcceptsSize(dog.SizeID) {
+			s.Score += 10
+			s.Reasons = append(s.Reasons, "kennel is sized for this dog")
+		}
+		if currentZone != 0 && k.ZoneID == currentZone {
+			s.Score += 5
+			s.Reasons = append(s.Reasons, "same zone as current kennel")
+		}
+		if len(occupants) == 0 {
+			s.Reasons = append(s.Reasons, "empty kennel")
+		}
+		s.Score -= 10 * len(occupants) / int(k.Capacity)
+		out = append(out, s)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		return out[i].Free > out[j].Free
+	})
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	c.JSON(http.StatusOK, gin.H{"dog_id": dog.ID, "quarantined": dog.Quarantined, "data": out})
+}
@@ -1,26 +1,45 @@
 package zcmanagement
 
 import (
+	"errors"
 	"net/http"
+	"sort"
 	"strconv"
 
 	"example.com/project-sa/configs"
 	"example.com/project-sa/entity"
+	"example.com/project-sa/middlewares"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
 type dogRef struct {
-	DogID uint `json:"dog_id" form:"dog_id"`
+	DogID  uint   `json:"dog_id" form:"dog_id"`
+	Reason string `json:"reason" form:"reason"`
 }
 
-func parseIDs(c *gin.Context) (kennelID uint, dogID uint, ok bool) {
+type batchMoveRequest struct {
+	Moves []struct {
+		DogID    uint  `json:"dog_id" binding:"required"`
+		KennelID *uint `json:"kennel_id"` // null/0 = เอาออกจากคอก
+	} `json:"moves" binding:"required,min=1,dive"`
+	Reason string `json:"reason"`
+}
+
+// moveError ย้ายไม่ได้ พร้อม HTTP status ที่ควรตอบ
+type moveError struct {
+	status int
+	msg    string
+}
+
+func (e *moveError) Error() string { return e.msg }
+
+func parseIDs(c *gin.Context) (kennelID uint, ref dogRef, ok bool) {
 	// path param
-	kidStr := c.Param("kennel_id")
-	kid64, err := strconv.ParseUint(kidStr, 10, 64)
+	kid64, err := strconv.ParseUint(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid kennel_id path param"})
-		return 0, 0, false
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid kennel id path param"})
+		return 0, ref, false
 	}
 	kennelID = uint(kid64)
 
@@ -39,105 +58,232 @@ func parseIDs(c *gin.Context) (kennelID uint, dogID uint, ok bool) {
 	}
 	if req.DogID == 0 {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "dog_id is required"})
-		return 0, 0, false
+		return 0, req, false
 	}
-	return kennelID, req.DogID, true
+	if r := c.Query("reason"); r != "" && req.Reason == "" {
+		req.Reason = r
+	}
+	return kennelID, req, true
 }
 
-// PUT /kennel/:kennel_id/dog   { dog_id }
-func UpdateDogInKennel(c *gin.Context) {
-	kennelID, dogID, ok := parseIDs(c)
-	if !ok {
-		return
+// respondMoveError แปลง error จากการย้ายเป็น HTTP status
+func respondMoveError(c *gin.Context, prefix string, err error) {
+	var me *moveError
+	switch {
+	case errors.As(err, &me):
+		c.JSON(me.status, gin.H{"error": prefix + me.msg})
+	case entity.IsCapacityError(err), entity.IsIsolationError(err):
+		c.JSON(http.StatusConflict, gin.H{"error": prefix + err.Error()})
+	case entity.IsCompatibilityError(err):
+		var ce *entity.CompatibilityError
+		errors.As(err, &ce)
+		c.JSON(http.StatusConflict, gin.H{"error": prefix + err.Error(), "violations": ce.Violations})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": prefix + err.Error()})
 	}
+}
 
-	// ensure the kennel exists (optional but nice)
-	var k entity.Kennel
-	if err := configs.DB().First(&k, kennelID).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "kennel not found"})
-			return
+// moveDog ย้ายสุนัขเข้าคอก to (nil = เอาออกจากคอก) ภายใน tx เดียว:
+// ตรวจกักโรค/ความจุ ย้าย แล้วบันทึกทั้งสมุดย้ายคอกและประวัติสุนัข
+// checkCapacity=false ใช้กับการย้ายหลายตัวที่ตรวจความจุรวมตอนท้าย
+func moveDog(tx *gorm.DB, dogID uint, to *uint, staffID uint, reason string, checkCapacity bool) (from *uint, err error) {
+	var dog entity.Dog
+	if err := tx.First(&dog, dogID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &moveError{http.StatusNotFound, "dog " + strconv.FormatUint(uint64(dogID), 10) + " not found"}
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "kennel query failed: " + err.Error()})
-		return
+		return nil, err
 	}
+	// สุนัขที่ไม่ได้อยู่ในศูนย์ (บ้านอุปถัมภ์/ส่งต่อแล้ว) ต้องรับกลับผ่าน flow ของตัวเองก่อน
+	if dog.Status != entity.DogStatusShelter {
+		return nil, &moveError{http.StatusConflict, "dog " + dog.Name + " is not in the shelter (status: " + dog.Status + ")"}
+	}
+	from = dog.KennelID
+	if to != nil && from != nil && *from == *to {
+		return from, nil
+	}
+	if to == nil && from == nil {
+		return nil, nil
+	}
+	if to != nil {
+		// สุนัขที่กักโรคอยู่ย้ายได้เฉพาะคอกในโซนแยกโรค
+		if err := entity.CheckKennelIsolation(tx, dogID, *to); err != nil {
+			return nil, err
+		}
+		if checkCapacity {
+			if _, err := entity.ReserveKennelSlot(tx, *to, dogID); err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return nil, &moveError{http.StatusNotFound, "kennel not found"}
+				}
+				return nil, err
+			}
+		}
+	}
+	if err := tx.Model(&dog).Updates(map[string]any{
+		"kennel_id":     to,
+		"updated_by_id": staffID,
+	}).Error; err != nil {
+		return nil, err
+	}
+	return from, entity.RecordDogKennelChange(tx, dogID, from, to, &staffID, reason)
+}
 
-	res := configs.DB().Model(&entity.Dog{}).
-		Where("id = ?", dogID).
-		Update("kennel_id", kennelID)
-
-	if res.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "assign failed: " + res.Error.Error()})
+// PUT /kennels/:id/dog   { dog_id }
+func UpdateDogInKennel(c *gin.Context) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"updated": res.RowsAffected, "dog_id": dogID, "kennel_id": kennelID})
-	staffID := uint(0)
-	if v, ok := c.Get("staff_id"); ok {
-		if id, ok2 := v.(uint); ok2 {
-			staffID = id
-		}
+	kennelID, ref, ok := parseIDs(c)
+	if !ok {
+		return
 	}
+	dogID := ref.DogID
 
-	// When logging:
-	log := entity.KennelManagement{
-		KennelID: kennelID,
-		DogID:    dogID,
-		StaffID:  staffID,
-		Action:   "assign", // or "remove"
+	var from *uint
+	var warnings []entity.KennelRuleViolation
+	err := configs.DB().Transaction(func(tx *gorm.DB) error {
+		var k entity.Kennel
+		if err := tx.First(&k, kennelID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return &moveError{http.StatusNotFound, "kennel not found"}
+			}
+			return err
+		}
+		var err error
+		if from, err = moveDog(tx, dogID, &kennelID, *staffID, ref.Reason, true); err != nil {
+			return err
+		}
+		warnings, err = entity.CheckKennelRules(tx, dogID, kennelID)
+		return err
+	})
+	if err != nil {
+		respondMoveError(c, "assign failed: ", err)
+		return
 	}
-	_ = configs.DB().Create(&log)
+	c.JSON(http.StatusOK, gin.H{"dog_id": dogID, "from_kennel_id": from, "kennel_id": kennelID, "kennel_warnings": warnings})
 }
 
-
+// DELETE /kennels/:id/dog?dog_id= — เอาสุนัขออกจากคอก (kennel_id = NULL)
 func DeleteDogFromKennel(c *gin.Context) {
-	kid64, _ := strconv.ParseUint(c.Param("kennel_id"), 10, 64)
-	kennelID := uint(kid64)
-
-	var dogID uint
-	if qs := c.Query("dog_id"); qs != "" {
-		if v, err := strconv.ParseUint(qs, 10, 64); err == nil {
-			dogID = uint(v)
-		}
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	kennelID, ref, ok := parseIDs(c)
+	if !ok {
+		return
 	}
-	if dogID == 0 {
-		var body struct {
-			DogID uint `json:"dog_id" form:"dog_id"`
+	dogID := ref.DogID
+
+	err := configs.DB().Transaction(func(tx *gorm.DB) error {
+		var dog entity.Dog
+		if err := tx.Select("id", "kennel_id").First(&dog, dogID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return &moveError{http.StatusNotFound, "dog not found"}
+			}
+			return err
 		}
-		_ = c.ShouldBind(&body)
-		if body.DogID == 0 {
-			_ = c.ShouldBindJSON(&body)
+		if dog.KennelID == nil || *dog.KennelID != kennelID {
+			return &moveError{http.StatusConflict, "dog is not in this kennel"}
 		}
-		dogID = body.DogID
-	}
-	if dogID == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "dog_id is required"})
+		_, err := moveDog(tx, dogID, nil, *staffID, ref.Reason, false)
+		return err
+	})
+	if err != nil {
+		respondMoveError(c, "remove failed: ", err)
 		return
 	}
+	c.JSON(http.StatusOK, gin.H{"dog_id": dogID, "from_kennel_id": kennelID, "kennel_id": nil})
+}
 
-	res := configs.DB().Model(&entity.Dog{}).
-		Where("id = ? AND kennel_id = ?", dogID, kennelID).
-		Update("kennel_id", 0)
-
-	if res.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "remove failed: " + res.Error.Error()})
+// POST /kennels/moves { moves: [{dog_id, kennel_id}], reason } — ย้ายหลายตัวพร้อมกัน (เช่น จัดคอกใหม่ทั้งโซน)
+// ทั้งชุดสำเร็จหรือไม่สำเร็จพร้อมกัน ความจุตรวจจากผลลัพธ์สุดท้าย จึงสลับคอกกันระหว่างคอกที่เต็มได้
+func BatchMoveDogs(c *gin.Context) {
+	staffID := middlewares.StaffID(c)
+	if staffID == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"updated": res.RowsAffected, "dog_id": dogID, "kennel_id": kennelID})
-	staffID := uint(0)
-	if v, ok := c.Get("staff_id"); ok {
-		if id, ok2 := v.(uint); ok2 {
-			staffID = id
+	var req batchMoveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload: " + err.Error()})
+		return
+	}
+	seen := map[uint]bool{}
+	for _, m := range req.Moves {
+		if seen[m.DogID] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "dog " + strconv.FormatUint(uint64(m.DogID), 10) + " is listed more than once"})
+			return
 		}
+		seen[m.DogID] = true
 	}
 
-	// When logging:
-	log := entity.KennelManagement{
-		KennelID: kennelID,
-		DogID:    dogID,
-		StaffID:  staffID,
-		Action:   "unassign",
+	type moveResult struct {
+		DogID        uint  `json:"dog_id"`
+		FromKennelID *uint `json:"from_kennel_id"`
+		KennelID     *uint `json:"kennel_id"`
+
+		Warnings []entity.KennelRuleViolation `json:"kennel_warnings,omitempty"`
+	}
+	results := make([]moveResult, 0, len(req.Moves))
+	err := configs.DB().Transaction(func(tx *gorm.DB) error {
+		// ล็อกคอกปลายทางทั้งหมดก่อน เรียงตาม id กัน deadlock
+		targets := map[uint]*entity.Kennel{}
+		ids := []uint{}
+		for _, m := range req.Moves {
+			if m.KennelID != nil && *m.KennelID > 0 && targets[*m.KennelID] == nil {
+				targets[*m.KennelID] = &entity.Kennel{}
+				ids = append(ids, *m.KennelID)
+			}
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		for _, id := range ids {
+			k, err := entity.LockKennel(tx, id)
+			if err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return &moveError{http.StatusBadRequest, "kennel " + strconv.FormatUint(uint64(id), 10) + " not found"}
+				}
+				return err
+			}
+			targets[id] = k
+		}
+		for _, m := range req.Moves {
+			to := m.KennelID
+			if to != nil && *to == 0 {
+				to = nil
+			}
+			from, err := moveDog(tx, m.DogID, to, *staffID, req.Reason, false)
+			if err != nil {
+				return err
+			}
+			results = append(results, moveResult{DogID: m.DogID, FromKennelID: from, KennelID: to})
+		}
+		for _, id := range ids {
+			if err := entity.CheckKennelOccupancy(tx, targets[id]); err != nil {
+				return err
+			}
+		}
+		// กฎการอยู่คอกตรวจกับผลลัพธ์สุดท้ายเช่นกัน (สลับคอกกันแล้วไม่ได้อยู่ด้วยกันจริง)
+		for i, m := range results {
+			if m.KennelID == nil {
+				continue
+			}
+			kw, err := entity.CheckKennelRules(tx, m.DogID, *m.KennelID)
+			if err != nil {
+				return err
+			}
+			results[i].Warnings = kw
+		}
+		return nil
+	})
+	if err != nil {
+		respondMoveError(c, "move failed: ", err)
+		return
 	}
-	_ = configs.DB().Create(&log)
+	c.JSON(http.StatusOK, gin.H{"data": results})
 }
 
 func preloadDog(db *gorm.DB) *gorm.DB {
@@ -150,9 +296,9 @@ func preloadDog(db *gorm.DB) *gorm.DB {
 		Preload("DogPersonalities.Personality")
 }
 
-// GET /kennel/:kennel_id/dog
+// GET /kennels/:id/dogs
 func GetDogInKennel(c *gin.Context) {
-	kidStr := c.Param("kennel_id")
+	kidStr := c.Param("id")
 	kid64, err := strconv.ParseUint(kidStr, 10, 64)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid kennel_id"})
@@ -192,44 +338,11 @@ func GetAll(c *gin.Context) {
 	})
 }
 
-type CreateZCManagementLogRequest struct {
-    KennelID uint   `json:"kennel_id" binding:"required"`
-    DogID    uint   `json:"dog_id" binding:"required"`
-    StaffID  uint   `json:"staff_id" binding:"required"`
-    Action   string `json:"action" binding:"required"` // "assign" or "remove"
-}
-
+// POST /zcmanagement/log — เลิกใช้แล้ว: การย้ายทุกช่องทางบันทึกลงสมุดย้ายคอกอัตโนมัติใน tx เดียวกับการย้าย
+// ตอบ 410 ให้ client รุ่นเก่ารู้ว่าไม่ต้องส่ง log เอง (ดูประวัติที่ GET /dogs/:id/locations, /kennels/:id/history)
 func CreateZCManagementLog(c *gin.Context) {
-    var req struct {
-        KennelID uint   `json:"kennel_id" binding:"required"`
-        DogID    uint   `json:"dog_id" binding:"required"`
-        Action   string `json:"action" binding:"required"`
-    }
-    if err := c.ShouldBindJSON(&req); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-        return
-    }
-
-    staffID := uint(0)
-    if v, ok := c.Get("staff_id"); ok {
-        if id, ok2 := v.(uint); ok2 {
-            staffID = id
-        }
-    }
-    if staffID == 0 {
-        c.JSON(http.StatusUnauthorized, gin.H{"error": "staff_id not found in context"})
-        return
-    }
-
-    log := entity.KennelManagement{
-        KennelID: req.KennelID,
-        DogID:    req.DogID,
-        StaffID:  staffID,
-        Action:   req.Action,
-    }
-    if err := configs.DB().Create(&log).Error; err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-        return
-    }
-    c.JSON(http.StatusOK, gin.H{"message": "log created", "data": log})
+	c.JSON(http.StatusGone, gin.H{
+		"error": "kennel moves are logged automatically when a dog is moved; this endpoint no longer accepts logs. " +
+			"Read the history from GET /dogs/:id/locations or GET /kennels/:id/history",
+	})
 }
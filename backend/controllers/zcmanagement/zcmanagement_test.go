@@ -0,0 +1,163 @@
+package zcmanagement
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"example.com/project-sa/configs"
+	"example.com/project-sa/entity"
+	"example.com/project-sa/utils/testutil"
+	"github.com/gin-gonic/gin"
+)
+
+func moveRouter() *gin.Engine {
+	r := testutil.Router(1)
+	r.PUT("/kennels/:id/dog", UpdateDogInKennel)
+	r.DELETE("/kennels/:id/dog", DeleteDogFromKennel)
+	r.POST("/kennels/moves", BatchMoveDogs)
+	return r
+}
+
+// สุนัขทำหมันแล้ว ไม่ติดกฎการอยู่คอกเรื่องเพศ
+func newMoveFixture(t *testing.T, prefix string, capacities []uint, dogs int) ([]entity.Kennel, []entity.Dog) {
+	t.Helper()
+	db := configs.DB()
+	sterilized, err := entity.ParseDate("2024-01-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ks []entity.Kennel
+	for i, c := range capacities {
+		k := entity.Kennel{Name: fmt.Sprintf("%s-%d", prefix, i+1), Capacity: c, ZoneID: 1}
+		if err := db.Create(&k).Error; err != nil {
+			t.Fatal(err)
+		}
+		ks = append(ks, k)
+	}
+	var ds []entity.Dog
+	for i := 0; i < dogs; i++ {
+		d := entity.Dog{Name: fmt.Sprintf("%s dog %d", prefix, i+1), BreedID: 1, AnimalSexID: 1, AnimalSizeID: 1,
+			Status: entity.DogStatusShelter, SterilizedAt: sterilized}
+		if err := db.Create(&d).Error; err != nil {
+			t.Fatal(err)
+		}
+		ds = append(ds, d)
+	}
+	return ks, ds
+}
+
+func kennelOf(t *testing.T, dogID uint) *uint {
+	t.Helper()
+	var d entity.Dog
+	if err := configs.DB().First(&d, dogID).Error; err != nil {
+		t.Fatal(err)
+	}
+	return d.KennelID
+}
+
+func moves(t *testing.T, dogID uint) []entity.KennelManagement {
+	t.Helper()
+	var rows []entity.KennelManagement
+	if err := configs.DB().Where("dog_id = ?", dogID).Order("id ASC").Find(&rows).Error; err != nil {
+		t.Fatal(err)
+	}
+	return rows
+}
+
+func TestKennelMovesAreTransactional(t *testing.T) {
+	ks, ds := newMoveFixture(t, "MV", []uint{1, 2}, 2)
+	r := moveRouter()
+	put := func(k entity.Kennel, d entity.Dog) int {
+		code, _ := testutil.Do(t, r, http.MethodPut, fmt.Sprintf("/kennels/%d/dog", k.ID), gin.H{"dog_id": d.ID})
+		return code
+	}
+
+	if code := put(ks[0], ds[0]); code != http.StatusOK {
+		t.Fatalf("assign into empty kennel = %d", code)
+	}
+	if code := put(ks[0], ds[1]); code != http.StatusConflict {
+		t.Errorf("assign into full kennel = %d, want %d", code, http.StatusConflict)
+	}
+	if k := kennelOf(t, ds[1].ID); k != nil || len(moves(t, ds[1].ID)) != 0 {
+		t.Errorf("refused move left kennel %v and %d ledger rows, want nothing", k, len(moves(t, ds[1].ID)))
+	}
+
+	if code := put(ks[1], ds[0]); code != http.StatusOK {
+		t.Fatalf("move between kennels = %d", code)
+	}
+	rows := moves(t, ds[0].ID)
+	if len(rows) != 2 || rows[1].FromKennelID == nil || *rows[1].FromKennelID != ks[0].ID || *rows[1].ToKennelID != ks[1].ID {
+		t.Errorf("ledger = %+v, want assign then move %d -> %d", rows, ks[0].ID, ks[1].ID)
+	}
+
+	if code, _ := testutil.Do(t, r, http.MethodDelete, fmt.Sprintf("/kennels/%d/dog", ks[0].ID), gin.H{"dog_id": ds[0].ID}); code != http.StatusConflict {
+		t.Errorf("remove from a kennel the dog is not in = %d, want %d", code, http.StatusConflict)
+	}
+	testutil.MustDo(t, r, http.MethodDelete, fmt.Sprintf("/kennels/%d/dog", ks[1].ID), gin.H{"dog_id": ds[0].ID})
+	var raw struct{ KennelID *uint }
+	configs.DB().Model(&entity.Dog{}).Select("kennel_id").Where("id = ?", ds[0].ID).Scan(&raw)
+	if raw.KennelID != nil {
+		t.Errorf("kennel_id after removal = %d, want NULL", *raw.KennelID)
+	}
+	if rows := moves(t, ds[0].ID); rows[len(rows)-1].ToKennelID != nil {
+		t.Errorf("last ledger row = %+v, want an unassign", rows[len(rows)-1])
+	}
+}
+
+func TestBatchMoveSwapsFullKennelsAtomically(t *testing.T) {
+	ks, ds := newMoveFixture(t, "BM", []uint{1, 1, 1}, 2)
+	r := moveRouter()
+	testutil.MustDo(t, r, http.MethodPost, "/kennels/moves", gin.H{"moves": []gin.H{
+		{"dog_id": ds[0].ID, "kennel_id": ks[0].ID}, {"dog_id": ds[1].ID, "kennel_id": ks[1].ID},
+	}})
+
+	// คอกเต็มทั้งสองคอก แต่สลับกันได้เพราะตรวจความจุจากผลลัพธ์สุดท้าย
+	testutil.MustDo(t, r, http.MethodPost, "/kennels/moves", gin.H{"moves": []gin.H{
+		{"dog_id": ds[0].ID, "kennel_id": ks[1].ID}, {"dog_id": ds[1].ID, "kennel_id": ks[0].ID},
+	}})
+	if a, b := kennelOf(t, ds[0].ID), kennelOf(t, ds[1].ID); *a != ks[1].ID || *b != ks[0].ID {
+		t.Errorf("after swap kennels = %d, %d, want %d, %d", *a, *b, ks[1].ID, ks[0].ID)
+	}
+
+	// ย้ายทั้งสองตัวเข้าคอกความจุ 1: ทั้งชุดต้องไม่เกิดขึ้นเลย
+	code, _ := testutil.Do(t, r, http.MethodPost, "/kennels/moves", gin.H{"moves": []gin.H{
+		{"dog_id": ds[0].ID, "kennel_id": ks[2].ID}, {"dog_id": ds[1].ID, "kennel_id": ks[2].ID},
+	}})
+	if code != http.StatusConflict {
+		t.Errorf("batch overfilling a kennel = %d, want %d", code, http.StatusConflict)
+	}
+	if a, b := kennelOf(t, ds[0].ID), kennelOf(t, ds[1].ID); *a != ks[1].ID || *b != ks[0].ID {
+		t.Errorf("refused batch moved dogs to %d, %d", *a, *b)
+	}
+	if n := len(moves(t, ds[0].ID)); n != 2 {
+		t.Errorf("ledger rows after refused batch = %d, want 2", n)
+	}
+}
+
+func TestConcurrentMovesDoNotOverfill(t *testing.T) {
+	ks, ds := newMoveFixture(t, "CC", []uint{1}, 6)
+	r := moveRouter()
+	var wg sync.WaitGroup
+	codes := make([]int, len(ds))
+	for i, d := range ds {
+		wg.Add(1)
+		go func(i int, d entity.Dog) {
+			defer wg.Done()
+			codes[i], _ = testutil.Do(t, r, http.MethodPut, fmt.Sprintf("/kennels/%d/dog", ks[0].ID), gin.H{"dog_id": d.ID})
+		}(i, d)
+	}
+	wg.Wait()
+	ok := 0
+	for _, c := range codes {
+		if c == http.StatusOK {
+			ok++
+		}
+	}
+	var n int64
+	configs.DB().Model(&entity.Dog{}).Where("kennel_id = ?", ks[0].ID).Count(&n)
+	if ok != 1 || n != 1 {
+		t.Errorf("concurrent moves into the last spot: %d succeeded, %d dogs in kennel (codes %v), want 1 and 1", ok, n, codes)
+	}
+}
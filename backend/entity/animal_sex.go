@@ -4,9 +4,26 @@ import (
 	"gorm.io/gorm"
 )
 
+// ชื่อเพศตามข้อมูลตั้งต้น
+const (
+	AnimalSexMale   = "ตัวผู้"
+	AnimalSexFemale = "ตัวเมีย"
+)
+
 type AnimalSex struct {
 	gorm.Model
 	Name string `json:"name"`
 
 	Dogs []Dog `gorm:"foreignKey:AnimalSexID" json:"dogs"`
-}
\ No newline at end of file
+}
+
+// SterilizationProcedure ประเภทการทำหมันที่ตรงกับเพศ ("" = ไม่ทราบเพศ)
+func (s AnimalSex) SterilizationProcedure() string {
+	switch s.Name {
+	case AnimalSexMale:
+		return ProcedureNeuter
+	case AnimalSexFemale:
+		return ProcedureSpay
+	}
+	return ""
+}
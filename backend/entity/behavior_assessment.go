@@ -0,0 +1,69 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// หัวข้อมาตรฐานของแบบประเมินพฤติกรรม
+const (
+	AssessmentHandling     = "handling"
+	AssessmentFoodGuarding = "food_guarding"
+	AssessmentDogDog       = "dog_dog"
+	AssessmentCat          = "cat"
+	AssessmentChild        = "child"
+)
+
+// ชื่อ Personality (ตาม seed lookups) ที่แบบประเมินแนะนำได้
+const (
+	PersonalityConfident = "มั่นใจในตนเอง"
+	PersonalityCalm      = "สงบ"
+	PersonalitySociable  = "เข้ากับคนอื่นง่าย"
+	PersonalityFriendly  = "เป็นมิตร"
+)
+
+// PersonalityScoreMinimums คะแนนขั้นต่ำรายหัวข้อที่ทำให้ระบบแนะนำ Personality นั้น
+var PersonalityScoreMinimums = map[string]map[string]int{
+	PersonalityFriendly:  {AssessmentHandling: 4, AssessmentChild: 4},
+	PersonalitySociable:  {AssessmentDogDog: 4, AssessmentCat: 4},
+	PersonalityCalm:      {AssessmentHandling: 5, AssessmentFoodGuarding: 4},
+	PersonalityConfident: {AssessmentHandling: 4, AssessmentDogDog: 3},
+}
+
+const (
+	AssessmentDraft     = "draft"
+	AssessmentCompleted = "completed"
+)
+
+// BehaviorAssessment = การประเมินพฤติกรรม 1 ครั้ง (ประเมินซ้ำได้เรื่อย ๆ ใช้ครั้งล่าสุดที่ completed)
+type BehaviorAssessment struct {
+	gorm.Model
+	DogID uint `gorm:"index" json:"dog_id"`
+	Dog   *Dog `gorm:"foreignKey:DogID" json:"dog,omitempty"`
+
+	AssessorID uint   `json:"assessor_id"`
+	Assessor   *Staff `gorm:"foreignKey:AssessorID" json:"assessor,omitempty"`
+
+	AssessedAt  time.Time  `json:"assessed_at"`
+	Status      string     `gorm:"not null;default:'draft'" json:"status"` // draft | completed
+	CompletedAt *time.Time `json:"completed_at"`
+	Notes       *string    `gorm:"type:text" json:"notes"`
+
+	// คำนวณตอน complete
+	TotalScore      int     `json:"total_score"`
+	MaxScore        int     `json:"max_score"`
+	SafeForAdoption *bool   `json:"safe_for_adoption"`
+	Restrictions    *string `json:"restrictions"` // เช่น "no_cats,adults_only"
+
+	Sections []BehaviorAssessmentSection `gorm:"foreignKey:AssessmentID;constraint:OnDelete:CASCADE" json:"sections"`
+}
+
+// BehaviorAssessmentSection = คะแนนรายหัวข้อ 1 (น่ากังวลมาก) - 5 (ไม่มีปัญหา), nil = ไม่ได้ทดสอบ
+type BehaviorAssessmentSection struct {
+	gorm.Model
+	AssessmentID uint    `gorm:"index" json:"assessment_id"`
+	Section      string  `gorm:"not null" json:"section"`
+	Score        *int    `json:"score"`
+	Notes        *string `gorm:"type:text" json:"notes"`
+}
@@ -13,4 +13,6 @@ type Building struct {
 	// StaffID uint `json:"staff_id`
 	// Staff *Staff `gorm:"foreignKey:StaffID`
 	// KennelManagements []KenelManagement `gorm:"foreignKey:BuildingID"`
+
+	Zones []Zone `gorm:"foreignKey:BuildingID" json:"zones,omitempty"`
 }
\ No newline at end of file
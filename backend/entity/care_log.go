@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ชนิดบันทึกการดูแลประจำวัน
+const (
+	CareLogFeeding    = "feeding"
+	CareLogWalk       = "walk"
+	CareLogStool      = "stool"
+	CareLogBehavior   = "behavior"
+	CareLogEnrichment = "enrichment"
+)
+
+// CareLog = บันทึกการดูแลรายวันของสุนัข (อาหาร, พาเดิน, อุจจาระ, พฤติกรรม, กิจกรรมเสริม)
+type CareLog struct {
+	gorm.Model
+	Type     string    `gorm:"not null;index" json:"type"`
+	LoggedAt time.Time `gorm:"index" json:"logged_at"`
+
+	AmountGrams     *float64 `json:"amount_grams"`     // feeding
+	DurationMinutes *int     `json:"duration_minutes"` // walk / enrichment
+	StoolScore      *int     `json:"stool_score"`      // stool: 1 (แข็ง) - 7 (เหลว)
+	Note            *string  `gorm:"type:text" json:"note"`
+
+	DogID uint `gorm:"index" json:"dog_id"`
+	Dog   *Dog `gorm:"foreignKey:DogID" json:"dog,omitempty"`
+
+	// คอกที่สุนัขอยู่ขณะบันทึก (ใช้สรุปรายโซนย้อนหลัง แม้สุนัขย้ายคอกไปแล้ว)
+	KennelID *uint   `json:"kennel_id"`
+	Kennel   *Kennel `gorm:"foreignKey:KennelID" json:"kennel,omitempty"`
+
+	// ผู้บันทึก: staff หรือ volunteer อย่างใดอย่างหนึ่ง
+	StaffID     *uint      `json:"staff_id"`
+	Staff       *Staff     `gorm:"foreignKey:StaffID" json:"staff,omitempty"`
+	VolunteerID *uint      `json:"volunteer_id"`
+	Volunteer   *Volunteer `gorm:"foreignKey:VolunteerID" json:"volunteer,omitempty"`
+}
@@ -0,0 +1,212 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ประเภทสินค้าในคลังคลินิก
+const (
+	ClinicItemVaccine = "vaccine"
+	ClinicItemDrug    = "drug"
+	ClinicItemSupply  = "supply"
+)
+
+// เหตุผลการเคลื่อนไหวสต็อก
+const (
+	StockReceive    = "receive"
+	StockVaccine    = "vaccine"    // ตัดจาก VaccineRecord
+	StockMedication = "medication" // ตัดจากการให้ยา
+	StockAdjust     = "adjust"     // ปรับยอดจากการตรวจนับ
+	StockDispose    = "dispose"    // ทิ้ง (หมดอายุ/เสียหาย/เรียกคืน)
+	StockReversal   = "reversal"   // คืนสต็อกเมื่อลบ/แก้บันทึก
+)
+
+type ClinicItem struct {
+	gorm.Model
+	Name         string `gorm:"uniqueIndex" json:"name"`
+	Kind         string `gorm:"index" json:"kind"` // vaccine | drug | supply
+	Unit         string `json:"unit"`              // dose | tablet | ml | piece
+	ReorderLevel int    `json:"reorder_level"`     // ต่ำกว่านี้แจ้งเตือน (รวมทุก lot ที่ใช้ได้)
+
+	// วัคซีนที่ตัดสต็อกจาก VaccineRecord
+	VaccineID *uint    `gorm:"uniqueIndex" json:"vaccine_id"`
+	Vaccine   *Vaccine `gorm:"foreignKey:VaccineID" json:"vaccine,omitempty"`
+
+	Lots []ClinicLot `gorm:"foreignKey:ItemID" json:"lots,omitempty"`
+}
+
+type ClinicLot struct {
+	gorm.Model
+	ItemID           uint        `gorm:"uniqueIndex:idx_clinic_lot" json:"item_id"`
+	Item             *ClinicItem `gorm:"foreignKey:ItemID" json:"item,omitempty"`
+	LotNumber        string      `gorm:"uniqueIndex:idx_clinic_lot" json:"lot_number"`
+	ExpiryDate       *Date       `json:"expiry_date"`
+	QuantityReceived int         `json:"quantity_received"`
+	QuantityOnHand   int         `json:"quantity_on_hand"`
+	Supplier         string      `json:"supplier"`
+	ReceivedAt       time.Time   `json:"received_at"`
+
+	RecalledAt   *time.Time `json:"recalled_at"`
+	RecallReason string     `json:"recall_reason"`
+}
+
+// Usable ใช้ได้ ณ วันที่ at (ไม่ถูกเรียกคืนและยังไม่หมดอายุ)
+func (l ClinicLot) Usable(at time.Time) bool {
+	if l.RecalledAt != nil {
+		return false
+	}
+	return l.ExpiryDate == nil || !l.ExpiryDate.Before(NewDate(at).Time)
+}
+
+// ความเคลื่อนไหวสต็อก (Quantity ติดลบ = ตัดออก)
+type StockMovement struct {
+	gorm.Model
+	LotID    uint       `gorm:"index" json:"lot_id"`
+	Lot      *ClinicLot `gorm:"foreignKey:LotID" json:"lot,omitempty"`
+	Quantity int        `json:"quantity"`
+	Reason   string     `gorm:"index" json:"reason"`
+	Note     string     `json:"note"`
+	At       time.Time  `json:"at"`
+
+	DogID            *uint `gorm:"index" json:"dog_id"`
+	Dog              *Dog  `gorm:"foreignKey:DogID" json:"dog,omitempty"`
+	VaccineRecordID  *uint `gorm:"index" json:"vaccine_record_id"`
+	AdministrationID *uint `gorm:"index" json:"administration_id"`
+
+	StaffID *uint  `json:"staff_id"`
+	Staff   *Staff `gorm:"foreignKey:StaffID" json:"staff,omitempty"`
+}
+
+// StockError ตัดสต็อกไม่ได้ (lot ไม่ถูกต้อง/หมดอายุ/ถูกเรียกคืน/ของไม่พอ)
+type StockError struct{ msg string }
+
+func (e *StockError) Error() string { return e.msg }
+
+func IsStockError(err error) bool {
+	var se *StockError
+	return errors.As(err, &se)
+}
+
+// StockUse รายการตัดสต็อก
+type StockUse struct {
+	ItemID    uint
+	LotNumber string // ว่าง = เลือก lot ที่หมดอายุก่อน (FEFO)
+	Quantity  int
+	At        time.Time
+	Reason    string
+
+	DogID            *uint
+	VaccineRecordID  *uint
+	AdministrationID *uint
+	StaffID          *uint
+}
+
+// ConsumeStock ตัดสต็อกตาม StockUse คืน lot ที่ถูกตัด
+func ConsumeStock(tx *gorm.DB, use StockUse) (*ClinicLot, error) {
+	var item ClinicItem
+	if err := tx.First(&item, use.ItemID).Error; err != nil {
+		return nil, err
+	}
+	var lot ClinicLot
+	if lotNo := strings.TrimSpace(use.LotNumber); lotNo != "" {
+		err := tx.Where("item_id = ? AND UPPER(lot_number) = UPPER(?)", item.ID, lotNo).First(&lot).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &StockError{"lot " + lotNo + " of " + item.Name + " is not in stock"}
+		}
+		if err != nil {
+			return nil, err
+		}
+		if lot.RecalledAt != nil {
+			return nil, &StockError{"lot " + lot.LotNumber + " of " + item.Name + " has been recalled"}
+		}
+		if !lot.Usable(use.At) {
+			return nil, &StockError{"lot " + lot.LotNumber + " of " + item.Name + " expired on " + lot.ExpiryDate.String()}
+		}
+		if lot.QuantityOnHand < use.Quantity {
+			return nil, &StockError{"lot " + lot.LotNumber + " of " + item.Name + " is out of stock"}
+		}
+	} else {
+		err := tx.Where("item_id = ? AND recalled_at IS NULL AND quantity_on_hand >= ?", item.ID, use.Quantity).
+			Where("expiry_date IS NULL OR expiry_date >= ?", NewDate(use.At)).
+			Order("expiry_date IS NULL, expiry_date ASC, id ASC").
+			First(&lot).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &StockError{item.Name + " is out of stock"}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	res := tx.Model(&ClinicLot{}).
+		Where("id = ? AND quantity_on_hand >= ?", lot.ID, use.Quantity).
+		Update("quantity_on_hand", gorm.Expr("quantity_on_hand - ?", use.Quantity))
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, &StockError{"lot " + lot.LotNumber + " of " + item.Name + " is out of stock"}
+	}
+	lot.QuantityOnHand -= use.Quantity
+
+	if err := tx.Create(&StockMovement{
+		LotID:            lot.ID,
+		Quantity:         -use.Quantity,
+		Reason:           use.Reason,
+		At:               use.At,
+		DogID:            use.DogID,
+		VaccineRecordID:  use.VaccineRecordID,
+		AdministrationID: use.AdministrationID,
+		StaffID:          use.StaffID,
+	}).Error; err != nil {
+		return nil, err
+	}
+	return &lot, nil
+}
+
+// ReleaseStock คืนสต็อกทั้งหมดที่ถูกตัดจาก movement ที่ตรงเงื่อนไข (เช่น vaccine_record_id = ?)
+func ReleaseStock(tx *gorm.DB, query string, args ...any) error {
+	var rows []struct {
+		LotID uint
+		Net   int
+	}
+	if err := tx.Model(&StockMovement{}).
+		Select("lot_id, SUM(quantity) AS net").
+		Where(query, args...).
+		Group("lot_id").
+		Scan(&rows).Error; err != nil {
+		return err
+	}
+	var sample StockMovement
+	if err := tx.Where(query, args...).Order("id ASC").First(&sample).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	for _, r := range rows {
+		if r.Net >= 0 {
+			continue
+		}
+		if err := tx.Model(&ClinicLot{}).Where("id = ?", r.LotID).
+			Update("quantity_on_hand", gorm.Expr("quantity_on_hand + ?", -r.Net)).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&StockMovement{
+			LotID:            r.LotID,
+			Quantity:         -r.Net,
+			Reason:           StockReversal,
+			At:               time.Now(),
+			DogID:            sample.DogID,
+			VaccineRecordID:  sample.VaccineRecordID,
+			AdministrationID: sample.AdministrationID,
+		}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"example.com/project-sa/utils/testutil"
+	"gorm.io/gorm"
+)
+
+// openStockDB DB เปล่าที่มีแค่ตารางคลัง: item วัคซีน 1 รายการ + lot ตาม lots
+func openStockDB(t *testing.T, lots []ClinicLot) (*gorm.DB, ClinicItem) {
+	t.Helper()
+	db := testutil.Open(t, &ClinicItem{}, &ClinicLot{}, &StockMovement{})
+	item := ClinicItem{Name: "Rabies", Kind: ClinicItemVaccine, Unit: "dose"}
+	if err := db.Create(&item).Error; err != nil {
+		t.Fatalf("create item: %v", err)
+	}
+	for i := range lots {
+		lots[i].ItemID = item.ID
+		lots[i].QuantityReceived = lots[i].QuantityOnHand
+		if err := db.Create(&lots[i]).Error; err != nil {
+			t.Fatalf("create lot: %v", err)
+		}
+	}
+	return db, item
+}
+
+func onHand(t *testing.T, db *gorm.DB, lotNumber string) int {
+	t.Helper()
+	var lot ClinicLot
+	if err := db.Where("lot_number = ?", lotNumber).First(&lot).Error; err != nil {
+		t.Fatalf("lot %s: %v", lotNumber, err)
+	}
+	return lot.QuantityOnHand
+}
+
+func TestConsumeStock(t *testing.T) {
+	at := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	date := func(s string) *Date {
+		d, err := ParseDate(s)
+		if err != nil {
+			t.Fatalf("ParseDate(%q): %v", s, err)
+		}
+		return d
+	}
+	recalled := at.AddDate(0, -1, 0)
+
+	tests := []struct {
+		name     string
+		lots     []ClinicLot
+		lot      string // ว่าง = FEFO
+		qty      int
+		wantLot  string
+		wantErr  bool
+		stockErr bool
+	}{
+		{
+			name: "fefo picks earliest expiry",
+			lots: []ClinicLot{
+				{LotNumber: "LATE", ExpiryDate: date("2026-01-01"), QuantityOnHand: 5},
+				{LotNumber: "SOON", ExpiryDate: date("2025-07-01"), QuantityOnHand: 5},
+				{LotNumber: "NOEXP", QuantityOnHand: 5},
+			},
+			qty: 1, wantLot: "SOON",
+		},
+		{
+			name: "fefo skips expired, recalled and short lots",
+			lots: []ClinicLot{
+				{LotNumber: "OLD", ExpiryDate: date("2025-05-31"), QuantityOnHand: 5},
+				{LotNumber: "RECALL", ExpiryDate: date("2025-06-15"), QuantityOnHand: 5, RecalledAt: &recalled},
+				{LotNumber: "ONE", ExpiryDate: date("2025-06-20"), QuantityOnHand: 1},
+				{LotNumber: "OK", ExpiryDate: date("2025-12-01"), QuantityOnHand: 5},
+			},
+			qty: 2, wantLot: "OK",
+		},
+		{
+			name:    "lot expiring today is still usable",
+			lots:    []ClinicLot{{LotNumber: "TODAY", ExpiryDate: date("2025-06-01"), QuantityOnHand: 1}},
+			qty:     1,
+			wantLot: "TODAY",
+		},
+		{
+			name:    "explicit lot matches case-insensitively",
+			lots:    []ClinicLot{{LotNumber: "AB12", QuantityOnHand: 3}, {LotNumber: "ZZ99", ExpiryDate: date("2025-07-01"), QuantityOnHand: 3}},
+			lot:     " ab12 ",
+			qty:     1,
+			wantLot: "AB12",
+		},
+		{
+			name:    "unknown lot",
+			lots:    []ClinicLot{{LotNumber: "AB12", QuantityOnHand: 3}},
+			lot:     "NOPE",
+			qty:     1,
+			wantErr: true, stockErr: true,
+		},
+		{
+			name:    "explicit recalled lot",
+			lots:    []ClinicLot{{LotNumber: "AB12", QuantityOnHand: 3, RecalledAt: &recalled}},
+			lot:     "AB12",
+			qty:     1,
+			wantErr: true, stockErr: true,
+		},
+		{
+			name:    "explicit expired lot",
+			lots:    []ClinicLot{{LotNumber: "AB12", ExpiryDate: date("2025-01-01"), QuantityOnHand: 3}},
+			lot:     "AB12",
+			qty:     1,
+			wantErr: true, stockErr: true,
+		},
+		{
+			name:    "explicit lot without enough stock",
+			lots:    []ClinicLot{{LotNumber: "AB12", QuantityOnHand: 1}},
+			lot:     "AB12",
+			qty:     2,
+			wantErr: true, stockErr: true,
+		},
+		{
+			name:    "no usable lot",
+			lots:    []ClinicLot{{LotNumber: "EMPTY", QuantityOnHand: 0}},
+			qty:     1,
+			wantErr: true, stockErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, item := openStockDB(t, tt.lots)
+			before := map[string]int{}
+			for _, l := range tt.lots {
+				before[l.LotNumber] = l.QuantityOnHand
+			}
+
+			lot, err := ConsumeStock(db, StockUse{ItemID: item.ID, LotNumber: tt.lot, Quantity: tt.qty, At: at, Reason: StockVaccine})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ConsumeStock took lot %s, want error", lot.LotNumber)
+				}
+				if IsStockError(err) != tt.stockErr {
+					t.Errorf("IsStockError(%v) = %v, want %v", err, !tt.stockErr, tt.stockErr)
+				}
+				for no, q := range before {
+					if got := onHand(t, db, no); got != q {
+						t.Errorf("lot %s on hand = %d after failed consume, want %d", no, got, q)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ConsumeStock: %v", err)
+			}
+			if lot.LotNumber != tt.wantLot {
+				t.Errorf("consumed lot %s, want %s", lot.LotNumber, tt.wantLot)
+			}
+			for no, q := range before {
+				want := q
+				if no == tt.wantLot {
+					want -= tt.qty
+				}
+				if got := onHand(t, db, no); got != want {
+					t.Errorf("lot %s on hand = %d, want %d", no, got, want)
+				}
+			}
+			var moves []StockMovement
+			db.Find(&moves)
+			if len(moves) != 1 || moves[0].Quantity != -tt.qty || moves[0].LotID != lot.ID {
+				t.Errorf("movements = %+v, want one -%d on lot %d", moves, tt.qty, lot.ID)
+			}
+		})
+	}
+}
+
+func TestReleaseStock(t *testing.T) {
+	at := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	db, item := openStockDB(t, []ClinicLot{{LotNumber: "A", QuantityOnHand: 5}, {LotNumber: "B", QuantityOnHand: 5}})
+	vrID := uint(7)
+	for _, lot := range []string{"A", "B", "B"} {
+		if _, err := ConsumeStock(db, StockUse{ItemID: item.ID, LotNumber: lot, Quantity: 1, At: at, Reason: StockVaccine, VaccineRecordID: &vrID}); err != nil {
+			t.Fatalf("consume %s: %v", lot, err)
+		}
+	}
+	other := uint(8)
+	if _, err := ConsumeStock(db, StockUse{ItemID: item.ID, LotNumber: "A", Quantity: 1, At: at, Reason: StockVaccine, VaccineRecordID: &other}); err != nil {
+		t.Fatalf("consume other: %v", err)
+	}
+
+	// คืนซ้ำต้องไม่คืนเกิน (movement คืนสต็อกหักล้างยอดเดิมแล้ว)
+	for i := 0; i < 2; i++ {
+		if err := ReleaseVaccineDose(db, vrID); err != nil {
+			t.Fatalf("release #%d: %v", i+1, err)
+		}
+		if got := onHand(t, db, "A"); got != 4 {
+			t.Errorf("release #%d: lot A on hand = %d, want 4 (other record keeps its dose)", i+1, got)
+		}
+		if got := onHand(t, db, "B"); got != 5 {
+			t.Errorf("release #%d: lot B on hand = %d, want 5", i+1, got)
+		}
+	}
+	if err := ReleaseVaccineDose(db, 99); err != nil {
+		t.Errorf("release without movements: %v", err)
+	}
+}
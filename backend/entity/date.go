@@ -0,0 +1,110 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const DateLayout = "2006-01-02"
+
+// Date วันที่ตามปฏิทิน (ไม่มีเวลา/โซนเวลา) เก็บในคอลัมน์ชนิด date และส่งออก JSON เป็น "YYYY-MM-DD"
+type Date struct {
+	time.Time
+}
+
+// ParseDate แปลง "YYYY-MM-DD" (ว่าง = nil)
+func ParseDate(s string) (*Date, error) {
+	if s == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(DateLayout, s)
+	if err != nil {
+		return nil, err
+	}
+	return &Date{t}, nil
+}
+
+// MustParseDate ใช้กับค่าคงที่ เช่น seeds
+func MustParseDate(s string) *Date {
+	d, err := ParseDate(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// NewDate ตัดเวลาออก เหลือเฉพาะวันที่ตามโซนของ t
+func NewDate(t time.Time) Date {
+	return Date{time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)}
+}
+
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.Format(DateLayout)
+}
+
+func (d Date) GormDataType() string {
+	return "date"
+}
+
+func (d Date) Value() (driver.Value, error) {
+	if d.IsZero() {
+		return nil, nil
+	}
+	return d.String(), nil
+}
+
+func (d *Date) Scan(v any) error {
+	switch x := v.(type) {
+	case nil:
+		d.Time = time.Time{}
+		return nil
+	case time.Time:
+		*d = NewDate(x)
+		return nil
+	case string:
+		return d.scanString(x)
+	case []byte:
+		return d.scanString(string(x))
+	}
+	return fmt.Errorf("cannot scan %T into Date", v)
+}
+
+// รูปแบบที่รับได้: วันที่ล้วน หรือ timestamp เต็ม (ค่าจาก driver/ frontend ที่ส่ง ISO) ซึ่งใช้เฉพาะส่วนวันที่
+var dateScanLayouts = []string{
+	DateLayout,
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05",
+}
+
+// scanString ต้องตรงรูปแบบทั้งสตริง (ไม่ตัดส่วนท้ายทิ้ง เช่น "2024-01-01garbage" ไม่ผ่าน)
+func (d *Date) scanString(s string) error {
+	for _, layout := range dateScanLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			*d = NewDate(t)
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid date %q (YYYY-MM-DD)", s)
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Date) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	if s == "" {
+		d.Time = time.Time{}
+		return nil
+	}
+	return d.scanString(s)
+}
@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDateScan(t *testing.T) {
+	tests := []struct {
+		in      any
+		want    string
+		wantErr bool
+	}{
+		{"2024-01-01", "2024-01-01", false},
+		{[]byte("2024-02-29"), "2024-02-29", false},
+		{"2024-01-01T00:00:00Z", "2024-01-01", false},
+		{"2024-01-01 00:00:00+00:00", "2024-01-01", false},
+		{"2024-01-01 00:00:00", "2024-01-01", false},
+		{nil, "", false},
+		{"2024-01-01garbage", "", true},
+		{"2024-01-01 junk", "", true},
+		{"2023-02-29", "", true},
+		{"01/02/2024", "", true},
+		{42, "", true},
+	}
+	for _, tt := range tests {
+		var d Date
+		err := d.Scan(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Scan(%v) = %s, want error", tt.in, d)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Scan(%v): %v", tt.in, err)
+			continue
+		}
+		if d.String() != tt.want {
+			t.Errorf("Scan(%v) = %q, want %q", tt.in, d.String(), tt.want)
+		}
+	}
+}
+
+func TestDateJSON(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{`"2024-01-01"`, "2024-01-01", false},
+		{`""`, "", false},
+		{`"2024-01-01garbage"`, "", true},
+		{`20240101`, "", true},
+	}
+	for _, tt := range tests {
+		var d Date
+		err := json.Unmarshal([]byte(tt.in), &d)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Unmarshal(%s) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if err == nil && d.String() != tt.want {
+			t.Errorf("Unmarshal(%s) = %q, want %q", tt.in, d.String(), tt.want)
+		}
+	}
+}
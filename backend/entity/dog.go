@@ -1,15 +1,38 @@
 package entity
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// สถานะที่อยู่ของสุนัข
+const (
+	DogStatusShelter     = "shelter"     // อยู่ในศูนย์ (มีคอก)
+	DogStatusFoster      = "foster"      // อยู่บ้านอุปถัมภ์
+	DogStatusTransferred = "transferred" // ส่งต่อให้องค์กรพันธมิตรแล้ว
+	DogStatusDeceased    = "deceased"    // เสียชีวิต (ดู DogOutcome)
+)
 
 type Dog struct {
 	gorm.Model
 	Name         string `json:"name"`
-	DateOfBirth  string `json:"date_of_birth"` // "YYYY-MM-DD"
-	SterilizedAt string `json:"sterilized_at"`
+	DateOfBirth  *Date  `json:"date_of_birth"`
+	DOBEstimated bool   `gorm:"not null;default:false" json:"dob_estimated"` // สุนัขจรส่วนใหญ่ไม่รู้วันเกิดจริง
+	DOBPrecision string `gorm:"default:day" json:"dob_precision"`            // day | month | year
+	SterilizedAt *Date  `json:"sterilized_at"`
 	PhotoURL     string `json:"photo_url"`
 	ReadyToAdopt bool   `json:"ready_to_adopt"`
 	IsAdopted    bool   `json:"is_adopted"`
+	Status       string `gorm:"default:shelter;index" json:"status"`
+
+	// ข้อมูลตอนรับเข้า (ใช้จับคู่กับประกาศหมา หาย/พบ)
+	Color      string `json:"color"`
+	IntakeDate string `json:"intake_date"` // "YYYY-MM-DD"
+	IntakeArea string `json:"intake_area"` // ย่าน/เขตที่พบสุนัข
+
+	// รหัสสำหรับลิงก์แชร์สาธารณะ /share/dogs/:code (ไม่เปลี่ยนตลอดอายุสุนัข)
+	ShareCode *string `gorm:"uniqueIndex" json:"share_code"`
 
 	BreedID uint   `json:"breed_id"`
 	Breed   *Breed `gorm:"foreignKey:BreedID" json:"breed"`
@@ -17,6 +40,18 @@ type Dog struct {
 	KennelID *uint   `json:"kennel_id"`
 	Kennel   *Kennel `gorm:"foreignKey:KennelID" json:"kennel"`
 
+	// อยู่บ้านอุปถัมภ์ (ไม่นับเป็นผู้อยู่ในคอก) — nil = อยู่ที่ศูนย์
+	FosterHomeID *uint       `json:"foster_home_id"`
+	FosterHome   *FosterHome `gorm:"foreignKey:FosterHomeID" json:"foster_home"`
+
+	// ครอบครัว: ครอกที่เกิด และพ่อแม่ (ถ้ารู้)
+	LitterID *uint   `gorm:"index" json:"litter_id"`
+	Litter   *Litter `gorm:"foreignKey:LitterID" json:"litter,omitempty"`
+	MotherID *uint   `gorm:"index" json:"mother_id"`
+	Mother   *Dog    `gorm:"foreignKey:MotherID" json:"mother,omitempty"`
+	FatherID *uint   `gorm:"index" json:"father_id"`
+	Father   *Dog    `gorm:"foreignKey:FatherID" json:"father,omitempty"`
+
 	AnimalSexID  uint        `json:"animal_sex_id"`
 	AnimalSex    *AnimalSex  `gorm:"foreignKey:AnimalSexID" json:"animal_sex"`
 	AnimalSizeID uint        `json:"animal_size_id"`
@@ -37,4 +72,23 @@ type Dog struct {
 	Adoptions        []Adoption       `gorm:"foreignKey:DogID" json:"adoptions"`
 	Sponsorships     []Sponsorship    `gorm:"foreignKey:DogID" json:"sponsorships"`
 	DogPersonalities []DogPersonality `gorm:"foreignKey:DogID" json:"dog_personalities"`
+
+	// คำนวณตอนอ่าน (ไม่เก็บในตาราง) — nil/ว่าง เมื่อไม่ทราบวันเกิด
+	AgeMonths *int   `gorm:"-" json:"age_months"`
+	AgeGroup  string `gorm:"-" json:"age_group"`
+
+	// เติมเฉพาะ GetDogById
+	Relatives *DogRelatives `gorm:"-" json:"relatives,omitempty"`
+	// เติมเมื่อย้ายคอกแล้วขัดกฎระดับเตือน
+	KennelWarnings []KennelRuleViolation `gorm:"-" json:"kennel_warnings,omitempty"`
+}
+
+func (d *Dog) AfterFind(tx *gorm.DB) error {
+	d.AgeMonths, d.AgeGroup = nil, ""
+	if d.DateOfBirth != nil {
+		m := AgeInMonths(*d.DateOfBirth, time.Now())
+		d.AgeMonths = &m
+		d.AgeGroup = AgeGroupOf(m)
+	}
+	return nil
 }
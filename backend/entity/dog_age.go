@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"time"
+
+	"example.com/project-sa/utils/timeutil"
+)
+
+// ความละเอียดของวันเกิด (ประเมินจากฟัน/รูปร่างได้แค่ระดับเดือนหรือปี)
+const (
+	DOBPrecisionDay   = "day"
+	DOBPrecisionMonth = "month"
+	DOBPrecisionYear  = "year"
+)
+
+// ช่วงอายุ (เดือน): ลูกสุนัข < 12, โตเต็มวัย < 84, สูงวัย 7 ปีขึ้นไป
+const (
+	AgeGroupPuppy  = "puppy"
+	AgeGroupAdult  = "adult"
+	AgeGroupSenior = "senior"
+
+	AdultFromMonths  = 12
+	SeniorFromMonths = 84
+)
+
+// AgeInMonths นับเดือนเต็มจากวันเกิดถึงวันนี้ (เวลาไทย)
+func AgeInMonths(dob Date, now time.Time) int {
+	today := NewDate(now.In(timeutil.TZBangkok()))
+	m := (today.Year()-dob.Year())*12 + int(today.Month()-dob.Month())
+	if today.Day() < dob.Day() {
+		m--
+	}
+	if m < 0 {
+		return 0
+	}
+	return m
+}
+
+func AgeGroupOf(months int) string {
+	switch {
+	case months < AdultFromMonths:
+		return AgeGroupPuppy
+	case months < SeniorFromMonths:
+		return AgeGroupAdult
+	}
+	return AgeGroupSenior
+}
+
+// AgeGroupRange ช่วงอายุ [min, max) เป็นเดือน (max = -1 คือไม่มีเพดาน)
+func AgeGroupRange(group string) (min, max int, ok bool) {
+	switch group {
+	case AgeGroupPuppy:
+		return 0, AdultFromMonths, true
+	case AgeGroupAdult:
+		return AdultFromMonths, SeniorFromMonths, true
+	case AgeGroupSenior:
+		return SeniorFromMonths, -1, true
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,112 @@
+package entity
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	DogChangeUpdate = "update"
+	DogChangeDelete = "delete"
+	DogChangeRevert = "revert"
+)
+
+// DogChange = ประวัติการแก้ไขข้อมูลสุนัขรายฟิลด์ (1 แถว ต่อ 1 ฟิลด์ที่เปลี่ยน)
+type DogChange struct {
+	gorm.Model
+	DogID uint `gorm:"index" json:"dog_id"`
+	Dog   *Dog `gorm:"foreignKey:DogID" json:"dog,omitempty"`
+
+	Action    string    `gorm:"not null" json:"action"` // update | delete | revert
+	Field     string    `gorm:"not null" json:"field"`  // ชื่อคอลัมน์ เช่น kennel_id, breed_id
+	OldValue  string    `json:"old_value"`
+	NewValue  string    `json:"new_value"`
+	ChangedAt time.Time `json:"changed_at"`
+
+	StaffID *uint  `json:"staff_id"`
+	Staff   *Staff `gorm:"foreignKey:StaffID;constraint:OnUpdate:RESTRICT,OnDelete:SET NULL;" json:"staff,omitempty"`
+
+	// ถ้าเป็นการ revert จะชี้กลับไปยังรายการที่ถูกย้อน
+	RevertOfID *uint `json:"revert_of_id"`
+}
+
+// FormatDogChangeValue รูปแบบเดียวของ old/new value (revert แปลงกลับจากรูปแบบนี้)
+// nil = "", id = เลข, วันที่ = YYYY-MM-DD, เวลา = RFC3339, ชุด id = "1,2,5" (เรียง ไม่ซ้ำ)
+func FormatDogChangeValue(v any) string {
+	switch n := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return n
+	case bool:
+		return strconv.FormatBool(n)
+	case uint:
+		return strconv.FormatUint(uint64(n), 10)
+	case *uint:
+		if n == nil {
+			return ""
+		}
+		return strconv.FormatUint(uint64(*n), 10)
+	case Date:
+		return n.String()
+	case *Date:
+		if n == nil {
+			return ""
+		}
+		return n.String()
+	case time.Time:
+		return n.UTC().Format(time.RFC3339)
+	case []uint:
+		seen := map[uint]bool{}
+		ids := make([]uint, 0, len(n))
+		for _, id := range n {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		parts := make([]string, len(ids))
+		for i, id := range ids {
+			parts[i] = strconv.FormatUint(uint64(id), 10)
+		}
+		return strings.Join(parts, ",")
+	default:
+		return fmt.Sprint(n)
+	}
+}
+
+// NewDogChange แถวประวัติ 1 ฟิลด์ ค่าแปลงด้วย FormatDogChangeValue
+func NewDogChange(dogID uint, action, field string, oldV, newV any, staffID *uint) DogChange {
+	return DogChange{
+		DogID:     dogID,
+		Action:    action,
+		Field:     field,
+		OldValue:  FormatDogChangeValue(oldV),
+		NewValue:  FormatDogChangeValue(newV),
+		ChangedAt: time.Now(),
+		StaffID:   staffID,
+	}
+}
+
+// RecordDogChange บันทึกการแก้ฟิลด์ของสุนัข (ค่าไม่เปลี่ยน = ไม่บันทึก) เรียกใน tx เดียวกับที่แก้ข้อมูล
+func RecordDogChange(tx *gorm.DB, dogID uint, field string, oldV, newV any, staffID *uint) error {
+	change := NewDogChange(dogID, DogChangeUpdate, field, oldV, newV, staffID)
+	if change.OldValue == change.NewValue {
+		return nil
+	}
+	return tx.Create(&change).Error
+}
+
+// RecordDogKennelChange ย้ายคอกนอกหน้าแก้ไขสุนัข: ลงทั้งสมุดย้ายคอกและประวัติสุนัข
+func RecordDogKennelChange(tx *gorm.DB, dogID uint, from, to *uint, staffID *uint, reason string) error {
+	if err := RecordKennelMove(tx, dogID, from, to, staffID, reason); err != nil {
+		return err
+	}
+	return RecordDogChange(tx, dogID, "kennel_id", from, to, staffID)
+}
@@ -0,0 +1,81 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"example.com/project-sa/utils/pointer"
+	"example.com/project-sa/utils/testutil"
+)
+
+func TestFormatDogChangeValue(t *testing.T) {
+	var noID *uint
+	var noDate *Date
+	tests := []struct {
+		name string
+		in   any
+		want string
+	}{
+		{"nil", nil, ""},
+		{"string", "Lucky", "Lucky"},
+		{"bool", false, "false"},
+		{"uint", uint(7), "7"},
+		{"uint pointer", pointer.P[uint](12), "12"},
+		{"nil uint pointer", noID, ""},
+		{"date", MustParseDate("2024-02-29"), "2024-02-29"},
+		{"date value", *MustParseDate("2024-02-29"), "2024-02-29"},
+		{"nil date", noDate, ""},
+		{"time in another zone", time.Date(2025, 3, 1, 9, 0, 0, 0, time.FixedZone("ICT", 7*3600)), "2025-03-01T02:00:00Z"},
+		{"id set is sorted and unique", []uint{5, 1, 2, 5}, "1,2,5"},
+		{"empty id set", []uint{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatDogChangeValue(tt.in); got != tt.want {
+				t.Errorf("FormatDogChangeValue(%v) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecordDogChange(t *testing.T) {
+	db := testutil.Open(t, &DogChange{})
+	db.Exec("PRAGMA foreign_keys = OFF") // ไม่ต้องสร้างสุนัข/staff จริง
+	staffID := pointer.P[uint](3)
+
+	tests := []struct {
+		field      string
+		old, new   any
+		wantOld    string
+		wantNew    string
+		wantRecord bool
+	}{
+		{"kennel_id", pointer.P[uint](2), nil, "2", "", true},
+		{"ready_to_adopt", true, false, "true", "false", true},
+		{"personality_ids", []uint{3, 1}, []uint{1, 3, 4}, "1,3", "1,3,4", true},
+		{"personality_ids", []uint{3, 1}, []uint{1, 3}, "", "", false},
+		{"kennel_id", pointer.P[uint](2), pointer.P[uint](2), "", "", false},
+		{"sterilized_at", (*Date)(nil), *MustParseDate("2025-01-02"), "", "2025-01-02", true},
+	}
+	for _, tt := range tests {
+		db.Where("1 = 1").Delete(&DogChange{})
+		if err := RecordDogChange(db, 1, tt.field, tt.old, tt.new, staffID); err != nil {
+			t.Fatalf("%s: RecordDogChange: %v", tt.field, err)
+		}
+		var rows []DogChange
+		db.Find(&rows)
+		if !tt.wantRecord {
+			if len(rows) != 0 {
+				t.Errorf("%s %v -> %v: recorded %+v, want nothing", tt.field, tt.old, tt.new, rows)
+			}
+			continue
+		}
+		if len(rows) != 1 {
+			t.Fatalf("%s: %d rows, want 1", tt.field, len(rows))
+		}
+		r := rows[0]
+		if r.Action != DogChangeUpdate || r.OldValue != tt.wantOld || r.NewValue != tt.wantNew || r.StaffID == nil || *r.StaffID != 3 {
+			t.Errorf("%s: row = %s %q -> %q (staff %v), want update %q -> %q by 3", tt.field, r.Action, r.OldValue, r.NewValue, r.StaffID, tt.wantOld, tt.wantNew)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ค่าวันที่แบบข้อความเดิมที่แปลงเป็นคอลัมน์ date ไม่ได้ตอน migrate — รอเจ้าหน้าที่แก้เอง
+type DogDateIssue struct {
+	gorm.Model
+	DogID      uint       `gorm:"index" json:"dog_id"`
+	Dog        *Dog       `gorm:"foreignKey:DogID" json:"dog,omitempty"`
+	Field      string     `json:"field"` // date_of_birth | sterilized_at
+	RawValue   string     `json:"raw_value"`
+	Reason     string     `json:"reason"`
+	ResolvedAt *time.Time `json:"resolved_at"`
+}
@@ -0,0 +1,55 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ประเภทการสิ้นสุดชีวิต
+const (
+	OutcomeDeath      = "death"      // เสียชีวิตเอง
+	OutcomeEuthanasia = "euthanasia" // การุณยฆาต (ต้องอนุมัติ 2 คน)
+)
+
+// สถานะบันทึก
+const (
+	OutcomePending   = "pending_approval"
+	OutcomeScheduled = "scheduled" // อนุมัติครบแล้ว รอถึงวันที่นัดการุณยฆาต
+	OutcomeApproved  = "approved"  // มีผลแล้ว (สุนัขเป็น deceased)
+	OutcomeRejected  = "rejected"
+)
+
+// จำนวนผู้อนุมัติการุณยฆาตที่ต้องมี (ไม่นับผู้ขอ)
+const EuthanasiaApprovalsRequired = 2
+
+type DogOutcome struct {
+	gorm.Model
+	DogID uint `gorm:"index" json:"dog_id"`
+	Dog   *Dog `gorm:"foreignKey:DogID" json:"dog,omitempty"`
+
+	Type        string    `gorm:"index" json:"type"`   // death | euthanasia
+	Status      string    `gorm:"index" json:"status"` // pending_approval | scheduled | approved | rejected
+	Cause       string    `json:"cause"`
+	OutcomeDate time.Time `json:"outcome_date"`
+	Notes       string    `json:"notes"`
+
+	AttendingVetID uint   `json:"attending_vet_id"`
+	AttendingVet   *Staff `gorm:"foreignKey:AttendingVetID" json:"attending_vet"`
+
+	RequestedByID *uint  `json:"requested_by_id"`
+	RequestedBy   *Staff `gorm:"foreignKey:RequestedByID" json:"requested_by"`
+
+	FinalizedAt *time.Time `json:"finalized_at"`
+
+	Approvals []DogOutcomeApproval `gorm:"foreignKey:OutcomeID;constraint:OnDelete:CASCADE" json:"approvals"`
+}
+
+type DogOutcomeApproval struct {
+	gorm.Model
+	OutcomeID uint   `gorm:"uniqueIndex:idx_outcome_approver" json:"outcome_id"`
+	StaffID   uint   `gorm:"uniqueIndex:idx_outcome_approver" json:"staff_id"`
+	Staff     *Staff `gorm:"foreignKey:StaffID" json:"staff"`
+	Decision  string `json:"decision"` // approve | reject
+	Comment   string `json:"comment"`
+}
@@ -0,0 +1,88 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// บ้านอุปถัมภ์ชั่วคราว (ผูกกับบัญชีผู้ใช้ 1 คน : 1 บ้าน)
+type FosterHome struct {
+	gorm.Model
+	UserID uint  `gorm:"uniqueIndex" json:"user_id"`
+	User   *User `gorm:"foreignKey:UserID" json:"user"`
+
+	Capacity    uint   `json:"capacity"` // จำนวนสุนัขที่รับได้พร้อมกัน
+	Address     string `json:"address"`
+	HousingType string `json:"housing_type"` // house | townhouse | condo | other
+
+	// ลักษณะครัวเรือน (ใช้เลือกสุนัขให้เหมาะ)
+	HasYard      bool    `json:"has_yard"`
+	HasChildren  bool    `json:"has_children"`
+	HasOtherDogs bool    `json:"has_other_dogs"`
+	HasCats      bool    `json:"has_cats"`
+	Note         *string `json:"note"`
+
+	Active bool `json:"active"`
+
+	Placements []FosterPlacement `gorm:"foreignKey:FosterHomeID" json:"placements,omitempty"`
+}
+
+// การส่งสุนัขไปอยู่บ้านอุปถัมภ์ 1 ช่วง (EndDate = nil คือยังอยู่)
+type FosterPlacement struct {
+	gorm.Model
+	FosterHomeID uint        `gorm:"index" json:"foster_home_id"`
+	FosterHome   *FosterHome `gorm:"foreignKey:FosterHomeID" json:"foster_home,omitempty"`
+	DogID        uint        `gorm:"index" json:"dog_id"`
+	Dog          *Dog        `gorm:"foreignKey:DogID" json:"dog,omitempty"`
+
+	StartDate       time.Time  `json:"start_date"`
+	ExpectedEndDate *time.Time `json:"expected_end_date"`
+	EndDate         *time.Time `json:"end_date"`
+	EndReason       string     `json:"end_reason"`
+	Note            string     `json:"note"`
+
+	// คอกเดิมก่อนออกไป (ใช้เป็นค่าเริ่มต้นตอนรับกลับ)
+	PreviousKennelID *uint `json:"previous_kennel_id"`
+
+	StaffID *uint  `json:"staff_id"`
+	Staff   *Staff `gorm:"foreignKey:StaffID" json:"staff,omitempty"`
+
+	CheckIns []FosterCheckIn `gorm:"foreignKey:PlacementID" json:"check_ins,omitempty"`
+	Supplies []FosterSupply  `gorm:"foreignKey:PlacementID" json:"supplies,omitempty"`
+}
+
+// รายงานความเป็นอยู่จากบ้านอุปถัมภ์
+type FosterCheckIn struct {
+	gorm.Model
+	PlacementID uint      `gorm:"index" json:"placement_id"`
+	ReportedAt  time.Time `json:"reported_at"`
+
+	WeightKg       *float64 `json:"weight_kg"`
+	Appetite       string   `json:"appetite"` // good | fair | poor
+	Behavior       string   `json:"behavior"`
+	HealthConcerns *string  `json:"health_concerns"`
+	PhotoURL       *string  `json:"photo_url"`
+	Note           string   `json:"note"`
+
+	// ผู้รายงาน: เจ้าของบ้าน (user) หรือเจ้าหน้าที่ที่ไปเยี่ยม
+	UserID  *uint  `json:"user_id"`
+	StaffID *uint  `json:"staff_id"`
+	Staff   *Staff `gorm:"foreignKey:StaffID" json:"staff,omitempty"`
+}
+
+// ของที่เบิกจากคลังบริจาคให้บ้านอุปถัมภ์
+type FosterSupply struct {
+	gorm.Model
+	PlacementID uint      `gorm:"index" json:"placement_id"`
+	ItemID      uint      `json:"item_id"`
+	Item        *Item     `gorm:"foreignKey:ItemID" json:"item"`
+	UnitID      uint      `json:"unit_id"`
+	Unit        *Unit     `gorm:"foreignKey:UnitID" json:"unit"`
+	Quantity    int       `json:"quantity"`
+	HandedOutAt time.Time `json:"handed_out_at"`
+	Note        string    `json:"note"`
+
+	StaffID *uint  `json:"staff_id"`
+	Staff   *Staff `gorm:"foreignKey:StaffID" json:"staff,omitempty"`
+}
@@ -1,6 +1,12 @@
 package entity
 
-import "gorm.io/gorm"
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Kennel struct {
 	gorm.Model
@@ -12,6 +18,102 @@ type Kennel struct {
 	ZoneID uint  `json:"zone_id"`
 	Zone   *Zone `gorm:"foreignKey:ZoneID" json:"zone"`
 
-	KennelManagements []KennelManagement `gorm:"foreignKey:KennelID" json:"kennel_managements"`
+	Environment  string       `gorm:"default:indoor" json:"environment"` // indoor | outdoor | covered
+	IsIsolation  bool         `json:"is_isolation"`                      // คอกแยกโรค (แม้โซนไม่ใช่โซนแยกโรค)
+	AllowedSizes []AnimalSize `gorm:"many2many:kennel_allowed_sizes" json:"allowed_sizes"` // ว่าง = รับทุกขนาด
+
+	KennelManagements []KennelManagement `gorm:"foreignKey:ToKennelID" json:"kennel_managements"`
 	Dogs []Dog `gorm:"foreignKey:KennelID" json:"dogs"`
 }
+
+// สภาพคอก
+const (
+	KennelIndoor  = "indoor"
+	KennelOutdoor = "outdoor"
+	KennelCovered = "covered" // กลางแจ้งมีหลังคา
+)
+
+// UnassignedKennelName คอก "00" ที่หน้าจัดการคอกใช้แทน "ยังไม่ได้เข้าคอก" ไม่ใช่คอกจริง
+// จึงไม่จำกัดความจุและไม่ตรวจกฎการอยู่ร่วมกัน
+const UnassignedKennelName = "00"
+
+// Unassigned คอกนี้คือกลุ่ม "ยังไม่ได้เข้าคอก"
+func (k Kennel) Unassigned() bool {
+	return k.Name == UnassignedKennelName
+}
+
+// Isolated คอกรับสุนัขกักโรคได้ (คอกแยกโรคเอง หรืออยู่ในโซนแยกโรค; ต้อง preload Zone)
+func (k Kennel) Isolated() bool {
+	return k.IsIsolation || (k.Zone != nil && k.Zone.IsIsolation)
+}
+
+// AcceptsSize ขนาดตัวนี้เหมาะกับคอก (ต้อง preload AllowedSizes)
+func (k Kennel) AcceptsSize(sizeID uint) bool {
+	if len(k.AllowedSizes) == 0 {
+		return true
+	}
+	for _, s := range k.AllowedSizes {
+		if s.ID == sizeID {
+			return true
+		}
+	}
+	return false
+}
+
+// CapacityError คอกเต็ม
+type CapacityError struct{ msg string }
+
+func (e *CapacityError) Error() string { return e.msg }
+
+func IsCapacityError(err error) bool {
+	var ce *CapacityError
+	return errors.As(err, &ce)
+}
+
+// LockKennel เขียนแถวคอกก่อนเพื่อล็อกไว้จนจบ tx
+// การย้ายเข้าคอกเดียวกันพร้อมกันจะต่อคิวกัน ไม่นับจำนวนซ้อนจนเกินความจุ
+func LockKennel(tx *gorm.DB, kennelID uint) (*Kennel, error) {
+	res := tx.Model(&Kennel{}).Where("id = ?", kennelID).Update("updated_at", time.Now())
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	var k Kennel
+	if err := tx.First(&k, kennelID).Error; err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+// ReserveKennelSlot ล็อกคอกแล้วตรวจว่ายังมีที่ว่างสำหรับสุนัขตัวนี้ (ตัวที่อยู่คอกนี้อยู่แล้วไม่นับ)
+func ReserveKennelSlot(tx *gorm.DB, kennelID, dogID uint) (*Kennel, error) {
+	k, err := LockKennel(tx, kennelID)
+	if err != nil || k.Unassigned() {
+		return k, err
+	}
+	var occupied int64
+	if err := tx.Model(&Dog{}).Where("kennel_id = ? AND id <> ?", kennelID, dogID).Count(&occupied).Error; err != nil {
+		return nil, err
+	}
+	if occupied >= int64(k.Capacity) {
+		return nil, &CapacityError{fmt.Sprintf("kennel %s is full (%d/%d)", k.Name, occupied, k.Capacity)}
+	}
+	return k, nil
+}
+
+// CheckKennelOccupancy ตรวจหลังย้ายหลายตัวพร้อมกัน (คอกต้องถูกล็อกไว้แล้ว)
+func CheckKennelOccupancy(tx *gorm.DB, k *Kennel) error {
+	if k.Unassigned() {
+		return nil
+	}
+	var occupied int64
+	if err := tx.Model(&Dog{}).Where("kennel_id = ?", k.ID).Count(&occupied).Error; err != nil {
+		return err
+	}
+	if occupied > int64(k.Capacity) {
+		return &CapacityError{fmt.Sprintf("kennel %s would hold %d dogs (capacity %d)", k.Name, occupied, k.Capacity)}
+	}
+	return nil
+}
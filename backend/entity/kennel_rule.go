@@ -0,0 +1,239 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ประเภทกฎการอยู่คอกเดียวกัน
+const (
+	KennelRuleIntactSameSex  = "intact_same_sex"      // ยังไม่ทำหมันทั้งคู่ และเพศ SexID ทั้งคู่ (เช่น ตัวผู้×ตัวผู้)
+	KennelRuleIntactMixedSex = "intact_mixed_sex"     // ยังไม่ทำหมันทั้งคู่ เพศ SexID กับ OtherSexID
+	KennelRuleSizeGap        = "size_gap"             // ขนาดต่างกันเกิน MaxSizeGap ขั้น (ถ้ามี PersonalityID = เฉพาะเมื่อตัวที่ใหญ่กว่ามีนิสัยนี้)
+	KennelRulePersonality    = "personality_conflict" // ฝั่งหนึ่งมีนิสัย PersonalityID อีกฝั่งมี OtherPersonalityID (ว่าง = นิสัยเดียวกัน)
+	KennelRuleQuarantineMix  = "quarantine_mix"       // สุนัขกักโรคอยู่กับตัวที่ไม่ได้กักโรค
+	KennelRuleKennelSize     = "kennel_size"          // ขนาดตัวไม่อยู่ในขนาดที่คอกรับ
+)
+
+// ระดับของกฎ
+const (
+	KennelRuleWarn  = "warn"  // ย้ายได้ แต่แจ้งเตือน
+	KennelRuleBlock = "block" // ย้ายไม่ได้
+)
+
+// KennelRule กฎความเข้ากันได้ของสุนัขในคอกเดียวกัน (ตั้งค่าได้) ตรวจทุกครั้งที่ย้ายสุนัขเข้าคอก
+type KennelRule struct {
+	gorm.Model
+	Name     string `gorm:"not null" json:"name"`
+	Kind     string `gorm:"not null" json:"kind"`
+	Severity string `gorm:"not null;default:warn" json:"severity"` // warn | block
+	Enabled  bool   `gorm:"not null;default:true" json:"enabled"`
+	Note     string `json:"note"`
+
+	SexID              *uint        `json:"sex_id"`
+	Sex                *AnimalSex   `gorm:"foreignKey:SexID" json:"sex,omitempty"`
+	OtherSexID         *uint        `json:"other_sex_id"`
+	OtherSex           *AnimalSex   `gorm:"foreignKey:OtherSexID" json:"other_sex,omitempty"`
+	PersonalityID      *uint        `json:"personality_id"`
+	Personality        *Personality `gorm:"foreignKey:PersonalityID" json:"personality,omitempty"`
+	OtherPersonalityID *uint        `json:"other_personality_id"`
+	OtherPersonality   *Personality `gorm:"foreignKey:OtherPersonalityID" json:"other_personality,omitempty"`
+	MaxSizeGap         int          `json:"max_size_gap"` // ขนาดเรียงตาม id ของ AnimalSize (เล็ก < กลาง < ใหญ่)
+}
+
+// KennelRuleViolation ผลการตรวจกฎหนึ่งข้อ
+type KennelRuleViolation struct {
+	RuleID     uint   `json:"rule_id"`
+	Rule       string `json:"rule"`
+	Kind       string `json:"kind"`
+	Severity   string `json:"severity"`
+	OtherDogID *uint  `json:"other_dog_id,omitempty"`
+	Message    string `json:"message"`
+}
+
+// CompatibilityError ย้ายไม่ได้เพราะขัดกฎระดับ block
+type CompatibilityError struct{ Violations []KennelRuleViolation }
+
+func (e *CompatibilityError) Error() string {
+	msgs := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		msgs = append(msgs, v.Message)
+	}
+	return "incompatible placement: " + strings.Join(msgs, "; ")
+}
+
+func IsCompatibilityError(err error) bool {
+	var ce *CompatibilityError
+	return errors.As(err, &ce)
+}
+
+// PlacementDog ข้อมูลสุนัขที่ใช้ตรวจกฎ
+type PlacementDog struct {
+	ID            uint
+	Name          string
+	KennelID      *uint
+	SexID         uint
+	SizeID        uint
+	SizeRank      int
+	Sterilized    bool
+	Quarantined   bool
+	Personalities map[uint]bool
+}
+
+// LoadPlacementDogs โหลดสุนัขตามเงื่อนไขพร้อมนิสัย สถานะกักโรค และลำดับขนาด
+func LoadPlacementDogs(tx *gorm.DB, query any, args ...any) ([]PlacementDog, error) {
+	var dogs []Dog
+	if err := tx.Preload("DogPersonalities").Where(query, args...).Find(&dogs).Error; err != nil {
+		return nil, err
+	}
+	if len(dogs) == 0 {
+		return []PlacementDog{}, nil
+	}
+	var sizeIDs []uint
+	if err := tx.Model(&AnimalSize{}).Order("id ASC").Pluck("id", &sizeIDs).Error; err != nil {
+		return nil, err
+	}
+	rank := map[uint]int{}
+	for i, id := range sizeIDs {
+		rank[id] = i
+	}
+	ids := make([]uint, 0, len(dogs))
+	for _, d := range dogs {
+		ids = append(ids, d.ID)
+	}
+	var quarantined []uint
+	if err := tx.Model(&QuarantineRecord{}).Where("dog_id IN ? AND status = ?", ids, QuarantineActive).
+		Distinct().Pluck("dog_id", &quarantined).Error; err != nil {
+		return nil, err
+	}
+	inQ := map[uint]bool{}
+	for _, id := range quarantined {
+		inQ[id] = true
+	}
+	out := make([]PlacementDog, 0, len(dogs))
+	for _, d := range dogs {
+		p := PlacementDog{
+			ID: d.ID, Name: d.Name, KennelID: d.KennelID,
+			SexID: d.AnimalSexID, SizeID: d.AnimalSizeID, SizeRank: rank[d.AnimalSizeID],
+			Sterilized: d.SterilizedAt != nil, Quarantined: inQ[d.ID],
+			Personalities: map[uint]bool{},
+		}
+		for _, dp := range d.DogPersonalities {
+			p.Personalities[dp.PersonalityID] = true
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// LoadKennelRules กฎที่เปิดใช้อยู่
+func LoadKennelRules(tx *gorm.DB) ([]KennelRule, error) {
+	var rules []KennelRule
+	err := tx.Where("enabled = ?", true).Order("id ASC").Find(&rules).Error
+	return rules, err
+}
+
+func pairMatches(r KennelRule, a, b PlacementDog) bool {
+	switch r.Kind {
+	case KennelRuleIntactSameSex:
+		return r.SexID != nil && !a.Sterilized && !b.Sterilized && a.SexID == *r.SexID && b.SexID == *r.SexID
+	case KennelRuleIntactMixedSex:
+		if r.SexID == nil || r.OtherSexID == nil || a.Sterilized || b.Sterilized {
+			return false
+		}
+		return (a.SexID == *r.SexID && b.SexID == *r.OtherSexID) || (a.SexID == *r.OtherSexID && b.SexID == *r.SexID)
+	case KennelRuleSizeGap:
+		if a.SizeID == 0 || b.SizeID == 0 {
+			return false
+		}
+		big, small := a, b
+		if b.SizeRank > a.SizeRank {
+			big, small = b, a
+		}
+		if big.SizeRank-small.SizeRank <= r.MaxSizeGap {
+			return false
+		}
+		return r.PersonalityID == nil || big.Personalities[*r.PersonalityID]
+	case KennelRulePersonality:
+		if r.PersonalityID == nil {
+			return false
+		}
+		other := *r.PersonalityID
+		if r.OtherPersonalityID != nil {
+			other = *r.OtherPersonalityID
+		}
+		return (a.Personalities[*r.PersonalityID] && b.Personalities[other]) ||
+			(a.Personalities[other] && b.Personalities[*r.PersonalityID])
+	case KennelRuleQuarantineMix:
+		return a.Quarantined != b.Quarantined
+	}
+	return false
+}
+
+// EvaluatePlacement ตรวจกฎทั้งหมดของการนำ dog เข้าคอก kennel ที่มี occupants อยู่แล้ว
+// (kennel ต้อง preload AllowedSizes; occupants ที่เป็นตัว dog เองจะถูกข้าม)
+func EvaluatePlacement(rules []KennelRule, dog PlacementDog, kennel Kennel, occupants []PlacementDog) []KennelRuleViolation {
+	out := []KennelRuleViolation{}
+	for _, r := range rules {
+		v := KennelRuleViolation{RuleID: r.ID, Rule: r.Name, Kind: r.Kind, Severity: r.Severity}
+		if r.Kind == KennelRuleKennelSize {
+			if dog.SizeID != 0 && !kennel.AcceptsSize(dog.SizeID) {
+				v.Message = fmt.Sprintf("%s: kennel %s does not accept %s's size", r.Name, kennel.Name, dog.Name)
+				out = append(out, v)
+			}
+			continue
+		}
+		for _, o := range occupants {
+			if o.ID == dog.ID || !pairMatches(r, dog, o) {
+				continue
+			}
+			id := o.ID
+			v.OtherDogID = &id
+			v.Message = fmt.Sprintf("%s: %s with %s", r.Name, dog.Name, o.Name)
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// CheckKennelRules ตรวจกฎก่อน/หลังย้ายสุนัขเข้าคอก (ใน tx เดียวกัน)
+// ขัดกฎ block = CompatibilityError; ไม่เช่นนั้นคืนรายการเตือน (คอก "00" ไม่ตรวจ)
+func CheckKennelRules(tx *gorm.DB, dogID, kennelID uint) ([]KennelRuleViolation, error) {
+	rules, err := LoadKennelRules(tx)
+	if err != nil || len(rules) == 0 {
+		return nil, err
+	}
+	dogs, err := LoadPlacementDogs(tx, "id = ?", dogID)
+	if err != nil {
+		return nil, err
+	}
+	if len(dogs) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	var kennel Kennel
+	if err := tx.Preload("AllowedSizes").First(&kennel, kennelID).Error; err != nil {
+		return nil, err
+	}
+	if kennel.Unassigned() {
+		return nil, nil
+	}
+	occupants, err := LoadPlacementDogs(tx, "kennel_id = ? AND id <> ?", kennelID, dogID)
+	if err != nil {
+		return nil, err
+	}
+	var warnings, blocks []KennelRuleViolation
+	for _, v := range EvaluatePlacement(rules, dogs[0], kennel, occupants) {
+		if v.Severity == KennelRuleBlock {
+			blocks = append(blocks, v)
+		} else {
+			warnings = append(warnings, v)
+		}
+	}
+	if len(blocks) > 0 {
+		return warnings, &CompatibilityError{Violations: blocks}
+	}
+	return warnings, nil
+}
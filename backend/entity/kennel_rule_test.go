@@ -0,0 +1,155 @@
+package entity
+
+import (
+	"testing"
+
+	"example.com/project-sa/utils/pointer"
+	"gorm.io/gorm"
+)
+
+const (
+	testMale   uint = 1
+	testFemale uint = 2
+
+	testSmall  uint = 1
+	testMedium uint = 2
+	testLarge  uint = 3
+
+	testDominant uint = 10
+	testShy      uint = 11
+)
+
+// placementDog ตัวทดสอบ: size = id ของ AnimalSize (rank = id-1 ตามลำดับ id)
+func placementDog(id, sex, size uint, sterilized bool, personalities ...uint) PlacementDog {
+	d := PlacementDog{
+		ID: id, Name: string(rune('A' + id - 1)), SexID: sex, SizeID: size,
+		Sterilized: sterilized, Personalities: map[uint]bool{},
+	}
+	if size != 0 {
+		d.SizeRank = int(size) - 1
+	}
+	for _, p := range personalities {
+		d.Personalities[p] = true
+	}
+	return d
+}
+
+func TestPairMatches(t *testing.T) {
+	intactMale := placementDog(1, testMale, testMedium, false)
+	intactMale2 := placementDog(2, testMale, testMedium, false)
+	neuteredMale := placementDog(3, testMale, testMedium, true)
+	intactFemale := placementDog(4, testFemale, testMedium, false)
+	smallDog := placementDog(5, testFemale, testSmall, true)
+	largeDog := placementDog(6, testMale, testLarge, true)
+	largeDominant := placementDog(7, testMale, testLarge, true, testDominant)
+	shyDog := placementDog(8, testFemale, testMedium, true, testShy)
+	dominantDog := placementDog(9, testMale, testMedium, true, testDominant)
+	noSize := placementDog(10, testMale, 0, true)
+	quarantined := placementDog(11, testMale, testMedium, true)
+	quarantined.Quarantined = true
+
+	sameSex := KennelRule{Kind: KennelRuleIntactSameSex, SexID: pointer.P(testMale)}
+	mixedSex := KennelRule{Kind: KennelRuleIntactMixedSex, SexID: pointer.P(testMale), OtherSexID: pointer.P(testFemale)}
+	sizeGap := KennelRule{Kind: KennelRuleSizeGap, MaxSizeGap: 1}
+	sizeGapDominant := KennelRule{Kind: KennelRuleSizeGap, MaxSizeGap: 1, PersonalityID: pointer.P(testDominant)}
+	conflict := KennelRule{Kind: KennelRulePersonality, PersonalityID: pointer.P(testDominant), OtherPersonalityID: pointer.P(testShy)}
+	sameTrait := KennelRule{Kind: KennelRulePersonality, PersonalityID: pointer.P(testDominant)}
+	quarantineMix := KennelRule{Kind: KennelRuleQuarantineMix}
+
+	tests := []struct {
+		name string
+		rule KennelRule
+		a, b PlacementDog
+		want bool
+	}{
+		{"intact males", sameSex, intactMale, intactMale2, true},
+		{"one male neutered", sameSex, intactMale, neuteredMale, false},
+		{"same-sex rule ignores other sex", sameSex, intactFemale, intactFemale, false},
+		{"same-sex rule without sex", KennelRule{Kind: KennelRuleIntactSameSex}, intactMale, intactMale2, false},
+		{"intact male and female", mixedSex, intactMale, intactFemale, true},
+		{"intact female and male (either order)", mixedSex, intactFemale, intactMale, true},
+		{"mixed-sex rule with two males", mixedSex, intactMale, intactMale2, false},
+		{"mixed-sex rule with a neutered dog", mixedSex, neuteredMale, intactFemale, false},
+		{"size gap of two", sizeGap, smallDog, largeDog, true},
+		{"size gap of two (either order)", sizeGap, largeDog, smallDog, true},
+		{"size gap within limit", sizeGap, smallDog, dominantDog, false},
+		{"size gap with unknown size", sizeGap, smallDog, noSize, false},
+		{"size gap when the bigger dog has the trait", sizeGapDominant, smallDog, largeDominant, true},
+		{"size gap when the bigger dog lacks the trait", sizeGapDominant, smallDog, largeDog, false},
+		{"conflicting personalities", conflict, dominantDog, shyDog, true},
+		{"conflicting personalities (either order)", conflict, shyDog, dominantDog, true},
+		{"only one side has a listed trait", conflict, dominantDog, largeDog, false},
+		{"same trait on both", sameTrait, dominantDog, largeDominant, true},
+		{"same trait on one side only", sameTrait, dominantDog, shyDog, false},
+		{"quarantined with healthy dog", quarantineMix, quarantined, intactMale, true},
+		{"both healthy", quarantineMix, intactMale, intactMale2, false},
+		{"kennel size is not a pair rule", KennelRule{Kind: KennelRuleKennelSize}, smallDog, largeDog, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pairMatches(tt.rule, tt.a, tt.b); got != tt.want {
+				t.Errorf("pairMatches = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluatePlacement(t *testing.T) {
+	rules := []KennelRule{
+		{Model: gorm.Model{ID: 1}, Name: "intact males", Kind: KennelRuleIntactSameSex, Severity: KennelRuleBlock, SexID: pointer.P(testMale)},
+		{Model: gorm.Model{ID: 2}, Name: "size gap", Kind: KennelRuleSizeGap, Severity: KennelRuleWarn, MaxSizeGap: 1},
+		{Model: gorm.Model{ID: 3}, Name: "kennel size", Kind: KennelRuleKennelSize, Severity: KennelRuleWarn},
+	}
+	smallOnly := Kennel{Name: "S-1", AllowedSizes: []AnimalSize{{Model: gorm.Model{ID: testSmall}}}}
+	anySize := Kennel{Name: "A-1"}
+
+	dog := placementDog(1, testMale, testLarge, false)
+	tests := []struct {
+		name      string
+		kennel    Kennel
+		occupants []PlacementDog
+		want      []KennelRuleViolation // เทียบ RuleID/OtherDogID/Severity
+	}{
+		{"empty kennel", anySize, nil, nil},
+		{"dog itself is skipped", anySize, []PlacementDog{dog}, nil},
+		{
+			name:      "block per matching occupant",
+			kennel:    anySize,
+			occupants: []PlacementDog{placementDog(2, testMale, testLarge, false), placementDog(3, testMale, testLarge, true), placementDog(4, testMale, testLarge, false)},
+			want: []KennelRuleViolation{
+				{RuleID: 1, OtherDogID: pointer.P[uint](2), Severity: KennelRuleBlock},
+				{RuleID: 1, OtherDogID: pointer.P[uint](4), Severity: KennelRuleBlock},
+			},
+		},
+		{
+			name:      "kennel size and size gap warnings",
+			kennel:    smallOnly,
+			occupants: []PlacementDog{placementDog(5, testFemale, testSmall, true)},
+			want: []KennelRuleViolation{
+				{RuleID: 2, OtherDogID: pointer.P[uint](5), Severity: KennelRuleWarn},
+				{RuleID: 3, Severity: KennelRuleWarn},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EvaluatePlacement(rules, dog, tt.kennel, tt.occupants)
+			if len(got) != len(tt.want) {
+				t.Fatalf("EvaluatePlacement = %+v, want %d violation(s)", got, len(tt.want))
+			}
+			for i, w := range tt.want {
+				g := got[i]
+				if g.RuleID != w.RuleID || g.Severity != w.Severity || !sameID(g.OtherDogID, w.OtherDogID) || g.Message == "" {
+					t.Errorf("violation %d = %+v, want rule %d other %v severity %s", i, g, w.RuleID, w.OtherDogID, w.Severity)
+				}
+			}
+		})
+	}
+}
+
+func sameID(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
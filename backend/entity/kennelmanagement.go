@@ -1,11 +1,126 @@
 package entity
 
+import (
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ประเภทการย้ายคอก
+const (
+	KennelMoveAssign   = "assign"   // เข้าคอก (ก่อนหน้าไม่มีคอก)
+	KennelMoveTransfer = "move"     // ย้ายจากคอกหนึ่งไปอีกคอก
+	KennelMoveUnassign = "unassign" // ออกจากคอก (บ้านอุปถัมภ์/ส่งต่อ/เสียชีวิต/เอาออก)
+)
+
+// KennelManagement สมุดบันทึกการย้ายคอกของสุนัข (หนึ่งแถวต่อการย้ายหนึ่งครั้ง)
+// ใช้ตอบว่า ณ เวลาใดสุนัขอยู่คอกไหน
 type KennelManagement struct {
-	KennelID uint
-	Kennel   *Kennel `gorm:"foreignKey:KennelID"`
-	DogID    uint
-	Dog      *Dog `gorm:"foreignKey:DogID"`
-	StaffID  uint
-	Staff    *Staff `gorm:"foreignKey:StaffID"`
-	Action   string `gorm:"not null"`
+	gorm.Model
+	DogID uint `gorm:"index" json:"dog_id"`
+	Dog   *Dog `gorm:"foreignKey:DogID" json:"dog,omitempty"`
+
+	FromKennelID *uint   `gorm:"index" json:"from_kennel_id"` // ว่าง = ก่อนหน้าไม่มีคอก
+	FromKennel   *Kennel `gorm:"foreignKey:FromKennelID" json:"from_kennel,omitempty"`
+	ToKennelID   *uint   `gorm:"index" json:"to_kennel_id"` // ว่าง = ออกจากคอก
+	ToKennel     *Kennel `gorm:"foreignKey:ToKennelID" json:"to_kennel,omitempty"`
+
+	MovedAt time.Time `gorm:"index" json:"moved_at"`
+	StaffID *uint     `json:"staff_id"`
+	Staff   *Staff    `gorm:"foreignKey:StaffID" json:"staff,omitempty"`
+	Action  string    `gorm:"not null" json:"action"` // assign | move | unassign
+	Reason  string    `json:"reason"`
+}
+
+// RecordKennelMove บันทึกการย้ายคอกลงสมุด (from == to = ไม่บันทึก) เรียกใน tx เดียวกับที่แก้ kennel_id
+func RecordKennelMove(tx *gorm.DB, dogID uint, from, to *uint, staffID *uint, reason string) error {
+	if (from == nil && to == nil) || (from != nil && to != nil && *from == *to) {
+		return nil
+	}
+	action := KennelMoveTransfer
+	switch {
+	case from == nil:
+		action = KennelMoveAssign
+	case to == nil:
+		action = KennelMoveUnassign
+	}
+	return tx.Create(&KennelManagement{
+		DogID:        dogID,
+		FromKennelID: from,
+		ToKennelID:   to,
+		MovedAt:      time.Now().UTC(), // sqlite เทียบเวลาแบบข้อความ เก็บ UTC ทั้งหมด
+		StaffID:      staffID,
+		Action:       action,
+		Reason:       reason,
+	}).Error
+}
+
+// KennelStay ช่วงเวลาที่สุนัขอยู่คอกหนึ่ง (KennelID ว่าง = ไม่มีคอก เช่น อยู่บ้านอุปถัมภ์)
+type KennelStay struct {
+	KennelID *uint             `json:"kennel_id"`
+	Kennel   *Kennel           `json:"kennel,omitempty"`
+	From     time.Time         `json:"from"`
+	To       *time.Time        `json:"to"` // ว่าง = ยังอยู่
+	MovedIn  *KennelManagement `json:"moved_in,omitempty"`
+}
+
+// KennelStays ไล่สมุดย้ายคอก (เรียงตามเวลา) เป็นช่วงเวลา เริ่มจากวันที่บันทึกสุนัขเข้าระบบ
+// สุนัขที่ยังไม่มีรายการย้ายเลยถือว่าอยู่คอกปัจจุบันมาตั้งแต่ต้น
+func KennelStays(dog Dog, moves []KennelManagement) []KennelStay {
+	cur := dog.KennelID
+	if len(moves) > 0 {
+		cur = moves[0].FromKennelID
+	}
+	out := []KennelStay{}
+	open := KennelStay{KennelID: cur, From: dog.CreatedAt}
+	for i := range moves {
+		m := moves[i]
+		at := m.MovedAt
+		open.To = &at
+		// สร้างสุนัขแล้วเข้าคอกทันที ไม่ต้องมีช่วง "ไม่มีคอก" สั้น ๆ ตอนต้น
+		if len(out) > 0 || open.KennelID != nil || at.Sub(open.From) >= time.Minute {
+			out = append(out, open)
+		}
+		open = KennelStay{KennelID: m.ToKennelID, From: at, MovedIn: &moves[i]}
+	}
+	if dog.DeletedAt.Valid {
+		end := dog.DeletedAt.Time
+		open.To = &end
+	}
+	return append(out, open)
+}
+
+// KennelAt คอกที่สุนัขอยู่ ณ เวลา at (nil = ไม่มีคอก หรือยังไม่เข้าระบบ)
+func KennelAt(dog Dog, moves []KennelManagement, at time.Time) *uint {
+	for _, s := range KennelStays(dog, moves) {
+		if !s.From.After(at) && (s.To == nil || s.To.After(at)) {
+			return s.KennelID
+		}
+	}
+	return nil
+}
+
+// SortKennelMoves เรียงตามเวลาจริง ไม่ใช่ข้อความในคอลัมน์ moved_at (แถวเก่าอาจมี offset ท้องถิ่น)
+func SortKennelMoves(rows []KennelManagement) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		if !rows[i].MovedAt.Equal(rows[j].MovedAt) {
+			return rows[i].MovedAt.Before(rows[j].MovedAt)
+		}
+		return rows[i].ID < rows[j].ID
+	})
+}
+
+// LoadKennelMoves สมุดย้ายคอกของสุนัขแต่ละตัว เรียงตามเวลา
+func LoadKennelMoves(db *gorm.DB, dogIDs []uint) (map[uint][]KennelManagement, error) {
+	var rows []KennelManagement
+	if err := db.Where("dog_id IN ?", dogIDs).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	SortKennelMoves(rows)
+	out := map[uint][]KennelManagement{}
+	for _, r := range rows {
+		out[r.DogID] = append(out[r.DogID], r)
+	}
+	return out, nil
 }
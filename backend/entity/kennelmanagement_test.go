@@ -0,0 +1,137 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var created = time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+func kid(id uint) *uint { return &id }
+
+func move(from, to *uint, after time.Duration) KennelManagement {
+	return KennelManagement{FromKennelID: from, ToKennelID: to, MovedAt: created.Add(after)}
+}
+
+// span ช่วงที่คาดหวัง: kennel 0 = ไม่มีคอก, to < 0 = ยังอยู่
+type span struct {
+	kennel   uint
+	from, to time.Duration
+}
+
+func spansOf(ss []KennelStay) []span {
+	out := make([]span, len(ss))
+	for i, s := range ss {
+		out[i] = span{from: s.From.Sub(created), to: -1}
+		if s.KennelID != nil {
+			out[i].kennel = *s.KennelID
+		}
+		if s.To != nil {
+			out[i].to = s.To.Sub(created)
+		}
+	}
+	return out
+}
+
+func TestKennelStays(t *testing.T) {
+	day := 24 * time.Hour
+	deleted := gorm.DeletedAt{Time: created.Add(10 * day), Valid: true}
+	tests := []struct {
+		name  string
+		dog   Dog
+		moves []KennelManagement
+		want  []span
+	}{
+		{
+			name: "no moves keeps the current kennel from creation",
+			dog:  Dog{KennelID: kid(1)},
+			want: []span{{1, 0, -1}},
+		},
+		{
+			name: "no moves and no kennel",
+			dog:  Dog{},
+			want: []span{{0, 0, -1}},
+		},
+		{
+			name:  "assigned right after creation skips the empty gap",
+			dog:   Dog{KennelID: kid(1)},
+			moves: []KennelManagement{move(nil, kid(1), 10*time.Second)},
+			want:  []span{{1, 10 * time.Second, -1}},
+		},
+		{
+			name:  "assigned a day later keeps the time without a kennel",
+			dog:   Dog{KennelID: kid(1)},
+			moves: []KennelManagement{move(nil, kid(1), day)},
+			want:  []span{{0, 0, day}, {1, day, -1}},
+		},
+		{
+			name: "moves and unassign",
+			dog:  Dog{},
+			moves: []KennelManagement{
+				move(kid(1), kid(2), 2*day),
+				move(kid(2), nil, 5*day),
+			},
+			want: []span{{1, 0, 2 * day}, {2, 2 * day, 5 * day}, {0, 5 * day, -1}},
+		},
+		{
+			name:  "deleted dog closes the last stay",
+			dog:   Dog{KennelID: kid(2), Model: gorm.Model{DeletedAt: deleted}},
+			moves: []KennelManagement{move(kid(1), kid(2), 3*day)},
+			want:  []span{{1, 0, 3 * day}, {2, 3 * day, 10 * day}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.dog.CreatedAt = created
+			got := spansOf(KennelStays(tt.dog, tt.moves))
+			if len(got) != len(tt.want) {
+				t.Fatalf("KennelStays = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("stay %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestKennelAt(t *testing.T) {
+	day := 24 * time.Hour
+	dog := Dog{Model: gorm.Model{
+		CreatedAt: created,
+		DeletedAt: gorm.DeletedAt{Time: created.Add(10 * day), Valid: true},
+	}}
+	moves := []KennelManagement{
+		move(kid(1), kid(2), 2*day),
+		move(kid(2), nil, 5*day),
+		move(nil, kid(3), 6*day),
+	}
+	tests := []struct {
+		name string
+		at   time.Duration
+		want uint // 0 = nil
+	}{
+		{"before the dog was registered", -time.Hour, 0},
+		{"at registration", 0, 1},
+		{"first kennel", day, 1},
+		{"exactly at a move", 2 * day, 2},
+		{"second kennel", 4 * day, 2},
+		{"unassigned", 5*day + time.Hour, 0},
+		{"last kennel", 8 * day, 3},
+		{"after deletion", 10 * day, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := KennelAt(dog, moves, created.Add(tt.at))
+			switch {
+			case got == nil && tt.want != 0:
+				t.Errorf("KennelAt = nil, want %d", tt.want)
+			case got != nil && *got != tt.want:
+				t.Errorf("KennelAt = %d, want %d", *got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ที่ตรวจ
+const (
+	LabInHouse  = "in_house"
+	LabExternal = "external"
+)
+
+// สถานะใบสั่งตรวจแล็บ
+const (
+	LabOrdered   = "ordered"
+	LabCollected = "collected" // เก็บตัวอย่างแล้ว รอผล
+	LabResulted  = "resulted"
+	LabCancelled = "cancelled"
+)
+
+// ผลเทียบค่าอ้างอิง
+const (
+	LabFlagNormal   = "normal"
+	LabFlagLow      = "low"
+	LabFlagHigh     = "high"
+	LabFlagAbnormal = "abnormal" // ผลเชิงคุณภาพไม่ตรงค่าปกติ (เช่น positive)
+)
+
+// LabPanel ชุดการตรวจ เช่น CBC, blood chemistry, ตรวจอุจจาระหาพยาธิ
+type LabPanel struct {
+	gorm.Model
+	Code        string `gorm:"uniqueIndex" json:"code"`
+	Name        string `json:"name"`
+	SampleType  string `json:"sample_type"` // blood | serum | feces | urine | skin
+	Description string `json:"description"`
+
+	Analytes []LabAnalyte `gorm:"foreignKey:PanelID" json:"analytes,omitempty"`
+}
+
+// LabAnalyte ค่าที่ตรวจในชุด พร้อมค่าอ้างอิง
+// ค่าเชิงปริมาณใช้ RefLow/RefHigh, ค่าเชิงคุณภาพใช้ NormalText (เช่น "negative")
+type LabAnalyte struct {
+	gorm.Model
+	PanelID    uint     `gorm:"index" json:"panel_id"`
+	Code       string   `json:"code"`
+	Name       string   `json:"name"`
+	Unit       string   `json:"unit"`
+	RefLow     *float64 `json:"ref_low"`
+	RefHigh    *float64 `json:"ref_high"`
+	NormalText string   `json:"normal_text"`
+	SortOrder  int      `json:"sort_order"`
+}
+
+func (a LabAnalyte) Qualitative() bool { return a.NormalText != "" }
+
+// LabOrder ใบสั่งตรวจแล็บของสุนัข (ผูกกับประวัติสุขภาพที่สั่งตรวจ)
+type LabOrder struct {
+	gorm.Model
+	DogID uint `gorm:"index" json:"dog_id"`
+	Dog   *Dog `gorm:"foreignKey:DogID" json:"dog,omitempty"`
+
+	MedicalRecordID *uint          `gorm:"index" json:"medical_record_id"`
+	MedicalRecord   *MedicalRecord `gorm:"foreignKey:MedicalRecordID" json:"medical_record,omitempty"`
+
+	PanelID uint      `gorm:"index" json:"panel_id"`
+	Panel   *LabPanel `gorm:"foreignKey:PanelID" json:"panel,omitempty"`
+
+	OrderedByID uint      `gorm:"index" json:"ordered_by_id"`
+	OrderedBy   *Staff    `gorm:"foreignKey:OrderedByID" json:"ordered_by,omitempty"`
+	OrderedAt   time.Time `json:"ordered_at"`
+
+	Location    string `json:"location"`     // in_house | external
+	ExternalLab string `json:"external_lab"` // ชื่อแล็บภายนอก
+	ExternalRef string `json:"external_ref"` // เลขที่อ้างอิงของแล็บภายนอก
+	Notes       string `json:"notes"`
+
+	SampleCollectedAt *time.Time `json:"sample_collected_at"`
+	Status            string     `gorm:"index" json:"status"`
+
+	ResultedAt   *time.Time `json:"resulted_at"`
+	ResultedByID *uint      `json:"resulted_by_id"`
+	ResultedBy   *Staff     `gorm:"foreignKey:ResultedByID" json:"resulted_by,omitempty"`
+	Abnormal     bool       `json:"abnormal"` // มีค่าผิดปกติอย่างน้อยหนึ่งค่า
+
+	CancelledAt  *time.Time `json:"cancelled_at"`
+	CancelReason string     `json:"cancel_reason"`
+
+	Results []LabResult `gorm:"foreignKey:LabOrderID;constraint:OnDelete:CASCADE" json:"results,omitempty"`
+}
+
+// LabResult ค่าผลตรวจหนึ่งค่า (เก็บสำเนาค่าอ้างอิง ณ ตอนบันทึก)
+// แก้ผล = soft delete ชุดเดิมแล้วสร้างชุดใหม่ ค่าใหม่ชี้กลับไปที่ค่าเดิมผ่าน CorrectedFromID
+type LabResult struct {
+	gorm.Model
+	LabOrderID uint        `gorm:"index" json:"lab_order_id"`
+	AnalyteID  *uint       `json:"analyte_id"`
+	Analyte    *LabAnalyte `gorm:"foreignKey:AnalyteID" json:"analyte,omitempty"`
+
+	EnteredByID     *uint      `json:"entered_by_id"`
+	EnteredBy       *Staff     `gorm:"foreignKey:EnteredByID;constraint:OnDelete:SET NULL" json:"entered_by,omitempty"`
+	CorrectedFromID *uint      `json:"corrected_from_id"`
+	CorrectedFrom   *LabResult `gorm:"foreignKey:CorrectedFromID" json:"-"`
+
+	Name       string   `json:"name"`
+	Unit       string   `json:"unit"`
+	Value      *float64 `json:"value"`
+	ValueText  string   `json:"value_text"`
+	RefLow     *float64 `json:"ref_low"`
+	RefHigh    *float64 `json:"ref_high"`
+	NormalText string   `json:"normal_text"`
+	Flag       string   `json:"flag"` // normal | low | high | abnormal ("" = ไม่มีค่าอ้างอิง)
+	Comment    string   `json:"comment"`
+}
+
+// StaffNotification แจ้งเตือนถึงเจ้าหน้าที่ (เช่น ผลแล็บออกแล้ว)
+type StaffNotification struct {
+	gorm.Model
+	StaffID    uint      `gorm:"index" json:"staff_id"`
+	DogID      *uint     `json:"dog_id"`
+	Dog        *Dog      `gorm:"foreignKey:DogID" json:"dog,omitempty"`
+	LabOrderID *uint     `json:"lab_order_id"`
+	LabOrder   *LabOrder `gorm:"foreignKey:LabOrderID" json:"lab_order,omitempty"`
+
+	Kind    string     `json:"kind"`
+	Message string     `json:"message"`
+	ReadAt  *time.Time `json:"read_at"`
+}
+
+const (
+	StaffNoticeLabResult         = "lab_result"
+	StaffNoticeLabResultAbnormal = "lab_result_abnormal"
+)
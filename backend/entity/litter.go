@@ -0,0 +1,32 @@
+package entity
+
+import "gorm.io/gorm"
+
+// ครอกลูกสุนัขที่รับเข้ามาพร้อมกัน (มักมาพร้อมแม่)
+type Litter struct {
+	gorm.Model
+	Name         string `json:"name"`
+	BirthDate    *Date  `json:"birth_date"`
+	DOBEstimated bool   `gorm:"not null;default:false" json:"dob_estimated"`
+	DOBPrecision string `gorm:"default:day" json:"dob_precision"`
+	Notes        string `json:"notes"`
+
+	// พ่อแม่ของครอก (ลูกทุกตัวจะได้ mother_id/father_id เดียวกัน)
+	MotherID *uint `json:"mother_id"`
+	FatherID *uint `json:"father_id"`
+
+	Dogs []Dog `gorm:"foreignKey:LitterID" json:"dogs"`
+
+	// เติมตอนส่งออก (ไม่ผูก FK เพื่อกันวงจร dogs <-> litters)
+	Mother *Dog `gorm:"-" json:"mother,omitempty"`
+	Father *Dog `gorm:"-" json:"father,omitempty"`
+}
+
+// สุนัขที่เกี่ยวข้องกัน (แสดงใน GetDogById)
+type DogRelatives struct {
+	Mother       *Dog  `json:"mother"`
+	Father       *Dog  `json:"father"`
+	Littermates  []Dog `json:"littermates"`   // ครอกเดียวกัน
+	HalfSiblings []Dog `json:"half_siblings"` // พ่อหรือแม่เดียวกัน แต่ต่างครอก
+	Offspring    []Dog `json:"offspring"`
+}
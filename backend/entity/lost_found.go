@@ -0,0 +1,81 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ประเภทประกาศ
+const (
+	LostFoundLost  = "lost"  // เจ้าของแจ้งหมาหาย
+	LostFoundFound = "found" // คนทั่วไปแจ้งพบหมา
+)
+
+// สถานะประกาศ
+const (
+	LostFoundOpen    = "open"
+	LostFoundMatched = "matched"
+	LostFoundClosed  = "closed"
+)
+
+// สถานะคู่ที่ระบบจับให้ (รอเจ้าหน้าที่ยืนยัน)
+const (
+	MatchCandidate = "candidate"
+	MatchConfirmed = "confirmed"
+	MatchRejected  = "rejected"
+)
+
+type LostFoundReport struct {
+	gorm.Model
+	Type   string `gorm:"index" json:"type"`   // lost | found
+	Status string `gorm:"index" json:"status"` // open | matched | closed
+
+	ReporterName  string `json:"reporter_name"`
+	ReporterPhone string `json:"reporter_phone"`
+	ReporterEmail string `json:"reporter_email"`
+
+	DogName      string      `json:"dog_name"`
+	BreedID      *uint       `json:"breed_id"`
+	Breed        *Breed      `gorm:"foreignKey:BreedID" json:"breed"`
+	AnimalSexID  *uint       `json:"animal_sex_id"`
+	AnimalSex    *AnimalSex  `gorm:"foreignKey:AnimalSexID" json:"animal_sex"`
+	AnimalSizeID *uint       `json:"animal_size_id"`
+	AnimalSize   *AnimalSize `gorm:"foreignKey:AnimalSizeID" json:"animal_size"`
+	Color        string      `json:"color"`
+
+	EventDate   string   `json:"event_date"` // "YYYY-MM-DD" วันที่หาย/พบ
+	Area        string   `json:"area"`       // ย่าน/เขต ใช้จับคู่
+	Location    string   `json:"location"`   // รายละเอียดสถานที่
+	Latitude    *float64 `json:"latitude"`
+	Longitude   *float64 `json:"longitude"`
+	Description string   `json:"description"`
+
+	LastMatchedAt *time.Time `json:"last_matched_at"`
+
+	Photos  []LostFoundPhoto `gorm:"foreignKey:ReportID;constraint:OnDelete:CASCADE" json:"photos"`
+	Matches []LostFoundMatch `gorm:"foreignKey:ReportID;constraint:OnDelete:CASCADE" json:"matches"`
+}
+
+type LostFoundPhoto struct {
+	gorm.Model
+	ReportID uint   `gorm:"index" json:"report_id"`
+	URL      string `json:"url"`
+}
+
+type LostFoundMatch struct {
+	gorm.Model
+	ReportID uint             `gorm:"uniqueIndex:idx_lost_found_match" json:"report_id"`
+	Report   *LostFoundReport `gorm:"foreignKey:ReportID" json:"report,omitempty"`
+	DogID    uint             `gorm:"uniqueIndex:idx_lost_found_match" json:"dog_id"`
+	Dog      *Dog             `gorm:"foreignKey:DogID" json:"dog,omitempty"`
+
+	Score   int    `json:"score"`   // 0-100
+	Reasons string `json:"reasons"` // ฟิลด์ที่ตรงกัน เช่น "breed,sex,color"
+	Status  string `gorm:"index" json:"status"`
+
+	ReviewedByID *uint      `json:"reviewed_by_id"`
+	ReviewedBy   *Staff     `gorm:"foreignKey:ReviewedByID" json:"reviewed_by"`
+	ReviewedAt   *time.Time `json:"reviewed_at"`
+	Note         string     `json:"note"`
+}
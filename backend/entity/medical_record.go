@@ -9,15 +9,15 @@ import (
 
 type MedicalRecord struct {
 	gorm.Model
-	DateRecord    time.Time `json:"date_record"`
-	Weight        float64   `json:"weight"`
-	Temperature   float64   `json:"temperature"`
-	Symptoms      string    `json:"symptoms"`
-	Diagnosis     string    `json:"diagnosis"`
-	TreatmentPlan string    `json:"treatment"`
-	Medication    string    `json:"medication"`
-	Vaccination   string    `json:"vaccination"` // should be "YES" or "NO"
-	Notes         string    `json:"notes"`       // Added Notes field
+	DateRecord    time.Time       `json:"date_record"`
+	Weight        float64         `json:"weight"`
+	Temperature   float64         `json:"temperature"`
+	Symptoms      string          `json:"symptoms"`
+	Diagnosis     string          `json:"diagnosis"`
+	TreatmentPlan string          `json:"treatment"`
+	Medication    string          `json:"medication"`
+	Vaccination   VaccinationFlag `json:"vaccination"` // YES | NO
+	Notes         string          `json:"notes"`       // Added Notes field
 
 	DogID uint `json:"dog_id"`
 	Dog   *Dog `gorm:"foreignKey:DogID" json:"dog"`
@@ -26,4 +26,29 @@ type MedicalRecord struct {
 	Staff   *Staff `gorm:"foreignKey:StaffID" json:"staff"` // Association to Staff
 
 	VaccineRecords []VaccineRecord `gorm:"foreignKey:MedID" json:"vaccine_records"`
+
+	Attachments []MedicalRecordAttachment `gorm:"foreignKey:MedicalRecordID;constraint:OnDelete:CASCADE" json:"attachments,omitempty"`
+
+	// ร่าง = แก้/ลบได้, ลงนามแล้ว = แก้ได้ผ่านการแก้ไขเพิ่มเติม (amendment) เท่านั้น
+	Status        string     `gorm:"default:draft;index" json:"status"`
+	FinalizedAt   *time.Time `json:"finalized_at"`
+	FinalizedByID *uint      `json:"finalized_by_id"`
+	FinalizedBy   *Staff     `gorm:"foreignKey:FinalizedByID" json:"finalized_by,omitempty"`
+	Version       int        `gorm:"default:1" json:"version"` // เพิ่มขึ้นทุกครั้งที่มี amendment
+
+	Amendments []MedicalRecordAmendment `gorm:"foreignKey:MedicalRecordID" json:"amendments,omitempty"`
+
+	LabOrders []LabOrder `gorm:"foreignKey:MedicalRecordID" json:"lab_orders,omitempty"`
+
+	// เจ้าหน้าที่ที่แก้ไขร่างล่าสุด / ลบร่าง
+	UpdatedByID *uint  `json:"updated_by_id"`
+	UpdatedBy   *Staff `gorm:"foreignKey:UpdatedByID;constraint:OnUpdate:RESTRICT,OnDelete:SET NULL;" json:"updated_by,omitempty"`
+	DeletedByID *uint  `json:"deleted_by_id"`
+	DeletedBy   *Staff `gorm:"foreignKey:DeletedByID;constraint:OnUpdate:RESTRICT,OnDelete:SET NULL;" json:"deleted_by,omitempty"`
 }
+
+// สถานะประวัติสุขภาพ
+const (
+	MedicalRecordDraft     = "draft"
+	MedicalRecordFinalized = "finalized"
+)
@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MedicalRecordAmendment การแก้ไขประวัติสุขภาพที่ลงนามแล้ว (เพิ่มต่อท้ายเท่านั้น เก็บค่าเดิมไว้ทุกฟิลด์)
+type MedicalRecordAmendment struct {
+	gorm.Model
+	MedicalRecordID uint           `gorm:"index" json:"medical_record_id"`
+	MedicalRecord   *MedicalRecord `gorm:"foreignKey:MedicalRecordID" json:"medical_record,omitempty"`
+
+	Version     int       `json:"version"` // เวอร์ชันที่ได้หลังแก้ (ต้นฉบับ = 1)
+	Reason      string    `json:"reason"`
+	AmendedAt   time.Time `json:"amended_at"`
+	AmendedByID uint      `json:"amended_by_id"`
+	AmendedBy   *Staff    `gorm:"foreignKey:AmendedByID" json:"amended_by,omitempty"`
+
+	Changes []MedicalRecordAmendmentChange `gorm:"foreignKey:AmendmentID;constraint:OnDelete:CASCADE" json:"changes"`
+}
+
+// MedicalRecordAmendmentChange ค่าก่อน/หลังของแต่ละฟิลด์ในการแก้ไขหนึ่งครั้ง
+type MedicalRecordAmendmentChange struct {
+	gorm.Model
+	AmendmentID uint   `gorm:"index" json:"amendment_id"`
+	Field       string `json:"field"`
+	OldValue    string `json:"old_value"`
+	NewValue    string `json:"new_value"`
+}
@@ -0,0 +1,26 @@
+package entity
+
+import "gorm.io/gorm"
+
+// ประเภทไฟล์แนบประวัติสุขภาพ
+const (
+	AttachmentLabResult = "lab_result"
+	AttachmentXRay      = "xray"
+	AttachmentInvoice   = "invoice"
+	AttachmentOther     = "other"
+)
+
+// ไฟล์แนบของ MedicalRecord (เก็บนอก static ดาวน์โหลดได้เฉพาะเจ้าหน้าที่)
+type MedicalRecordAttachment struct {
+	gorm.Model
+	MedicalRecordID uint   `gorm:"index" json:"medical_record_id"`
+	Type            string `json:"type"` // lab_result | xray | invoice | other
+	Description     string `json:"description"`
+	FileName        string `json:"file_name"` // ชื่อไฟล์ต้นฉบับ
+	FilePath        string `json:"-"`
+	ContentType     string `json:"content_type"`
+	SizeBytes       int64  `json:"size_bytes"`
+
+	UploadedByID uint   `json:"uploaded_by_id"`
+	UploadedBy   *Staff `gorm:"foreignKey:UploadedByID" json:"uploaded_by,omitempty"`
+}
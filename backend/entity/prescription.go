@@ -0,0 +1,88 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// วิธีให้ยา
+const (
+	RouteOral      = "oral"
+	RouteInjection = "injection"
+	RouteTopical   = "topical"
+	RouteEye       = "eye"
+	RouteEar       = "ear"
+	RouteOther     = "other"
+)
+
+// สถานะใบสั่งยา
+const (
+	PrescriptionActive    = "active"
+	PrescriptionCompleted = "completed" // ให้ยาครบคอร์ส/เลยวันสิ้นสุดแล้ว
+	PrescriptionStopped   = "stopped"   // หยุดยาก่อนครบคอร์ส
+)
+
+// สถานะการให้ยาแต่ละมื้อ
+const (
+	AdminScheduled = "scheduled"
+	AdminGiven     = "given"
+	AdminSkipped   = "skipped"   // ตั้งใจไม่ให้ (เช่น งดอาหาร)
+	AdminRefused   = "refused"   // สุนัขไม่ยอมกิน/ดิ้น
+	AdminMissed    = "missed"    // เลยเวลาแล้วไม่มีการบันทึก (ตั้งโดยระบบ)
+	AdminCancelled = "cancelled" // ใบสั่งยาถูกหยุดก่อนถึงเวลา
+)
+
+type Prescription struct {
+	gorm.Model
+	DogID uint `gorm:"index" json:"dog_id"`
+	Dog   *Dog `gorm:"foreignKey:DogID" json:"dog,omitempty"`
+
+	MedicalRecordID *uint          `json:"medical_record_id"`
+	MedicalRecord   *MedicalRecord `gorm:"foreignKey:MedicalRecordID" json:"medical_record,omitempty"`
+
+	Drug         string    `json:"drug"`
+	Dose         string    `json:"dose"` // เช่น "250 mg", "1 tablet"
+	Route        string    `json:"route"`
+	TimesPerDay  int       `json:"times_per_day"`
+	Times        string    `json:"times"` // เวลาให้ยา "08:00,20:00" (เวลาไทย)
+	DurationDays int       `json:"duration_days"`
+	StartDate    time.Time `json:"start_date"`
+	EndDate      time.Time `json:"end_date"` // วันสุดท้ายของคอร์ส
+	Instructions string    `json:"instructions"`
+
+	// ยาในคลังคลินิกที่ตัดสต็อกเมื่อให้ยา (ว่าง = ไม่ตัดสต็อก)
+	ClinicItemID *uint       `json:"clinic_item_id"`
+	ClinicItem   *ClinicItem `gorm:"foreignKey:ClinicItemID" json:"clinic_item,omitempty"`
+	StockPerDose int         `json:"stock_per_dose"` // จำนวนหน่วยของ ClinicItem ต่อมื้อ
+
+	Status        string     `gorm:"index" json:"status"`
+	StoppedAt     *time.Time `json:"stopped_at"`
+	StoppedReason string     `json:"stopped_reason"`
+
+	PrescribedByID *uint  `json:"prescribed_by_id"`
+	PrescribedBy   *Staff `gorm:"foreignKey:PrescribedByID" json:"prescribed_by,omitempty"`
+
+	Administrations []MedicationAdministration `gorm:"foreignKey:PrescriptionID;constraint:OnDelete:CASCADE" json:"administrations,omitempty"`
+}
+
+type MedicationAdministration struct {
+	gorm.Model
+	PrescriptionID uint          `gorm:"index" json:"prescription_id"`
+	Prescription   *Prescription `gorm:"foreignKey:PrescriptionID" json:"prescription,omitempty"`
+	DogID          uint          `gorm:"index" json:"dog_id"`
+	Dog            *Dog          `gorm:"foreignKey:DogID" json:"dog,omitempty"`
+
+	ScheduledAt time.Time `gorm:"index" json:"scheduled_at"`
+	Status      string    `gorm:"index" json:"status"`
+	Note        string    `json:"note"`
+
+	LotID        *uint      `json:"lot_id"` // lot ที่ตัดสต็อก
+	RecordedAt   *time.Time `json:"recorded_at"`
+	RecordedByID *uint      `json:"recorded_by_id"`
+	RecordedBy   *Staff     `gorm:"foreignKey:RecordedByID" json:"recorded_by,omitempty"`
+
+	// แจ้งเตือนมื้อที่พลาด: ผู้ดูแลรับทราบแล้ว
+	MissedAcknowledgedAt   *time.Time `json:"missed_acknowledged_at"`
+	MissedAcknowledgedByID *uint      `json:"missed_acknowledged_by_id"`
+}
@@ -0,0 +1,103 @@
+package entity
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// โรคติดต่อที่ต้องกักโรค
+const (
+	ConditionParvo       = "parvo"
+	ConditionDistemper   = "distemper"
+	ConditionKennelCough = "kennel_cough"
+	ConditionOther       = "other"
+)
+
+// ระยะฟักตัว (วัน) ใช้หาสุนัขที่อยู่คอกเดียวกันย้อนหลัง เมื่อไม่ได้ระบุเอง
+var IncubationDays = map[string]int{
+	ConditionParvo:       14,
+	ConditionDistemper:   21,
+	ConditionKennelCough: 14,
+	ConditionOther:       14,
+}
+
+// สถานะการกักโรค
+const (
+	QuarantineActive  = "active"
+	QuarantineCleared = "cleared" // สัตวแพทย์/เจ้าหน้าที่ลงนามพ้นกักโรคแล้ว
+)
+
+type QuarantineRecord struct {
+	gorm.Model
+	DogID uint `gorm:"index" json:"dog_id"`
+	Dog   *Dog `gorm:"foreignKey:DogID" json:"dog,omitempty"`
+
+	MedicalRecordID *uint          `json:"medical_record_id"` // ผลตรวจที่วินิจฉัย (ถ้ามี)
+	MedicalRecord   *MedicalRecord `gorm:"foreignKey:MedicalRecordID" json:"medical_record,omitempty"`
+
+	Condition         string `json:"condition"`
+	StartDate         Date   `json:"start_date"`
+	IncubationDays    int    `json:"incubation_days"`
+	ClearanceCriteria string `json:"clearance_criteria"` // เช่น "ตรวจ parvo antigen เป็นลบ 2 ครั้ง ห่างกัน 48 ชม."
+	Notes             string `json:"notes"`
+
+	Status        string     `gorm:"index" json:"status"`
+	ClearedAt     *time.Time `json:"cleared_at"`
+	ClearedByID   *uint      `json:"cleared_by_id"`
+	ClearedBy     *Staff     `gorm:"foreignKey:ClearedByID" json:"cleared_by,omitempty"`
+	ClearanceNote string     `json:"clearance_note"`
+
+	CreatedByID *uint  `json:"created_by_id"`
+	CreatedBy   *Staff `gorm:"foreignKey:CreatedByID" json:"created_by,omitempty"`
+}
+
+// IsolationError ย้ายสุนัขที่กักโรคเข้าคอกนอกโซนแยกโรคไม่ได้
+type IsolationError struct{ msg string }
+
+func (e *IsolationError) Error() string { return e.msg }
+
+func IsIsolationError(err error) bool {
+	var ie *IsolationError
+	return errors.As(err, &ie)
+}
+
+// activeQuarantine การกักโรคที่ยังไม่พ้นของสุนัข (nil = ไม่ได้กักโรค)
+func activeQuarantine(tx *gorm.DB, dogID uint) (*QuarantineRecord, error) {
+	var q QuarantineRecord
+	err := tx.Where("dog_id = ? AND status = ?", dogID, QuarantineActive).
+		Order("start_date ASC").First(&q).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &q, nil
+}
+
+// CheckKennelIsolation ตรวจก่อนย้ายสุนัขเข้าคอก: ถ้ากักโรคอยู่ คอกต้องเป็นคอกแยกโรคหรืออยู่ในโซนแยกโรค
+func CheckKennelIsolation(tx *gorm.DB, dogID, kennelID uint) error {
+	q, err := activeQuarantine(tx, dogID)
+	if err != nil || q == nil {
+		return err
+	}
+	var kennel Kennel
+	if err := tx.Preload("Zone").First(&kennel, kennelID).Error; err != nil {
+		return err
+	}
+	if !kennel.Isolated() {
+		return &IsolationError{"dog is quarantined (" + q.Condition + "); kennel " + kennel.Name + " is not an isolation kennel"}
+	}
+	return nil
+}
+
+// CheckFosterIsolation ตรวจก่อนส่งสุนัขไปบ้านอุปถัมภ์: บ้านอุปถัมภ์ไม่ใช่คอกแยกโรค สุนัขที่กักโรคอยู่จึงไปไม่ได้
+func CheckFosterIsolation(tx *gorm.DB, dogID uint) error {
+	q, err := activeQuarantine(tx, dogID)
+	if err != nil || q == nil {
+		return err
+	}
+	return &IsolationError{"dog is quarantined (" + q.Condition + "); it cannot leave isolation for a foster home"}
+}
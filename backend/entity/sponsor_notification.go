@@ -0,0 +1,23 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const SponsorNoticeDogDeceased = "dog_deceased"
+
+// แจ้งเตือนถึงผู้อุปถัมภ์ (แสดงในหน้าของผู้ใช้)
+type SponsorNotification struct {
+	gorm.Model
+	SponsorID     uint         `gorm:"index" json:"sponsor_id"`
+	SponsorshipID *uint        `json:"sponsorship_id"`
+	Sponsorship   *Sponsorship `gorm:"foreignKey:SponsorshipID" json:"sponsorship,omitempty"`
+	DogID         uint         `json:"dog_id"`
+	Dog           *Dog         `gorm:"foreignKey:DogID" json:"dog,omitempty"`
+
+	Kind    string     `json:"kind"`
+	Message string     `json:"message"`
+	ReadAt  *time.Time `json:"read_at"`
+}
@@ -22,6 +22,8 @@ type Staff struct {
 	GenderID uint    `json:"gender_id"`
 	Gender   *Gender `gorm:"foreignKey:GenderID" json:"gender"`
 
+	Role string `gorm:"not null;default:staff" json:"role"` // staff | admin (ตั้งได้จาก DB/seed เท่านั้น)
+
 	CreatedBys        []Dog              `gorm:"foreignKey:CreatedByID" json:"created_bys"`
 	UpdatedBys        []Dog              `gorm:"foreignKey:UpdatedByID" json:"updated_bys"`
 	DeletedBys        []Dog              `gorm:"foreignKey:DeletedByID" json:"deleted_bys"`
@@ -31,3 +33,14 @@ type Staff struct {
 	KennelManagements []KennelManagement `gorm:"foreignKey:StaffID" json:"kennel_managements"`
 	MedicalRecords    []MedicalRecord    `gorm:"foreignKey:StaffID" json:"medical_records"`
 }
+
+// บทบาทเจ้าหน้าที่
+const (
+	StaffRoleStaff = "staff"
+	StaffRoleAdmin = "admin"
+)
+
+// IsAdmin เจ้าหน้าที่ผู้ดูแลระบบ (เช่น ย้อนการแก้ไขข้อมูลสุนัข)
+func (s Staff) IsAdmin() bool {
+	return s.Role == StaffRoleAdmin
+}
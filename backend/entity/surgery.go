@@ -0,0 +1,99 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ประเภทการผ่าตัด
+const (
+	ProcedureSpay        = "spay"   // ทำหมันเพศเมีย
+	ProcedureNeuter      = "neuter" // ทำหมันเพศผู้
+	ProcedureDental      = "dental"
+	ProcedureMassRemoval = "mass_removal"
+	ProcedureOrthopedic  = "orthopedic"
+	ProcedureWoundRepair = "wound_repair"
+	ProcedureOther       = "other"
+)
+
+// สถานะการผ่าตัด
+const (
+	SurgeryScheduled = "scheduled"
+	SurgeryCompleted = "completed"
+	SurgeryCancelled = "cancelled"
+)
+
+// สถานะการตรวจหลังผ่าตัด
+const (
+	PostOpPending   = "pending"
+	PostOpDone      = "done"
+	PostOpCancelled = "cancelled" // ผ่าตัดถูกยกเลิก/สุนัขออกจากความดูแล
+)
+
+func IsSterilization(procedure string) bool {
+	return procedure == ProcedureSpay || procedure == ProcedureNeuter
+}
+
+// PostOpSchedule นัดตรวจหลังผ่าตัด (วันหลังผ่าตัด -> หัวข้อ) ตามประเภท
+func PostOpSchedule(procedure string) map[int]string {
+	switch procedure {
+	case ProcedureSpay, ProcedureNeuter:
+		return map[int]string{1: "wound check", 3: "wound check", 10: "suture removal"}
+	case ProcedureDental:
+		return map[int]string{1: "eating/pain check", 7: "oral recheck"}
+	}
+	return map[int]string{1: "wound check", 7: "wound check", 14: "suture removal / final check"}
+}
+
+type SurgeryRecord struct {
+	gorm.Model
+	DogID uint `gorm:"index" json:"dog_id"`
+	Dog   *Dog `gorm:"foreignKey:DogID" json:"dog,omitempty"`
+
+	// ประวัติสุขภาพที่สร้างตอนผ่าตัดเสร็จ
+	MedicalRecordID *uint          `json:"medical_record_id"`
+	MedicalRecord   *MedicalRecord `gorm:"foreignKey:MedicalRecordID" json:"medical_record,omitempty"`
+
+	Procedure   string `gorm:"index" json:"procedure"`
+	Description string `json:"description"`
+	SurgeonID   uint   `json:"surgeon_id"`
+	Surgeon     *Staff `gorm:"foreignKey:SurgeonID" json:"surgeon,omitempty"`
+
+	AnesthesiaProtocol string `json:"anesthesia_protocol"` // ยา/ขนาด premed, induction, maintenance
+	AnesthesiaNotes    string `json:"anesthesia_notes"`
+	Complications      string `json:"complications"`
+	Notes              string `json:"notes"`
+
+	ScheduledAt *time.Time `gorm:"index" json:"scheduled_at"`
+	PerformedAt *time.Time `gorm:"index" json:"performed_at"`
+	Status      string     `gorm:"index" json:"status"`
+
+	CancelledAt  *time.Time `json:"cancelled_at"`
+	CancelReason string     `json:"cancel_reason"`
+
+	CreatedByID *uint  `json:"created_by_id"`
+	CreatedBy   *Staff `gorm:"foreignKey:CreatedByID" json:"created_by,omitempty"`
+
+	PostOpChecks []PostOpCheck `gorm:"foreignKey:SurgeryID;constraint:OnDelete:CASCADE" json:"post_op_checks,omitempty"`
+}
+
+// PostOpCheck งานตรวจหลังผ่าตัด (สร้างอัตโนมัติเมื่อผ่าตัดเสร็จ)
+type PostOpCheck struct {
+	gorm.Model
+	SurgeryID uint           `gorm:"index" json:"surgery_id"`
+	Surgery   *SurgeryRecord `gorm:"foreignKey:SurgeryID" json:"surgery,omitempty"`
+	DogID     uint           `gorm:"index" json:"dog_id"`
+	Dog       *Dog           `gorm:"foreignKey:DogID" json:"dog,omitempty"`
+
+	DayAfter int    `json:"day_after"`
+	DueDate  Date   `gorm:"index" json:"due_date"`
+	Title    string `json:"title"`
+	Status   string `gorm:"index" json:"status"`
+
+	Findings      string     `json:"findings"`
+	Concern       bool       `json:"concern"` // พบปัญหา (แผลอักเสบ/แยก ฯลฯ)
+	CompletedAt   *time.Time `json:"completed_at"`
+	CompletedByID *uint      `json:"completed_by_id"`
+	CompletedBy   *Staff     `gorm:"foreignKey:CompletedByID" json:"completed_by,omitempty"`
+}
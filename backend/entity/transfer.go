@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ทิศทางการย้ายสุนัขระหว่างองค์กร
+const (
+	TransferOut = "out" // ส่งออกไปองค์กรพันธมิตร
+	TransferIn  = "in"  // รับเข้าจากองค์กรพันธมิตร
+)
+
+// องค์กร/มูลนิธิพันธมิตรที่รับ-ส่งสุนัขกัน
+type PartnerOrganization struct {
+	gorm.Model
+	Name        string  `gorm:"uniqueIndex" json:"name"`
+	ContactName string  `json:"contact_name"`
+	Phone       string  `json:"phone"`
+	Email       string  `json:"email"`
+	Address     string  `json:"address"`
+	Note        *string `json:"note"`
+}
+
+type DogTransfer struct {
+	gorm.Model
+	Direction string `gorm:"index" json:"direction"` // out | in
+
+	DogID     uint                 `gorm:"index" json:"dog_id"`
+	Dog       *Dog                 `gorm:"foreignKey:DogID" json:"dog,omitempty"`
+	PartnerID uint                 `gorm:"index" json:"partner_id"`
+	Partner   *PartnerOrganization `gorm:"foreignKey:PartnerID" json:"partner,omitempty"`
+
+	TransferDate time.Time `json:"transfer_date"`
+	Reason       string    `json:"reason"`
+	ExternalRef  string    `json:"external_ref"` // รหัสสุนัขฝั่งองค์กรพันธมิตร (ถ้ามี)
+
+	StaffID *uint  `json:"staff_id"`
+	Staff   *Staff `gorm:"foreignKey:StaffID" json:"staff,omitempty"`
+}
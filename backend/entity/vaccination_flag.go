@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// VaccinationFlag ประวัติสุขภาพนี้มีการฉีดวัคซีนหรือไม่ (เก็บเป็น "YES"/"NO" ตามเดิม)
+type VaccinationFlag string
+
+const (
+	VaccinationYes VaccinationFlag = "YES"
+	VaccinationNo  VaccinationFlag = "NO"
+)
+
+var ErrInvalidVaccination = errors.New(`vaccination must be "YES" or "NO"`)
+
+// ParseVaccinationFlag รับ YES/NO (ไม่สนตัวพิมพ์) หรือ true/false
+func ParseVaccinationFlag(s string) (VaccinationFlag, error) {
+	switch strings.ToUpper(strings.TrimSpace(s)) {
+	case "YES", "TRUE":
+		return VaccinationYes, nil
+	case "NO", "FALSE":
+		return VaccinationNo, nil
+	}
+	return "", ErrInvalidVaccination
+}
+
+func (v VaccinationFlag) Valid() bool {
+	return v == VaccinationYes || v == VaccinationNo
+}
+
+func (v *VaccinationFlag) UnmarshalJSON(b []byte) error {
+	var flag bool
+	if err := json.Unmarshal(b, &flag); err == nil {
+		*v = VaccinationNo
+		if flag {
+			*v = VaccinationYes
+		}
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return ErrInvalidVaccination
+	}
+	f, err := ParseVaccinationFlag(s)
+	if err != nil {
+		return err
+	}
+	*v = f
+	return nil
+}
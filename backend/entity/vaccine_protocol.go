@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ตารางฉีดวัคซีนแยกตามช่วงวัย (ลูกสุนัขต้องฉีดชุดแรกหลายเข็มกว่า)
+const (
+	ProtocolPuppy = "puppy" // อายุ < 12 เดือน ณ วันที่ฉีด
+	ProtocolAdult = "adult"
+)
+
+type VaccineProtocol struct {
+	gorm.Model
+	VaccineID uint     `gorm:"uniqueIndex:idx_vaccine_protocol" json:"vaccine_id"`
+	Vaccine   *Vaccine `gorm:"foreignKey:VaccineID" json:"vaccine,omitempty"`
+	AgeGroup  string   `gorm:"uniqueIndex:idx_vaccine_protocol" json:"age_group"` // puppy | adult
+
+	DoseCount           int `json:"dose_count"`            // จำนวนเข็มชุดแรก
+	DoseIntervalDays    int `json:"dose_interval_days"`    // ระยะห่างระหว่างเข็มในชุดแรก
+	BoosterIntervalDays int `json:"booster_interval_days"` // ครบชุดแล้วกระตุ้นทุก N วัน (0 = ไม่ต้อง)
+}
+
+// NextDue วันครบกำหนดเข็มถัดไปหลังฉีดเข็มที่ dose (false = ไม่มีเข็มถัดไป)
+func (p VaccineProtocol) NextDue(dose int, given time.Time) (time.Time, bool) {
+	switch {
+	case dose < p.DoseCount && p.DoseIntervalDays > 0:
+		return given.AddDate(0, 0, p.DoseIntervalDays), true
+	case p.BoosterIntervalDays > 0:
+		return given.AddDate(0, 0, p.BoosterIntervalDays), true
+	}
+	return time.Time{}, false
+}
+
+// แจ้งเตือนวัคซีนเลยกำหนด (หนึ่งแถวต่อเข็มล่าสุดที่เลยกำหนด; ปิดเองเมื่อฉีดเข็มใหม่)
+type VaccinationAlert struct {
+	gorm.Model
+	VaccineRecordID uint           `gorm:"uniqueIndex" json:"vaccine_record_id"`
+	VaccineRecord   *VaccineRecord `gorm:"foreignKey:VaccineRecordID" json:"vaccine_record,omitempty"`
+	DogID           uint           `gorm:"index" json:"dog_id"`
+	Dog             *Dog           `gorm:"foreignKey:DogID" json:"dog,omitempty"`
+	VaccineID       uint           `json:"vaccine_id"`
+	Vaccine         *Vaccine       `gorm:"foreignKey:VaccineID" json:"vaccine,omitempty"`
+	DueDate         time.Time      `json:"due_date"`
+
+	AcknowledgedAt   *time.Time `json:"acknowledged_at"`
+	AcknowledgedByID *uint      `json:"acknowledged_by_id"`
+	AcknowledgedBy   *Staff     `gorm:"foreignKey:AcknowledgedByID" json:"acknowledged_by,omitempty"`
+	ResolvedAt       *time.Time `gorm:"index" json:"resolved_at"`
+}
@@ -1,7 +1,9 @@
 package entity
 
 import (
+	"errors"
 	"time"
+
 	"gorm.io/gorm"
 )
 
@@ -17,3 +19,112 @@ type VaccineRecord struct {
 	VaccineID uint     `json:"vaccine_id"`
 	Vaccine   *Vaccine `gorm:"foreignKey:VaccineID" json:"vaccine"`
 }
+
+// BeforeSave เติมเข็มที่/วันครบกำหนดเข็มถัดไปจาก VaccineProtocol (ถ้ามี) เฉพาะช่องที่ยังว่าง
+// ค่าที่ส่งมาเอง (เช่น สัตวแพทย์นัดเร็วกว่าตาราง) ใช้ตามนั้น
+func (vr *VaccineRecord) BeforeSave(tx *gorm.DB) error {
+	if vr.DoseNumber > 0 && !vr.NextDueDate.IsZero() {
+		return nil
+	}
+	db := tx.Session(&gorm.Session{NewDB: true})
+
+	var mr MedicalRecord
+	if err := db.Select("id, dog_id, date_record").First(&mr, vr.MedID).Error; err != nil {
+		return err
+	}
+	given := mr.DateRecord
+	if given.IsZero() {
+		given = time.Now()
+	}
+	if vr.DoseNumber <= 0 {
+		var prev int64
+		if err := db.Model(&VaccineRecord{}).
+			Joins("JOIN medical_records mr ON mr.id = vaccine_records.med_id AND mr.deleted_at IS NULL").
+			Where("mr.dog_id = ? AND vaccine_records.vaccine_id = ? AND mr.date_record <= ? AND vaccine_records.id <> ?",
+				mr.DogID, vr.VaccineID, given, vr.ID).
+			Count(&prev).Error; err != nil {
+			return err
+		}
+		vr.DoseNumber = int(prev) + 1
+	}
+
+	if !vr.NextDueDate.IsZero() {
+		return nil
+	}
+
+	var dog Dog
+	if err := db.Select("id, date_of_birth").First(&dog, mr.DogID).Error; err != nil {
+		return err
+	}
+	group := ProtocolAdult
+	if dog.DateOfBirth != nil && AgeInMonths(*dog.DateOfBirth, given) < AdultFromMonths {
+		group = ProtocolPuppy
+	}
+
+	// ไม่มี protocol ของช่วงวัยนี้ ใช้ของอีกช่วงแทน
+	var protocols []VaccineProtocol
+	if err := db.Where("vaccine_id = ?", vr.VaccineID).Find(&protocols).Error; err != nil {
+		return err
+	}
+	if len(protocols) == 0 {
+		return nil
+	}
+	p := protocols[0]
+	for _, candidate := range protocols {
+		if candidate.AgeGroup == group {
+			p = candidate
+		}
+	}
+	next, ok := p.NextDue(vr.DoseNumber, given)
+	if !ok {
+		return nil
+	}
+	vr.NextDueDate = time.Date(next.Year(), next.Month(), next.Day(), 0, 0, 0, 0, time.UTC)
+	return nil
+}
+
+// ConsumeVaccineDose ตัดสต็อกวัคซีน 1 โดสของเข็มนี้ (เฉพาะวัคซีนที่มีในคลังคลินิก)
+// เรียกเองจาก flow ที่ฉีดในคลินิกเท่านั้น — ประวัติที่นำเข้าหรือบันทึกย้อนหลังไม่ตัดสต็อก
+// lot ว่าง = เลือก lot ที่หมดอายุก่อน แล้วบันทึก lot ที่ใช้ลงเข็มนี้
+func ConsumeVaccineDose(tx *gorm.DB, vr *VaccineRecord, staffID *uint) error {
+	var item ClinicItem
+	err := tx.Where("vaccine_id = ?", vr.VaccineID).First(&item).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var mr MedicalRecord
+	if err := tx.Select("id, dog_id, date_record").First(&mr, vr.MedID).Error; err != nil {
+		return err
+	}
+	given := mr.DateRecord
+	if given.IsZero() {
+		given = time.Now()
+	}
+	id, dogID := vr.ID, mr.DogID
+	lot, err := ConsumeStock(tx, StockUse{
+		ItemID:          item.ID,
+		LotNumber:       vr.LotNumber,
+		Quantity:        1,
+		At:              given,
+		Reason:          StockVaccine,
+		DogID:           &dogID,
+		VaccineRecordID: &id,
+		StaffID:         staffID,
+	})
+	if err != nil {
+		return err
+	}
+	if vr.LotNumber != lot.LotNumber {
+		vr.LotNumber = lot.LotNumber
+		return tx.Model(&VaccineRecord{}).Where("id = ?", vr.ID).UpdateColumn("lot_number", lot.LotNumber).Error
+	}
+	return nil
+}
+
+// ReleaseVaccineDose คืนสต็อกที่เข็มนี้ตัดไป (ลบเข็ม/เปลี่ยน lot/ลบประวัติ) ไม่มีการตัด = ไม่ทำอะไร
+func ReleaseVaccineDose(tx *gorm.DB, vaccineRecordID uint) error {
+	return ReleaseStock(tx, "vaccine_record_id = ?", vaccineRecordID)
+}
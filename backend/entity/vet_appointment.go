@@ -0,0 +1,38 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// สถานะนัดหมายสัตวแพทย์
+const (
+	AppointmentScheduled = "scheduled"
+	AppointmentCompleted = "completed" // ตรวจแล้ว มี MedicalRecord
+	AppointmentCancelled = "cancelled"
+	AppointmentNoShow    = "no_show" // ไม่ได้พาสุนัขมาตามนัด
+)
+
+type VetAppointment struct {
+	gorm.Model
+	DogID uint   `gorm:"index" json:"dog_id"`
+	Dog   *Dog   `gorm:"foreignKey:DogID" json:"dog,omitempty"`
+	VetID uint   `gorm:"index" json:"vet_id"`
+	Vet   *Staff `gorm:"foreignKey:VetID" json:"vet,omitempty"`
+
+	Reason  string    `json:"reason"`
+	StartAt time.Time `gorm:"index" json:"start_at"` // เก็บเป็น UTC
+	EndAt   time.Time `gorm:"index" json:"end_at"`
+	Status  string    `gorm:"index" json:"status"`
+	Notes   string    `json:"notes"`
+
+	BookedByID *uint  `json:"booked_by_id"`
+	BookedBy   *Staff `gorm:"foreignKey:BookedByID" json:"booked_by,omitempty"`
+
+	CancelReason string     `json:"cancel_reason"`
+	ClosedAt     *time.Time `json:"closed_at"` // เวลาที่ปิดนัด (ตรวจเสร็จ/ยกเลิก/ไม่มาตามนัด)
+
+	MedicalRecordID *uint          `json:"medical_record_id"`
+	MedicalRecord   *MedicalRecord `gorm:"foreignKey:MedicalRecordID" json:"medical_record,omitempty"`
+}
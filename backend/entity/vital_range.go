@@ -0,0 +1,69 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// VitalRange ค่าปกติของสัญญาณชีพตามขนาดตัว (ตั้งค่าได้)
+type VitalRange struct {
+	gorm.Model
+	AnimalSizeID uint        `gorm:"uniqueIndex" json:"animal_size_id"`
+	AnimalSize   *AnimalSize `gorm:"foreignKey:AnimalSizeID" json:"animal_size,omitempty"`
+
+	TempMin   float64 `json:"temp_min"` // °C
+	TempMax   float64 `json:"temp_max"`
+	WeightMin float64 `json:"weight_min"` // kg
+	WeightMax float64 `json:"weight_max"`
+
+	// น้ำหนักลดลงเกิน WeightLossPercent % เมื่อเทียบกับน้ำหนักสูงสุดใน WeightLossDays วันก่อนหน้า
+	WeightLossPercent float64 `json:"weight_loss_percent"`
+	WeightLossDays    int     `json:"weight_loss_days"`
+}
+
+// DefaultVitalRange ใช้เมื่อสุนัขไม่มีขนาดตัวหรือยังไม่ได้ตั้งค่าขนาดนั้น
+var DefaultVitalRange = VitalRange{
+	TempMin:           37.5,
+	TempMax:           39.2,
+	WeightMin:         1,
+	WeightMax:         90,
+	WeightLossPercent: 10,
+	WeightLossDays:    30,
+}
+
+// ประเภทแจ้งเตือนสัญญาณชีพ
+const (
+	HealthAlertFever       = "temperature_high"
+	HealthAlertHypothermia = "temperature_low"
+	HealthAlertWeightLoss  = "weight_loss"
+	HealthAlertWeightRange = "weight_out_of_range"
+)
+
+// HealthAlert แจ้งเตือนสัญญาณชีพผิดปกติจากประวัติสุขภาพ (หนึ่งแถวต่อประวัติต่อประเภท)
+type HealthAlert struct {
+	gorm.Model
+	DogID           uint           `gorm:"index" json:"dog_id"`
+	Dog             *Dog           `gorm:"foreignKey:DogID" json:"dog,omitempty"`
+	MedicalRecordID uint           `gorm:"uniqueIndex:idx_health_alert" json:"medical_record_id"`
+	MedicalRecord   *MedicalRecord `gorm:"foreignKey:MedicalRecordID" json:"medical_record,omitempty"`
+	Kind            string         `gorm:"uniqueIndex:idx_health_alert" json:"kind"`
+	Value           float64        `json:"value"`
+	Message         string         `json:"message"`
+
+	AcknowledgedAt   *time.Time `json:"acknowledged_at"`
+	AcknowledgedByID *uint      `json:"acknowledged_by_id"`
+	AcknowledgedBy   *Staff     `gorm:"foreignKey:AcknowledgedByID" json:"acknowledged_by,omitempty"`
+	ResolvedAt       *time.Time `gorm:"index" json:"resolved_at"` // ค่าถูกแก้จนกลับมาปกติ
+}
+
+// PuppyGrowthReference น้ำหนักอ้างอิงของลูกสุนัขตามขนาดตัวและอายุ (เดือน) ใช้คำนวณเปอร์เซ็นไทล์การเติบโต
+type PuppyGrowthReference struct {
+	gorm.Model
+	AnimalSizeID uint        `gorm:"uniqueIndex:idx_growth_ref" json:"animal_size_id"`
+	AnimalSize   *AnimalSize `gorm:"foreignKey:AnimalSizeID" json:"animal_size,omitempty"`
+	AgeMonths    int         `gorm:"uniqueIndex:idx_growth_ref" json:"age_months"`
+	P10          float64     `json:"p10"` // kg
+	P50          float64     `json:"p50"`
+	P90          float64     `json:"p90"`
+}
@@ -6,5 +6,11 @@ import (
 
 type Zone struct {
 	gorm.Model
-	Name string `json:"name"`
+	Name        string `json:"name"`
+	IsIsolation bool   `json:"is_isolation"` // โซนแยกโรค รับสุนัขที่อยู่ระหว่างกักโรคได้
+
+	BuildingID *uint     `json:"building_id"` // อาคารที่โซนนี้อยู่ (ว่าง = กลางแจ้ง/ไม่ระบุ)
+	Building   *Building `gorm:"foreignKey:BuildingID" json:"building,omitempty"`
+
+	Kennels []Kennel `gorm:"foreignKey:ZoneID" json:"kennels,omitempty"`
 }
@@ -3,23 +3,37 @@ package main
 import (
 	"log"
 	"net/http"
+	"time"
 
 	"example.com/project-sa/configs"
 	adopter "example.com/project-sa/controllers/adoption"
+	appointment "example.com/project-sa/controllers/appointment"
 	auth "example.com/project-sa/controllers/auth"
+	behavior "example.com/project-sa/controllers/behavior"
 	buildings "example.com/project-sa/controllers/building"
+	carelog "example.com/project-sa/controllers/carelog"
 	dashboard "example.com/project-sa/controllers/dashboard"
 	dog "example.com/project-sa/controllers/dog"
 	donation "example.com/project-sa/controllers/donation"
 	event "example.com/project-sa/controllers/event"
+	foster "example.com/project-sa/controllers/foster"
 	gender "example.com/project-sa/controllers/gender"
 	health_record "example.com/project-sa/controllers/health_record"
+	inventory "example.com/project-sa/controllers/inventory"
+	lab "example.com/project-sa/controllers/lab"
+	lostfound "example.com/project-sa/controllers/lostfound"
 	manage "example.com/project-sa/controllers/manage"
+	medication "example.com/project-sa/controllers/medication"
+	outcome "example.com/project-sa/controllers/outcome"
+	partner "example.com/project-sa/controllers/partner"
 	payment_method "example.com/project-sa/controllers/payment_method"
 	personalities "example.com/project-sa/controllers/personality"
+	quarantine "example.com/project-sa/controllers/quarantine"
 	sponsorship "example.com/project-sa/controllers/sponsorship"
 	staffs "example.com/project-sa/controllers/staff"
+	surgery "example.com/project-sa/controllers/surgery"
 	user "example.com/project-sa/controllers/user"
+	vaccination "example.com/project-sa/controllers/vaccination"
 	vaccine "example.com/project-sa/controllers/vaccine"
 	visit "example.com/project-sa/controllers/visit"
 	volunteers "example.com/project-sa/controllers/volunteerRegister"
@@ -46,6 +60,18 @@ func main() {
 		log.Fatal(err)
 	}
 
+	// งานจับคู่ประกาศ หาย/พบ กับสุนัขที่รับเข้าใหม่
+	lostfound.StartMatchingJob(30 * time.Minute)
+
+	// แจ้งเตือนวัคซีนเลยกำหนด
+	vaccination.StartOverdueAlertJob(1 * time.Hour)
+
+	// มื้อยาที่เลยเวลาโดยไม่มีการบันทึก
+	medication.StartMissedDoseJob(15 * time.Minute)
+
+	// นัดการุณยฆาตที่อนุมัติแล้วและถึงวัน
+	outcome.StartScheduledOutcomeJob(1 * time.Hour)
+
 	//  Setup Gin
 	r := gin.Default()
 	r.Use(CORSMiddleware())
@@ -57,6 +83,7 @@ func main() {
 
 	r.GET("/dogs", dog.GetAllDogs)
 	r.GET("/dogs/:id", dog.GetDogById)
+	r.GET("/share/dogs/:code", dog.OpenDogShareLink)
 	// r.POST("/dogs", dogs.CreateDog)
 	// r.PUT("/dogs/:id", dogs.UpdateDog)
 	// r.DELETE("/dogs/:id", dogs.DeleteDog)
@@ -73,10 +100,6 @@ func main() {
 	r.GET("/units", donation.GetAllUnits)
 
 	r.GET("/health-records/dog/:id", health_record.GetHealthRecordsByDogId)
-	r.POST("/health-records", health_record.CreateHealthRecord)
-	r.PUT("/health-records/:id", health_record.UpdateHealthRecord)
-	r.DELETE("/health-records/:id", health_record.DeleteHealthRecord)
-	r.GET("/health-records/:id", health_record.GetHealthRecordById)
 	r.POST("/visits", visit.CreateVisit)
 
 	r.GET("/animal-sexes", dog.GetAllAnimalSexes)
@@ -101,6 +124,7 @@ func main() {
 	r.DELETE("/staffs/:id", staffs.DeleteStaff)
 
 	r.GET("/buildings", buildings.GetAllBuildings)
+	r.GET("/buildings/:id", buildings.GetBuildingById)
 
 	r.GET("/personalities", personalities.GetAllPersonalities)
 	r.GET("/breeds", dog.GetAllBreeds)
@@ -114,10 +138,11 @@ func main() {
 	r.DELETE("/events/:id", event.DeleteEvent)
 	r.POST("/events/upload-image", event.UploadEventImage)
 
-	// 7) Routes (protected)
-	r.PUT("/kennels/:id", zcmanagement.UpdateDogInKennel)
-	r.DELETE("/kennels/:id", zcmanagement.DeleteDogFromKennel)
-	r.GET("/kennels", zcmanagement.GetDogInKennel)
+	r.GET("/kennels/:id/dogs", zcmanagement.GetDogInKennel)
+	r.GET("/kennels", zcmanagement.GetKennels)
+	r.GET("/kennels/:id", zcmanagement.GetKennel)
+	r.GET("/zones", zcmanagement.GetZones)
+	r.GET("/zones/:id", zcmanagement.GetZone)
 	r.GET("/zcmanagement", zcmanagement.GetAll)
 
 	r.GET("/volunteers", volunteers.GetAllVolunteers)
@@ -130,6 +155,10 @@ func main() {
 	r.GET("/statusfv", volunteers.GetAllStatusFV) //new
 
 	r.POST("/donations/guest", donation.CreateDonation)
+
+	// Lost & found (แจ้งได้โดยไม่ต้องล็อกอิน)
+	r.POST("/lost-found/photos", lostfound.UploadReportPhoto)
+	r.POST("/lost-found/reports", lostfound.CreateReport)
 	r.PUT("/volunteer/:id/status", volunteers.UpdateVolunteerStatus)
 	// 7) Routes (protected)
 
@@ -156,10 +185,205 @@ func main() {
 		protected.GET("/sponsorships/my", sponsorship.GetMySponsorships)
 		protected.POST("/files/dogs", dog.UploadDogImage)
 		protected.POST("/zcmanagement/log", zcmanagement.CreateZCManagementLog)
+		protected.PUT("/kennels/:id/dog", zcmanagement.UpdateDogInKennel)
+		protected.DELETE("/kennels/:id/dog", zcmanagement.DeleteDogFromKennel)
+		protected.POST("/kennels/moves", zcmanagement.BatchMoveDogs)
+		protected.GET("/kennels/occupancy", zcmanagement.GetOccupancyAt)
+		protected.GET("/kennels/:id/history", zcmanagement.GetKennelHistory)
+		protected.POST("/kennels", zcmanagement.CreateKennel)
+		protected.PUT("/kennels/:id", zcmanagement.UpdateKennel)
+		protected.DELETE("/kennels/:id", zcmanagement.DeleteKennel)
+		protected.POST("/zones", zcmanagement.CreateZone)
+		protected.PUT("/zones/:id", zcmanagement.UpdateZone)
+		protected.DELETE("/zones/:id", zcmanagement.DeleteZone)
+		protected.GET("/kennel-rules", zcmanagement.GetKennelRules)
+		protected.POST("/kennel-rules", zcmanagement.CreateKennelRule)
+		protected.PUT("/kennel-rules/:id", zcmanagement.UpdateKennelRule)
+		protected.DELETE("/kennel-rules/:id", zcmanagement.DeleteKennelRule)
+		protected.GET("/dogs/:id/kennel-check", zcmanagement.CheckDogKennel)
+		protected.GET("/dogs/:id/kennel-suggestions", zcmanagement.SuggestKennels)
+		protected.POST("/buildings", buildings.CreateBuilding)
+		protected.PUT("/buildings/:id", buildings.UpdateBuilding)
+		protected.DELETE("/buildings/:id", buildings.DeleteBuilding)
+		protected.GET("/dogs/:id/locations", zcmanagement.GetDogLocations)
 		protected.POST("/dogs", dog.CreateDog)
 		protected.PUT("/dogs/:id", dog.UpdateDog)
 		protected.DELETE("/dogs/:id", dog.DeleteDog)
+		protected.GET("/dogs/date-issues", dog.GetDogDateIssues)
+		protected.GET("/dogs/:id/history", dog.GetDogHistory)
+		protected.POST("/dogs/:id/history/:change_id/revert", dog.RevertDogChange)
+		protected.GET("/dogs/:id/share", dog.GetDogShareLink)
+		protected.GET("/dogs/:id/card.pdf", dog.GetDogCard)
+		protected.GET("/zones/:id/cards.pdf", dog.GetZoneCards)
+
+		// Daily care log
+		protected.POST("/care-logs", carelog.CreateCareLog)
+		protected.DELETE("/care-logs/:id", carelog.DeleteCareLog)
+		protected.POST("/kennels/:id/care-logs", carelog.BulkCreateKennelCareLogs)
+		protected.GET("/dogs/:id/care-logs", carelog.GetDogCareLogs)
+		protected.GET("/zones/:id/care-summary", carelog.GetZoneCareSummary)
+
+		// Behavior assessments
+		protected.POST("/dogs/:id/assessments", behavior.CreateAssessment)
+		protected.GET("/dogs/:id/assessments", behavior.GetDogAssessments)
+		protected.GET("/assessments/:id", behavior.GetAssessment)
+		protected.PUT("/assessments/:id", behavior.UpdateAssessment)
+		protected.POST("/assessments/:id/complete", behavior.CompleteAssessment)
+		protected.POST("/assessments/:id/apply-personalities", behavior.ApplySuggestedPersonalities)
 		protected.DELETE("/sponsorships/:id", sponsorship.DeleteSponsorship)
+
+		// Lost & found matching (staff)
+		protected.GET("/lost-found/reports", lostfound.GetReports)
+		protected.GET("/lost-found/reports/:id", lostfound.GetReport)
+		protected.PUT("/lost-found/reports/:id/status", lostfound.UpdateReportStatus)
+		protected.POST("/lost-found/match", lostfound.RunMatchingNow)
+		protected.GET("/lost-found/matches", lostfound.GetMatches)
+		protected.POST("/lost-found/matches/:id/confirm", lostfound.ConfirmMatch)
+		protected.POST("/lost-found/matches/:id/reject", lostfound.RejectMatch)
+
+		// Foster care
+		protected.POST("/foster-homes", foster.CreateFosterHome)
+		protected.GET("/foster-homes", foster.GetFosterHomes)
+		protected.GET("/foster-homes/me", foster.GetMyFosterHome)
+		protected.GET("/foster-homes/:id", foster.GetFosterHome)
+		protected.PUT("/foster-homes/:id", foster.UpdateFosterHome)
+		protected.POST("/foster-placements", foster.CreatePlacement)
+		protected.GET("/foster-placements", foster.GetPlacements)
+		protected.GET("/foster-placements/:id", foster.GetPlacement)
+		protected.POST("/foster-placements/:id/end", foster.EndPlacement)
+		protected.POST("/foster-placements/:id/check-ins", foster.CreateCheckIn)
+		protected.POST("/foster-placements/:id/supplies", foster.CreateSupplyHandout)
+		protected.GET("/foster-supplies/stock", foster.GetSupplyStock)
+
+		// Transfers to/from partner organizations
+		protected.GET("/partners", partner.GetAllPartners)
+		protected.POST("/partners", partner.CreatePartner)
+		protected.PUT("/partners/:id", partner.UpdatePartner)
+		protected.POST("/dogs/:id/transfer-out", dog.TransferDogOut)
+		protected.GET("/transfers", dog.GetTransfers)
+		protected.GET("/transfers/:id/bundle", dog.GetTransferBundle)
+		protected.POST("/transfers/import", dog.ImportTransfer)
+
+		// Litters / family
+		protected.GET("/litters", dog.GetLitters)
+		protected.POST("/litters", dog.CreateLitter)
+		protected.GET("/litters/:id", dog.GetLitter)
+		protected.PUT("/litters/:id", dog.UpdateLitter)
+		protected.POST("/litters/:id/dogs", dog.AddLitterDogs)
+		protected.DELETE("/litters/:id/dogs/:dog_id", dog.RemoveLitterDog)
+		protected.POST("/litters/:id/health-records", dog.CreateLitterHealthRecords)
+		protected.POST("/litters/:id/vaccinations", dog.CreateLitterVaccinations)
+
+		// End-of-life outcomes
+		protected.POST("/dogs/:id/outcomes", outcome.CreateOutcome)
+		protected.GET("/outcomes", outcome.GetOutcomes)
+		protected.GET("/outcomes/:id", outcome.GetOutcome)
+		protected.POST("/outcomes/:id/approve", outcome.ApproveOutcome)
+		protected.POST("/outcomes/:id/reject", outcome.RejectOutcome)
+		protected.GET("/sponsorships/notifications/my", sponsorship.GetMyNotifications)
+		protected.POST("/sponsorships/notifications/:id/read", sponsorship.MarkNotificationRead)
+
+		// Vaccination schedule
+		protected.GET("/vaccine-protocols", vaccination.GetProtocols)
+		protected.PUT("/vaccines/:id/protocols/:age_group", vaccination.UpsertProtocol)
+		protected.DELETE("/vaccines/:id/protocols/:age_group", vaccination.DeleteProtocol)
+		protected.GET("/vaccinations/due", vaccination.GetDueVaccinations)
+		protected.GET("/vaccinations/alerts", vaccination.GetAlerts)
+		protected.POST("/vaccinations/alerts/:id/ack", vaccination.AcknowledgeAlert)
+
+		// Medications
+		protected.POST("/dogs/:id/prescriptions", medication.CreatePrescription)
+		protected.GET("/dogs/:id/prescriptions", medication.GetDogPrescriptions)
+		protected.GET("/prescriptions/:id", medication.GetPrescription)
+		protected.POST("/prescriptions/:id/stop", medication.StopPrescription)
+		protected.POST("/medication-administrations/:id", medication.RecordAdministration)
+		protected.POST("/medication-administrations/:id/ack-missed", medication.AcknowledgeMissedDose)
+		protected.GET("/medications/round", medication.GetDailyRound)
+		protected.GET("/medications/missed", medication.GetMissedDoses)
+
+		// Vet appointments
+		protected.POST("/appointments", appointment.CreateAppointment)
+		protected.GET("/appointments", appointment.GetAppointments)
+		protected.GET("/appointments/calendar", appointment.GetCalendar)
+		protected.GET("/appointments/:id", appointment.GetAppointment)
+		protected.PUT("/appointments/:id", appointment.UpdateAppointment)
+		protected.POST("/appointments/:id/cancel", appointment.CancelAppointment)
+		protected.POST("/appointments/:id/no-show", appointment.MarkNoShow)
+		protected.POST("/appointments/:id/complete", appointment.CompleteAppointment)
+
+		// Health record detail / draft edits (เฉพาะเจ้าหน้าที่)
+		protected.POST("/health-records", health_record.CreateHealthRecord)
+		protected.GET("/health-records/:id", health_record.GetHealthRecordById)
+		protected.PUT("/health-records/:id", health_record.UpdateHealthRecord)
+		protected.DELETE("/health-records/:id", health_record.DeleteHealthRecord)
+
+		// Health record attachments
+		protected.POST("/health-records/:id/attachments", health_record.UploadAttachment)
+		protected.GET("/health-records/:id/attachments", health_record.GetAttachments)
+		protected.GET("/health-record-attachments/:id/file", health_record.DownloadAttachment)
+		protected.DELETE("/health-record-attachments/:id", health_record.DeleteAttachment)
+
+		// Health record sign-off / amendments
+		protected.POST("/health-records/:id/finalize", health_record.FinalizeHealthRecord)
+		protected.POST("/health-records/:id/amendments", health_record.AmendHealthRecord)
+		protected.GET("/health-records/:id/amendments", health_record.GetAmendments)
+		protected.GET("/health-records/:id/versions", health_record.GetHealthRecordVersions)
+
+		// Vital ranges / health alerts
+		protected.GET("/vital-ranges", health_record.GetVitalRanges)
+		protected.PUT("/vital-ranges/:size_id", health_record.UpsertVitalRange)
+		protected.GET("/health-alerts", health_record.GetHealthAlerts)
+		protected.POST("/health-alerts/:id/ack", health_record.AcknowledgeHealthAlert)
+		protected.GET("/dogs/:id/health-alerts", health_record.GetDogHealthAlerts)
+		protected.GET("/dogs/:id/vitals", health_record.GetDogVitals)
+		protected.GET("/zones/:id/weight-loss", health_record.GetZoneWeightLoss)
+
+		// Clinic inventory
+		protected.GET("/inventory/items", inventory.GetItems)
+		protected.POST("/inventory/items", inventory.CreateItem)
+		protected.GET("/inventory/items/:id", inventory.GetItem)
+		protected.PUT("/inventory/items/:id", inventory.UpdateItem)
+		protected.POST("/inventory/items/:id/lots", inventory.ReceiveLot)
+		protected.POST("/inventory/lots/:id/adjust", inventory.AdjustLot)
+		protected.GET("/inventory/lots/:id/movements", inventory.GetLotMovements)
+		protected.GET("/inventory/lots/:id/recipients", inventory.GetLotRecipients)
+		protected.POST("/inventory/lots/:id/recall", inventory.RecallLot)
+		protected.GET("/inventory/recall", inventory.SearchRecall)
+		protected.GET("/inventory/warnings", inventory.GetWarnings)
+
+		// Quarantine / isolation
+		protected.POST("/dogs/:id/quarantines", quarantine.CreateQuarantine)
+		protected.GET("/dogs/:id/quarantines", quarantine.GetDogQuarantines)
+		protected.GET("/quarantines", quarantine.GetQuarantines)
+		protected.GET("/quarantines/:id", quarantine.GetQuarantine)
+		protected.POST("/quarantines/:id/clear", quarantine.ClearQuarantine)
+		protected.GET("/quarantines/:id/contacts", quarantine.GetContacts)
+		protected.PUT("/zones/:id/isolation", quarantine.SetZoneIsolation)
+
+		// Surgery / sterilization
+		protected.POST("/dogs/:id/surgeries", surgery.CreateSurgery)
+		protected.GET("/dogs/:id/surgeries", surgery.GetDogSurgeries)
+		protected.GET("/surgeries", surgery.GetSurgeries)
+		protected.GET("/surgeries/:id", surgery.GetSurgery)
+		protected.PUT("/surgeries/:id", surgery.UpdateSurgery)
+		protected.POST("/surgeries/:id/complete", surgery.CompleteSurgery)
+		protected.POST("/surgeries/:id/cancel", surgery.CancelSurgery)
+		protected.GET("/post-op-checks", surgery.GetPostOpChecks)
+		protected.POST("/post-op-checks/:id/complete", surgery.CompletePostOpCheck)
+		protected.GET("/reports/sterilization", surgery.GetSterilizationReport)
+
+		// Lab orders / results
+		protected.GET("/lab-panels", lab.GetPanels)
+		protected.POST("/dogs/:id/lab-orders", lab.CreateOrder)
+		protected.GET("/dogs/:id/lab-orders", lab.GetDogOrders)
+		protected.GET("/lab-orders", lab.GetOrders)
+		protected.GET("/lab-orders/:id", lab.GetOrder)
+		protected.POST("/lab-orders/:id/sample", lab.CollectSample)
+		protected.POST("/lab-orders/:id/results", lab.EnterResults)
+		protected.GET("/lab-orders/:id/results/history", lab.GetResultHistory)
+		protected.POST("/lab-orders/:id/cancel", lab.CancelOrder)
+		protected.GET("/staff-notifications/my", lab.GetMyNotifications)
+		protected.POST("/staff-notifications/:id/read", lab.MarkNotificationRead)
 	}
 	don := r.Group("/donations", middlewares.OptionalAuthorize())
 	{
@@ -0,0 +1,26 @@
+package middlewares
+
+import "github.com/gin-gonic/gin"
+
+// StaffID staff ที่ login อยู่ (Authorizes ใส่ staff_id ไว้) ไม่ใช่ staff = nil
+func StaffID(c *gin.Context) *uint {
+	v, ok := c.Get("staff_id")
+	if !ok || v == nil {
+		return nil
+	}
+	switch n := v.(type) {
+	case uint:
+		if n == 0 {
+			return nil
+		}
+		return &n
+	case int:
+		if n <= 0 {
+			return nil
+		}
+		u := uint(n)
+		return &u
+	default:
+		return nil
+	}
+}
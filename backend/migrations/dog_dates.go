@@ -0,0 +1,144 @@
+package migrations
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"example.com/project-sa/entity"
+	"gorm.io/gorm"
+)
+
+/* ========== dogs.date_of_birth / sterilized_at: ข้อความ -> date ========== */
+
+const legacySuffix = "_legacy"
+
+var legacyDogDateColumns = []string{"date_of_birth", "sterilized_at"}
+
+// renameLegacyDogDates ย้ายคอลัมน์ข้อความเดิมไปเป็น *_legacy ก่อน AutoMigrate
+// สร้างคอลัมน์ date ใหม่ (คืนชื่อคอลัมน์ที่ต้องแปลงต่อ)
+func renameLegacyDogDates(db *gorm.DB) ([]string, error) {
+	m := db.Migrator()
+	if !m.HasTable(&entity.Dog{}) {
+		return nil, nil
+	}
+	types, err := m.ColumnTypes(&entity.Dog{})
+	if err != nil {
+		return nil, err
+	}
+	var renamed []string
+	for _, ct := range types {
+		name := ct.Name()
+		if !isLegacyDateColumn(name) || strings.EqualFold(ct.DatabaseTypeName(), "date") {
+			continue
+		}
+		if err := m.RenameColumn(&entity.Dog{}, name, name+legacySuffix); err != nil {
+			return nil, fmt.Errorf("rename dogs.%s: %w", name, err)
+		}
+		renamed = append(renamed, name)
+	}
+	return renamed, nil
+}
+
+func isLegacyDateColumn(name string) bool {
+	for _, c := range legacyDogDateColumns {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
+// convertLegacyDogDates แปลงค่าเดิมลงคอลัมน์ใหม่ ค่าที่แปลงไม่ได้บันทึกลง dog_date_issues
+// แล้วลบคอลัมน์ *_legacy ทิ้ง
+func convertLegacyDogDates(db *gorm.DB, columns []string) error {
+	for _, col := range columns {
+		var rows []struct {
+			ID  uint
+			Raw string
+		}
+		if err := db.Table("dogs").
+			Select("id, " + col + legacySuffix + " AS raw").
+			Where(col + legacySuffix + " IS NOT NULL AND TRIM(" + col + legacySuffix + ") <> ''").
+			Scan(&rows).Error; err != nil {
+			return err
+		}
+
+		converted, failed := 0, 0
+		err := db.Transaction(func(tx *gorm.DB) error {
+			for _, r := range rows {
+				d, precision, err := parseLegacyDate(r.Raw)
+				if err != nil {
+					failed++
+					if err := tx.Create(&entity.DogDateIssue{
+						DogID:    r.ID,
+						Field:    col,
+						RawValue: r.Raw,
+						Reason:   err.Error(),
+					}).Error; err != nil {
+						return err
+					}
+					continue
+				}
+				updates := map[string]any{col: d}
+				if col == "date_of_birth" {
+					updates["dob_precision"] = precision
+					updates["dob_estimated"] = precision != entity.DOBPrecisionDay
+				}
+				if err := tx.Table("dogs").Where("id = ?", r.ID).Updates(updates).Error; err != nil {
+					return err
+				}
+				converted++
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		// ALTER ตรง ๆ (Migrator ของ sqlite สร้างตารางใหม่ ซึ่งติด foreign key ของตารางอื่น)
+		if err := db.Exec("ALTER TABLE dogs DROP COLUMN " + col + legacySuffix).Error; err != nil {
+			return fmt.Errorf("drop dogs.%s%s: %w", col, legacySuffix, err)
+		}
+		log.Printf("migrate dogs.%s: converted %d, failed %d (see dog_date_issues)", col, converted, failed)
+	}
+	return nil
+}
+
+// รูปแบบที่พบในข้อมูลเดิม (เรียงจากละเอียดมากไปน้อย)
+var legacyDateLayouts = []struct{ layout, precision string }{
+	{time.RFC3339, entity.DOBPrecisionDay},
+	{"2006-01-02 15:04:05", entity.DOBPrecisionDay},
+	{"2006-01-02", entity.DOBPrecisionDay},
+	{"2006-1-2", entity.DOBPrecisionDay},
+	{"2006/01/02", entity.DOBPrecisionDay},
+	{"02/01/2006", entity.DOBPrecisionDay},
+	{"2/1/2006", entity.DOBPrecisionDay},
+	{"2006-01", entity.DOBPrecisionMonth},
+	{"01/2006", entity.DOBPrecisionMonth},
+	{"2006", entity.DOBPrecisionYear},
+}
+
+func parseLegacyDate(raw string) (*entity.Date, string, error) {
+	s := strings.TrimSpace(raw)
+	for _, f := range legacyDateLayouts {
+		t, err := time.Parse(f.layout, s)
+		if err != nil {
+			continue
+		}
+		// ปี พ.ศ.
+		if t.Year() > 2400 {
+			t = t.AddDate(-543, 0, 0)
+		}
+		d := entity.NewDate(t)
+		if d.After(time.Now()) {
+			return nil, "", errors.New("date is in the future")
+		}
+		if d.Year() < 1980 {
+			return nil, "", errors.New("date is implausibly old")
+		}
+		return &d, f.precision, nil
+	}
+	return nil, "", errors.New("unrecognized date format")
+}
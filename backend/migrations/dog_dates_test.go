@@ -0,0 +1,96 @@
+package migrations
+
+import (
+	"testing"
+
+	"example.com/project-sa/entity"
+)
+
+func TestConvertLegacyDogDates(t *testing.T) {
+	db := migratedBaselineDB(t)
+
+	m := db.Migrator()
+	for _, col := range []string{"date_of_birth_legacy", "sterilized_at_legacy"} {
+		if m.HasColumn(&entity.Dog{}, col) {
+			t.Errorf("dogs.%s was not dropped", col)
+		}
+	}
+
+	tests := []struct {
+		dogID      uint
+		dob        string // ว่าง = NULL
+		precision  string
+		estimated  bool
+		sterilized string
+	}{
+		{1, "2020-02-14", entity.DOBPrecisionDay, false, ""},
+		{3, "2019-05-01", entity.DOBPrecisionMonth, true, ""}, // "2562-05" (พ.ศ.)
+		{4, "", entity.DOBPrecisionDay, false, "2021-03-04"},  // "garbage" -> dog_date_issues
+		{5, "2020-02-14", entity.DOBPrecisionDay, false, ""},  // "14/02/2563"
+	}
+	for _, tt := range tests {
+		var dog entity.Dog
+		if err := db.First(&dog, tt.dogID).Error; err != nil {
+			t.Fatalf("dog %d: %v", tt.dogID, err)
+		}
+		if got := dateString(dog.DateOfBirth); got != tt.dob {
+			t.Errorf("dog %d date_of_birth = %q, want %q", tt.dogID, got, tt.dob)
+		}
+		if dog.DOBPrecision != tt.precision || dog.DOBEstimated != tt.estimated {
+			t.Errorf("dog %d precision/estimated = %s/%v, want %s/%v",
+				tt.dogID, dog.DOBPrecision, dog.DOBEstimated, tt.precision, tt.estimated)
+		}
+		if got := dateString(dog.SterilizedAt); got != tt.sterilized {
+			t.Errorf("dog %d sterilized_at = %q, want %q", tt.dogID, got, tt.sterilized)
+		}
+	}
+
+	var issues []entity.DogDateIssue
+	db.Find(&issues)
+	if len(issues) != 1 || issues[0].DogID != 4 || issues[0].RawValue != "garbage" {
+		t.Errorf("dog_date_issues = %+v, want one row for dog 4 %q", issues, "garbage")
+	}
+}
+
+func dateString(d *entity.Date) string {
+	if d == nil {
+		return ""
+	}
+	return d.String()
+}
+
+func TestParseLegacyDate(t *testing.T) {
+	tests := []struct {
+		raw       string
+		want      string
+		precision string
+		wantErr   bool
+	}{
+		{"2020-02-14", "2020-02-14", entity.DOBPrecisionDay, false},
+		{" 2020-2-4 ", "2020-02-04", entity.DOBPrecisionDay, false},
+		{"2020-02-14T10:00:00Z", "2020-02-14", entity.DOBPrecisionDay, false},
+		{"14/02/2563", "2020-02-14", entity.DOBPrecisionDay, false},
+		{"2562-05", "2019-05-01", entity.DOBPrecisionMonth, false},
+		{"2018", "2018-01-01", entity.DOBPrecisionYear, false},
+		{"1970-01-01", "", "", true},
+		{"2999-01-01", "", "", true},
+		{"garbage", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			d, precision, err := parseLegacyDate(tt.raw)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseLegacyDate(%q) = %v, want error", tt.raw, d)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseLegacyDate(%q): %v", tt.raw, err)
+			}
+			if d.String() != tt.want || precision != tt.precision {
+				t.Errorf("parseLegacyDate(%q) = %s/%s, want %s/%s", tt.raw, d, precision, tt.want, tt.precision)
+			}
+		})
+	}
+}
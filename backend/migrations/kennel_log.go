@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+/* ========== kennel_managements: log เดิม -> สมุดย้ายคอก ========== */
+
+const legacyKennelLogTable = "kennel_managements_legacy"
+
+// renameLegacyKennelLog log เดิมไม่มี id/เวลา/คอกต้นทาง แปลงเป็นรายการย้ายไม่ได้
+// ย้ายไปเก็บเป็น kennel_managements_legacy ไว้อ้างอิง แล้วให้ AutoMigrate สร้างสมุดใหม่
+// (สุนัขที่ยังไม่มีรายการย้ายถือว่าอยู่คอกปัจจุบันมาตั้งแต่ต้น)
+func renameLegacyKennelLog(db *gorm.DB) error {
+	m := db.Migrator()
+	if !m.HasTable("kennel_managements") {
+		return nil
+	}
+	// HasColumn ของ sqlite เทียบจากข้อความ DDL (id ชนกับ kennel_id) จึงไล่คอลัมน์เอง
+	types, err := m.ColumnTypes("kennel_managements")
+	if err != nil {
+		return err
+	}
+	for _, ct := range types {
+		if ct.Name() == "id" {
+			return nil
+		}
+	}
+	return m.RenameTable("kennel_managements", legacyKennelLogTable)
+}
@@ -0,0 +1,34 @@
+package migrations
+
+import (
+	"testing"
+
+	"example.com/project-sa/entity"
+)
+
+func TestRenameLegacyKennelLog(t *testing.T) {
+	db := migratedBaselineDB(t)
+
+	m := db.Migrator()
+	if !m.HasTable(legacyKennelLogTable) {
+		t.Fatalf("%s was not kept", legacyKennelLogTable)
+	}
+	var legacy []struct {
+		KennelID uint
+		DogID    uint
+		Action   string
+	}
+	db.Table(legacyKennelLogTable).Order("dog_id").Find(&legacy)
+	if len(legacy) != 2 || legacy[0].DogID != 1 || legacy[0].KennelID != 2 || legacy[1].DogID != 2 || legacy[1].KennelID != 3 {
+		t.Errorf("%s rows = %+v, want dog 1 -> kennel 2 and dog 2 -> kennel 3", legacyKennelLogTable, legacy)
+	}
+
+	// สมุดใหม่สร้างจาก entity และใช้งานได้
+	move := entity.KennelManagement{DogID: 1, ToKennelID: &legacy[0].KennelID, Action: "move"}
+	if err := db.Create(&move).Error; err != nil {
+		t.Fatalf("insert into new ledger: %v", err)
+	}
+	if move.ID == 0 {
+		t.Errorf("new kennel_managements has no id")
+	}
+}
@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"example.com/project-sa/entity"
+	"gorm.io/gorm"
+)
+
+/* ========== medical_records.status: ประวัติเดิมถือว่าลงนามแล้ว ========== */
+
+// medicalRecordsBeforeDrafts ตาราง medical_records ที่มีอยู่ก่อนมีร่าง/ลงนาม (ยังไม่มีคอลัมน์ status)
+// AutoMigrate เติมคอลัมน์ด้วย default draft ทุกแถว ซึ่งจะทำให้แก้/ลบประวัติเก่าได้
+func medicalRecordsBeforeDrafts(db *gorm.DB) (bool, error) {
+	m := db.Migrator()
+	if !m.HasTable("medical_records") {
+		return false, nil
+	}
+	// HasColumn ของ sqlite เทียบจากข้อความ DDL จึงไล่คอลัมน์เอง (เหมือน kennel_managements)
+	types, err := m.ColumnTypes("medical_records")
+	if err != nil {
+		return false, err
+	}
+	for _, ct := range types {
+		if ct.Name() == "status" {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// finalizeLegacyMedicalRecords ประวัติที่บันทึกก่อนมีร่าง = ลงนามแล้ว ณ เวลาที่บันทึก (ไม่รู้ผู้ลงนาม)
+// แก้ไขต่อได้ทาง amendment เท่านั้น
+func finalizeLegacyMedicalRecords(db *gorm.DB) error {
+	return db.Exec("UPDATE medical_records SET status = ?, finalized_at = COALESCE(created_at, date_record)",
+		entity.MedicalRecordFinalized).Error
+}
@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"testing"
+
+	"example.com/project-sa/entity"
+)
+
+func TestFinalizeLegacyMedicalRecords(t *testing.T) {
+	db := migratedBaselineDB(t)
+
+	var record entity.MedicalRecord
+	if err := db.First(&record, 1).Error; err != nil {
+		t.Fatalf("baseline record: %v", err)
+	}
+	if record.Status != entity.MedicalRecordFinalized || record.FinalizedAt == nil {
+		t.Errorf("baseline record status = %q finalized_at = %v, want finalized with a time", record.Status, record.FinalizedAt)
+	}
+
+	// ประวัติที่สร้างหลัง migrate ยังเป็นร่างตามปกติ และ migrate ซ้ำไม่ลงนามให้
+	fresh := entity.MedicalRecord{DogID: record.DogID, StaffID: record.StaffID, Symptoms: "new"}
+	if err := db.Create(&fresh).Error; err != nil {
+		t.Fatalf("create record: %v", err)
+	}
+	if err := AutoMigrate(db); err != nil {
+		t.Fatalf("AutoMigrate: %v", err)
+	}
+	db.First(&fresh, fresh.ID)
+	if fresh.Status != entity.MedicalRecordDraft {
+		t.Errorf("new record status = %q after migrate, want %q", fresh.Status, entity.MedicalRecordDraft)
+	}
+}
@@ -1,12 +1,39 @@
 package migrations
 
 import (
+	"fmt"
+
 	"example.com/project-sa/entity"
 	"gorm.io/gorm"
 )
 
+// AutoMigrate ปรับ schema ของ DB เดิมให้ตรงกับ entity
+// Migrator ของ sqlite แก้คอลัมน์/constraint ด้วยการสร้างตารางใหม่แล้ว DROP ตารางเดิม
+// ซึ่งไม่ผ่านถ้าเปิด foreign key อยู่ จึงปิด FK บน connection เดียว (PRAGMA นี้ตั้งใน tx ไม่ได้)
+// ทำทุกขั้นใน tx เดียว แล้วตรวจ FK ทั้ง DB ก่อน commit — พังกลางทางก็ rollback กลับ schema เดิม
 func AutoMigrate(db *gorm.DB) error {
-	return db.AutoMigrate(
+	return db.Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("PRAGMA foreign_keys = OFF").Error; err != nil {
+			return err
+		}
+		defer conn.Exec("PRAGMA foreign_keys = ON")
+		return conn.Transaction(migrate)
+	})
+}
+
+func migrate(db *gorm.DB) error {
+	legacy, err := renameLegacyDogDates(db)
+	if err != nil {
+		return err
+	}
+	if err := renameLegacyKennelLog(db); err != nil {
+		return err
+	}
+	legacyRecords, err := medicalRecordsBeforeDrafts(db)
+	if err != nil {
+		return err
+	}
+	if err := db.AutoMigrate(
 		&entity.Item{},
 		&entity.Unit{},
 		&entity.Breed{},
@@ -14,20 +41,39 @@ func AutoMigrate(db *gorm.DB) error {
 		&entity.AnimalSex{},
 		&entity.AnimalSize{},
 		&entity.Personality{},
+		&entity.Building{},
 		&entity.Zone{},
 		&entity.Vaccine{},
 		&entity.Kennel{},
 		&entity.Adopter{},
 		&entity.Adoption{},
 		&entity.Attendee{},
-		&entity.Building{},
+		&entity.Litter{},
 		&entity.Dog{},
+		&entity.DogChange{},
+		&entity.DogDateIssue{},
+		&entity.CareLog{},
+		&entity.BehaviorAssessment{},
+		&entity.BehaviorAssessmentSection{},
+		&entity.LostFoundReport{},
+		&entity.LostFoundPhoto{},
+		&entity.LostFoundMatch{},
+		&entity.FosterHome{},
+		&entity.FosterPlacement{},
+		&entity.FosterCheckIn{},
+		&entity.FosterSupply{},
+		&entity.PartnerOrganization{},
+		&entity.DogTransfer{},
+		&entity.DogOutcome{},
+		&entity.DogOutcomeApproval{},
+		&entity.SponsorNotification{},
 		&entity.DogPersonality{},
 		&entity.Donation{},
 		&entity.Donor{},
 		&entity.Event{},
 		&entity.ItemDonation{},
 		&entity.KennelManagement{},
+		&entity.KennelRule{},
 		&entity.MedicalRecord{},
 		&entity.MoneyDonation{},
 		&entity.PaymentMethod{},
@@ -38,6 +84,28 @@ func AutoMigrate(db *gorm.DB) error {
 		&entity.Staff{},
 		&entity.User{},
 		&entity.VaccineRecord{},
+		&entity.MedicalRecordAttachment{},
+		&entity.MedicalRecordAmendment{},
+		&entity.MedicalRecordAmendmentChange{},
+		&entity.VitalRange{},
+		&entity.HealthAlert{},
+		&entity.PuppyGrowthReference{},
+		&entity.VaccineProtocol{},
+		&entity.VaccinationAlert{},
+		&entity.ClinicItem{},
+		&entity.ClinicLot{},
+		&entity.StockMovement{},
+		&entity.Prescription{},
+		&entity.MedicationAdministration{},
+		&entity.VetAppointment{},
+		&entity.QuarantineRecord{},
+		&entity.SurgeryRecord{},
+		&entity.PostOpCheck{},
+		&entity.LabPanel{},
+		&entity.LabAnalyte{},
+		&entity.LabOrder{},
+		&entity.LabResult{},
+		&entity.StaffNotification{},
 		&entity.Volunteer{},
 		&entity.Skill{},
 		&entity.StatusFV{},
@@ -45,5 +113,33 @@ func AutoMigrate(db *gorm.DB) error {
 		&entity.Visit{},
 		&entity.VisitDetail{},
 		
-	)
+	); err != nil {
+		return err
+	}
+	if err := convertLegacyDogDates(db, legacy); err != nil {
+		return err
+	}
+	if legacyRecords {
+		if err := finalizeLegacyMedicalRecords(db); err != nil {
+			return err
+		}
+	}
+	return checkForeignKeys(db)
+}
+
+// checkForeignKeys ข้อมูลทุกแถวยังอ้างอิงถูกต้องหลังสร้างตารางใหม่ (แทนการตรวจของ FK ที่ปิดไว้)
+func checkForeignKeys(db *gorm.DB) error {
+	var bad []struct {
+		Table  string
+		RowID  int64 `gorm:"column:rowid"`
+		Parent string
+	}
+	if err := db.Raw("PRAGMA foreign_key_check").Scan(&bad).Error; err != nil {
+		return err
+	}
+	if len(bad) > 0 {
+		return fmt.Errorf("foreign key check failed: %s row %d references missing %s (%d violations)",
+			bad[0].Table, bad[0].RowID, bad[0].Parent, len(bad))
+	}
+	return nil
 }	
\ No newline at end of file
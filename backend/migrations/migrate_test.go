@@ -0,0 +1,68 @@
+package migrations
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"example.com/project-sa/entity"
+	"example.com/project-sa/utils/testutil"
+	"gorm.io/gorm"
+)
+
+// openBaselineDB DB ชั่วคราวที่โหลด testdata/baseline.sql (schema/ข้อมูลก่อนเริ่ม backlog)
+func openBaselineDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db := testutil.Open(t)
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("db: %v", err)
+	}
+
+	dump, err := os.ReadFile(filepath.Join("testdata", "baseline.sql"))
+	if err != nil {
+		t.Fatalf("read baseline: %v", err)
+	}
+	// dump เรียงตารางตามชื่อ ไม่ใช่ตามลำดับอ้างอิง
+	sqlDB.Exec("PRAGMA foreign_keys = OFF")
+	if _, err := sqlDB.Exec(string(dump)); err != nil {
+		t.Fatalf("load baseline: %v", err)
+	}
+	sqlDB.Exec("PRAGMA foreign_keys = ON")
+	return db
+}
+
+// migratedBaselineDB baseline ที่ AutoMigrate แล้วสองรอบ (รันซ้ำบน DB ที่ migrate แล้วต้องไม่พัง)
+func migratedBaselineDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db := openBaselineDB(t)
+	if err := AutoMigrate(db); err != nil {
+		t.Fatalf("AutoMigrate: %v", err)
+	}
+	if err := AutoMigrate(db); err != nil {
+		t.Fatalf("AutoMigrate again: %v", err)
+	}
+	return db
+}
+
+func TestAutoMigrateBaselineDB(t *testing.T) {
+	db := migratedBaselineDB(t)
+
+	var fk int
+	db.Raw("PRAGMA foreign_keys").Scan(&fk)
+	if fk != 1 {
+		t.Errorf("foreign_keys = %d after migrate, want 1", fk)
+	}
+	var bad []map[string]any
+	db.Raw("PRAGMA foreign_key_check").Scan(&bad)
+	if len(bad) > 0 {
+		t.Errorf("foreign_key_check: %v", bad)
+	}
+
+	m := db.Migrator()
+	for _, c := range []string{"fk_dogs_foster_home", "fk_dogs_mother", "fk_litters_dogs"} {
+		if !m.HasConstraint(&entity.Dog{}, c) {
+			t.Errorf("dogs is missing constraint %s", c)
+		}
+	}
+}
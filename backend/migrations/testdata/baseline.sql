@@ -0,0 +1,211 @@
+-- DB ที่สร้างจากเวอร์ชันก่อนมีการ migrate (baseline + ข้อมูลวันเกิดแบบข้อความที่พบจริง)
+BEGIN TRANSACTION;
+CREATE TABLE `adopters` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`first_name` text NOT NULL,`last_name` text NOT NULL,`phone_number` text NOT NULL,`address` text NOT NULL,`district` text NOT NULL,`city` text NOT NULL,`province` text NOT NULL,`zip_code` text NOT NULL,`job` text NOT NULL,`income` real,`status` text DEFAULT "pending",`user_id` integer,`dog_id` integer NOT NULL,CONSTRAINT `fk_users_adopters` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`),CONSTRAINT `fk_adopters_dog` FOREIGN KEY (`dog_id`) REFERENCES `dogs`(`id`));
+CREATE TABLE `adoptions` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`adoption_date` datetime,`status` text,`note` text,`adopter_id` integer,`dog_id` integer,CONSTRAINT `fk_adoptions_adopter` FOREIGN KEY (`adopter_id`) REFERENCES `adopters`(`id`),CONSTRAINT `fk_dogs_adoptions` FOREIGN KEY (`dog_id`) REFERENCES `dogs`(`id`));
+CREATE TABLE `animal_sexes` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`name` text);
+INSERT INTO "animal_sexes" VALUES(1,'2026-10-19 12:16:31.422447547+00:00','2026-10-19 12:16:31.422447547+00:00',NULL,'ตัวผู้');
+INSERT INTO "animal_sexes" VALUES(2,'2026-10-19 12:16:31.422567082+00:00','2026-10-19 12:16:31.422567082+00:00',NULL,'ตัวเมีย');
+INSERT INTO "animal_sexes" VALUES(3,'2026-10-19 12:16:31.422649456+00:00','2026-10-19 12:16:31.422649456+00:00',NULL,'ไม่ทราบ');
+CREATE TABLE `animal_sizes` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`name` text);
+INSERT INTO "animal_sizes" VALUES(1,'2026-10-19 12:16:31.42272684+00:00','2026-10-19 12:16:31.42272684+00:00',NULL,'เล็ก');
+INSERT INTO "animal_sizes" VALUES(2,'2026-10-19 12:16:31.422813301+00:00','2026-10-19 12:16:31.422813301+00:00',NULL,'กลาง');
+INSERT INTO "animal_sizes" VALUES(3,'2026-10-19 12:16:31.422888863+00:00','2026-10-19 12:16:31.422888863+00:00',NULL,'ใหญ่');
+CREATE TABLE `attendees` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`event_id` integer,`first_name` text,`last_name` text,`email` text,`phone` text,`note` text,CONSTRAINT `fk_attendees_event` FOREIGN KEY (`event_id`) REFERENCES `events`(`id`));
+CREATE TABLE `breeds` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`name` text,`description` text);
+INSERT INTO "breeds" VALUES(1,'2026-10-19 12:16:31.423620765+00:00','2026-10-19 12:16:31.423620765+00:00',NULL,'Golden Retriever','a medium-to-large, muscular dog breed from Scotland known for its dense, lustrous golden coat, gentle and affectionate nature, and high intelligence');
+INSERT INTO "breeds" VALUES(2,'2026-10-19 12:16:31.423697043+00:00','2026-10-19 12:16:31.423697043+00:00',NULL,'Poodle','highly intelligent, active, and trainable water retriever dogs originating from Germany');
+INSERT INTO "breeds" VALUES(3,'2026-10-19 12:16:31.423751225+00:00','2026-10-19 12:16:31.423751225+00:00',NULL,'Bulldog','stocky, medium-sized dogs known for their distinct appearance: a large, wrinkled, short-muzzled face, a protruding undershot jaw, and a short, fine-textured coat in various colors');
+CREATE TABLE `buildings` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`building_name` text,`size` text);
+INSERT INTO "buildings" VALUES(1,'2026-10-19 12:16:31.432702409+00:00','2026-10-19 12:16:31.432702409+00:00',NULL,'ตึก A','small');
+INSERT INTO "buildings" VALUES(2,'2026-10-19 12:16:31.4327865+00:00','2026-10-19 12:16:31.4327865+00:00',NULL,'ตึก B','medium');
+INSERT INTO "buildings" VALUES(3,'2026-10-19 12:16:31.432861103+00:00','2026-10-19 12:16:31.432861103+00:00',NULL,'ตึก C','large');
+INSERT INTO "buildings" VALUES(4,'2026-10-19 12:16:31.432921822+00:00','2026-10-19 12:16:31.432921822+00:00',NULL,'ตึก D','medium');
+INSERT INTO "buildings" VALUES(5,'2026-10-19 12:16:31.432978038+00:00','2026-10-19 12:16:31.432978038+00:00',NULL,'ตึก E','small');
+CREATE TABLE `dog_personalities` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`dog_id` integer,`personality_id` integer,CONSTRAINT `fk_personalities_dog_personalities` FOREIGN KEY (`personality_id`) REFERENCES `personalities`(`id`),CONSTRAINT `fk_dogs_dog_personalities` FOREIGN KEY (`dog_id`) REFERENCES `dogs`(`id`));
+INSERT INTO "dog_personalities" VALUES(1,'2026-10-19 12:16:31.429561181+00:00','2026-10-19 12:16:31.429561181+00:00',NULL,1,1);
+INSERT INTO "dog_personalities" VALUES(2,'2026-10-19 12:16:31.42965364+00:00','2026-10-19 12:16:31.42965364+00:00',NULL,1,2);
+INSERT INTO "dog_personalities" VALUES(3,'2026-10-19 12:16:31.429690854+00:00','2026-10-19 12:16:31.429690854+00:00',NULL,1,3);
+INSERT INTO "dog_personalities" VALUES(4,'2026-10-19 12:16:31.429723579+00:00','2026-10-19 12:16:31.429723579+00:00',NULL,1,4);
+INSERT INTO "dog_personalities" VALUES(5,'2026-10-19 12:16:31.429755674+00:00','2026-10-19 12:16:31.429755674+00:00',NULL,1,5);
+INSERT INTO "dog_personalities" VALUES(6,'2026-10-19 12:16:31.429786707+00:00','2026-10-19 12:16:31.429786707+00:00',NULL,1,6);
+INSERT INTO "dog_personalities" VALUES(7,'2026-10-19 12:16:31.429827442+00:00','2026-10-19 12:16:31.429827442+00:00',NULL,2,1);
+INSERT INTO "dog_personalities" VALUES(8,'2026-10-19 12:16:31.429860618+00:00','2026-10-19 12:16:31.429860618+00:00',NULL,2,2);
+INSERT INTO "dog_personalities" VALUES(9,'2026-10-19 12:16:31.429892402+00:00','2026-10-19 12:16:31.429892402+00:00',NULL,2,3);
+INSERT INTO "dog_personalities" VALUES(10,'2026-10-19 12:16:31.429930884+00:00','2026-10-19 12:16:31.429930884+00:00',NULL,2,4);
+INSERT INTO "dog_personalities" VALUES(11,'2026-10-19 12:16:31.429960305+00:00','2026-10-19 12:16:31.429960305+00:00',NULL,2,5);
+INSERT INTO "dog_personalities" VALUES(12,'2026-10-19 12:16:31.429994896+00:00','2026-10-19 12:16:31.429994896+00:00',NULL,2,6);
+INSERT INTO "dog_personalities" VALUES(13,'2026-10-19 12:16:31.430023557+00:00','2026-10-19 12:16:31.430023557+00:00',NULL,3,1);
+INSERT INTO "dog_personalities" VALUES(14,'2026-10-19 12:16:31.430055185+00:00','2026-10-19 12:16:31.430055185+00:00',NULL,3,2);
+CREATE TABLE `dogs` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`name` text,`date_of_birth` text,`sterilized_at` text,`photo_url` text,`ready_to_adopt` numeric,`is_adopted` numeric,`breed_id` integer,`kennel_id` integer,`animal_sex_id` integer,`animal_size_id` integer,`created_by_id` integer,`updated_by_id` integer,`deleted_by_id` integer,CONSTRAINT `fk_kennels_dogs` FOREIGN KEY (`kennel_id`) REFERENCES `kennels`(`id`),CONSTRAINT `fk_breeds_dogs` FOREIGN KEY (`breed_id`) REFERENCES `breeds`(`id`),CONSTRAINT `fk_staffs_created_bys` FOREIGN KEY (`created_by_id`) REFERENCES `staffs`(`id`),CONSTRAINT `fk_staffs_updated_bys` FOREIGN KEY (`updated_by_id`) REFERENCES `staffs`(`id`),CONSTRAINT `fk_staffs_deleted_bys` FOREIGN KEY (`deleted_by_id`) REFERENCES `staffs`(`id`),CONSTRAINT `fk_animal_sexes_dogs` FOREIGN KEY (`animal_sex_id`) REFERENCES `animal_sexes`(`id`),CONSTRAINT `fk_animal_sizes_dogs` FOREIGN KEY (`animal_size_id`) REFERENCES `animal_sizes`(`id`));
+INSERT INTO "dogs" VALUES(1,'2026-10-19 12:16:31.42888732+00:00','2026-10-19 12:16:31.42888732+00:00',NULL,'Tor','2020-02-14','','http://localhost:8000/static/images/dog/dog1.jpg',1,0,1,2,1,1,1,NULL,NULL);
+INSERT INTO "dogs" VALUES(2,'2026-10-19 12:16:31.429098314+00:00','2026-10-19 12:16:31.429098314+00:00',NULL,'Taa','2020-02-14','','http://localhost:8000/static/images/dog/dog2.jpg',1,0,2,3,2,2,1,NULL,NULL);
+INSERT INTO "dogs" VALUES(3,'2026-10-19 12:16:31.429230889+00:00','2026-10-19 12:16:31.429230889+00:00',NULL,'Jia','2562-05','','http://localhost:8000/static/images/dog/dog3.jpg',1,0,3,2,1,3,1,NULL,NULL);
+INSERT INTO "dogs" VALUES(4,'2026-10-19 12:16:31.429346703+00:00','2026-10-19 12:16:31.429346703+00:00',NULL,'Sam','garbage','2021-03-04','http://localhost:8000/static/images/dog/dog5.jpg',1,0,3,2,1,3,1,NULL,NULL);
+INSERT INTO "dogs" VALUES(5,'2026-10-19 12:16:31.429486092+00:00','2026-10-19 12:16:31.429486092+00:00',NULL,'Nam','14/02/2563','','http://localhost:8000/static/images/dog/dog6.jpg',1,0,3,2,1,3,1,NULL,NULL);
+CREATE TABLE `donations` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`donation_date` datetime,`donation_type` text,`status` text,`description` text,`donor_id` integer,CONSTRAINT `fk_donors_donations` FOREIGN KEY (`donor_id`) REFERENCES `donors`(`id`));
+INSERT INTO "donations" VALUES(1,'2026-10-19 12:16:31.431097026+00:00','2026-10-19 12:16:31.431097026+00:00',NULL,'2023-07-01 00:00:00+00:00','Money','','',1);
+CREATE TABLE `donors` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`first_name` text,`last_name` text,`phone` text,`email` text,`donor_type` text,`user_id` integer,CONSTRAINT `fk_users_donors` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`));
+INSERT INTO "donors" VALUES(1,'2026-10-19 12:16:31.430859377+00:00','2026-10-19 12:16:31.430859377+00:00',NULL,'John','Doe','0812345678','john.doe@example.com','guest',NULL);
+INSERT INTO "donors" VALUES(2,'2026-10-19 12:16:31.430957847+00:00','2026-10-19 12:16:31.430957847+00:00',NULL,'Jane','Smith','0898765432','jane.smith@example.com','guest',NULL);
+CREATE TABLE `events` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`name` text NOT NULL,`description` text,`start_at` datetime,`end_at` datetime,`location` text,`organizer` text,`contact_info` text,`capacity` integer,`image_url` text,`visit_id` integer,`medical_record_id` integer,CONSTRAINT `fk_events_visit` FOREIGN KEY (`visit_id`) REFERENCES `visits`(`id`),CONSTRAINT `fk_events_medical_record` FOREIGN KEY (`medical_record_id`) REFERENCES `medical_records`(`id`));
+INSERT INTO "events" VALUES(1,'2026-10-19 12:16:31.433142493+00:00','2026-10-19 12:16:31.433142493+00:00',NULL,'กิจกรรมฉีดวัคซีนประจำปี','เพื่อป้องกันโรคติดต่อของน้องหมา','2025-08-11 08:00:00+00:00','2025-08-11 18:00:00+00:00','คลินิกสัตวแพทย์','Member01','vaccination@animalshelter.com',100,'/static/images/events/dog_vaccin.jpg',NULL,NULL);
+INSERT INTO "events" VALUES(2,'2026-10-19 12:16:31.433286358+00:00','2026-10-19 12:16:31.433286358+00:00',NULL,'กิจกรรมตรวจสุขภาพประจำปี','เพื่อตรวจหาโรคในระยะเริ่มต้น','2025-09-12 08:00:00+00:00','2025-09-12 16:00:00+00:00','ศูนย์สุขภาพสัตว์','Member02','health@animalshelter.com',80,'/static/images/events/ตรวจสุขภาพประจำปี.png',NULL,NULL);
+INSERT INTO "events" VALUES(3,'2026-10-19 12:16:31.433434364+00:00','2026-10-19 12:16:31.433434364+00:00',NULL,'กิจกรรมวิ่งระดมทุน','เพื่อระดมทุนสำหรับซื้ออาหารและปัจจัยในการดำรงชีวิตของให้เหล่าสุนัข','2024-07-02 08:00:00+00:00','2024-07-02 14:00:00+00:00','สวนสาธารณะลุมพินี','Member02','fundraising@animalshelter.com',200,'/static/images/events/วิ่งระดมทุน.jpg',NULL,NULL);
+INSERT INTO "events" VALUES(4,'2026-10-19 12:16:31.433565153+00:00','2026-10-19 12:16:31.433565153+00:00',NULL,'กิจกรรมพบปะเหล่าน้องหมา','เพื่อหาผู้สนใจรับเลี้ยง','2024-07-02 08:00:00+00:00','2024-07-02 11:00:00+00:00','สวนสาธารณะเบญจกิติ','Member02','adoption@animalshelter.com',150,'/static/images/events/พบปะเหล่าน้องหมา.jpg',NULL,NULL);
+INSERT INTO "events" VALUES(5,'2026-10-19 12:16:31.433697107+00:00','2026-10-19 12:16:31.433697107+00:00',NULL,'กิจกรรมอบรมการดูแลสุนัข','อบรมความรู้พื้นฐานในการดูแลสุนัขสำหรับเจ้าของใหม่','2025-10-15 09:00:00+00:00','2025-10-15 17:00:00+00:00','ห้องประชุมใหญ่ ชั้น 2','ทีมสัตวแพทย์','training@animalshelter.com',50,'/static/images/events/กิจกรรมอบรมการดูแลสุนัข.jpg',NULL,NULL);
+INSERT INTO "events" VALUES(6,'2026-10-19 12:16:31.433800922+00:00','2026-10-19 12:16:31.433800922+00:00',NULL,'งานแสดงสุนัขและการประกวด','งานแสดงสุนัขประจำปี พร้อมการประกวดในหมวดต่างๆ','2025-11-20 10:00:00+00:00','2025-11-20 18:00:00+00:00','ศูนย์การแสดงสินค้าและการประชุม','สมาคมคนรักสุนัข','dogshow@animalshelter.com',300,'/static/images/events/งานแสดงสุนัขและการประกวด.jpg',NULL,NULL);
+INSERT INTO "events" VALUES(7,'2026-10-19 12:16:31.433900664+00:00','2026-10-19 12:16:31.433900664+00:00',NULL,'กิจกรรมจิตอาสาดูแลสุนัขจรจัด','กิจกรรมจิตอาสาช่วยเหลือและดูแลสุนัขจรจัดในชุมชน','2025-12-05 07:00:00+00:00','2025-12-05 15:00:00+00:00','ชุมชนคลองเตย','กลุ่มจิตอาสา','volunteer@animalshelter.com',30,'/static/images/events/กิจกรรมจิตอาสาดูแลสุนัขจรจัด.jpg',NULL,NULL);
+INSERT INTO "events" VALUES(8,'2026-10-19 12:16:31.434012669+00:00','2026-10-19 12:16:31.434012669+00:00',NULL,'การบรรยายเรื่องโภชนาการสุนัข','บรรยายพิเศษเรื่องโภชนาการที่เหมาะสมสำหรับสุนัขในแต่ละช่วงวัย','2026-01-10 14:00:00+00:00','2026-01-10 17:00:00+00:00','ห้องประชุมเล็ก ชั้น 3','นักโภชนาการสัตว์','nutrition@animalshelter.com',40,'/static/images/events/การบรรยายเรื่องโภชนาการสุนัข.jpg',NULL,NULL);
+CREATE TABLE `genders` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`code` text,`name` text);
+INSERT INTO "genders" VALUES(1,'2026-10-19 12:16:31.421766138+00:00','2026-10-19 12:16:31.421766138+00:00',NULL,'M','ชาย');
+INSERT INTO "genders" VALUES(2,'2026-10-19 12:16:31.422299619+00:00','2026-10-19 12:16:31.422299619+00:00',NULL,'F','หญิง');
+CREATE TABLE `item_donations` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`quantity` integer,`item_ref` text,`donation_id` integer,`item_id` integer,`unit_id` integer,CONSTRAINT `fk_items_item_donations` FOREIGN KEY (`item_id`) REFERENCES `items`(`id`),CONSTRAINT `fk_donations_item_donations` FOREIGN KEY (`donation_id`) REFERENCES `donations`(`id`),CONSTRAINT `fk_units_item_donations` FOREIGN KEY (`unit_id`) REFERENCES `units`(`id`));
+CREATE TABLE `items` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`name` text NOT NULL,CONSTRAINT `uni_items_name` UNIQUE (`name`));
+INSERT INTO "items" VALUES(1,'2026-10-19 12:16:31.424645332+00:00','2026-10-19 12:16:31.424645332+00:00',NULL,'ข้าว');
+INSERT INTO "items" VALUES(2,'2026-10-19 12:16:31.424764345+00:00','2026-10-19 12:16:31.424764345+00:00',NULL,'อาหารเม็ดสุนัข');
+INSERT INTO "items" VALUES(3,'2026-10-19 12:16:31.424856874+00:00','2026-10-19 12:16:31.424856874+00:00',NULL,'อาหารกระป๋อง');
+INSERT INTO "items" VALUES(4,'2026-10-19 12:16:31.424961647+00:00','2026-10-19 12:16:31.424961647+00:00',NULL,'เสื้อผ้าสุนัข');
+INSERT INTO "items" VALUES(5,'2026-10-19 12:16:31.42504739+00:00','2026-10-19 12:16:31.42504739+00:00',NULL,'ผ้าห่ม');
+INSERT INTO "items" VALUES(6,'2026-10-19 12:16:31.425139783+00:00','2026-10-19 12:16:31.425139783+00:00',NULL,'ของเล่น');
+INSERT INTO "items" VALUES(7,'2026-10-19 12:16:31.425231797+00:00','2026-10-19 12:16:31.425231797+00:00',NULL,'อุปกรณ์การเรียน');
+INSERT INTO "items" VALUES(8,'2026-10-19 12:16:31.425321632+00:00','2026-10-19 12:16:31.425321632+00:00',NULL,'น้ำยาล้างมือ');
+INSERT INTO "items" VALUES(9,'2026-10-19 12:16:31.425430126+00:00','2026-10-19 12:16:31.425430126+00:00',NULL,'น้ำยาถูพื้น');
+INSERT INTO "items" VALUES(10,'2026-10-19 12:16:31.425526475+00:00','2026-10-19 12:16:31.425526475+00:00',NULL,'ถุงขยะ');
+CREATE TABLE `kennel_managements` (`kennel_id` integer,`dog_id` integer,`staff_id` integer,`action` text NOT NULL,CONSTRAINT `fk_kennel_managements_dog` FOREIGN KEY (`dog_id`) REFERENCES `dogs`(`id`),CONSTRAINT `fk_staffs_kennel_managements` FOREIGN KEY (`staff_id`) REFERENCES `staffs`(`id`),CONSTRAINT `fk_kennels_kennel_managements` FOREIGN KEY (`kennel_id`) REFERENCES `kennels`(`id`));
+INSERT INTO "kennel_managements" VALUES(2,1,1,'assign');
+INSERT INTO "kennel_managements" VALUES(3,2,1,'assign');
+CREATE TABLE `kennels` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`name` text,`capacity` integer,`color` text,`note` text,`zone_id` integer,CONSTRAINT `fk_kennels_zone` FOREIGN KEY (`zone_id`) REFERENCES `zones`(`id`));
+INSERT INTO "kennels" VALUES(1,'2026-10-19 12:16:31.424198627+00:00','2026-10-19 12:16:31.424198627+00:00',NULL,'00',0,'',NULL,1);
+INSERT INTO "kennels" VALUES(2,'2026-10-19 12:16:31.424417626+00:00','2026-10-19 12:16:31.428095754+00:00',NULL,'A-1',10,'Blue','ใกล้ทางเข้า',1);
+INSERT INTO "kennels" VALUES(3,'2026-10-19 12:16:31.424532069+00:00','2026-10-19 12:16:31.428330317+00:00',NULL,'B-1',8,'Green','พื้นที่เล่น',2);
+INSERT INTO "kennels" VALUES(4,'2026-10-19 12:16:31.428198021+00:00','2026-10-19 12:16:31.428198021+00:00',NULL,'A-2',8,'Cyan','เงียบสงบ',1);
+CREATE TABLE `manages` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`date_task` datetime,`type_task` text NOT NULL,`detail_task` text,`staff_id` integer,`building_id` integer,CONSTRAINT `fk_manages_staff` FOREIGN KEY (`staff_id`) REFERENCES `staffs`(`id`),CONSTRAINT `fk_manages_building` FOREIGN KEY (`building_id`) REFERENCES `buildings`(`id`));
+CREATE TABLE `medical_records` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`date_record` datetime,`weight` real,`temperature` real,`symptoms` text,`diagnosis` text,`treatment_plan` text,`medication` text,`vaccination` text,`notes` text,`dog_id` integer,`staff_id` integer,CONSTRAINT `fk_staffs_medical_records` FOREIGN KEY (`staff_id`) REFERENCES `staffs`(`id`),CONSTRAINT `fk_dogs_medical_records` FOREIGN KEY (`dog_id`) REFERENCES `dogs`(`id`));
+INSERT INTO "medical_records" VALUES(1,'2026-10-19 12:16:31.430448903+00:00','2026-10-19 12:16:31.430448903+00:00',NULL,'2023-02-01 00:00:00+00:00',25.5,38.0,'None','Healthy','Routine checkup','None','YES','',5,1);
+CREATE TABLE `money_donations` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`amount` real,`payment_type` text,`next_payment_date` text,`billing_date` text,`transaction_ref` text,`status` text,`donation_id` integer,`payment_method_id` integer,CONSTRAINT `fk_donations_money_donations` FOREIGN KEY (`donation_id`) REFERENCES `donations`(`id`),CONSTRAINT `fk_payment_methods_money_donations` FOREIGN KEY (`payment_method_id`) REFERENCES `payment_methods`(`id`));
+INSERT INTO "money_donations" VALUES(1,'2026-10-19 12:16:31.431395583+00:00','2026-10-19 12:16:31.431395583+00:00',NULL,1000.0,'','','','','',1,2);
+CREATE TABLE `payment_methods` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`code` text,`name` text);
+INSERT INTO "payment_methods" VALUES(1,'2026-10-19 12:16:31.423465001+00:00','2026-10-19 12:16:31.423465001+00:00',NULL,'credit','บัตรเครดิต');
+INSERT INTO "payment_methods" VALUES(2,'2026-10-19 12:16:31.423556821+00:00','2026-10-19 12:16:31.423556821+00:00',NULL,'qr','พร้อมเพย์');
+CREATE TABLE `personalities` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`name` text);
+INSERT INTO "personalities" VALUES(1,'2026-10-19 12:16:31.422979088+00:00','2026-10-19 12:16:31.422979088+00:00',NULL,'ชอบผจญภัย');
+INSERT INTO "personalities" VALUES(2,'2026-10-19 12:16:31.423084094+00:00','2026-10-19 12:16:31.423084094+00:00',NULL,'ชอบเรียนรู้สิ่งใหม่ ๆ');
+INSERT INTO "personalities" VALUES(3,'2026-10-19 12:16:31.423201949+00:00','2026-10-19 12:16:31.423201949+00:00',NULL,'มั่นใจในตนเอง');
+INSERT INTO "personalities" VALUES(4,'2026-10-19 12:16:31.423262085+00:00','2026-10-19 12:16:31.423262085+00:00',NULL,'สงบ');
+INSERT INTO "personalities" VALUES(5,'2026-10-19 12:16:31.423321862+00:00','2026-10-19 12:16:31.423321862+00:00',NULL,'เข้ากับคนอื่นง่าย');
+INSERT INTO "personalities" VALUES(6,'2026-10-19 12:16:31.423404029+00:00','2026-10-19 12:16:31.423404029+00:00',NULL,'เป็นมิตร');
+CREATE TABLE `skills` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`description` text);
+INSERT INTO "skills" VALUES(1,'2026-10-19 12:16:31.431577234+00:00','2026-10-19 12:16:31.431577234+00:00',NULL,'การดูแลสุนัข');
+INSERT INTO "skills" VALUES(2,'2026-10-19 12:16:31.431664269+00:00','2026-10-19 12:16:31.431664269+00:00',NULL,'การฝึกสุนัข');
+INSERT INTO "skills" VALUES(3,'2026-10-19 12:16:31.431726897+00:00','2026-10-19 12:16:31.431726897+00:00',NULL,'การจัดการเหตุฉุกเฉิน');
+INSERT INTO "skills" VALUES(4,'2026-10-19 12:16:31.431775229+00:00','2026-10-19 12:16:31.431775229+00:00',NULL,'การสื่อสารและการประชาสัมพันธ์');
+INSERT INTO "skills" VALUES(5,'2026-10-19 12:16:31.431841658+00:00','2026-10-19 12:16:31.431841658+00:00',NULL,'อื่นๆ');
+CREATE TABLE `sponsors` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`kind` text NOT NULL,`user_id` integer,`title` text,`first_name` text,`last_name` text,`email` text,`phone` text,`gender_id` integer,`note` text,CONSTRAINT `fk_users_sponsor` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`) ON DELETE CASCADE,CONSTRAINT `fk_genders_sponsors` FOREIGN KEY (`gender_id`) REFERENCES `genders`(`id`));
+CREATE TABLE `sponsorship_payments` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`sponsorship_id` integer,`subscription_id` integer,`payment_method_id` integer,`amount` integer,`status` text,`transaction_ref` text,CONSTRAINT `fk_payment_methods_sponsorship_payments` FOREIGN KEY (`payment_method_id`) REFERENCES `payment_methods`(`id`),CONSTRAINT `fk_subscriptions_sponsorship_payments` FOREIGN KEY (`subscription_id`) REFERENCES `subscriptions`(`id`),CONSTRAINT `fk_sponsorships_sponsorship_payments` FOREIGN KEY (`sponsorship_id`) REFERENCES `sponsorships`(`id`));
+CREATE TABLE `sponsorships` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`sponsor_id` integer,`dog_id` integer,`plan_type` text,`amount` integer,`status` text,`note` text,`enabled` numeric,`channel` text,`frequency` text,`deleted_by_staff_id` integer,CONSTRAINT `fk_sponsors_sponsorships` FOREIGN KEY (`sponsor_id`) REFERENCES `sponsors`(`id`),CONSTRAINT `fk_staffs_deleted_by_staffs` FOREIGN KEY (`deleted_by_staff_id`) REFERENCES `staffs`(`id`),CONSTRAINT `fk_dogs_sponsorships` FOREIGN KEY (`dog_id`) REFERENCES `dogs`(`id`));
+CREATE TABLE `staffs` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`username` text,`password` text,`first_name` text,`last_name` text,`email` text,`phone` text,`date_of_birth` text,`photo_url` text,`note` text,`status` text,`zone_id` integer,`gender_id` integer,CONSTRAINT `fk_staffs_zone` FOREIGN KEY (`zone_id`) REFERENCES `zones`(`id`),CONSTRAINT `fk_genders_staffs` FOREIGN KEY (`gender_id`) REFERENCES `genders`(`id`));
+INSERT INTO "staffs" VALUES(1,'2026-10-19 12:16:31.427521467+00:00','2026-10-19 12:16:31.427521467+00:00',NULL,'wichai','$2a$14$LV.wkUAfbEXf7RyJuvZaUuCU3fYg/Rv1AyXujv/SSZiS7uEcjDdCq','Wichai','Srisuruk','wichi@example.com','0880088000','1992-06-25','http://localhost:8000/static/images/staff_profile/staff1.png','Ops manager','active',2,2);
+INSERT INTO "staffs" VALUES(2,'2026-10-19 12:16:31.427763813+00:00','2026-10-19 12:16:31.427763813+00:00',NULL,'kittisak','$2a$14$LV.wkUAfbEXf7RyJuvZaUuCU3fYg/Rv1AyXujv/SSZiS7uEcjDdCq','Kittisak','Kerdprasop','kittisak@example.com','0999999999','1992-06-25','http://localhost:8000/static/images/staff_profile/staff2.png','Ops manager','active',2,2);
+CREATE TABLE `status_fvs` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`status` text);
+INSERT INTO "status_fvs" VALUES(1,'2026-10-19 12:16:31.431903977+00:00','2026-10-19 12:16:31.431903977+00:00',NULL,'pending');
+INSERT INTO "status_fvs" VALUES(2,'2026-10-19 12:16:31.431990894+00:00','2026-10-19 12:16:31.431990894+00:00',NULL,'approved');
+INSERT INTO "status_fvs" VALUES(3,'2026-10-19 12:16:31.432076453+00:00','2026-10-19 12:16:31.432076453+00:00',NULL,'rejected');
+INSERT INTO "status_fvs" VALUES(4,'2026-10-19 12:16:31.432227547+00:00','2026-10-19 12:16:31.432227547+00:00',NULL,'none');
+CREATE TABLE `subscriptions` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`sponsorship_id` integer,`amount` integer,`interval` text,`start_date` datetime,`cancel_at` datetime,`ended_at` datetime,`cancel_at_period_end` numeric,`status` text,`next_payment_at` datetime,CONSTRAINT `fk_sponsorships_subscription` FOREIGN KEY (`sponsorship_id`) REFERENCES `sponsorships`(`id`) ON DELETE CASCADE);
+CREATE TABLE `units` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`name` text NOT NULL,CONSTRAINT `uni_units_name` UNIQUE (`name`));
+INSERT INTO "units" VALUES(1,'2026-10-19 12:16:31.425632314+00:00','2026-10-19 12:16:31.425632314+00:00',NULL,'กิโลกรัม');
+INSERT INTO "units" VALUES(2,'2026-10-19 12:16:31.425818365+00:00','2026-10-19 12:16:31.425818365+00:00',NULL,'ชิ้น');
+INSERT INTO "units" VALUES(3,'2026-10-19 12:16:31.425928942+00:00','2026-10-19 12:16:31.425928942+00:00',NULL,'กล่อง');
+INSERT INTO "units" VALUES(4,'2026-10-19 12:16:31.426019347+00:00','2026-10-19 12:16:31.426019347+00:00',NULL,'ถุง');
+INSERT INTO "units" VALUES(5,'2026-10-19 12:16:31.426119254+00:00','2026-10-19 12:16:31.426119254+00:00',NULL,'ขวด');
+INSERT INTO "units" VALUES(6,'2026-10-19 12:16:31.426243364+00:00','2026-10-19 12:16:31.426243364+00:00',NULL,'แผ่น');
+INSERT INTO "units" VALUES(7,'2026-10-19 12:16:31.426563775+00:00','2026-10-19 12:16:31.426563775+00:00',NULL,'ห่อ');
+INSERT INTO "units" VALUES(8,'2026-10-19 12:16:31.426688467+00:00','2026-10-19 12:16:31.426688467+00:00',NULL,'แพ็ค');
+INSERT INTO "units" VALUES(9,'2026-10-19 12:16:31.426781782+00:00','2026-10-19 12:16:31.426781782+00:00',NULL,'ลัง');
+INSERT INTO "units" VALUES(10,'2026-10-19 12:16:31.427031664+00:00','2026-10-19 12:16:31.427031664+00:00',NULL,'ผืน');
+CREATE TABLE `users` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`title` text,`first_name` text,`last_name` text,`date_of_birth` text,`email` text,`phone` text,`username` text,`password` text,`photo_url` text,`gender_id` integer,CONSTRAINT `fk_genders_users` FOREIGN KEY (`gender_id`) REFERENCES `genders`(`id`));
+INSERT INTO "users" VALUES(1,'2026-10-19 12:16:31.432391358+00:00','2026-10-19 12:16:31.432391358+00:00',NULL,NULL,'ศิริเดช','สุภาพ','1990-01-01','nam@example.com','0800000000','Nam','$2a$14$LV.wkUAfbEXf7RyJuvZaUuCU3fYg/Rv1AyXujv/SSZiS7uEcjDdCq','http://localhost:8000/static/images/user_profile/profile1.jpg',1);
+INSERT INTO "users" VALUES(2,'2026-10-19 12:16:31.43256027+00:00','2026-10-19 12:16:31.43256027+00:00',NULL,NULL,'รับเช็ค','อึ่งชัยภูมิ','1995-09-20','ta@example.com','0898765432','Ta','$2a$14$LV.wkUAfbEXf7RyJuvZaUuCU3fYg/Rv1AyXujv/SSZiS7uEcjDdCq','http://localhost:8000/static/images/user_profile/profile2.jpg',1);
+CREATE TABLE `vaccine_records` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`dose_number` integer,`lot_number` text,`next_due_date` datetime,`med_id` integer,`vaccine_id` integer,CONSTRAINT `fk_vaccines_vaccine_records` FOREIGN KEY (`vaccine_id`) REFERENCES `vaccines`(`id`),CONSTRAINT `fk_medical_records_vaccine_records` FOREIGN KEY (`med_id`) REFERENCES `medical_records`(`id`));
+INSERT INTO "vaccine_records" VALUES(1,'2026-10-19 12:16:31.430702818+00:00','2026-10-19 12:16:31.430702818+00:00',NULL,1,'ABC12345','2024-02-01 00:00:00+00:00',1,1);
+CREATE TABLE `vaccines` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`name` text,`manufacturer` text);
+INSERT INTO "vaccines" VALUES(1,'2026-10-19 12:16:31.43013307+00:00','2026-10-19 12:16:31.43013307+00:00',NULL,'Rabies','VetCorp');
+INSERT INTO "vaccines" VALUES(2,'2026-10-19 12:16:31.43021773+00:00','2026-10-19 12:16:31.43021773+00:00',NULL,'Distemper','PetHealth');
+CREATE TABLE `visit_details` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`visit_id` integer NOT NULL,`dog_id` integer NOT NULL,CONSTRAINT `fk_visits_visit_details` FOREIGN KEY (`visit_id`) REFERENCES `visits`(`id`) ON DELETE CASCADE ON UPDATE CASCADE,CONSTRAINT `fk_visit_details_dog` FOREIGN KEY (`dog_id`) REFERENCES `dogs`(`id`) ON DELETE RESTRICT ON UPDATE CASCADE);
+CREATE TABLE `visits` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`visit_name` text NOT NULL,`start_at` datetime NOT NULL,`end_at` datetime NOT NULL);
+CREATE TABLE `volunteers` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`user_id` integer,`role` text,`address` text,`another_contact` text,`health_detail` text,`working_date` datetime,`working_time` text,`skill` text,`note` text,`status_fv_id` integer,CONSTRAINT `fk_volunteers_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`),CONSTRAINT `fk_volunteers_status_fv` FOREIGN KEY (`status_fv_id`) REFERENCES `status_fvs`(`id`));
+CREATE TABLE `zones` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`name` text);
+INSERT INTO "zones" VALUES(1,'2026-10-19 12:16:31.423952422+00:00','2026-10-19 12:16:31.423952422+00:00',NULL,'A');
+INSERT INTO "zones" VALUES(2,'2026-10-19 12:16:31.424068958+00:00','2026-10-19 12:16:31.424068958+00:00',NULL,'B');
+CREATE INDEX `idx_items_deleted_at` ON `items`(`deleted_at`);
+CREATE INDEX `idx_units_deleted_at` ON `units`(`deleted_at`);
+CREATE INDEX `idx_breeds_deleted_at` ON `breeds`(`deleted_at`);
+CREATE INDEX `idx_genders_deleted_at` ON `genders`(`deleted_at`);
+CREATE INDEX `idx_animal_sexes_deleted_at` ON `animal_sexes`(`deleted_at`);
+CREATE INDEX `idx_animal_sizes_deleted_at` ON `animal_sizes`(`deleted_at`);
+CREATE INDEX `idx_personalities_deleted_at` ON `personalities`(`deleted_at`);
+CREATE INDEX `idx_zones_deleted_at` ON `zones`(`deleted_at`);
+CREATE INDEX `idx_vaccines_deleted_at` ON `vaccines`(`deleted_at`);
+CREATE INDEX `idx_kennels_deleted_at` ON `kennels`(`deleted_at`);
+CREATE INDEX `idx_staffs_deleted_at` ON `staffs`(`deleted_at`);
+CREATE INDEX `idx_dogs_deleted_at` ON `dogs`(`deleted_at`);
+CREATE UNIQUE INDEX `idx_users_username` ON `users`(`username`);
+CREATE UNIQUE INDEX `idx_users_email` ON `users`(`email`);
+CREATE INDEX `idx_users_deleted_at` ON `users`(`deleted_at`);
+CREATE INDEX `idx_adopters_deleted_at` ON `adopters`(`deleted_at`);
+CREATE INDEX `idx_adoptions_deleted_at` ON `adoptions`(`deleted_at`);
+CREATE INDEX `idx_visits_deleted_at` ON `visits`(`deleted_at`);
+CREATE INDEX `idx_medical_records_deleted_at` ON `medical_records`(`deleted_at`);
+CREATE INDEX `idx_events_deleted_at` ON `events`(`deleted_at`);
+CREATE INDEX `idx_attendees_deleted_at` ON `attendees`(`deleted_at`);
+CREATE INDEX `idx_buildings_deleted_at` ON `buildings`(`deleted_at`);
+CREATE INDEX `idx_dog_personalities_deleted_at` ON `dog_personalities`(`deleted_at`);
+CREATE INDEX `idx_donors_deleted_at` ON `donors`(`deleted_at`);
+CREATE INDEX `idx_donations_deleted_at` ON `donations`(`deleted_at`);
+CREATE INDEX `idx_item_donations_deleted_at` ON `item_donations`(`deleted_at`);
+CREATE INDEX `idx_payment_methods_deleted_at` ON `payment_methods`(`deleted_at`);
+CREATE INDEX `idx_money_donations_deleted_at` ON `money_donations`(`deleted_at`);
+CREATE UNIQUE INDEX `uniq_kind_email` ON `sponsors`(`email`);
+CREATE INDEX `idx_sponsors_email` ON `sponsors`(`email`);
+CREATE UNIQUE INDEX `idx_sponsors_user_id` ON `sponsors`(`user_id`);
+CREATE INDEX `idx_sponsors_kind` ON `sponsors`(`kind`);
+CREATE INDEX `idx_sponsors_deleted_at` ON `sponsors`(`deleted_at`);
+CREATE INDEX `idx_sponsorships_deleted_at` ON `sponsorships`(`deleted_at`);
+CREATE INDEX `idx_subscriptions_deleted_at` ON `subscriptions`(`deleted_at`);
+CREATE INDEX `idx_sponsorship_payments_transaction_ref` ON `sponsorship_payments`(`transaction_ref`);
+CREATE INDEX `idx_sponsorship_payments_deleted_at` ON `sponsorship_payments`(`deleted_at`);
+CREATE INDEX `idx_vaccine_records_deleted_at` ON `vaccine_records`(`deleted_at`);
+CREATE UNIQUE INDEX `idx_status_fvs_status` ON `status_fvs`(`status`);
+CREATE INDEX `idx_status_fvs_deleted_at` ON `status_fvs`(`deleted_at`);
+CREATE INDEX `idx_volunteers_deleted_at` ON `volunteers`(`deleted_at`);
+CREATE UNIQUE INDEX `idx_skills_description` ON `skills`(`description`);
+CREATE INDEX `idx_skills_deleted_at` ON `skills`(`deleted_at`);
+CREATE INDEX `idx_manages_deleted_at` ON `manages`(`deleted_at`);
+CREATE UNIQUE INDEX `idx_visit_dog` ON `visit_details`(`visit_id`,`dog_id`);
+CREATE INDEX `idx_visit_details_deleted_at` ON `visit_details`(`deleted_at`);
+DELETE FROM "sqlite_sequence";
+INSERT INTO "sqlite_sequence" VALUES('genders',2);
+INSERT INTO "sqlite_sequence" VALUES('animal_sexes',3);
+INSERT INTO "sqlite_sequence" VALUES('animal_sizes',3);
+INSERT INTO "sqlite_sequence" VALUES('personalities',6);
+INSERT INTO "sqlite_sequence" VALUES('payment_methods',2);
+INSERT INTO "sqlite_sequence" VALUES('breeds',3);
+INSERT INTO "sqlite_sequence" VALUES('zones',2);
+INSERT INTO "sqlite_sequence" VALUES('kennels',4);
+INSERT INTO "sqlite_sequence" VALUES('items',10);
+INSERT INTO "sqlite_sequence" VALUES('units',10);
+INSERT INTO "sqlite_sequence" VALUES('staffs',2);
+INSERT INTO "sqlite_sequence" VALUES('dogs',5);
+INSERT INTO "sqlite_sequence" VALUES('dog_personalities',14);
+INSERT INTO "sqlite_sequence" VALUES('vaccines',2);
+INSERT INTO "sqlite_sequence" VALUES('medical_records',1);
+INSERT INTO "sqlite_sequence" VALUES('vaccine_records',1);
+INSERT INTO "sqlite_sequence" VALUES('donors',2);
+INSERT INTO "sqlite_sequence" VALUES('donations',1);
+INSERT INTO "sqlite_sequence" VALUES('money_donations',1);
+INSERT INTO "sqlite_sequence" VALUES('skills',5);
+INSERT INTO "sqlite_sequence" VALUES('status_fvs',4);
+INSERT INTO "sqlite_sequence" VALUES('users',2);
+INSERT INTO "sqlite_sequence" VALUES('buildings',5);
+INSERT INTO "sqlite_sequence" VALUES('events',8);
+COMMIT;
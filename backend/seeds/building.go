@@ -21,5 +21,17 @@ func seedBuildings(db *gorm.DB) error {
 		}
 	}
 
+	// ผูกโซนกับอาคาร
+	for zone, building := range map[string]string{"A": "ตึก A", "B": "ตึก B", "ISO": "ตึก C"} {
+		var b entity.Building
+		if err := db.Where("building_name = ?", building).First(&b).Error; err != nil {
+			return err
+		}
+		if err := db.Model(&entity.Zone{}).Where("name = ? AND building_id IS NULL", zone).
+			Update("building_id", b.ID).Error; err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
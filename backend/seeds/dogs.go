@@ -62,7 +62,7 @@ func seedDogs(db *gorm.DB) error {
 			AnimalSizeID: sizeSmall.ID,
 			BreedID:      golden.ID,
 			KennelID:     pointer.P(kenA.ID),
-			DateOfBirth:  "2020-02-14",
+			DateOfBirth:  entity.MustParseDate("2020-02-14"),
 			IsAdopted:    false,
 			ReadyToAdopt: true,
 			PhotoURL:     fmt.Sprintf("%s/static/images/dog/dog1.jpg", PublicBaseURL),
@@ -74,7 +74,7 @@ func seedDogs(db *gorm.DB) error {
 			AnimalSizeID: sizeMedium.ID,
 			BreedID:      poodle.ID,
 			KennelID:     pointer.P(kenB.ID),
-			DateOfBirth:  "2020-02-14",
+			DateOfBirth:  entity.MustParseDate("2020-02-14"),
 			IsAdopted:    false,
 			ReadyToAdopt: true,
 			PhotoURL:     fmt.Sprintf("%s/static/images/dog/dog2.jpg", PublicBaseURL),
@@ -86,7 +86,7 @@ func seedDogs(db *gorm.DB) error {
 			AnimalSizeID: sizeLarge.ID,
 			BreedID:      bulldog.ID,
 			KennelID:     pointer.P(kenA.ID),
-			DateOfBirth:  "2020-02-14",
+			DateOfBirth:  entity.MustParseDate("2020-02-14"),
 			IsAdopted:    false,
 			ReadyToAdopt: true,
 			PhotoURL:     fmt.Sprintf("%s/static/images/dog/dog3.jpg", PublicBaseURL),
@@ -98,7 +98,7 @@ func seedDogs(db *gorm.DB) error {
 			AnimalSizeID: sizeLarge.ID,
 			BreedID:      bulldog.ID,
 			KennelID:     pointer.P(kenA.ID),
-			DateOfBirth:  "2020-02-14",
+			DateOfBirth:  entity.MustParseDate("2020-02-14"),
 			IsAdopted:    false,
 			ReadyToAdopt: true,
 			PhotoURL:     fmt.Sprintf("%s/static/images/dog/dog5.jpg", PublicBaseURL),
@@ -110,7 +110,7 @@ func seedDogs(db *gorm.DB) error {
 			AnimalSizeID: sizeLarge.ID,
 			BreedID:      bulldog.ID,
 			KennelID:     pointer.P(kenA.ID),
-			DateOfBirth:  "2020-02-14",
+			DateOfBirth:  entity.MustParseDate("2020-02-14"),
 			IsAdopted:    false,
 			ReadyToAdopt: true,
 			PhotoURL:     fmt.Sprintf("%s/static/images/dog/dog6.jpg", PublicBaseURL),
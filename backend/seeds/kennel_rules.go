@@ -0,0 +1,38 @@
+package seeds
+
+import (
+	"example.com/project-sa/entity"
+	"gorm.io/gorm"
+)
+
+// กฎการอยู่คอกเดียวกันเริ่มต้น (แก้/ปิดได้ที่ /kennel-rules)
+func seedKennelRules(db *gorm.DB) error {
+	var male, female entity.AnimalSex
+	if err := db.Where("name = ?", "ตัวผู้").First(&male).Error; err != nil {
+		return err
+	}
+	if err := db.Where("name = ?", "ตัวเมีย").First(&female).Error; err != nil {
+		return err
+	}
+	reactive := entity.Personality{Name: "ไม่ถูกกับสุนัขตัวอื่น"}
+	if err := db.Where("name = ?", reactive.Name).FirstOrCreate(&reactive).Error; err != nil {
+		return err
+	}
+
+	rules := []entity.KennelRule{
+		{Name: "ตัวผู้ไม่ทำหมันอยู่ด้วยกัน", Kind: entity.KennelRuleIntactSameSex, Severity: entity.KennelRuleBlock, SexID: &male.ID},
+		{Name: "ตัวผู้กับตัวเมียไม่ทำหมันอยู่ด้วยกัน", Kind: entity.KennelRuleIntactMixedSex, Severity: entity.KennelRuleBlock, SexID: &male.ID, OtherSexID: &female.ID},
+		{Name: "ตัวเล็กอยู่กับตัวใหญ่ที่ไม่ถูกกับสุนัขอื่น", Kind: entity.KennelRuleSizeGap, Severity: entity.KennelRuleBlock, MaxSizeGap: 0, PersonalityID: &reactive.ID},
+		{Name: "ขนาดตัวต่างกันมาก", Kind: entity.KennelRuleSizeGap, Severity: entity.KennelRuleWarn, MaxSizeGap: 1},
+		{Name: "ไม่ถูกกับสุนัขอื่นทั้งคู่", Kind: entity.KennelRulePersonality, Severity: entity.KennelRuleWarn, PersonalityID: &reactive.ID},
+		{Name: "สุนัขกักโรคอยู่กับตัวที่ไม่ได้กักโรค", Kind: entity.KennelRuleQuarantineMix, Severity: entity.KennelRuleBlock},
+		{Name: "ขนาดตัวไม่ตรงกับคอก", Kind: entity.KennelRuleKennelSize, Severity: entity.KennelRuleWarn},
+	}
+	for i := range rules {
+		rules[i].Enabled = true
+		if err := db.Where("name = ?", rules[i].Name).FirstOrCreate(&rules[i]).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -14,6 +14,11 @@ func seedKennels(db *gorm.DB) error {
 	if err := db.Where("name = ?", "B").First(&zoneB).Error; err != nil {
 		return err
 	}
+	// โซนแยกโรค สำหรับสุนัขที่กักโรค
+	zoneISO := entity.Zone{Name: "ISO", IsIsolation: true}
+	if err := db.Where("name = ?", zoneISO.Name).FirstOrCreate(&zoneISO).Error; err != nil {
+		return err
+	}
 
 	if err := db.
 		Where("name = ?", "00").
@@ -31,6 +36,7 @@ func seedKennels(db *gorm.DB) error {
 		{Name: "A-1", ZoneID: zoneA.ID, Capacity: 10, Color: "Blue", Note: pointer.P("ใกล้ทางเข้า")},
 		{Name: "A-2", ZoneID: zoneA.ID, Capacity: 8, Color: "Cyan", Note: pointer.P("เงียบสงบ")},
 		{Name: "B-1", ZoneID: zoneB.ID, Capacity: 8, Color: "Green", Note: pointer.P("พื้นที่เล่น")},
+		{Name: "ISO-1", ZoneID: zoneISO.ID, Capacity: 2, Color: "Red", Note: pointer.P("คอกกักโรค")},
 	}
 
 	for i := range kennels {
@@ -0,0 +1,67 @@
+package seeds
+
+import (
+	"example.com/project-sa/entity"
+	"example.com/project-sa/utils/pointer"
+	"gorm.io/gorm"
+)
+
+// ชุดตรวจแล็บพื้นฐานพร้อมค่าอ้างอิงของสุนัขโตเต็มวัย
+func seedLabPanels(db *gorm.DB) error {
+	num := func(code, name, unit string, lo, hi float64) entity.LabAnalyte {
+		return entity.LabAnalyte{Code: code, Name: name, Unit: unit, RefLow: pointer.P(lo), RefHigh: pointer.P(hi)}
+	}
+	neg := func(code, name string) entity.LabAnalyte {
+		return entity.LabAnalyte{Code: code, Name: name, NormalText: "negative"}
+	}
+	panels := []entity.LabPanel{
+		{Code: "CBC", Name: "Complete blood count", SampleType: "blood", Analytes: []entity.LabAnalyte{
+			num("WBC", "White blood cells", "10^3/µL", 6, 17),
+			num("RBC", "Red blood cells", "10^6/µL", 5.5, 8.5),
+			num("HGB", "Hemoglobin", "g/dL", 12, 18),
+			num("HCT", "Hematocrit", "%", 37, 55),
+			num("PLT", "Platelets", "10^3/µL", 200, 500),
+		}},
+		{Code: "CHEM", Name: "Blood chemistry", SampleType: "serum", Analytes: []entity.LabAnalyte{
+			num("ALT", "Alanine aminotransferase", "U/L", 10, 100),
+			num("ALP", "Alkaline phosphatase", "U/L", 20, 150),
+			num("BUN", "Blood urea nitrogen", "mg/dL", 7, 27),
+			num("CREA", "Creatinine", "mg/dL", 0.5, 1.8),
+			num("GLU", "Glucose", "mg/dL", 74, 143),
+			num("TP", "Total protein", "g/dL", 5.2, 8.2),
+			num("ALB", "Albumin", "g/dL", 2.3, 4.0),
+		}},
+		{Code: "FECAL", Name: "Fecal parasite exam", SampleType: "feces", Analytes: []entity.LabAnalyte{
+			neg("ROUND", "Roundworm"),
+			neg("HOOK", "Hookworm"),
+			neg("WHIP", "Whipworm"),
+			neg("GIARDIA", "Giardia"),
+			neg("COCCI", "Coccidia"),
+		}},
+		{Code: "4DX", Name: "Heartworm / tick-borne disease test", SampleType: "blood", Analytes: []entity.LabAnalyte{
+			neg("HW", "Heartworm antigen"),
+			neg("EHR", "Ehrlichia antibody"),
+			neg("ANA", "Anaplasma antibody"),
+			neg("LYME", "Lyme antibody"),
+		}},
+		{Code: "SKIN", Name: "Skin scraping", SampleType: "skin", Analytes: []entity.LabAnalyte{
+			neg("DEMO", "Demodex mites"),
+			neg("SARC", "Sarcoptes mites"),
+		}},
+	}
+	for _, p := range panels {
+		analytes := p.Analytes
+		p.Analytes = nil
+		if err := db.FirstOrCreate(&p, &entity.LabPanel{Code: p.Code}).Error; err != nil {
+			return err
+		}
+		for i, a := range analytes {
+			a.PanelID = p.ID
+			a.SortOrder = i + 1
+			if err := db.FirstOrCreate(&a, &entity.LabAnalyte{PanelID: p.ID, Code: a.Code}).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
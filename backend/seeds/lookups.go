@@ -43,10 +43,10 @@ func seedLookupsBase(db *gorm.DB) error {
 	personalities := []entity.Personality{
 		{Name: "ชอบผจญภัย"},
 		{Name: "ชอบเรียนรู้สิ่งใหม่ ๆ"},
-		{Name: "มั่นใจในตนเอง"},
-		{Name: "สงบ"},
-		{Name: "เข้ากับคนอื่นง่าย"},
-		{Name: "เป็นมิตร"},
+		{Name: entity.PersonalityConfident},
+		{Name: entity.PersonalityCalm},
+		{Name: entity.PersonalitySociable},
+		{Name: entity.PersonalityFriendly},
 	}
 
 	for i := range personalities {
@@ -32,7 +32,7 @@ func seedMedicalRecords(db *gorm.DB) error {
 		Diagnosis:     "Healthy",
 		TreatmentPlan: "Routine checkup",
 		Medication:    "None",
-		Vaccination:   "YES",
+		Vaccination:   entity.VaccinationYes,
 		DogID:         dog1.ID, // Link to Dog1
 		StaffID:       staff1.ID,
 	}
@@ -44,7 +44,7 @@ func seedMedicalRecords(db *gorm.DB) error {
 		Diagnosis:     "Kennel Cough",
 		TreatmentPlan: "Antibiotics",
 		Medication:    "Doxycycline",
-		Vaccination:   "NO",
+		Vaccination:   entity.VaccinationNo,
 		DogID:         dog2.ID, // Link to Dog2
 		StaffID:       staff2.ID,
 	}
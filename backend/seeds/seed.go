@@ -36,6 +36,21 @@ func SeedAll(db *gorm.DB) error {
 		if err := seedVaccineRecords(tx); err != nil {
 			return err
 		}
+		if err := seedVaccineProtocols(tx); err != nil {
+			return err
+		}
+		if err := seedVitalRanges(tx); err != nil {
+			return err
+		}
+		if err := seedPuppyGrowth(tx); err != nil {
+			return err
+		}
+		if err := seedLabPanels(tx); err != nil {
+			return err
+		}
+		if err := seedKennelRules(tx); err != nil {
+			return err
+		}
 		if err := seedDonors(tx); err != nil {
 			return err
 		}
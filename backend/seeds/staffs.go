@@ -42,6 +42,7 @@ func seedStaffs(db *gorm.DB) error {
 			PhotoURL:    pointer.P(fmt.Sprintf("%s/static/images/staff_profile/staff1.png", PublicBaseURL)),
 			Note:        pointer.P("Ops manager"),
 			ZoneID:      zoneB.ID,
+			Role:        entity.StaffRoleAdmin,
 		},
 		{
 			FirstName:   "Kittisak",
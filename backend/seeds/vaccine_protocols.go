@@ -0,0 +1,33 @@
+package seeds
+
+import (
+	"example.com/project-sa/entity"
+	"gorm.io/gorm"
+)
+
+// seed หลัง vaccine records เพื่อไม่ให้ next_due_date ของข้อมูลตัวอย่างถูกคำนวณใหม่
+func seedVaccineProtocols(db *gorm.DB) error {
+	protocols := map[string][]entity.VaccineProtocol{
+		"Rabies": {
+			{AgeGroup: entity.ProtocolPuppy, DoseCount: 2, DoseIntervalDays: 28, BoosterIntervalDays: 365},
+			{AgeGroup: entity.ProtocolAdult, DoseCount: 1, BoosterIntervalDays: 365},
+		},
+		"Distemper": {
+			{AgeGroup: entity.ProtocolPuppy, DoseCount: 3, DoseIntervalDays: 21, BoosterIntervalDays: 365},
+			{AgeGroup: entity.ProtocolAdult, DoseCount: 1, BoosterIntervalDays: 365},
+		},
+	}
+	for name, list := range protocols {
+		var vac entity.Vaccine
+		if err := db.Where("name = ?", name).First(&vac).Error; err != nil {
+			return err
+		}
+		for _, p := range list {
+			p.VaccineID = vac.ID
+			if err := db.FirstOrCreate(&p, &entity.VaccineProtocol{VaccineID: vac.ID, AgeGroup: p.AgeGroup}).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package seeds
+
+import (
+	"example.com/project-sa/entity"
+	"gorm.io/gorm"
+)
+
+// ค่าปกติของสัญญาณชีพตามขนาดตัว (แก้ได้ที่ PUT /vital-ranges/:size_id)
+func seedVitalRanges(db *gorm.DB) error {
+	ranges := map[string]entity.VitalRange{
+		"เล็ก": {TempMin: 38.0, TempMax: 39.2, WeightMin: 1, WeightMax: 10, WeightLossPercent: 10, WeightLossDays: 30},
+		"กลาง": {TempMin: 37.8, TempMax: 39.2, WeightMin: 10, WeightMax: 25, WeightLossPercent: 10, WeightLossDays: 30},
+		"ใหญ่": {TempMin: 37.5, TempMax: 39.0, WeightMin: 25, WeightMax: 70, WeightLossPercent: 10, WeightLossDays: 30},
+	}
+	for name, r := range ranges {
+		var size entity.AnimalSize
+		if err := db.Where("name = ?", name).First(&size).Error; err != nil {
+			return err
+		}
+		r.AnimalSizeID = size.ID
+		if err := db.FirstOrCreate(&r, &entity.VitalRange{AnimalSizeID: size.ID}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// น้ำหนักมัธยฐานลูกสุนัขอายุ 1–12 เดือน (kg); P10/P90 ประมาณ ±20%
+var puppyGrowthP50 = map[string][]float64{
+	"เล็ก": {0.8, 1.5, 2.3, 3.0, 3.6, 4.1, 4.5, 4.8, 5.0, 5.2, 5.3, 5.4},
+	"กลาง": {1.5, 3.5, 6.0, 8.0, 10.0, 11.5, 12.8, 13.8, 14.6, 15.2, 15.6, 16.0},
+	"ใหญ่": {2.5, 6.0, 10.0, 14.0, 17.5, 20.5, 23.0, 25.0, 26.5, 27.8, 28.8, 29.5},
+}
+
+func seedPuppyGrowth(db *gorm.DB) error {
+	for name, p50s := range puppyGrowthP50 {
+		var size entity.AnimalSize
+		if err := db.Where("name = ?", name).First(&size).Error; err != nil {
+			return err
+		}
+		for i, p50 := range p50s {
+			ref := entity.PuppyGrowthReference{AnimalSizeID: size.ID, AgeMonths: i + 1, P10: p50 * 0.8, P50: p50, P90: p50 * 1.2}
+			if err := db.FirstOrCreate(&ref, &entity.PuppyGrowthReference{AnimalSizeID: size.ID, AgeMonths: i + 1}).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
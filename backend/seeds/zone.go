@@ -9,6 +9,7 @@ func seedZones(db *gorm.DB) error {
 	zones := []entity.Zone{
 		{Name: "A"},
 		{Name: "B"},
+		{Name: "ISO", IsIsolation: true},
 	}
 
 	for i := range zones {
@@ -0,0 +1,111 @@
+// Package testutil ตัวช่วยของเทส: เปิด DB ชั่วคราว และยิง request เข้า router
+package testutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"example.com/project-sa/configs"
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Open DB sqlite ชั่วคราวต่อเทส เปิดแบบเดียวกับ configs (FK, connection เดียว) แล้ว AutoMigrate models
+func Open(t testing.TB, models ...any) *gorm.DB {
+	t.Helper()
+	dsn := "file:" + filepath.Join(t.TempDir(), "test.db") + "?_fk=1&_busy_timeout=5000"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("db: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	if len(models) > 0 {
+		if err := db.AutoMigrate(models...); err != nil {
+			t.Fatalf("migrate: %v", err)
+		}
+	}
+	return db
+}
+
+// Main ใช้ใน TestMain ของ package ที่เรียก configs.DB()
+// DB ของ configs เปิดจากไฟล์ใน working directory จึงย้ายไปโฟลเดอร์ชั่วคราวก่อน
+// แล้วเตรียม DB ตาม prepare (เช่น migrations.AutoMigrate, seeds.SeedAll)
+func Main(m *testing.M, prepare ...func(*gorm.DB) error) {
+	dir, err := os.MkdirTemp("", "project-sa-test")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		log.Fatal(err)
+	}
+	db := configs.MustOpenDB()
+	db.Logger = logger.Discard
+	for _, p := range prepare {
+		if err := p(db); err != nil {
+			log.Fatal(err)
+		}
+	}
+	gin.SetMode(gin.TestMode)
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// Router gin เปล่าที่ใส่ staff_id ให้ทุก request (0 = ไม่ได้ login)
+func Router(staffID uint) *gin.Engine {
+	r := gin.New()
+	if staffID != 0 {
+		r.Use(func(c *gin.Context) { c.Set("staff_id", staffID) })
+	}
+	return r
+}
+
+// Do ยิง request (body เป็น JSON) แล้วคืน status กับ body ที่ decode แล้ว
+func Do(t testing.TB, r http.Handler, method, path string, body any) (int, map[string]any) {
+	t.Helper()
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			t.Fatalf("encode body: %v", err)
+		}
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(method, path, &buf))
+	var out map[string]any
+	json.Unmarshal(w.Body.Bytes(), &out)
+	return w.Code, out
+}
+
+// MustDo เหมือน Do แต่ fail ถ้า status ไม่ใช่ 2xx แล้วคืน "data"
+func MustDo(t testing.TB, r http.Handler, method, path string, body any) map[string]any {
+	t.Helper()
+	code, out := Do(t, r, method, path, body)
+	if code >= 300 {
+		t.Fatalf("%s %s = %d %v", method, path, code, out)
+	}
+	data, _ := out["data"].(map[string]any)
+	return data
+}
+
+// ID อ่านฟิลด์ ID (ตัวเลขจาก JSON) ของ object ที่ decode แล้ว
+func ID(obj map[string]any) uint {
+	for _, k := range []string{"ID", "id"} {
+		if v, ok := obj[k].(float64); ok {
+			return uint(v)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,108 @@
+package upload
+
+import (
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrNoFile          = errors.New("file is required")
+	ErrUnsupportedType = errors.New("unsupported file type")
+	ErrTooLarge        = errors.New("file is too large")
+)
+
+// ขนาดไฟล์สูงสุดที่รับ
+const MaxFileSize = 20 << 20
+
+const (
+	publicRoot  = "static"  // เสิร์ฟผ่าน /static
+	privateRoot = "private" // ไม่เสิร์ฟ
+)
+
+var imageExts = map[string]bool{".jpg": true, ".jpeg": true, ".png": true, ".webp": true, ".gif": true}
+
+// SaveImage เก็บไฟล์รูปจาก form field "file" ไว้ใต้ static/uploads/<folder>/YYYY/MM
+// แล้วคืน URL สำหรับ FE (เริ่มด้วย /static/...)
+func SaveImage(c *gin.Context, folder, prefix string) (string, error) {
+	return Save(c, folder, prefix, imageExts)
+}
+
+// Save ใช้ร่วมกันกับไฟล์ชนิดอื่น (allowed = นามสกุลที่รับ เช่น ".pdf")
+func Save(c *gin.Context, folder, prefix string, allowed map[string]bool) (string, error) {
+	full, _, err := save(c, publicRoot, folder, prefix, allowed)
+	if err != nil {
+		return "", err
+	}
+	return "/" + filepath.ToSlash(full), nil
+}
+
+// SavePrivate เหมือน Save แต่เก็บนอก static (ไม่มี URL สาธารณะ ต้องส่งไฟล์ผ่าน handler ที่ตรวจสิทธิ์)
+// คืน path บนดิสก์และข้อมูลไฟล์ต้นฉบับ
+func SavePrivate(c *gin.Context, folder, prefix string, allowed map[string]bool) (string, *multipart.FileHeader, error) {
+	return save(c, privateRoot, folder, prefix, allowed)
+}
+
+// Remove ลบไฟล์ที่เก็บไว้ (ไม่มีไฟล์แล้วถือว่าสำเร็จ)
+func Remove(path string) error {
+	if err := os.Remove(strings.TrimPrefix(path, "/")); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func save(c *gin.Context, root, folder, prefix string, allowed map[string]bool) (string, *multipart.FileHeader, error) {
+	f, err := c.FormFile("file")
+	if err != nil {
+		return "", nil, ErrNoFile
+	}
+
+	ext := strings.ToLower(filepath.Ext(f.Filename))
+	if !allowed[ext] {
+		return "", nil, ErrUnsupportedType
+	}
+	if f.Size > MaxFileSize {
+		return "", nil, ErrTooLarge
+	}
+
+	full, err := newPath(root, folder, prefix, ext)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := c.SaveUploadedFile(f, full); err != nil {
+		return "", nil, fmt.Errorf("save failed: %w", err)
+	}
+	return full, f, nil
+}
+
+// SaveData เก็บไฟล์จากข้อมูลในหน่วยความจำ (เช่น รูปใน bundle ที่นำเข้า)
+func SaveData(folder, prefix, ext string, data []byte) (string, error) {
+	ext = strings.ToLower(ext)
+	if !imageExts[ext] {
+		return "", ErrUnsupportedType
+	}
+	full, err := newPath(publicRoot, folder, prefix, ext)
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(full, data, 0644); err != nil {
+		return "", fmt.Errorf("save failed: %w", err)
+	}
+	return "/" + filepath.ToSlash(full), nil
+}
+
+func newPath(root, folder, prefix, ext string) (string, error) {
+	now := time.Now()
+	dir := filepath.Join(root, "uploads", folder, now.Format("2006"), now.Format("01"))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("mkdir failed: %w", err)
+	}
+	return filepath.Join(dir, fmt.Sprintf("%s-%s%s", prefix, uuid.NewString(), ext)), nil
+}
@@ -9,10 +9,3 @@ export interface ZcManagementInterface {
     staff?: AppStaffInterface;
     action: string;
 }
-
-export interface UpdateZCManagementRequest {
-    kennel?: Partial<KennelInterface>;
-    dog?: Partial<DogInterface>;
-    staff?: Partial<AppStaffInterface>;
-    action: string;
-}
@@ -332,30 +332,14 @@ const ZoneCageManagementPage = () => {
       const kennelIdNum = Number(selectedCage);
       const ops: Promise<any>[] = [];
 
-      // assign ops + logs
+      // assign ops (the backend logs every move itself)
       for (const id of added) {
         ops.push(guardAssign(kennelIdNum, id));
-        ops.push(
-          zcManagementAPI.createLog({
-            kennel: { id: kennelIdNum },
-            dog:    { ID: id },
-            staff:  { ID: staffId },
-            action: "assign",
-          }).catch(() => null) // <- don't block on log
-        );
       }
 
-      // remove ops + logs
+      // remove ops
       for (const id of removed) {
         ops.push(guardRemove(kennelIdNum, id));
-        ops.push(
-          zcManagementAPI.createLog({
-            kennel: { id: kennelIdNum },
-            dog:    { ID: id },
-            staff:  { ID: staffId },
-            action: "remove",
-          }).catch(() => null)
-        );
       }
 
       const results = await Promise.allSettled(ops);
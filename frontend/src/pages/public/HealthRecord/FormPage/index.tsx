@@ -178,7 +178,6 @@ const FormPage: React.FC = () => {
 
     const healthRecordData = {
       dog_id: parseInt(dogId),
-      staff_id: 1, // Assuming staff ID 1 exists for now
       weight: parseFloat(values.weight),
       temperature: parseFloat(values.temperature),
       symptoms: values.symptoms?.trim(),
@@ -22,7 +22,6 @@ const mpHeaders = { "Content-Type": "multipart/form-data" };
 import type { CreateAdoptionRequest, UpdateStatusRequest } from "../interfaces/Adoption";
 import type { CreateSponsorshipRequest } from "../interfaces/Sponsorship";
 import type { CreateManageRequest,UpdateManageRequest } from "../interfaces/Manage";
-import type { UpdateZCManagementRequest } from "../interfaces/zcManagement";
 
 import type { CreateEventRequest, UpdateEventRequest } from "../interfaces/Event";
 /** ---------- AUTH ---------- */
@@ -340,26 +339,11 @@ export const zcManagementAPI = {
     dogAPI.update(Number(dogId), { kennel_id: Number(kennelId) } as UpdateDogRequest),
     
 
-  // "Unassign": move dog into kennel named "00"
-  removeDogFromKennel: async (_kennelId: number, dogId: number) => {
-    const k00 = await resolveKennel00Id();
-    if (!k00) throw new Error('Kennel "00" not found. Seed it first.');
-    return dogAPI.update(Number(dogId), { kennel_id: k00 } as UpdateDogRequest);
-  },
+  // "Unassign": take the dog out of its kennel (kennel_id = null)
+  removeDogFromKennel: (kennelId: number, dogId: number) =>
+    Delete(`/kennels/${Number(kennelId)}/dog?dog_id=${Number(dogId)}`),
 
   getUnassignedKennelId: () => resolveKennel00Id(),
-  createLog: (data: UpdateZCManagementRequest) =>
-    Post(
-      "/zcmanagement/log",
-      {
-        // accept either .id or .ID from whatever you pass in
-        kennel_id: Number((data as any)?.kennel?.id ?? (data as any)?.kennel?.ID ?? 0),
-        dog_id:    Number((data as any)?.dog?.ID    ?? (data as any)?.dog?.id    ?? 0),
-        staff_id:  Number((data as any)?.staff?.ID  ?? (data as any)?.staff?.id  ?? 0), // <-- FIXED key
-        action:    String((data as any)?.action ?? ""),
-      },
-      true 
-    ),
 };
 
 